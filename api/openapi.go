@@ -0,0 +1,9 @@
+// Package api содержит описания API сервиса: спецификацию HTTP API и protobuf-описание gRPC.
+package api
+
+import _ "embed"
+
+// OpenAPI — спецификация HTTP API (openapi.yml). По ней проверяются запросы и ответы.
+//
+//go:embed openapi.yml
+var OpenAPI []byte
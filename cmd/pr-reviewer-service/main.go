@@ -7,17 +7,36 @@ import (
 	"errors"
 	"fmt"
 	"log/slog"
+	"net"
 	"net/http"
 	"os"
 	"os/signal"
 	"syscall"
 	"time"
 
+	"google.golang.org/grpc"
+
+	"github.com/dixitix/pr-reviewer-service/api"
+	"github.com/dixitix/pr-reviewer-service/internal/auth"
+	"github.com/dixitix/pr-reviewer-service/internal/authn"
 	"github.com/dixitix/pr-reviewer-service/internal/config"
+	"github.com/dixitix/pr-reviewer-service/internal/domain"
+	grpcserver "github.com/dixitix/pr-reviewer-service/internal/grpc"
 	httpserver "github.com/dixitix/pr-reviewer-service/internal/http"
+	"github.com/dixitix/pr-reviewer-service/internal/http/events"
+	"github.com/dixitix/pr-reviewer-service/internal/http/idempotency"
+	"github.com/dixitix/pr-reviewer-service/internal/http/middleware"
+	"github.com/dixitix/pr-reviewer-service/internal/http/openapi"
 	"github.com/dixitix/pr-reviewer-service/internal/logger"
+	"github.com/dixitix/pr-reviewer-service/internal/metrics"
+	"github.com/dixitix/pr-reviewer-service/internal/notify"
+	"github.com/dixitix/pr-reviewer-service/internal/outbox"
+	"github.com/dixitix/pr-reviewer-service/internal/repository"
 	"github.com/dixitix/pr-reviewer-service/internal/repository/postgres"
 	"github.com/dixitix/pr-reviewer-service/internal/service"
+	"github.com/dixitix/pr-reviewer-service/internal/sla"
+	"github.com/dixitix/pr-reviewer-service/internal/vcs"
+	"github.com/dixitix/pr-reviewer-service/internal/webhook"
 
 	_ "github.com/jackc/pgx/v5/stdlib"
 )
@@ -32,7 +51,7 @@ func main() {
 	}
 }
 
-// run настраивает конфиг, подключение к БД, сервисы и HTTP-сервер и
+// run настраивает конфиг, подключение к БД, сервисы, HTTP- и gRPC-серверы и
 // блокируется до завершения контекста (SIGINT/SIGTERM).
 func run(log *slog.Logger) error {
 	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
@@ -53,24 +72,115 @@ func run(log *slog.Logger) error {
 		}
 	}()
 
+	orgRepo := postgres.NewOrganizationRepository(db)
+	apiKeyRepo := postgres.NewAPIKeyRepository(db)
 	teamRepo := postgres.NewTeamRepository(db)
 	userRepo := postgres.NewUserRepository(db)
 	prRepo := postgres.NewPullRequestRepository(db)
+	webhookRepo := postgres.NewWebhookRepository(db)
+	outboxRepo := postgres.NewOutboxRepository(db)
+	notifyRepo := postgres.NewNotificationRepository(db)
+	idempotencyRepo := postgres.NewIdempotencyRepository(db)
+
+	var (
+		registry      *metrics.Registry
+		domainMetrics service.Metrics
+	)
+
+	if cfg.Metrics.Addr != "" {
+		registry = metrics.NewRegistry(log.With("component", "metrics"))
+		metrics.RegisterDBStats(registry, db)
+		domainMetrics = metrics.NewDomain(registry)
+	}
+
+	svc := service.NewService(postgres.NewTransactor(db), orgRepo, apiKeyRepo, teamRepo, userRepo, prRepo, webhookRepo, notifyRepo, postgres.NewAuditRepository(db), service.Config{
+		ReviewerPool: domain.ReviewerPool(cfg.Assign.ReviewerPool),
+		Metrics:      domainMetrics,
+	})
+
+	if registry != nil {
+		metrics.RegisterOpenReviews(registry, svc)
+	}
+
+	sinks := newOutboxSinks(ctx, cfg, webhookRepo, userRepo, notifyRepo, postgres.NewVCSSyncRepository(db), log)
+
+	if cfg.Notify.SMTPHost != "" {
+		emailSink, err := startEmailNotifications(ctx, cfg.Notify, orgRepo, userRepo, prRepo, notifyRepo, log.With("component", "email"))
+		if err != nil {
+			return fmt.Errorf("init email notifications: %w", err)
+		}
+
+		sinks = append(sinks, emailSink)
+	}
+
+	relay := outbox.NewRelay(outboxRepo, sinks, outbox.Config{
+		PollInterval: cfg.Outbox.PollInterval,
+		BatchSize:    cfg.Outbox.BatchSize,
+		Retention:    cfg.Outbox.Retention,
+	}, log.With("component", "outbox"))
+	go relay.Run(ctx)
+
+	if cfg.SLA.Enabled {
+		scheduler := sla.NewScheduler(svc, domain.ReviewPolicy{
+			ReminderAfter: cfg.SLA.ReminderAfter,
+			EscalateAfter: cfg.SLA.EscalateAfter,
+			Action:        domain.EscalationAction(cfg.SLA.EscalationAction),
+		}, cfg.SLA.Interval, log.With("component", "sla"))
+		go scheduler.Run(ctx)
+	}
+
+	idempotencyMiddleware := idempotency.New(idempotencyRepo, idempotency.Config{
+		TTL: cfg.HTTP.IdempotencyTTL,
+	}, log.With("component", "idempotency"))
+	go idempotencyMiddleware.Run(ctx)
+
+	authCfg := authn.Config{
+		Enabled: cfg.HTTP.AuthEnabled,
+		RootKey: cfg.HTTP.RootAPIKey,
+		Tokens:  newTokenVerifier(cfg.JWT, log),
+	}
+
+	// Спецификация нужна и проверке запросов, и метрикам: по ней путь переводится в шаблон маршрута.
+	doc, err := openapi.Load(api.OpenAPI)
+	if err != nil {
+		return fmt.Errorf("load openapi spec: %w", err)
+	}
+
+	var validation *openapi.Middleware
 
-	svc := service.NewService(teamRepo, userRepo, prRepo)
+	if cfg.HTTP.ValidateRequests {
+		validation = openapi.New(doc, openapi.Config{
+			ValidateResponses: cfg.HTTP.ValidateResponses,
+		}, log.With("component", "openapi"))
+	}
 
-	httpHandler := httpserver.NewHandler(svc, log.With("layer", "http"))
+	httpHandler := httpserver.NewHandler(svc, httpserver.Config{
+		DefaultOrganization: domain.OrganizationID(cfg.HTTP.DefaultOrganization),
+		Auth:                authCfg,
+		Idempotency:         idempotencyMiddleware,
+		Validation:          validation,
+		Events: events.Config{
+			PollInterval:      cfg.HTTP.EventsPollInterval,
+			HeartbeatInterval: cfg.HTTP.EventsHeartbeatInterval,
+		},
+	}, log.With("layer", "http"))
 
 	mux := http.NewServeMux()
 	httpHandler.RegisterRoutes(mux)
 
+	var httpMiddleware []func(http.Handler) http.Handler
+	if registry != nil {
+		httpMiddleware = append(httpMiddleware, metrics.NewHTTP(registry, doc.Route).Wrap)
+	}
+
 	srv := &http.Server{
 		Addr:         cfg.HTTP.Addr,
-		Handler:      mux,
+		Handler:      middleware.Wrap(mux, log.With("layer", "http"), httpMiddleware...),
 		ReadTimeout:  10 * time.Second,
 		WriteTimeout: 10 * time.Second,
 		IdleTimeout:  60 * time.Second,
 	}
+	srv.RegisterOnShutdown(httpHandler.Shutdown)
 
 	go func() {
 		log.Info("http server starting", slog.String("addr", cfg.HTTP.Addr))
@@ -81,22 +191,236 @@ func run(log *slog.Logger) error {
 		}
 	}()
 
+	var metricsSrv *http.Server
+
+	// Метрики отдаются без API-ключа и с данными всех организаций, поэтому не на публичном
+	// HTTP-сервере, а на отдельном адресе для сбора Prometheus во внутренней сети.
+	if registry != nil {
+		metricsMux := http.NewServeMux()
+		metricsMux.Handle("/metrics", registry.Handler())
+
+		metricsSrv = &http.Server{
+			Addr:         cfg.Metrics.Addr,
+			Handler:      metricsMux,
+			ReadTimeout:  10 * time.Second,
+			WriteTimeout: 10 * time.Second,
+			IdleTimeout:  60 * time.Second,
+		}
+
+		go func() {
+			log.Info("metrics server starting", slog.String("addr", cfg.Metrics.Addr))
+
+			if err := metricsSrv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+				log.Error("metrics server failed", slog.Any("err", err))
+				stop()
+			}
+		}()
+	}
+
+	var grpcSrv *grpc.Server
+
+	if cfg.GRPC.Addr != "" {
+		lis, err := net.Listen("tcp", cfg.GRPC.Addr)
+		if err != nil {
+			return fmt.Errorf("grpc listen: %w", err)
+		}
+
+		grpcSrv = grpcserver.New(svc, grpcserver.Config{
+			DefaultOrganization: domain.OrganizationID(cfg.HTTP.DefaultOrganization),
+			Auth:                authCfg,
+		}, log.With("layer", "grpc"))
+
+		go func() {
+			log.Info("grpc server starting", slog.String("addr", cfg.GRPC.Addr))
+
+			if err := grpcSrv.Serve(lis); err != nil {
+				log.Error("grpc server failed", slog.Any("err", err))
+				stop()
+			}
+		}()
+	}
+
 	<-ctx.Done()
 
 	shutDownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
+	if grpcSrv != nil {
+		log.Info("grpc server shutting down")
+		stopGRPC(shutDownCtx, grpcSrv)
+	}
+
 	log.Info("http server shutting down")
 
 	if err := srv.Shutdown(shutDownCtx); err != nil {
 		return fmt.Errorf("http server shutdown: %w", err)
 	}
 
+	if metricsSrv != nil {
+		log.Info("metrics server shutting down")
+
+		if err := metricsSrv.Shutdown(shutDownCtx); err != nil {
+			return fmt.Errorf("metrics server shutdown: %w", err)
+		}
+	}
+
 	log.Info("application stopped cleanly")
 
 	return nil
 }
 
+// stopGRPC дожидается завершения активных gRPC-вызовов, а по истечении ctx прерывает их.
+func stopGRPC(ctx context.Context, srv *grpc.Server) {
+	done := make(chan struct{})
+
+	go func() {
+		srv.GracefulStop()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-ctx.Done():
+		srv.Stop()
+	}
+}
+
+// newOutboxSinks создаёт и запускает получателей событий из outbox согласно конфигурации.
+func newOutboxSinks(
+	ctx context.Context,
+	cfg config.Config,
+	webhookRepo repository.WebhookRepository,
+	userRepo repository.UserRepository,
+	notifyRepo repository.NotificationRepository,
+	vcsRepo repository.VCSSyncRepository,
+	log *slog.Logger,
+) []outbox.Sink {
+	dispatcher := webhook.NewDispatcher(webhookRepo, webhook.Config{
+		Workers:        cfg.Webhook.Workers,
+		MaxAttempts:    cfg.Webhook.MaxAttempts,
+		InitialBackoff: cfg.Webhook.InitialBackoff,
+		MaxBackoff:     cfg.Webhook.MaxBackoff,
+		Timeout:        cfg.Webhook.Timeout,
+	}, nil, log.With("component", "webhook"))
+	go dispatcher.Run(ctx)
+
+	sinks := []outbox.Sink{dispatcher}
+
+	if cfg.VCS.Provider != "" {
+		syncer := newVCSSyncer(cfg.VCS, vcsRepo, log.With("component", "vcs"))
+		go syncer.Run(ctx)
+
+		sinks = append(sinks, syncer)
+	}
+
+	if cfg.Notify.ChatEnabled {
+		sinks = append(sinks, notify.NewChatNotifier(
+			userRepo,
+			notifyRepo,
+			notify.NewSlackClient(nil, cfg.Notify.ChatTimeout),
+			cfg.Notify.ChatDefaultWebhookURL,
+			log.With("component", "chat"),
+		))
+	}
+
+	if cfg.Outbox.NATSURL != "" {
+		log.Info("nats event publishing enabled", slog.String("url", cfg.Outbox.NATSURL))
+
+		sinks = append(sinks, outbox.NewNATSSink(outbox.NATSConfig{
+			URL:           cfg.Outbox.NATSURL,
+			SubjectPrefix: cfg.Outbox.NATSSubjectPrefix,
+		}))
+	}
+
+	if cfg.Outbox.LogSink {
+		sinks = append(sinks, outbox.NewLogSink(log.With("component", "outbox-log")))
+	}
+
+	return sinks
+}
+
+// startEmailNotifications создаёт получателя писем о назначениях и запускает ежедневную сводку.
+func startEmailNotifications(
+	ctx context.Context,
+	cfg config.NotifyConfig,
+	orgRepo repository.OrganizationRepository,
+	userRepo repository.UserRepository,
+	prRepo repository.PullRequestRepository,
+	notifyRepo repository.NotificationRepository,
+	log *slog.Logger,
+) (outbox.Sink, error) {
+	templates, err := notify.LoadEmailTemplates(cfg.EmailTemplatesDir)
+	if err != nil {
+		return nil, err
+	}
+
+	location, err := time.LoadLocation(cfg.DigestTimezone)
+	if err != nil {
+		return nil, fmt.Errorf("load digest timezone: %w", err)
+	}
+
+	mailer := notify.NewSMTPMailer(notify.SMTPConfig{
+		Host:     cfg.SMTPHost,
+		Port:     cfg.SMTPPort,
+		Username: cfg.SMTPUsername,
+		Password: cfg.SMTPPassword,
+		From:     cfg.SMTPFrom,
+		Timeout:  cfg.SMTPTimeout,
+	})
+
+	digest := notify.NewDigest(orgRepo, userRepo, prRepo, notifyRepo, mailer, templates, notify.DigestConfig{
+		At:           cfg.DigestAt,
+		Location:     location,
+		Organization: cfg.EmailOrganization,
+	}, log)
+	go digest.Run(ctx)
+
+	log.Info("email notifications enabled", slog.String("smtp_host", cfg.SMTPHost))
+
+	return notify.NewEmailNotifier(userRepo, notifyRepo, mailer, templates, cfg.EmailOrganization, log), nil
+}
+
+// newTokenVerifier создаёт проверку JWT или возвращает nil, если JWKS не задан.
+func newTokenVerifier(cfg config.JWTConfig, log *slog.Logger) authn.TokenVerifier {
+	if cfg.JWKSURL == "" && cfg.JWKSFile == "" {
+		return nil
+	}
+
+	keys := auth.NewJWKS(auth.JWKSConfig{
+		URL:     cfg.JWKSURL,
+		File:    cfg.JWKSFile,
+		Refresh: cfg.JWKSRefresh,
+	}, nil)
+
+	log.Info("jwt authentication enabled", slog.String("issuer", cfg.Issuer))
+
+	return auth.NewJWTVerifier(keys, auth.JWTConfig{
+		Issuer:            cfg.Issuer,
+		Audience:          cfg.Audience,
+		UserClaim:         cfg.UserClaim,
+		RolesClaim:        cfg.RolesClaim,
+		OrganizationClaim: cfg.OrganizationClaim,
+	})
+}
+
+// newVCSSyncer создаёт клиента VCS-провайдера и очередь синхронизации ревьюверов.
+func newVCSSyncer(cfg config.VCSConfig, repo repository.VCSSyncRepository, log *slog.Logger) *vcs.Syncer {
+	client := vcs.NewGitHubClient(vcs.GitHubConfig{
+		APIURL: cfg.GitHubAPIURL,
+		Token:  cfg.GitHubToken,
+		Owner:  cfg.GitHubOwner,
+		Repo:   cfg.GitHubRepo,
+	}, nil)
+
+	syncCfg := vcs.DefaultSyncerConfig()
+	syncCfg.MaxAttempts = cfg.MaxAttempts
+	syncCfg.InitialBackoff = cfg.InitialBackoff
+
+	log.Info("vcs reviewer sync enabled", slog.String("provider", cfg.Provider))
+
+	return vcs.NewSyncer(client, repo, syncCfg, log)
+}
+
 // newDB создаёт и настраивает пул подключений к БД и проверяет соединение.
 func newDB(ctx context.Context, cfg config.Config, log *slog.Logger) (*sql.DB, error) {
 	db, err := sql.Open("pgx", cfg.DB.DSN)
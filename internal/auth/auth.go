@@ -0,0 +1,74 @@
+// Package auth передаёт через context личность вызывающего, установленную при аутентификации.
+package auth
+
+import (
+	"context"
+	"slices"
+
+	"github.com/dixitix/pr-reviewer-service/internal/domain"
+)
+
+// Identity описывает аутентифицированного вызывающего: API-ключ или пользователя с токеном
+// провайдера идентификации.
+type Identity struct {
+	// KeyID — API-ключ, которым выполнен запрос. Пусто для запросов с токеном.
+	KeyID domain.APIKeyID
+	// UserID — пользователь из токена. Пусто для запросов с API-ключом.
+	UserID domain.UserID
+	// Roles — роли пользователя из токена.
+	Roles []domain.Role
+	// Organization — организация ключа или пользователя. Пусто для корневого ключа
+	// и токенов без организации.
+	Organization domain.OrganizationID
+	// Scopes — права вызывающего.
+	Scopes []domain.APIScope
+	// Root — корневой ключ из конфигурации: все права в любой организации.
+	Root bool
+	// System — фоновая задача сервиса, а не внешний вызывающий.
+	System bool
+}
+
+// Actor возвращает вызывающего в виде, в котором он записывается в PR и события.
+func (i Identity) Actor() domain.Actor {
+	switch {
+	case i.System:
+		return domain.ActorSystem
+	case i.Root:
+		return domain.ActorRoot
+	case i.UserID != "":
+		return domain.Actor("user:" + string(i.UserID))
+	case i.KeyID != "":
+		return domain.Actor("api_key:" + string(i.KeyID))
+	default:
+		return ""
+	}
+}
+
+// HasScope возвращает true, если вызывающему выдано право scope.
+func (i Identity) HasScope(scope domain.APIScope) bool {
+	return i.Root || slices.Contains(i.Scopes, scope)
+}
+
+// HasRole возвращает true, если у вызывающего есть роль role.
+func (i Identity) HasRole(role domain.Role) bool {
+	return slices.Contains(i.Roles, role)
+}
+
+type identityKey struct{}
+
+// WithIdentity возвращает контекст с личностью вызывающего.
+func WithIdentity(ctx context.Context, id Identity) context.Context {
+	return context.WithValue(ctx, identityKey{}, id)
+}
+
+// Actor возвращает вызывающего из контекста или пустое значение, если запрос не аутентифицирован.
+func Actor(ctx context.Context) domain.Actor {
+	id, _ := FromContext(ctx)
+	return id.Actor()
+}
+
+// FromContext возвращает личность вызывающего. ok == false, если запрос не аутентифицирован.
+func FromContext(ctx context.Context) (id Identity, ok bool) {
+	id, ok = ctx.Value(identityKey{}).(Identity)
+	return id, ok
+}
@@ -0,0 +1,257 @@
+// Package auth передаёт через context личность вызывающего, установленную при аутентификации.
+package auth
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+)
+
+// ErrUnknownKey возвращается, если в JWKS нет ключа с нужным kid.
+var ErrUnknownKey = errors.New("unknown signing key")
+
+// minJWKSRefresh — минимальный интервал между загрузками JWKS по неизвестному kid,
+// чтобы поток токенов с чужими kid не превращался в поток запросов к провайдеру.
+const minJWKSRefresh = 30 * time.Second
+
+// jwk описывает открытый ключ в формате RFC 7517. Поддерживаются RSA и EC P-256.
+type jwk struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+// JWKSConfig описывает источник ключей: URL провайдера или локальный файл для работы без сети.
+type JWKSConfig struct {
+	URL  string
+	File string
+	// Refresh — как часто перечитывать ключи. Ноль — раз в 10 минут.
+	Refresh time.Duration
+}
+
+// JWKS хранит открытые ключи провайдера идентификации и перечитывает их по истечении Refresh
+// или при появлении токена с неизвестным kid (ротация ключей).
+type JWKS struct {
+	cfg        JWKSConfig
+	httpClient *http.Client
+
+	mu       sync.Mutex
+	keys     map[string]crypto.PublicKey
+	loadedAt time.Time
+	now      func() time.Time
+}
+
+// NewJWKS создаёт набор ключей. Если httpClient == nil, используется клиент с таймаутом 10 секунд.
+// Ключи загружаются при первом обращении.
+func NewJWKS(cfg JWKSConfig, httpClient *http.Client) *JWKS {
+	if cfg.Refresh <= 0 {
+		cfg.Refresh = 10 * time.Minute
+	}
+
+	if httpClient == nil {
+		httpClient = &http.Client{Timeout: 10 * time.Second}
+	}
+
+	return &JWKS{
+		cfg:        cfg,
+		httpClient: httpClient,
+		now:        time.Now,
+	}
+}
+
+// Key возвращает открытый ключ с идентификатором kid.
+func (s *JWKS) Key(ctx context.Context, kid string) (crypto.PublicKey, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	age := s.now().Sub(s.loadedAt)
+
+	key, ok := s.keys[kid]
+	if ok && age < s.cfg.Refresh {
+		return key, nil
+	}
+
+	if s.keys == nil || age >= s.cfg.Refresh || (!ok && age >= minJWKSRefresh) {
+		if err := s.load(ctx); err != nil {
+			// Провайдер недоступен — продолжаем работать на загруженных ранее ключах.
+			if s.keys == nil {
+				return nil, err
+			}
+		}
+
+		key, ok = s.keys[kid]
+	}
+
+	if !ok {
+		return nil, fmt.Errorf("%w %q", ErrUnknownKey, kid)
+	}
+
+	return key, nil
+}
+
+// load перечитывает ключи из источника.
+func (s *JWKS) load(ctx context.Context) error {
+	data, err := s.read(ctx)
+	if err != nil {
+		return err
+	}
+
+	keys, err := parseJWKS(data)
+	if err != nil {
+		return err
+	}
+
+	s.keys = keys
+	s.loadedAt = s.now()
+
+	return nil
+}
+
+// read возвращает документ JWKS из файла или по URL.
+func (s *JWKS) read(ctx context.Context) ([]byte, error) {
+	if s.cfg.File != "" {
+		data, err := os.ReadFile(s.cfg.File)
+		if err != nil {
+			return nil, fmt.Errorf("read jwks file: %w", err)
+		}
+
+		return data, nil
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.cfg.URL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("build jwks request: %w", err)
+	}
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("fetch jwks: %w", err)
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("fetch jwks: unexpected status %d", resp.StatusCode)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return nil, fmt.Errorf("read jwks response: %w", err)
+	}
+
+	return data, nil
+}
+
+// parseJWKS разбирает документ JWKS. Ключи неподдерживаемых типов и ключи шифрования пропускаются.
+func parseJWKS(data []byte) (map[string]crypto.PublicKey, error) {
+	var doc struct {
+		Keys []jwk `json:"keys"`
+	}
+
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("decode jwks: %w", err)
+	}
+
+	keys := make(map[string]crypto.PublicKey, len(doc.Keys))
+
+	for _, k := range doc.Keys {
+		if k.Use != "" && k.Use != "sig" {
+			continue
+		}
+
+		key, err := k.publicKey()
+		if err != nil {
+			return nil, fmt.Errorf("jwk %q: %w", k.Kid, err)
+		}
+
+		if key != nil {
+			keys[k.Kid] = key
+		}
+	}
+
+	return keys, nil
+}
+
+// publicKey возвращает открытый ключ или nil для неподдерживаемого типа ключа.
+func (k jwk) publicKey() (crypto.PublicKey, error) {
+	switch k.Kty {
+	case "RSA":
+		n, err := decodeBigInt(k.N)
+		if err != nil {
+			return nil, fmt.Errorf("decode n: %w", err)
+		}
+
+		e, err := decodeBigInt(k.E)
+		if err != nil {
+			return nil, fmt.Errorf("decode e: %w", err)
+		}
+
+		if !e.IsInt64() || e.Int64() < 3 || e.Int64() > 1<<31-1 {
+			return nil, errors.New("invalid rsa exponent")
+		}
+
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+	case "EC":
+		if k.Crv != "P-256" {
+			return nil, nil
+		}
+
+		x, err := base64.RawURLEncoding.DecodeString(k.X)
+		if err != nil {
+			return nil, fmt.Errorf("decode x: %w", err)
+		}
+
+		y, err := base64.RawURLEncoding.DecodeString(k.Y)
+		if err != nil {
+			return nil, fmt.Errorf("decode y: %w", err)
+		}
+
+		if len(x) != 32 || len(y) != 32 {
+			return nil, errors.New("invalid P-256 coordinates")
+		}
+
+		point := append(append([]byte{4}, x...), y...)
+
+		key, err := ecdsa.ParseUncompressedPublicKey(elliptic.P256(), point)
+		if err != nil {
+			return nil, fmt.Errorf("parse ec key: %w", err)
+		}
+
+		return key, nil
+	default:
+		return nil, nil
+	}
+}
+
+// decodeBigInt декодирует число в base64url без дополнения.
+func decodeBigInt(s string) (*big.Int, error) {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(b) == 0 {
+		return nil, errors.New("empty value")
+	}
+
+	return new(big.Int).SetBytes(b), nil
+}
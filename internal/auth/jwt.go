@@ -0,0 +1,321 @@
+// Package auth передаёт через context личность вызывающего, установленную при аутентификации.
+package auth
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/dixitix/pr-reviewer-service/internal/domain"
+)
+
+// ErrInvalidToken возвращается, если токен повреждён, подписан неизвестным ключом, просрочен
+// или выдан не тем издателем или не для этой аудитории.
+var ErrInvalidToken = errors.New("invalid token")
+
+// KeySource возвращает открытый ключ подписи по kid.
+type KeySource interface {
+	Key(ctx context.Context, kid string) (crypto.PublicKey, error)
+}
+
+// JWTConfig описывает проверку токенов и отображение их claims на личность вызывающего.
+type JWTConfig struct {
+	// Issuer — ожидаемый iss; пустая строка — не проверяется.
+	Issuer string
+	// Audience — значение, которое должно быть в aud; пустая строка — не проверяется.
+	Audience string
+	// UserClaim — claim с идентификатором пользователя. По умолчанию sub.
+	UserClaim string
+	// RolesClaim — claim со списком ролей, вложенные поля через точку (realm_access.roles).
+	// По умолчанию roles.
+	RolesClaim string
+	// OrganizationClaim — claim с организацией пользователя. По умолчанию org.
+	OrganizationClaim string
+	// Leeway — допустимое расхождение часов при проверке exp и nbf. По умолчанию минута.
+	Leeway time.Duration
+}
+
+// JWTVerifier проверяет токены RS256 и ES256, подписанные ключами из KeySource.
+type JWTVerifier struct {
+	keys KeySource
+	cfg  JWTConfig
+	now  func() time.Time
+}
+
+// NewJWTVerifier создаёт проверку токенов.
+func NewJWTVerifier(keys KeySource, cfg JWTConfig) *JWTVerifier {
+	if cfg.UserClaim == "" {
+		cfg.UserClaim = "sub"
+	}
+
+	if cfg.RolesClaim == "" {
+		cfg.RolesClaim = "roles"
+	}
+
+	if cfg.OrganizationClaim == "" {
+		cfg.OrganizationClaim = "org"
+	}
+
+	if cfg.Leeway == 0 {
+		cfg.Leeway = time.Minute
+	}
+
+	return &JWTVerifier{
+		keys: keys,
+		cfg:  cfg,
+		now:  time.Now,
+	}
+}
+
+// jwtHeader описывает заголовок JWS.
+type jwtHeader struct {
+	Alg string `json:"alg"`
+	Kid string `json:"kid"`
+}
+
+// LooksLikeJWT возвращает true, если token похож на компактный JWS, а не на API-ключ.
+func LooksLikeJWT(token string) bool {
+	return strings.Count(token, ".") == 2
+}
+
+// Verify проверяет подпись и сроки токена и возвращает личность вызывающего. Права (scopes)
+// берутся из claim scope (или scp), без него — из ролей (см. roleScopes). Ошибки самого токена
+// оборачивают ErrInvalidToken.
+func (v *JWTVerifier) Verify(ctx context.Context, token string) (Identity, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return Identity{}, fmt.Errorf("%w: malformed token", ErrInvalidToken)
+	}
+
+	var header jwtHeader
+	if err := decodeSegment(parts[0], &header); err != nil {
+		return Identity{}, fmt.Errorf("%w: header: %v", ErrInvalidToken, err)
+	}
+
+	sig, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return Identity{}, fmt.Errorf("%w: signature: %v", ErrInvalidToken, err)
+	}
+
+	if header.Alg != "RS256" && header.Alg != "ES256" {
+		return Identity{}, fmt.Errorf("%w: unsupported alg %q", ErrInvalidToken, header.Alg)
+	}
+
+	key, err := v.keys.Key(ctx, header.Kid)
+	if err != nil {
+		if errors.Is(err, ErrUnknownKey) {
+			return Identity{}, fmt.Errorf("%w: %v", ErrInvalidToken, err)
+		}
+
+		return Identity{}, fmt.Errorf("get signing key: %w", err)
+	}
+
+	if err := verifySignature(header.Alg, key, parts[0]+"."+parts[1], sig); err != nil {
+		return Identity{}, fmt.Errorf("%w: %v", ErrInvalidToken, err)
+	}
+
+	var claims map[string]any
+	if err := decodeSegment(parts[1], &claims); err != nil {
+		return Identity{}, fmt.Errorf("%w: claims: %v", ErrInvalidToken, err)
+	}
+
+	if err := v.validateClaims(claims); err != nil {
+		return Identity{}, fmt.Errorf("%w: %v", ErrInvalidToken, err)
+	}
+
+	user, _ := lookupClaim(claims, v.cfg.UserClaim).(string)
+	if user == "" {
+		return Identity{}, fmt.Errorf("%w: claim %s is required", ErrInvalidToken, v.cfg.UserClaim)
+	}
+
+	org, _ := lookupClaim(claims, v.cfg.OrganizationClaim).(string)
+
+	id := Identity{
+		UserID:       domain.UserID(user),
+		Organization: domain.OrganizationID(org),
+	}
+
+	for _, role := range claimStrings(lookupClaim(claims, v.cfg.RolesClaim)) {
+		id.Roles = append(id.Roles, domain.Role(role))
+	}
+
+	if raw, ok := claims["scope"]; ok {
+		id.Scopes = tokenScopes(raw)
+	} else if raw, ok := claims["scp"]; ok {
+		id.Scopes = tokenScopes(raw)
+	} else {
+		id.Scopes = roleScopes(id.Roles)
+	}
+
+	return id, nil
+}
+
+// validateClaims проверяет exp, nbf, iss и aud.
+func (v *JWTVerifier) validateClaims(claims map[string]any) error {
+	now := v.now()
+
+	exp, ok := numericDate(claims["exp"])
+	if !ok {
+		return errors.New("claim exp is required")
+	}
+
+	if now.After(exp.Add(v.cfg.Leeway)) {
+		return errors.New("token expired")
+	}
+
+	if nbf, ok := numericDate(claims["nbf"]); ok && now.Add(v.cfg.Leeway).Before(nbf) {
+		return errors.New("token not yet valid")
+	}
+
+	if v.cfg.Issuer != "" {
+		if iss, _ := claims["iss"].(string); iss != v.cfg.Issuer {
+			return fmt.Errorf("unexpected issuer %q", iss)
+		}
+	}
+
+	if v.cfg.Audience != "" && !slices.Contains(claimStrings(claims["aud"]), v.cfg.Audience) {
+		return errors.New("token is not issued for this audience")
+	}
+
+	return nil
+}
+
+// verifySignature проверяет подпись signingInput алгоритмом alg.
+func verifySignature(alg string, key crypto.PublicKey, signingInput string, sig []byte) error {
+	digest := sha256.Sum256([]byte(signingInput))
+
+	switch alg {
+	case "RS256":
+		pub, ok := key.(*rsa.PublicKey)
+		if !ok {
+			return errors.New("key type does not match alg RS256")
+		}
+
+		if err := rsa.VerifyPKCS1v15(pub, crypto.SHA256, digest[:], sig); err != nil {
+			return errors.New("bad signature")
+		}
+	case "ES256":
+		pub, ok := key.(*ecdsa.PublicKey)
+		if !ok {
+			return errors.New("key type does not match alg ES256")
+		}
+
+		// Подпись JWS для ES256 — r и s по 32 байта подряд, а не ASN.1.
+		if len(sig) != 64 {
+			return errors.New("bad signature")
+		}
+
+		r := new(big.Int).SetBytes(sig[:32])
+		s := new(big.Int).SetBytes(sig[32:])
+
+		if !ecdsa.Verify(pub, digest[:], r, s) {
+			return errors.New("bad signature")
+		}
+	}
+
+	return nil
+}
+
+// decodeSegment декодирует base64url-сегмент токена как JSON.
+func decodeSegment(segment string, out any) error {
+	data, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		return err
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+
+	return dec.Decode(out)
+}
+
+// lookupClaim возвращает claim по пути с точками в качестве разделителя.
+func lookupClaim(claims map[string]any, path string) any {
+	var cur any = claims
+
+	for _, name := range strings.Split(path, ".") {
+		obj, ok := cur.(map[string]any)
+		if !ok {
+			return nil
+		}
+
+		cur = obj[name]
+	}
+
+	return cur
+}
+
+// claimStrings возвращает строку или массив строк claim как срез.
+func claimStrings(v any) []string {
+	switch v := v.(type) {
+	case string:
+		return []string{v}
+	case []any:
+		out := make([]string, 0, len(v))
+		for _, item := range v {
+			if s, ok := item.(string); ok {
+				out = append(out, s)
+			}
+		}
+
+		return out
+	default:
+		return nil
+	}
+}
+
+// roleScopes возвращает права токена без claim scope: администратору — все, остальным — чтение
+// и работа с PR. Управление командами, пользователями, вебхуками и API-ключами без явного права
+// team:admin в токене доступно только администратору.
+func roleScopes(roles []domain.Role) []domain.APIScope {
+	if slices.Contains(roles, domain.RoleAdmin) {
+		return domain.AllAPIScopes()
+	}
+
+	return []domain.APIScope{domain.APIScopeRead, domain.APIScopePRWrite, domain.APIScopeStatsRead}
+}
+
+// tokenScopes возвращает известные права из claim scope: строки через пробел или массива.
+// Неизвестные значения (openid, profile и т. п.) пропускаются.
+func tokenScopes(v any) []domain.APIScope {
+	raw := claimStrings(v)
+	if len(raw) == 1 {
+		raw = strings.Fields(raw[0])
+	}
+
+	var scopes []domain.APIScope
+
+	for _, s := range raw {
+		if scope := domain.APIScope(s); scope.Valid() {
+			scopes = append(scopes, scope)
+		}
+	}
+
+	return scopes
+}
+
+// numericDate разбирает NumericDate (секунды Unix) из claim.
+func numericDate(v any) (time.Time, bool) {
+	n, ok := v.(json.Number)
+	if !ok {
+		return time.Time{}, false
+	}
+
+	f, err := n.Float64()
+	if err != nil {
+		return time.Time{}, false
+	}
+
+	return time.Unix(int64(f), 0), true
+}
@@ -0,0 +1,253 @@
+package auth
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/dixitix/pr-reviewer-service/internal/domain"
+)
+
+// testKeys — ключи провайдера идентификации для тестов.
+type testKeys struct {
+	rsa *rsa.PrivateKey
+	ec  *ecdsa.PrivateKey
+}
+
+func newTestKeys(t *testing.T) testKeys {
+	t.Helper()
+
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("generate rsa key: %v", err)
+	}
+
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("generate ec key: %v", err)
+	}
+
+	return testKeys{rsa: rsaKey, ec: ecKey}
+}
+
+// jwks возвращает документ JWKS с открытыми ключами.
+func (k testKeys) jwks(t *testing.T) []byte {
+	t.Helper()
+
+	b64 := base64.RawURLEncoding.EncodeToString
+
+	ecPoint, err := k.ec.PublicKey.Bytes()
+	if err != nil {
+		t.Fatalf("encode ec key: %v", err)
+	}
+
+	doc := map[string]any{"keys": []map[string]string{
+		{
+			"kty": "RSA", "kid": "rsa-1", "use": "sig", "alg": "RS256",
+			"n": b64(k.rsa.N.Bytes()), "e": b64(big.NewInt(int64(k.rsa.E)).Bytes()),
+		},
+		{
+			"kty": "EC", "kid": "ec-1", "use": "sig", "alg": "ES256", "crv": "P-256",
+			"x": b64(ecPoint[1:33]), "y": b64(ecPoint[33:]),
+		},
+	}}
+
+	data, err := json.Marshal(doc)
+	if err != nil {
+		t.Fatalf("marshal jwks: %v", err)
+	}
+
+	return data
+}
+
+// sign выпускает токен с claims, подписанный ключом kid алгоритмом alg.
+func (k testKeys) sign(t *testing.T, alg, kid string, claims map[string]any) string {
+	t.Helper()
+
+	header, _ := json.Marshal(map[string]string{"alg": alg, "kid": kid, "typ": "JWT"})
+	payload, _ := json.Marshal(claims)
+
+	input := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(payload)
+	digest := sha256.Sum256([]byte(input))
+
+	var sig []byte
+
+	switch alg {
+	case "RS256":
+		var err error
+		if sig, err = rsa.SignPKCS1v15(rand.Reader, k.rsa, crypto.SHA256, digest[:]); err != nil {
+			t.Fatalf("sign rs256: %v", err)
+		}
+	case "ES256":
+		r, s, err := ecdsa.Sign(rand.Reader, k.ec, digest[:])
+		if err != nil {
+			t.Fatalf("sign es256: %v", err)
+		}
+
+		sig = make([]byte, 64)
+		r.FillBytes(sig[:32])
+		s.FillBytes(sig[32:])
+	}
+
+	return input + "." + base64.RawURLEncoding.EncodeToString(sig)
+}
+
+// TestJWTVerifier_Verify проверяет подписи RS256/ES256, сроки, издателя, аудиторию и отображение claims.
+func TestJWTVerifier_Verify(t *testing.T) {
+	keys := newTestKeys(t)
+
+	file := filepath.Join(t.TempDir(), "jwks.json")
+	if err := os.WriteFile(file, keys.jwks(t), 0o600); err != nil {
+		t.Fatalf("write jwks: %v", err)
+	}
+
+	verifier := NewJWTVerifier(NewJWKS(JWKSConfig{File: file}, nil), JWTConfig{
+		Issuer:     "https://idp.example.com",
+		Audience:   "pr-reviewer",
+		RolesClaim: "realm_access.roles",
+	})
+
+	now := time.Now()
+
+	claims := func(mutate func(map[string]any)) map[string]any {
+		c := map[string]any{
+			"iss":          "https://idp.example.com",
+			"aud":          []string{"pr-reviewer", "other"},
+			"sub":          "u1",
+			"org":          "acme",
+			"exp":          now.Add(time.Hour).Unix(),
+			"realm_access": map[string]any{"roles": []string{"admin", "engineer"}},
+		}
+		if mutate != nil {
+			mutate(c)
+		}
+
+		return c
+	}
+
+	for _, alg := range []struct{ alg, kid string }{{"RS256", "rsa-1"}, {"ES256", "ec-1"}} {
+		id, err := verifier.Verify(context.Background(), keys.sign(t, alg.alg, alg.kid, claims(nil)))
+		if err != nil {
+			t.Fatalf("%s: Verify returned error: %v", alg.alg, err)
+		}
+
+		if id.UserID != "u1" || id.Organization != "acme" || !id.HasRole(domain.RoleAdmin) || len(id.Roles) != 2 {
+			t.Fatalf("%s: unexpected identity: %+v", alg.alg, id)
+		}
+
+		if !slices.Equal(id.Scopes, domain.AllAPIScopes()) {
+			t.Fatalf("%s: expected all scopes for admin without scope claim, got %v", alg.alg, id.Scopes)
+		}
+	}
+
+	id, err := verifier.Verify(context.Background(), keys.sign(t, "RS256", "rsa-1", claims(func(c map[string]any) {
+		c["realm_access"] = map[string]any{"roles": []string{"engineer"}}
+	})))
+	if err != nil {
+		t.Fatalf("Verify returned error: %v", err)
+	}
+
+	if id.HasScope(domain.APIScopeTeamAdmin) {
+		t.Fatalf("expected no team:admin for non-admin without scope claim, got %v", id.Scopes)
+	}
+
+	if !id.HasScope(domain.APIScopeRead) || !id.HasScope(domain.APIScopePRWrite) {
+		t.Fatalf("expected read and pr:write for non-admin without scope claim, got %v", id.Scopes)
+	}
+
+	id, err = verifier.Verify(context.Background(), keys.sign(t, "RS256", "rsa-1", claims(func(c map[string]any) {
+		c["scope"] = "openid read stats:read"
+	})))
+	if err != nil {
+		t.Fatalf("Verify returned error: %v", err)
+	}
+
+	if !slices.Equal(id.Scopes, []domain.APIScope{domain.APIScopeRead, domain.APIScopeStatsRead}) {
+		t.Fatalf("unexpected scopes: %v", id.Scopes)
+	}
+
+	rejected := map[string]string{
+		"expired":        keys.sign(t, "RS256", "rsa-1", claims(func(c map[string]any) { c["exp"] = now.Add(-time.Hour).Unix() })),
+		"no exp":         keys.sign(t, "RS256", "rsa-1", claims(func(c map[string]any) { delete(c, "exp") })),
+		"not yet valid":  keys.sign(t, "RS256", "rsa-1", claims(func(c map[string]any) { c["nbf"] = now.Add(time.Hour).Unix() })),
+		"wrong issuer":   keys.sign(t, "RS256", "rsa-1", claims(func(c map[string]any) { c["iss"] = "https://evil.example.com" })),
+		"wrong audience": keys.sign(t, "RS256", "rsa-1", claims(func(c map[string]any) { c["aud"] = "other" })),
+		"no subject":     keys.sign(t, "RS256", "rsa-1", claims(func(c map[string]any) { delete(c, "sub") })),
+		"unknown kid":    keys.sign(t, "RS256", "rsa-2", claims(nil)),
+		"alg mismatch":   keys.sign(t, "ES256", "rsa-1", claims(nil)),
+		"alg none":       keys.sign(t, "none", "rsa-1", claims(nil)),
+		"malformed":      "not-a-token",
+	}
+
+	tampered := keys.sign(t, "RS256", "rsa-1", claims(nil))
+	other := keys.sign(t, "RS256", "rsa-1", claims(func(c map[string]any) { c["sub"] = "u2" }))
+	rejected["tampered"] = tampered[:len(tampered)-10] + other[len(other)-10:]
+
+	for name, token := range rejected {
+		if _, err := verifier.Verify(context.Background(), token); !errors.Is(err, ErrInvalidToken) {
+			t.Fatalf("%s: expected ErrInvalidToken, got %v", name, err)
+		}
+	}
+}
+
+// TestJWKS_URLRotation проверяет загрузку ключей по URL и повторную загрузку при неизвестном kid.
+func TestJWKS_URLRotation(t *testing.T) {
+	oldKeys, newKeys := newTestKeys(t), newTestKeys(t)
+
+	current := oldKeys.jwks(t)
+	fetches := 0
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fetches++
+		_, _ = w.Write(current)
+	}))
+	defer srv.Close()
+
+	clock := time.Now()
+
+	jwks := NewJWKS(JWKSConfig{URL: srv.URL}, srv.Client())
+	jwks.now = func() time.Time { return clock }
+
+	verifier := NewJWTVerifier(jwks, JWTConfig{})
+	claims := map[string]any{"sub": "u1", "exp": clock.Add(time.Hour).Unix()}
+
+	if _, err := verifier.Verify(context.Background(), oldKeys.sign(t, "RS256", "rsa-1", claims)); err != nil {
+		t.Fatalf("Verify returned error: %v", err)
+	}
+
+	// Провайдер сменил ключ с тем же kid: до повторной загрузки новый токен не проходит.
+	current = newKeys.jwks(t)
+
+	if _, err := verifier.Verify(context.Background(), newKeys.sign(t, "RS256", "rsa-1", claims)); !errors.Is(err, ErrInvalidToken) {
+		t.Fatalf("expected ErrInvalidToken before refresh, got %v", err)
+	}
+
+	clock = clock.Add(11 * time.Minute)
+
+	if _, err := verifier.Verify(context.Background(), newKeys.sign(t, "RS256", "rsa-1", claims)); err != nil {
+		t.Fatalf("Verify after refresh returned error: %v", err)
+	}
+
+	if _, err := verifier.Verify(context.Background(), newKeys.sign(t, "RS256", "rsa-9", claims)); !errors.Is(err, ErrInvalidToken) {
+		t.Fatalf("expected ErrInvalidToken for unknown kid, got %v", err)
+	}
+
+	if fetches != 2 {
+		t.Fatalf("expected 2 jwks fetches, got %d", fetches)
+	}
+}
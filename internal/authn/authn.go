@@ -0,0 +1,128 @@
+// Package authn аутентифицирует вызывающих по корневому ключу, JWT и API-ключам организаций
+// и проверяет их права. Не зависит от транспорта: используется HTTP- и gRPC-серверами.
+package authn
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/dixitix/pr-reviewer-service/internal/auth"
+	"github.com/dixitix/pr-reviewer-service/internal/domain"
+	"github.com/dixitix/pr-reviewer-service/internal/service"
+)
+
+// ErrInsufficientScope возвращается Authorize, если у вызывающего нет нужного права.
+var ErrInsufficientScope = errors.New("insufficient scope")
+
+// TokenVerifier проверяет bearer-токены провайдера идентификации.
+type TokenVerifier interface {
+	Verify(ctx context.Context, token string) (auth.Identity, error)
+}
+
+// Config описывает аутентификацию запросов.
+type Config struct {
+	// Enabled включает проверку API-ключей. Если выключено, все запросы выполняются без ограничений.
+	Enabled bool
+	// RootKey — корневой ключ из конфигурации: все права в любой организации, создание организаций.
+	// Пустая строка — корневого ключа нет.
+	RootKey string
+	// Tokens проверяет JWT пользователей; nil — принимаются только API-ключи.
+	Tokens TokenVerifier
+}
+
+// Authenticator проверяет секреты вызывающих и их права.
+type Authenticator struct {
+	svc    service.APIKeyService
+	cfg    Config
+	logger *slog.Logger
+}
+
+// New создаёт проверку API-ключей и токенов.
+func New(svc service.APIKeyService, cfg Config, logger *slog.Logger) *Authenticator {
+	return &Authenticator{
+		svc:    svc,
+		cfg:    cfg,
+		logger: logger,
+	}
+}
+
+// Enabled сообщает, включена ли проверка API-ключей.
+func (a *Authenticator) Enabled() bool {
+	return a.cfg.Enabled
+}
+
+// Authenticate возвращает личность вызывающего по секрету: корневому ключу, JWT (если задан Tokens)
+// или API-ключу организации. Для неверного ключа возвращается ошибка service.ErrUnauthenticated,
+// для неверного токена — ещё и auth.ErrInvalidToken.
+func (a *Authenticator) Authenticate(ctx context.Context, secret string) (auth.Identity, error) {
+	if a.cfg.RootKey != "" && subtle.ConstantTimeCompare([]byte(secret), []byte(a.cfg.RootKey)) == 1 {
+		return auth.Identity{
+			Scopes: domain.AllAPIScopes(),
+			Root:   true,
+		}, nil
+	}
+
+	if a.cfg.Tokens != nil && auth.LooksLikeJWT(secret) {
+		id, err := a.cfg.Tokens.Verify(ctx, secret)
+		if err != nil {
+			if errors.Is(err, auth.ErrInvalidToken) {
+				if a.logger != nil {
+					a.logger.InfoContext(ctx, "auth: rejected token", slog.Any("error", err))
+				}
+
+				return auth.Identity{}, fmt.Errorf("%w: %w", service.ErrUnauthenticated, err)
+			}
+
+			if a.logger != nil {
+				a.logger.ErrorContext(ctx, "auth: Verify error", slog.Any("error", err))
+			}
+
+			return auth.Identity{}, fmt.Errorf("verify token: %w", err)
+		}
+
+		return id, nil
+	}
+
+	key, err := a.svc.AuthenticateAPIKey(ctx, secret)
+	if err != nil {
+		if !errors.Is(err, service.ErrUnauthenticated) && a.logger != nil {
+			a.logger.ErrorContext(ctx, "auth: AuthenticateAPIKey error", slog.Any("error", err))
+		}
+
+		return auth.Identity{}, err
+	}
+
+	return auth.Identity{
+		KeyID:        key.ID,
+		Organization: key.OrganizationID,
+		Scopes:       key.Scopes,
+	}, nil
+}
+
+// Authorize проверяет, что у вызывающего есть право scope; иначе возвращает ErrInsufficientScope.
+func (a *Authenticator) Authorize(id auth.Identity, scope domain.APIScope) error {
+	if !id.HasScope(scope) {
+		return fmt.Errorf("%w: api key lacks scope %s", ErrInsufficientScope, scope)
+	}
+
+	return nil
+}
+
+// Secret выбирает секрет вызывающего из значений Authorization и X-API-Key (HTTP-заголовков
+// или gRPC-метаданных). Authorization со схемой, отличной от Bearer, даёт пустую строку.
+func Secret(authorization, apiKey string) string {
+	if authorization != "" {
+		scheme, token, ok := strings.Cut(authorization, " ")
+		if ok && strings.EqualFold(scheme, "Bearer") {
+			return strings.TrimSpace(token)
+		}
+
+		return ""
+	}
+
+	return strings.TrimSpace(apiKey)
+}
@@ -12,6 +12,51 @@ import (
 // HTTPConfig описывает настройки HTTP-сервера.
 type HTTPConfig struct {
 	Addr string
+	// DefaultOrganization — организация запросов без заголовка X-Organization-ID.
+	DefaultOrganization string
+	// AuthEnabled включает аутентификацию запросов по API-ключам.
+	AuthEnabled bool
+	// RootAPIKey — корневой ключ со всеми правами в любой организации; нужен для создания
+	// организаций и выпуска первых ключей. Пустая строка — корневого ключа нет.
+	RootAPIKey string
+	// IdempotencyTTL — сколько хранятся ответы на запросы с заголовком Idempotency-Key.
+	IdempotencyTTL time.Duration
+	// ValidateRequests включает проверку запросов по спецификации API (api/openapi.yml).
+	ValidateRequests bool
+	// ValidateResponses включает проверку ответов по спецификации: расхождения логируются.
+	ValidateResponses bool
+	// EventsPollInterval — как часто поток /events/stream проверяет новые события.
+	EventsPollInterval time.Duration
+	// EventsHeartbeatInterval — как часто в простаивающий поток /events/stream пишется комментарий.
+	EventsHeartbeatInterval time.Duration
+}
+
+// GRPCConfig описывает настройки gRPC-сервера.
+type GRPCConfig struct {
+	// Addr — адрес gRPC-сервера; пустая строка — gRPC выключен.
+	Addr string
+}
+
+// MetricsConfig описывает отдельный сервер метрик Prometheus.
+type MetricsConfig struct {
+	// Addr — адрес сервера с /metrics; пустая строка — метрики выключены. Сервер не проверяет
+	// API-ключи и отдаёт данные всех организаций, поэтому адрес не должен быть доступен снаружи.
+	Addr string
+}
+
+// JWTConfig описывает аутентификацию пользователей по JWT провайдера идентификации.
+type JWTConfig struct {
+	// JWKSURL — адрес JWKS провайдера; JWKSFile — локальный файл JWKS для работы без сети.
+	// Если оба пусты, токены не принимаются.
+	JWKSURL     string
+	JWKSFile    string
+	JWKSRefresh time.Duration
+	Issuer      string
+	Audience    string
+	// UserClaim, RolesClaim и OrganizationClaim — claims с пользователем, ролями и организацией.
+	UserClaim         string
+	RolesClaim        string
+	OrganizationClaim string
 }
 
 // DBConfig описывает настройки подключения к базе данных.
@@ -22,17 +67,128 @@ type DBConfig struct {
 	ConnMaxLifetime time.Duration
 }
 
+// VCSConfig описывает синхронизацию ревьюверов с VCS-провайдером.
+type VCSConfig struct {
+	// Provider — имя провайдера: "github" или пустая строка, если синхронизация выключена.
+	Provider       string
+	GitHubAPIURL   string
+	GitHubToken    string
+	GitHubOwner    string
+	GitHubRepo     string
+	MaxAttempts    int
+	InitialBackoff time.Duration
+}
+
+// WebhookConfig описывает доставку исходящих вебхуков.
+type WebhookConfig struct {
+	Workers        int
+	MaxAttempts    int
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+	Timeout        time.Duration
+}
+
+// OutboxConfig описывает ретрансляцию событий из outbox и дополнительные получатели.
+type OutboxConfig struct {
+	PollInterval time.Duration
+	BatchSize    int
+	Retention    time.Duration
+	// LogSink включает запись событий в лог.
+	LogSink bool
+	// NATSURL — адрес NATS-совместимого брокера; пустая строка отключает публикацию в брокер.
+	NATSURL           string
+	NATSSubjectPrefix string
+}
+
+// NotifyConfig описывает уведомления ревьюверов.
+type NotifyConfig struct {
+	// ChatEnabled включает уведомления в Slack-совместимые чаты.
+	ChatEnabled bool
+	// ChatDefaultWebhookURL — входящий вебхук для команд без собственного канала;
+	// пустая строка означает, что такие команды уведомления не получают.
+	ChatDefaultWebhookURL string
+	ChatTimeout           time.Duration
+
+	// SMTPHost включает почтовые уведомления; пустая строка — письма не отправляются.
+	SMTPHost     string
+	SMTPPort     int
+	SMTPUsername string
+	SMTPPassword string
+	SMTPFrom     string
+	SMTPTimeout  time.Duration
+	// EmailTemplatesDir — каталог с шаблонами писем по организациям; пустая строка — встроенные шаблоны.
+	EmailTemplatesDir string
+	// EmailOrganization — организация, шаблоны которой используются для писем.
+	EmailOrganization string
+	// DigestAt — время отправки ежедневной сводки от начала суток в часовом поясе DigestTimezone.
+	DigestAt       time.Duration
+	DigestTimezone string
+}
+
+// ReviewSLAConfig описывает контроль сроков ревью и SLA по умолчанию для команд без собственного.
+type ReviewSLAConfig struct {
+	// Enabled включает фоновую проверку просроченных ревью.
+	Enabled  bool
+	Interval time.Duration
+	// ReminderAfter — через сколько после назначения (и затем с тем же интервалом) напоминать ревьюверу.
+	// Ноль отключает напоминания.
+	ReminderAfter time.Duration
+	// EscalateAfter — через сколько после назначения выполнять EscalationAction. Ноль отключает эскалацию.
+	EscalateAfter    time.Duration
+	EscalationAction string
+}
+
+// AssignmentConfig описывает подбор ревьюверов.
+type AssignmentConfig struct {
+	// ReviewerPool — "primary", чтобы подбирать ревьюверов только из основной команды,
+	// или "all", чтобы из всех команд пользователя.
+	ReviewerPool string
+}
+
 // Config агрегирует все настройки приложения.
 type Config struct {
-	HTTP HTTPConfig
-	DB   DBConfig
+	HTTP    HTTPConfig
+	GRPC    GRPCConfig
+	Metrics MetricsConfig
+	JWT     JWTConfig
+	DB      DBConfig
+	VCS     VCSConfig
+	Webhook WebhookConfig
+	Outbox  OutboxConfig
+	Notify  NotifyConfig
+	SLA     ReviewSLAConfig
+	Assign  AssignmentConfig
 }
 
 // Load загружает конфигурацию из переменных окружения и проверяет обязательные поля.
 func Load() (Config, error) {
 	cfg := Config{
 		HTTP: HTTPConfig{
-			Addr: getEnv("HTTP_ADDR", ":8080"),
+			Addr:                    getEnv("HTTP_ADDR", ":8080"),
+			DefaultOrganization:     getEnv("DEFAULT_ORGANIZATION", "default"),
+			AuthEnabled:             mustParseBool("AUTH_ENABLED", true),
+			RootAPIKey:              os.Getenv("ROOT_API_KEY"),
+			IdempotencyTTL:          mustParseDuration("IDEMPOTENCY_TTL", 24*time.Hour),
+			ValidateRequests:        mustParseBool("OPENAPI_VALIDATE_REQUESTS", true),
+			ValidateResponses:       mustParseBool("OPENAPI_VALIDATE_RESPONSES", false),
+			EventsPollInterval:      mustParseDuration("EVENTS_POLL_INTERVAL", time.Second),
+			EventsHeartbeatInterval: mustParseDuration("EVENTS_HEARTBEAT_INTERVAL", 15*time.Second),
+		},
+		GRPC: GRPCConfig{
+			Addr: getEnv("GRPC_ADDR", ":9090"),
+		},
+		Metrics: MetricsConfig{
+			Addr: getEnv("METRICS_ADDR", ":9100"),
+		},
+		JWT: JWTConfig{
+			JWKSURL:           os.Getenv("JWT_JWKS_URL"),
+			JWKSFile:          os.Getenv("JWT_JWKS_FILE"),
+			JWKSRefresh:       mustParseDuration("JWT_JWKS_REFRESH", 10*time.Minute),
+			Issuer:            os.Getenv("JWT_ISSUER"),
+			Audience:          os.Getenv("JWT_AUDIENCE"),
+			UserClaim:         getEnv("JWT_USER_CLAIM", "sub"),
+			RolesClaim:        getEnv("JWT_ROLES_CLAIM", "roles"),
+			OrganizationClaim: getEnv("JWT_ORGANIZATION_CLAIM", "org"),
 		},
 		DB: DBConfig{
 			DSN:             os.Getenv("DATABASE_DSN"),
@@ -40,12 +196,100 @@ func Load() (Config, error) {
 			MaxIdleConns:    mustParseInt("DB_MAX_IDLE_CONNS", 5),
 			ConnMaxLifetime: mustParseDuration("DB_CONN_MAX_LIFETIME", 5*time.Minute),
 		},
+		VCS: VCSConfig{
+			Provider:       os.Getenv("VCS_PROVIDER"),
+			GitHubAPIURL:   getEnv("GITHUB_API_URL", "https://api.github.com"),
+			GitHubToken:    os.Getenv("GITHUB_TOKEN"),
+			GitHubOwner:    os.Getenv("GITHUB_OWNER"),
+			GitHubRepo:     os.Getenv("GITHUB_REPO"),
+			MaxAttempts:    mustParseInt("VCS_SYNC_MAX_ATTEMPTS", 5),
+			InitialBackoff: mustParseDuration("VCS_SYNC_INITIAL_BACKOFF", 500*time.Millisecond),
+		},
+		Webhook: WebhookConfig{
+			Workers:        mustParseInt("WEBHOOK_WORKERS", 4),
+			MaxAttempts:    mustParseInt("WEBHOOK_MAX_ATTEMPTS", 6),
+			InitialBackoff: mustParseDuration("WEBHOOK_INITIAL_BACKOFF", time.Second),
+			MaxBackoff:     mustParseDuration("WEBHOOK_MAX_BACKOFF", 5*time.Minute),
+			Timeout:        mustParseDuration("WEBHOOK_TIMEOUT", 5*time.Second),
+		},
+		Outbox: OutboxConfig{
+			PollInterval:      mustParseDuration("OUTBOX_POLL_INTERVAL", 500*time.Millisecond),
+			BatchSize:         mustParseInt("OUTBOX_BATCH_SIZE", 100),
+			Retention:         mustParseDuration("OUTBOX_RETENTION", 7*24*time.Hour),
+			LogSink:           mustParseBool("OUTBOX_LOG_SINK", false),
+			NATSURL:           os.Getenv("NATS_URL"),
+			NATSSubjectPrefix: getEnv("NATS_SUBJECT_PREFIX", "pr-reviewer.events"),
+		},
+		Notify: NotifyConfig{
+			ChatEnabled:           mustParseBool("CHAT_NOTIFICATIONS_ENABLED", true),
+			ChatDefaultWebhookURL: os.Getenv("CHAT_DEFAULT_WEBHOOK_URL"),
+			ChatTimeout:           mustParseDuration("CHAT_TIMEOUT", 5*time.Second),
+			SMTPHost:              os.Getenv("SMTP_HOST"),
+			SMTPPort:              mustParseInt("SMTP_PORT", 587),
+			SMTPUsername:          os.Getenv("SMTP_USERNAME"),
+			SMTPPassword:          os.Getenv("SMTP_PASSWORD"),
+			SMTPFrom:              os.Getenv("SMTP_FROM"),
+			SMTPTimeout:           mustParseDuration("SMTP_TIMEOUT", 10*time.Second),
+			EmailTemplatesDir:     os.Getenv("EMAIL_TEMPLATES_DIR"),
+			EmailOrganization:     getEnv("EMAIL_ORGANIZATION", "default"),
+			DigestAt:              mustParseClock("EMAIL_DIGEST_AT", 9*time.Hour),
+			DigestTimezone:        getEnv("EMAIL_DIGEST_TIMEZONE", "UTC"),
+		},
+		SLA: ReviewSLAConfig{
+			Enabled:          mustParseBool("REVIEW_SLA_ENABLED", true),
+			Interval:         mustParseDuration("REVIEW_SLA_INTERVAL", 5*time.Minute),
+			ReminderAfter:    mustParseDuration("REVIEW_SLA_REMINDER_AFTER", 24*time.Hour),
+			EscalateAfter:    mustParseDuration("REVIEW_SLA_ESCALATE_AFTER", 72*time.Hour),
+			EscalationAction: getEnv("REVIEW_SLA_ACTION", "escalate"),
+		},
+		Assign: AssignmentConfig{
+			ReviewerPool: getEnv("REVIEWER_POOL", "primary"),
+		},
 	}
 
 	if cfg.DB.DSN == "" {
 		return Config{}, fmt.Errorf("DATABASE_DSN is required")
 	}
 
+	if cfg.HTTP.RootAPIKey != "" && len(cfg.HTTP.RootAPIKey) < 32 {
+		return Config{}, fmt.Errorf("ROOT_API_KEY must be at least 32 characters")
+	}
+
+	if cfg.JWT.JWKSURL != "" && cfg.JWT.JWKSFile != "" {
+		return Config{}, fmt.Errorf("only one of JWT_JWKS_URL and JWT_JWKS_FILE may be set")
+	}
+
+	switch cfg.VCS.Provider {
+	case "", "none":
+		cfg.VCS.Provider = ""
+	case "github":
+		if cfg.VCS.GitHubToken == "" {
+			return Config{}, fmt.Errorf("GITHUB_TOKEN is required for VCS_PROVIDER=github")
+		}
+	default:
+		return Config{}, fmt.Errorf("unsupported VCS_PROVIDER %q", cfg.VCS.Provider)
+	}
+
+	if cfg.Notify.SMTPHost != "" && cfg.Notify.SMTPFrom == "" {
+		return Config{}, fmt.Errorf("SMTP_FROM is required when SMTP_HOST is set")
+	}
+
+	if _, err := time.LoadLocation(cfg.Notify.DigestTimezone); err != nil {
+		return Config{}, fmt.Errorf("invalid EMAIL_DIGEST_TIMEZONE %q: %w", cfg.Notify.DigestTimezone, err)
+	}
+
+	switch cfg.SLA.EscalationAction {
+	case "none", "reassign", "escalate":
+	default:
+		return Config{}, fmt.Errorf("unsupported REVIEW_SLA_ACTION %q", cfg.SLA.EscalationAction)
+	}
+
+	switch cfg.Assign.ReviewerPool {
+	case "primary", "all":
+	default:
+		return Config{}, fmt.Errorf("unsupported REVIEWER_POOL %q", cfg.Assign.ReviewerPool)
+	}
+
 	return cfg, nil
 }
 
@@ -87,3 +331,34 @@ func mustParseDuration(name string, def time.Duration) time.Duration {
 
 	return d
 }
+
+// mustParseBool парсит bool из переменной окружения или возвращает дефолт.
+func mustParseBool(name string, def bool) bool {
+	raw := os.Getenv(name)
+	if raw == "" {
+		return def
+	}
+
+	v, err := strconv.ParseBool(raw)
+	if err != nil {
+		return def
+	}
+
+	return v
+}
+
+// mustParseClock парсит время суток "HH:MM" из переменной окружения как смещение от полуночи
+// или возвращает дефолт.
+func mustParseClock(name string, def time.Duration) time.Duration {
+	raw := os.Getenv(name)
+	if raw == "" {
+		return def
+	}
+
+	t, err := time.Parse("15:04", raw)
+	if err != nil {
+		return def
+	}
+
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute
+}
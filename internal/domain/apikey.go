@@ -0,0 +1,53 @@
+// Package domain содержит основные сущности сервиса назначения ревьюеров для Pull Request'ов.
+package domain
+
+import (
+	"slices"
+	"time"
+)
+
+// APIKeyID — тип идентификатора API-ключа.
+type APIKeyID string
+
+// APIScope — право, выдаваемое API-ключу.
+type APIScope string
+
+// Права API-ключей.
+const (
+	// APIScopeRead — чтение команд, пользователей, PR, подписок и настроек уведомлений.
+	APIScopeRead APIScope = "read"
+	// APIScopePRWrite — создание, merge PR и переназначение ревьюверов.
+	APIScopePRWrite APIScope = "pr:write"
+	// APIScopeTeamAdmin — управление командами, пользователями, вебхуками, уведомлениями и API-ключами.
+	APIScopeTeamAdmin APIScope = "team:admin"
+	// APIScopeStatsRead — чтение статистики назначений.
+	APIScopeStatsRead APIScope = "stats:read"
+)
+
+// AllAPIScopes возвращает все права API-ключей.
+func AllAPIScopes() []APIScope {
+	return []APIScope{APIScopeRead, APIScopePRWrite, APIScopeTeamAdmin, APIScopeStatsRead}
+}
+
+// Valid возвращает true для известных прав.
+func (s APIScope) Valid() bool {
+	return slices.Contains(AllAPIScopes(), s)
+}
+
+// APIKey описывает API-ключ организации. Сам ключ не хранится, известен только его префикс для опознания.
+type APIKey struct {
+	ID             APIKeyID
+	OrganizationID OrganizationID
+	Name           string
+	// Prefix — начало ключа, по которому его можно узнать в списке.
+	Prefix     string
+	Scopes     []APIScope
+	CreatedAt  time.Time
+	LastUsedAt *time.Time
+	RevokedAt  *time.Time
+}
+
+// HasScope возвращает true, если ключу выдано право scope.
+func (k APIKey) HasScope(scope APIScope) bool {
+	return slices.Contains(k.Scopes, scope)
+}
@@ -0,0 +1,39 @@
+// Package domain содержит основные сущности сервиса назначения ревьюеров для Pull Request'ов.
+package domain
+
+import "time"
+
+// AuditAction — тип изменяющей операции в журнале изменений.
+type AuditAction string
+
+// Операции журнала изменений. Изменения PR записываются в историю PR (Event.Actor).
+const (
+	AuditOrganizationCreated    AuditAction = "organization.created"
+	AuditTeamCreated            AuditAction = "team.created"
+	AuditTeamLeadsSet           AuditAction = "team.leads_set"
+	AuditTeamParentSet          AuditAction = "team.parent_set"
+	AuditTeamMemberAdded        AuditAction = "team.member_added"
+	AuditTeamMemberRemoved      AuditAction = "team.member_removed"
+	AuditReviewPolicySet        AuditAction = "team.review_policy_set"
+	AuditTeamChatChannelSet     AuditAction = "team.chat_channel_set"
+	AuditTeamChatChannelDeleted AuditAction = "team.chat_channel_deleted"
+	AuditUserActiveSet          AuditAction = "user.active_set"
+	AuditNotificationPrefsSet   AuditAction = "user.notification_preferences_set"
+	AuditAPIKeyCreated          AuditAction = "api_key.created"
+	AuditAPIKeyRevoked          AuditAction = "api_key.revoked"
+	AuditWebhookCreated         AuditAction = "webhook.created"
+	AuditWebhookDeleted         AuditAction = "webhook.deleted"
+)
+
+// AuditEntry — запись журнала изменений: кто, когда и над чем выполнил изменяющую операцию.
+type AuditEntry struct {
+	ID     int64
+	Action AuditAction
+	// Target — объект операции: имя команды, ID пользователя, ключа или подписки.
+	Target string
+	// Actor — вызывающий; пусто, если запрос выполнен без аутентификации.
+	Actor Actor
+	// Details — параметры операции, например новые лиды или значение активности.
+	Details    map[string]any
+	OccurredAt time.Time
+}
@@ -23,6 +23,7 @@ const (
 )
 
 // User представляет пользователя, который может создавать PR и выступать ревьювером.
+// TeamName — основная команда пользователя; кроме неё он может состоять в других командах (гильдиях).
 type User struct {
 	ID       UserID
 	Username string
@@ -31,11 +32,60 @@ type User struct {
 }
 
 // Team представляет команду разработчиков.
+// Leads — ответственные за команду: им адресуются эскалации и принудительные merge,
+// и на них переназначается ревью, если в команде нет других кандидатов.
+// Parent — родительская команда (отдел для команды, команда для сквада); пустое значение — корень.
+// FallbackToParent разрешает искать ревьюверов вверх по иерархии, если в команде и среди её лидов кандидатов нет.
 type Team struct {
-	Name TeamName
+	Name             TeamName
+	Parent           TeamName
+	FallbackToParent bool
+	Leads            []UserID
+	// Version увеличивается при изменении иерархии, лидов, состава и активности участников команды.
+	Version int64
 }
 
+// TeamMembership описывает членство пользователя в команде.
+// У каждого пользователя ровно одна основная команда (IsPrimary), она же User.TeamName.
+type TeamMembership struct {
+	TeamName  TeamName
+	UserID    UserID
+	IsPrimary bool
+}
+
+// ReviewerPool определяет, из каких команд пользователя подбираются ревьюверы.
+type ReviewerPool string
+
+const (
+	// ReviewerPoolPrimary — только основная команда (автора PR или заменяемого ревьювера).
+	ReviewerPoolPrimary ReviewerPool = "primary"
+	// ReviewerPoolAllTeams — все команды, в которых состоит пользователь.
+	ReviewerPoolAllTeams ReviewerPool = "all"
+)
+
+// TeamAssignmentStat описывает число назначений ревьюверов команды.
+// Assignments — назначения участников самой команды, TotalAssignments — вместе со всеми дочерними командами.
+type TeamAssignmentStat struct {
+	TeamName         TeamName
+	Parent           TeamName
+	Assignments      int
+	TotalAssignments int
+}
+
+// Actor — кто выполнил действие: "user:<id>" для пользователя с токеном, "api_key:<id>" для API-ключа,
+// ActorRoot для корневого ключа и ActorSystem для фоновых задач. Пустое значение — неизвестно
+// (аутентификация выключена или действие выполнено до появления атрибуции).
+type Actor string
+
+const (
+	// ActorRoot — корневой API-ключ из конфигурации.
+	ActorRoot Actor = "root"
+	// ActorSystem — фоновые задачи сервиса (SLA).
+	ActorSystem Actor = "system"
+)
+
 // PullRequest представляет Pull Request и список назначенных ревьюверов.
+// CreatedBy, MergedBy и ReassignedBy — кто создал PR, выполнил merge и последним переназначил ревьювера.
 type PullRequest struct {
 	ID                PullRequestID
 	Name              string
@@ -44,4 +94,9 @@ type PullRequest struct {
 	AssignedReviewers []UserID
 	CreatedAt         *time.Time
 	MergedAt          *time.Time
+	CreatedBy         Actor
+	MergedBy          Actor
+	ReassignedBy      Actor
+	// Version увеличивается при каждом изменении PR, начиная с 1.
+	Version int64
 }
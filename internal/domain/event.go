@@ -0,0 +1,63 @@
+// Package domain содержит основные сущности сервиса назначения ревьюеров для Pull Request'ов.
+package domain
+
+import "time"
+
+// EventType описывает тип доменного события.
+type EventType string
+
+const (
+	// EventReviewersAssigned — ревьюверы назначены при создании PR.
+	EventReviewersAssigned EventType = "reviewers.assigned"
+	// EventReviewerReassigned — ревьювер PR заменён на другого.
+	EventReviewerReassigned EventType = "reviewer.reassigned"
+	// EventPullRequestMerged — PR переведён в статус MERGED.
+	EventPullRequestMerged EventType = "pull_request.merged"
+	// EventReviewReminder — ревьювер не закончил ревью в срок, ему отправлено напоминание.
+	EventReviewReminder EventType = "review.reminder"
+	// EventReviewEscalated — ревью просрочено, проблема передана команде.
+	EventReviewEscalated EventType = "review.escalated"
+)
+
+// EventTypes возвращает все известные типы событий.
+func EventTypes() []EventType {
+	return []EventType{
+		EventReviewersAssigned,
+		EventReviewerReassigned,
+		EventPullRequestMerged,
+		EventReviewReminder,
+		EventReviewEscalated,
+	}
+}
+
+// Valid возвращает true, если тип события известен сервису.
+func (t EventType) Valid() bool {
+	for _, known := range EventTypes() {
+		if t == known {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Event описывает изменение состояния PR, о котором нужно сообщить внешним системам.
+// Sequence — порядковый номер события в outbox, заполняется после сохранения.
+// Reviewer и WaitingSince заполняются для напоминаний и эскалаций: ревьювер, чьё ревью просрочено,
+// и момент его назначения. Reason — причина действия, если оно выполнено не по запросу пользователя.
+// Leads заполняется, если событие адресовано лидам команды: эскалация ревью или принудительный merge.
+// OrganizationID — организация PR; заполняется при записи в outbox. Actor — кто выполнил действие.
+type Event struct {
+	Sequence         int64
+	OrganizationID   OrganizationID
+	Type             EventType
+	PullRequest      PullRequest
+	AddedReviewers   []UserID
+	RemovedReviewers []UserID
+	Reviewer         UserID
+	WaitingSince     *time.Time
+	Reason           string
+	Leads            []UserID
+	Actor            Actor
+	OccurredAt       time.Time
+}
@@ -0,0 +1,50 @@
+// Package domain содержит основные сущности сервиса назначения ревьюеров для Pull Request'ов.
+package domain
+
+// EmailMode описывает, как пользователь получает уведомления по почте.
+type EmailMode string
+
+const (
+	// EmailModeOff — письма не отправляются.
+	EmailModeOff EmailMode = "off"
+	// EmailModeImmediate — письмо отправляется сразу при назначении.
+	EmailModeImmediate EmailMode = "immediate"
+	// EmailModeDigest — раз в день отправляется сводка открытых ревью.
+	EmailModeDigest EmailMode = "digest"
+)
+
+// Valid возвращает true для известного режима.
+func (m EmailMode) Valid() bool {
+	switch m {
+	case EmailModeOff, EmailModeImmediate, EmailModeDigest:
+		return true
+	default:
+		return false
+	}
+}
+
+// NotificationPreferences описывает настройки уведомлений пользователя.
+// Пользователь без сохранённых настроек получает уведомления в чат и не получает писем.
+type NotificationPreferences struct {
+	UserID      UserID
+	ChatEnabled bool
+	Email       string
+	EmailMode   EmailMode
+}
+
+// DefaultNotificationPreferences возвращает настройки пользователя по умолчанию.
+func DefaultNotificationPreferences(userID UserID) NotificationPreferences {
+	return NotificationPreferences{
+		UserID:      userID,
+		ChatEnabled: true,
+		EmailMode:   EmailModeOff,
+	}
+}
+
+// TeamChatChannel описывает канал чата команды: адрес входящего вебхука
+// Slack-совместимого мессенджера и, опционально, имя канала.
+type TeamChatChannel struct {
+	TeamName   TeamName
+	WebhookURL string
+	Channel    string
+}
@@ -0,0 +1,18 @@
+// Package domain содержит основные сущности сервиса назначения ревьюеров для Pull Request'ов.
+package domain
+
+import "time"
+
+// OrganizationID — тип идентификатора организации (арендатора).
+type OrganizationID string
+
+// DefaultOrganizationID — организация, в которую перенесены данные, созданные до разделения по организациям.
+const DefaultOrganizationID OrganizationID = "default"
+
+// Organization описывает организацию: команды, пользователи, PR и настройки
+// одной организации недоступны другим, идентификаторы уникальны в пределах организации.
+type Organization struct {
+	ID        OrganizationID
+	Name      string
+	CreatedAt time.Time
+}
@@ -0,0 +1,48 @@
+// Package domain содержит основные сущности сервиса назначения ревьюеров для Pull Request'ов.
+package domain
+
+import "time"
+
+// EscalationAction описывает, что делать с просроченным ревью после порога эскалации.
+type EscalationAction string
+
+const (
+	// EscalationActionNone — только напоминания.
+	EscalationActionNone EscalationAction = "none"
+	// EscalationActionReassign — ревью переназначается на другого участника команды.
+	EscalationActionReassign EscalationAction = "reassign"
+	// EscalationActionEscalate — о просрочке сообщается команде.
+	EscalationActionEscalate EscalationAction = "escalate"
+)
+
+// Valid возвращает true для известного действия.
+func (a EscalationAction) Valid() bool {
+	switch a {
+	case EscalationActionNone, EscalationActionReassign, EscalationActionEscalate:
+		return true
+	default:
+		return false
+	}
+}
+
+// ReviewPolicy описывает SLA ревью команды автора PR.
+// Нулевой ReminderAfter отключает напоминания, нулевой EscalateAfter — эскалацию.
+type ReviewPolicy struct {
+	TeamName TeamName
+	// ReminderAfter — через сколько после назначения и затем с каким интервалом напоминать ревьюверу.
+	ReminderAfter time.Duration
+	// EscalateAfter — через сколько после назначения выполнять Action.
+	EscalateAfter time.Duration
+	Action        EscalationAction
+}
+
+// ReviewAssignment описывает назначение ревьювера на открытый PR.
+type ReviewAssignment struct {
+	PullRequest PullRequest
+	ReviewerID  UserID
+	// TeamName — команда автора PR, по ней выбирается ReviewPolicy.
+	TeamName    TeamName
+	AssignedAt  time.Time
+	RemindedAt  *time.Time
+	EscalatedAt *time.Time
+}
@@ -0,0 +1,8 @@
+// Package domain содержит основные сущности сервиса назначения ревьюеров для Pull Request'ов.
+package domain
+
+// Role — роль пользователя, выданная провайдером идентификации.
+type Role string
+
+// RoleAdmin — администратор организации.
+const RoleAdmin Role = "admin"
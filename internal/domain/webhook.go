@@ -0,0 +1,44 @@
+// Package domain содержит основные сущности сервиса назначения ревьюеров для Pull Request'ов.
+package domain
+
+import "time"
+
+// WebhookSubscriptionID — тип идентификатора подписки на вебхуки.
+type WebhookSubscriptionID string
+
+// WebhookSubscription описывает зарегистрированного получателя вебхуков.
+// Пустой список Events означает подписку на все события.
+type WebhookSubscription struct {
+	ID        WebhookSubscriptionID
+	URL       string
+	Events    []EventType
+	Secret    string
+	CreatedAt time.Time
+}
+
+// Matches возвращает true, если подписка должна получить событие данного типа.
+func (s WebhookSubscription) Matches(eventType EventType) bool {
+	if len(s.Events) == 0 {
+		return true
+	}
+
+	for _, t := range s.Events {
+		if t == eventType {
+			return true
+		}
+	}
+
+	return false
+}
+
+// WebhookDelivery описывает доставку вебхука, которая не удалась после всех повторов.
+type WebhookDelivery struct {
+	ID             string
+	SubscriptionID WebhookSubscriptionID
+	EventType      EventType
+	Payload        []byte
+	Attempts       int
+	LastError      string
+	LastStatusCode int
+	FailedAt       time.Time
+}
@@ -0,0 +1,114 @@
+// Package event содержит JSON-представление доменных событий,
+// общее для outbox, вебхуков и брокеров сообщений.
+package event
+
+import (
+	"time"
+
+	"github.com/dixitix/pr-reviewer-service/internal/domain"
+)
+
+// PullRequest описывает PR в теле события.
+type PullRequest struct {
+	PullRequestID     string     `json:"pull_request_id"`
+	PullRequestName   string     `json:"pull_request_name"`
+	AuthorID          string     `json:"author_id"`
+	Status            string     `json:"status"`
+	AssignedReviewers []string   `json:"assigned_reviewers"`
+	CreatedAt         *time.Time `json:"createdAt"`
+	MergedAt          *time.Time `json:"mergedAt"`
+}
+
+// Message описывает событие в JSON.
+type Message struct {
+	Sequence         int64       `json:"sequence,omitempty"`
+	OrganizationID   string      `json:"organization_id,omitempty"`
+	Event            string      `json:"event"`
+	OccurredAt       time.Time   `json:"occurred_at"`
+	PullRequest      PullRequest `json:"pull_request"`
+	AddedReviewers   []string    `json:"added_reviewers"`
+	RemovedReviewers []string    `json:"removed_reviewers"`
+	Reviewer         string      `json:"reviewer_id,omitempty"`
+	WaitingSince     *time.Time  `json:"waiting_since,omitempty"`
+	Reason           string      `json:"reason,omitempty"`
+	Leads            []string    `json:"leads,omitempty"`
+	Actor            string      `json:"actor,omitempty"`
+}
+
+// NewMessage строит JSON-представление доменного события.
+func NewMessage(e domain.Event) Message {
+	pr := e.PullRequest
+
+	return Message{
+		Sequence:       e.Sequence,
+		OrganizationID: string(e.OrganizationID),
+		Event:          string(e.Type),
+		OccurredAt:     e.OccurredAt,
+		PullRequest: PullRequest{
+			PullRequestID:     string(pr.ID),
+			PullRequestName:   pr.Name,
+			AuthorID:          string(pr.AuthorID),
+			Status:            string(pr.Status),
+			AssignedReviewers: userIDsToStrings(pr.AssignedReviewers),
+			CreatedAt:         pr.CreatedAt,
+			MergedAt:          pr.MergedAt,
+		},
+		AddedReviewers:   userIDsToStrings(e.AddedReviewers),
+		RemovedReviewers: userIDsToStrings(e.RemovedReviewers),
+		Reviewer:         string(e.Reviewer),
+		WaitingSince:     e.WaitingSince,
+		Reason:           e.Reason,
+		Leads:            userIDsToStrings(e.Leads),
+		Actor:            string(e.Actor),
+	}
+}
+
+// Domain восстанавливает доменное событие из JSON-представления.
+func (m Message) Domain() domain.Event {
+	return domain.Event{
+		Sequence:       m.Sequence,
+		OrganizationID: domain.OrganizationID(m.OrganizationID),
+		Type:           domain.EventType(m.Event),
+		PullRequest: domain.PullRequest{
+			ID:                domain.PullRequestID(m.PullRequest.PullRequestID),
+			Name:              m.PullRequest.PullRequestName,
+			AuthorID:          domain.UserID(m.PullRequest.AuthorID),
+			Status:            domain.PullRequestStatus(m.PullRequest.Status),
+			AssignedReviewers: stringsToUserIDs(m.PullRequest.AssignedReviewers),
+			CreatedAt:         m.PullRequest.CreatedAt,
+			MergedAt:          m.PullRequest.MergedAt,
+		},
+		AddedReviewers:   stringsToUserIDs(m.AddedReviewers),
+		RemovedReviewers: stringsToUserIDs(m.RemovedReviewers),
+		Reviewer:         domain.UserID(m.Reviewer),
+		WaitingSince:     m.WaitingSince,
+		Reason:           m.Reason,
+		Leads:            stringsToUserIDs(m.Leads),
+		Actor:            domain.Actor(m.Actor),
+		OccurredAt:       m.OccurredAt,
+	}
+}
+
+// userIDsToStrings конвертирует список идентификаторов пользователей в строки.
+func userIDsToStrings(ids []domain.UserID) []string {
+	result := make([]string, len(ids))
+	for i, id := range ids {
+		result[i] = string(id)
+	}
+
+	return result
+}
+
+// stringsToUserIDs конвертирует строки в список идентификаторов пользователей.
+func stringsToUserIDs(ids []string) []domain.UserID {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	result := make([]domain.UserID, len(ids))
+	for i, id := range ids {
+		result[i] = domain.UserID(id)
+	}
+
+	return result
+}
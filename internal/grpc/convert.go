@@ -0,0 +1,231 @@
+// Package grpcserver содержит gRPC-сервер сервиса назначения ревьюеров.
+package grpcserver
+
+import (
+	"sort"
+	"time"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/dixitix/pr-reviewer-service/internal/domain"
+	"github.com/dixitix/pr-reviewer-service/internal/grpc/reviewerv1"
+)
+
+// teamFromProto конвертирует команду из запроса в доменную команду и её участников.
+func teamFromProto(t *reviewerv1.Team) (domain.Team, []domain.User) {
+	team := domain.Team{
+		Name:             domain.TeamName(t.GetTeamName()),
+		Parent:           domain.TeamName(t.GetParentName()),
+		FallbackToParent: t.GetFallbackToParent(),
+		Leads:            toUserIDs(t.GetLeads()),
+	}
+
+	members := make([]domain.User, len(t.GetMembers()))
+	for i, m := range t.GetMembers() {
+		members[i] = domain.User{
+			ID:       domain.UserID(m.GetUserId()),
+			Username: m.GetUsername(),
+			TeamName: team.Name,
+			IsActive: m.GetIsActive(),
+		}
+	}
+
+	return team, members
+}
+
+// teamToProto конвертирует доменную команду и её участников в сообщение Team.
+func teamToProto(team domain.Team, users []domain.User) *reviewerv1.Team {
+	members := make([]*reviewerv1.TeamMember, len(users))
+
+	for i, u := range users {
+		members[i] = &reviewerv1.TeamMember{
+			UserId:   string(u.ID),
+			Username: u.Username,
+			IsActive: u.IsActive,
+		}
+
+		if u.TeamName != team.Name {
+			members[i].PrimaryTeamName = string(u.TeamName)
+		}
+	}
+
+	return &reviewerv1.Team{
+		TeamName:         string(team.Name),
+		ParentName:       string(team.Parent),
+		FallbackToParent: team.FallbackToParent,
+		Leads:            fromUserIDs(team.Leads),
+		Members:          members,
+		Version:          team.Version,
+	}
+}
+
+// userToProto конвертирует доменного пользователя в сообщение User.
+func userToProto(u domain.User) *reviewerv1.User {
+	return &reviewerv1.User{
+		UserId:   string(u.ID),
+		Username: u.Username,
+		TeamName: string(u.TeamName),
+		IsActive: u.IsActive,
+	}
+}
+
+// membershipsToProto конвертирует команды пользователя в ответ TeamMembershipsResponse.
+func membershipsToProto(userID domain.UserID, memberships []domain.TeamMembership) *reviewerv1.TeamMembershipsResponse {
+	teams := make([]*reviewerv1.TeamMembership, len(memberships))
+	for i, m := range memberships {
+		teams[i] = &reviewerv1.TeamMembership{
+			TeamName:  string(m.TeamName),
+			IsPrimary: m.IsPrimary,
+		}
+	}
+
+	return &reviewerv1.TeamMembershipsResponse{
+		UserId: string(userID),
+		Teams:  teams,
+	}
+}
+
+// reviewPolicyFromProto конвертирует SLA ревью из запроса в доменную модель.
+func reviewPolicyFromProto(p *reviewerv1.ReviewPolicy) domain.ReviewPolicy {
+	return domain.ReviewPolicy{
+		TeamName:      domain.TeamName(p.GetTeamName()),
+		ReminderAfter: time.Duration(p.GetReminderAfterMinutes()) * time.Minute,
+		EscalateAfter: time.Duration(p.GetEscalateAfterMinutes()) * time.Minute,
+		Action:        domain.EscalationAction(p.GetEscalationAction()),
+	}
+}
+
+// reviewPolicyToProto конвертирует доменное SLA ревью в сообщение ReviewPolicy.
+func reviewPolicyToProto(p domain.ReviewPolicy) *reviewerv1.ReviewPolicy {
+	return &reviewerv1.ReviewPolicy{
+		TeamName:             string(p.TeamName),
+		ReminderAfterMinutes: int32(p.ReminderAfter / time.Minute),
+		EscalateAfterMinutes: int32(p.EscalateAfter / time.Minute),
+		EscalationAction:     string(p.Action),
+	}
+}
+
+// pullRequestToProto конвертирует доменный PR в сообщение PullRequest.
+func pullRequestToProto(pr domain.PullRequest) *reviewerv1.PullRequest {
+	return &reviewerv1.PullRequest{
+		PullRequestId:     string(pr.ID),
+		PullRequestName:   pr.Name,
+		AuthorId:          string(pr.AuthorID),
+		Status:            string(pr.Status),
+		AssignedReviewers: fromUserIDs(pr.AssignedReviewers),
+		CreatedAt:         timestamp(pr.CreatedAt),
+		MergedAt:          timestamp(pr.MergedAt),
+		CreatedBy:         string(pr.CreatedBy),
+		MergedBy:          string(pr.MergedBy),
+		ReassignedBy:      string(pr.ReassignedBy),
+		Version:           pr.Version,
+	}
+}
+
+// pullRequestsToShort конвертирует PR'ы в краткие описания.
+func pullRequestsToShort(prs []domain.PullRequest) []*reviewerv1.PullRequestShort {
+	result := make([]*reviewerv1.PullRequestShort, len(prs))
+	for i, pr := range prs {
+		result[i] = &reviewerv1.PullRequestShort{
+			PullRequestId:   string(pr.ID),
+			PullRequestName: pr.Name,
+			AuthorId:        string(pr.AuthorID),
+			Status:          string(pr.Status),
+		}
+	}
+
+	return result
+}
+
+// historyToProto конвертирует события PR в записи истории.
+func historyToProto(events []domain.Event) []*reviewerv1.HistoryEntry {
+	result := make([]*reviewerv1.HistoryEntry, len(events))
+
+	for i, e := range events {
+		result[i] = &reviewerv1.HistoryEntry{
+			EventType:        string(e.Type),
+			OccurredAt:       timestamppb.New(e.OccurredAt),
+			Status:           string(e.PullRequest.Status),
+			AddedReviewers:   fromUserIDs(e.AddedReviewers),
+			RemovedReviewers: fromUserIDs(e.RemovedReviewers),
+			ReviewerId:       string(e.Reviewer),
+			WaitingSince:     timestamp(e.WaitingSince),
+			Reason:           e.Reason,
+			Actor:            string(e.Actor),
+		}
+	}
+
+	return result
+}
+
+// userStatsToProto конвертирует статистику по пользователям в список, отсортированный по user_id.
+func userStatsToProto(stats map[domain.UserID]int) []*reviewerv1.UserAssignmentStat {
+	result := make([]*reviewerv1.UserAssignmentStat, 0, len(stats))
+	for id, n := range stats {
+		result = append(result, &reviewerv1.UserAssignmentStat{UserId: string(id), Assignments: int64(n)})
+	}
+
+	sort.Slice(result, func(i, j int) bool { return result[i].UserId < result[j].UserId })
+
+	return result
+}
+
+// pullRequestStatsToProto конвертирует статистику по PR в список, отсортированный по pull_request_id.
+func pullRequestStatsToProto(stats map[domain.PullRequestID]int) []*reviewerv1.PullRequestAssignmentStat {
+	result := make([]*reviewerv1.PullRequestAssignmentStat, 0, len(stats))
+	for id, n := range stats {
+		result = append(result, &reviewerv1.PullRequestAssignmentStat{PullRequestId: string(id), Assignments: int64(n)})
+	}
+
+	sort.Slice(result, func(i, j int) bool { return result[i].PullRequestId < result[j].PullRequestId })
+
+	return result
+}
+
+// teamStatsToProto конвертирует статистику по командам.
+func teamStatsToProto(stats []domain.TeamAssignmentStat) []*reviewerv1.TeamAssignmentStat {
+	result := make([]*reviewerv1.TeamAssignmentStat, len(stats))
+	for i, st := range stats {
+		result[i] = &reviewerv1.TeamAssignmentStat{
+			TeamName:         string(st.TeamName),
+			ParentName:       string(st.Parent),
+			Assignments:      int64(st.Assignments),
+			TotalAssignments: int64(st.TotalAssignments),
+		}
+	}
+
+	return result
+}
+
+// timestamp конвертирует необязательное время; nil и нулевое время дают nil.
+func timestamp(t *time.Time) *timestamppb.Timestamp {
+	if t == nil || t.IsZero() {
+		return nil
+	}
+
+	return timestamppb.New(*t)
+}
+
+// toUserIDs конвертирует строки в ID пользователей; для пустого списка возвращает nil.
+func toUserIDs(ids []string) []domain.UserID {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	result := make([]domain.UserID, len(ids))
+	for i, id := range ids {
+		result[i] = domain.UserID(id)
+	}
+
+	return result
+}
+
+// fromUserIDs конвертирует ID пользователей в строки.
+func fromUserIDs(ids []domain.UserID) []string {
+	result := make([]string, len(ids))
+	for i, id := range ids {
+		result[i] = string(id)
+	}
+
+	return result
+}
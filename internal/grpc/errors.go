@@ -0,0 +1,89 @@
+// Package grpcserver содержит gRPC-сервер сервиса назначения ревьюеров.
+package grpcserver
+
+import (
+	"errors"
+	"log/slog"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/dixitix/pr-reviewer-service/internal/service"
+)
+
+// ErrorDomain — домен в деталях ошибок (errdetails.ErrorInfo).
+const ErrorDomain = "pr-reviewer-service"
+
+// Причины ошибок (errdetails.ErrorInfo.Reason); совпадают с кодами ошибок HTTP API.
+const (
+	ReasonNotFound           = "NOT_FOUND"
+	ReasonTeamExists         = "TEAM_EXISTS"
+	ReasonPRExists           = "PR_EXISTS"
+	ReasonOrganizationExists = "ORGANIZATION_EXISTS"
+	ReasonPRMerged           = "PR_MERGED"
+	ReasonNotAssigned        = "NOT_ASSIGNED"
+	ReasonNoCandidate        = "NO_CANDIDATE"
+	ReasonPreconditionFailed = "PRECONDITION_FAILED"
+	ReasonConflict           = "CONFLICT"
+	ReasonValidation         = "VALIDATION_ERROR"
+	ReasonForbidden          = "FORBIDDEN"
+	ReasonUnauthorized       = "UNAUTHORIZED"
+	ReasonInternal           = "INTERNAL_ERROR"
+)
+
+// serviceErrors сопоставляет ошибки сервисного слоя gRPC-кодам и причинам.
+var serviceErrors = []struct {
+	err    error
+	code   codes.Code
+	reason string
+}{
+	{service.ErrNotFound, codes.NotFound, ReasonNotFound},
+	{service.ErrTeamAlreadyExists, codes.AlreadyExists, ReasonTeamExists},
+	{service.ErrPullRequestAlreadyExists, codes.AlreadyExists, ReasonPRExists},
+	{service.ErrOrganizationExists, codes.AlreadyExists, ReasonOrganizationExists},
+	{service.ErrPullRequestMerged, codes.FailedPrecondition, ReasonPRMerged},
+	{service.ErrReviewerNotAssigned, codes.FailedPrecondition, ReasonNotAssigned},
+	{service.ErrNoCandidate, codes.FailedPrecondition, ReasonNoCandidate},
+	{service.ErrPreconditionFailed, codes.FailedPrecondition, ReasonPreconditionFailed},
+	{service.ErrConflict, codes.Aborted, ReasonConflict},
+	{service.ErrTeamHierarchyCycle, codes.InvalidArgument, ReasonValidation},
+	{service.ErrPrimaryTeamMembership, codes.InvalidArgument, ReasonValidation},
+	{service.ErrEmailRequired, codes.InvalidArgument, ReasonValidation},
+	{service.ErrForbidden, codes.PermissionDenied, ReasonForbidden},
+	{service.ErrUnauthenticated, codes.Unauthenticated, ReasonUnauthorized},
+}
+
+// toStatus преобразует ошибку сервисного слоя в gRPC-статус. Неизвестные ошибки логируются
+// и возвращаются как Internal без подробностей.
+func (s *Server) toStatus(method string, err error) error {
+	for _, e := range serviceErrors {
+		if errors.Is(err, e.err) {
+			return withReason(e.code, e.err.Error(), e.reason)
+		}
+	}
+
+	if s.logger != nil {
+		s.logger.Error("grpc "+method+" error", slog.Any("error", err))
+	}
+
+	return withReason(codes.Internal, "internal server error", ReasonInternal)
+}
+
+// invalidArgument возвращает статус InvalidArgument для ошибки проверки запроса.
+func invalidArgument(message string) error {
+	return withReason(codes.InvalidArgument, message, ReasonValidation)
+}
+
+// withReason создаёт статус с деталью errdetails.ErrorInfo, по которой клиент различает
+// ошибки с одинаковым кодом (например, PR_MERGED и NO_CANDIDATE).
+func withReason(code codes.Code, message, reason string) error {
+	st := status.New(code, message)
+
+	detailed, err := st.WithDetails(&errdetails.ErrorInfo{Reason: reason, Domain: ErrorDomain})
+	if err != nil {
+		return st.Err()
+	}
+
+	return detailed.Err()
+}
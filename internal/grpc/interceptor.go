@@ -0,0 +1,174 @@
+// Package grpcserver содержит gRPC-сервер сервиса назначения ревьюеров.
+package grpcserver
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/dixitix/pr-reviewer-service/internal/auth"
+	"github.com/dixitix/pr-reviewer-service/internal/authn"
+	"github.com/dixitix/pr-reviewer-service/internal/domain"
+	"github.com/dixitix/pr-reviewer-service/internal/grpc/reviewerv1"
+	"github.com/dixitix/pr-reviewer-service/internal/service"
+	"github.com/dixitix/pr-reviewer-service/internal/tenant"
+)
+
+// Метаданные запросов; совпадают с HTTP-заголовками Authorization, X-API-Key и X-Organization-ID.
+const (
+	MetadataAuthorization = "authorization"
+	MetadataAPIKey        = "x-api-key"
+	MetadataOrganization  = "x-organization-id"
+)
+
+// methodScopes — права, нужные для вызова методов; те же, что у соответствующих HTTP-маршрутов.
+// Методы, которых нет в списке, недоступны.
+var methodScopes = map[string]domain.APIScope{
+	reviewerv1.TeamService_CreateTeam_FullMethodName:          domain.APIScopeTeamAdmin,
+	reviewerv1.TeamService_GetTeam_FullMethodName:             domain.APIScopeRead,
+	reviewerv1.TeamService_ListTeams_FullMethodName:           domain.APIScopeRead,
+	reviewerv1.TeamService_SetTeamLeads_FullMethodName:        domain.APIScopeTeamAdmin,
+	reviewerv1.TeamService_SetTeamParent_FullMethodName:       domain.APIScopeTeamAdmin,
+	reviewerv1.TeamService_ListTeamTreeMembers_FullMethodName: domain.APIScopeRead,
+	reviewerv1.TeamService_AddTeamMember_FullMethodName:       domain.APIScopeTeamAdmin,
+	reviewerv1.TeamService_RemoveTeamMember_FullMethodName:    domain.APIScopeTeamAdmin,
+	reviewerv1.TeamService_GetReviewPolicy_FullMethodName:     domain.APIScopeRead,
+	reviewerv1.TeamService_SetReviewPolicy_FullMethodName:     domain.APIScopeTeamAdmin,
+
+	reviewerv1.UserService_GetUser_FullMethodName:         domain.APIScopeRead,
+	reviewerv1.UserService_SetUserActive_FullMethodName:   domain.APIScopeTeamAdmin,
+	reviewerv1.UserService_ListUserReviews_FullMethodName: domain.APIScopeRead,
+	reviewerv1.UserService_ListUserTeams_FullMethodName:   domain.APIScopeRead,
+
+	reviewerv1.PullRequestService_CreatePullRequest_FullMethodName:     domain.APIScopePRWrite,
+	reviewerv1.PullRequestService_GetPullRequest_FullMethodName:        domain.APIScopeRead,
+	reviewerv1.PullRequestService_MergePullRequest_FullMethodName:      domain.APIScopePRWrite,
+	reviewerv1.PullRequestService_ReassignReviewer_FullMethodName:      domain.APIScopePRWrite,
+	reviewerv1.PullRequestService_GetPullRequestHistory_FullMethodName: domain.APIScopeRead,
+
+	reviewerv1.StatsService_AssignmentsByUser_FullMethodName:        domain.APIScopeStatsRead,
+	reviewerv1.StatsService_AssignmentsByPullRequest_FullMethodName: domain.APIScopeStatsRead,
+	reviewerv1.StatsService_AssignmentsByTeam_FullMethodName:        domain.APIScopeStatsRead,
+}
+
+// unaryInterceptor аутентифицирует вызов, проверяет права на метод и кладёт в контекст
+// организацию вызывающего — то же, что делают auth.Middleware, Require и organization.Middleware в HTTP-слое.
+func (s *Server) unaryInterceptor(
+	ctx context.Context,
+	req any,
+	info *grpc.UnaryServerInfo,
+	handler grpc.UnaryHandler,
+) (any, error) {
+	ctx, err := s.authenticate(ctx, info.FullMethod)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, err = s.resolveOrganization(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return handler(ctx, req)
+}
+
+// authenticate кладёт в контекст личность вызывающего и проверяет, что у неё есть право на метод.
+func (s *Server) authenticate(ctx context.Context, method string) (context.Context, error) {
+	if !s.auth.Enabled() {
+		return ctx, nil
+	}
+
+	secret := requestKey(ctx)
+	if secret == "" {
+		return nil, status.Error(codes.Unauthenticated, "api key is required")
+	}
+
+	id, err := s.auth.Authenticate(ctx, secret)
+	if err != nil {
+		if errors.Is(err, auth.ErrInvalidToken) {
+			return nil, status.Error(codes.Unauthenticated, "invalid token")
+		}
+
+		if errors.Is(err, service.ErrUnauthenticated) {
+			return nil, status.Error(codes.Unauthenticated, "invalid api key")
+		}
+
+		return nil, status.Error(codes.Internal, "internal server error")
+	}
+
+	scope, ok := methodScopes[method]
+	if !ok {
+		return nil, status.Error(codes.PermissionDenied, "method is not available")
+	}
+
+	if err := s.auth.Authorize(id, scope); err != nil {
+		return nil, status.Error(codes.PermissionDenied, "api key lacks scope "+string(scope))
+	}
+
+	return auth.WithIdentity(ctx, id), nil
+}
+
+// resolveOrganization кладёт в контекст организацию вызывающего: организацию его ключа или токена,
+// а для корневого ключа и без аутентификации — из метаданных или организацию по умолчанию.
+func (s *Server) resolveOrganization(ctx context.Context) (context.Context, error) {
+	id := domain.OrganizationID(strings.TrimSpace(firstMetadata(ctx, MetadataOrganization)))
+
+	// Ключ или токен работает только в своей организации; чужая организация для него не существует.
+	if caller, ok := auth.FromContext(ctx); ok && !caller.Root {
+		own := caller.Organization
+		if own == "" {
+			own = s.defaultOrg
+		}
+
+		if id != "" && id != own {
+			return nil, status.Error(codes.NotFound, "organization not found")
+		}
+
+		id = own
+	}
+
+	if id == "" {
+		id = s.defaultOrg
+	}
+
+	ok, err := s.svc.OrganizationExists(ctx, id)
+	if err != nil {
+		if s.logger != nil {
+			s.logger.Error("grpc organization interceptor: OrganizationExists error", slog.Any("error", err))
+		}
+
+		return nil, status.Error(codes.Internal, "internal server error")
+	}
+
+	if !ok {
+		return nil, status.Error(codes.NotFound, "organization not found")
+	}
+
+	return tenant.WithOrganization(ctx, id), nil
+}
+
+// requestKey возвращает API-ключ или токен из метаданных запроса.
+func requestKey(ctx context.Context) string {
+	return authn.Secret(firstMetadata(ctx, MetadataAuthorization), firstMetadata(ctx, MetadataAPIKey))
+}
+
+// firstMetadata возвращает первое значение метаданных key или пустую строку.
+func firstMetadata(ctx context.Context, key string) string {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return ""
+	}
+
+	values := md.Get(key)
+	if len(values) == 0 {
+		return ""
+	}
+
+	return values[0]
+}
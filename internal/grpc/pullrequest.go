@@ -0,0 +1,101 @@
+// Package grpcserver содержит gRPC-сервер сервиса назначения ревьюеров.
+package grpcserver
+
+import (
+	"context"
+
+	"github.com/dixitix/pr-reviewer-service/internal/domain"
+	"github.com/dixitix/pr-reviewer-service/internal/grpc/reviewerv1"
+	"github.com/dixitix/pr-reviewer-service/internal/service"
+)
+
+// CreatePullRequest создаёт PR и назначает ревьюверов.
+func (s *Server) CreatePullRequest(ctx context.Context, req *reviewerv1.CreatePullRequestRequest) (*reviewerv1.PullRequest, error) {
+	if req.GetPullRequestId() == "" || req.GetPullRequestName() == "" || req.GetAuthorId() == "" {
+		return nil, invalidArgument("pull_request_id, pull_request_name and author_id are required")
+	}
+
+	pr, err := s.svc.CreatePullRequest(
+		ctx,
+		domain.PullRequestID(req.GetPullRequestId()),
+		req.GetPullRequestName(),
+		domain.UserID(req.GetAuthorId()),
+	)
+	if err != nil {
+		return nil, s.toStatus("CreatePullRequest", err)
+	}
+
+	return pullRequestToProto(pr), nil
+}
+
+// GetPullRequest возвращает PR.
+func (s *Server) GetPullRequest(ctx context.Context, req *reviewerv1.GetPullRequestRequest) (*reviewerv1.PullRequest, error) {
+	if req.GetPullRequestId() == "" {
+		return nil, invalidArgument("pull_request_id is required")
+	}
+
+	pr, err := s.svc.GetPullRequest(ctx, domain.PullRequestID(req.GetPullRequestId()))
+	if err != nil {
+		return nil, s.toStatus("GetPullRequest", err)
+	}
+
+	return pullRequestToProto(pr), nil
+}
+
+// MergePullRequest выполняет идемпотентный merge PR.
+func (s *Server) MergePullRequest(ctx context.Context, req *reviewerv1.MergePullRequestRequest) (*reviewerv1.PullRequest, error) {
+	if req.GetPullRequestId() == "" {
+		return nil, invalidArgument("pull_request_id is required")
+	}
+
+	ctx = withExpectedVersion(ctx, req.GetExpectedVersion())
+
+	pr, err := s.svc.MergePullRequest(ctx, domain.PullRequestID(req.GetPullRequestId()), service.MergeOptions{Force: req.GetForce()})
+	if err != nil {
+		return nil, s.toStatus("MergePullRequest", err)
+	}
+
+	return pullRequestToProto(pr), nil
+}
+
+// ReassignReviewer заменяет ревьювера другим участником его команды.
+func (s *Server) ReassignReviewer(
+	ctx context.Context,
+	req *reviewerv1.ReassignReviewerRequest,
+) (*reviewerv1.ReassignReviewerResponse, error) {
+	if req.GetPullRequestId() == "" || req.GetOldUserId() == "" {
+		return nil, invalidArgument("pull_request_id and old_user_id are required")
+	}
+
+	ctx = withExpectedVersion(ctx, req.GetExpectedVersion())
+
+	pr, replacedBy, err := s.svc.ReassignReviewer(ctx, domain.PullRequestID(req.GetPullRequestId()), domain.UserID(req.GetOldUserId()))
+	if err != nil {
+		return nil, s.toStatus("ReassignReviewer", err)
+	}
+
+	return &reviewerv1.ReassignReviewerResponse{
+		PullRequest: pullRequestToProto(pr),
+		ReplacedBy:  string(replacedBy),
+	}, nil
+}
+
+// GetPullRequestHistory возвращает события PR в порядке их записи.
+func (s *Server) GetPullRequestHistory(
+	ctx context.Context,
+	req *reviewerv1.GetPullRequestHistoryRequest,
+) (*reviewerv1.GetPullRequestHistoryResponse, error) {
+	if req.GetPullRequestId() == "" {
+		return nil, invalidArgument("pull_request_id is required")
+	}
+
+	events, err := s.svc.GetPullRequestHistory(ctx, domain.PullRequestID(req.GetPullRequestId()))
+	if err != nil {
+		return nil, s.toStatus("GetPullRequestHistory", err)
+	}
+
+	return &reviewerv1.GetPullRequestHistoryResponse{
+		PullRequestId: req.GetPullRequestId(),
+		History:       historyToProto(events),
+	}, nil
+}
@@ -0,0 +1,60 @@
+// Package grpcserver содержит gRPC-сервер сервиса назначения ревьюеров.
+package grpcserver
+
+import (
+	"context"
+	"log/slog"
+	"runtime/debug"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+)
+
+// recoverUnary перехватывает панику унарного обработчика, логирует её со стеком и возвращает
+// клиенту Internal — как middleware.Recover в HTTP-слое.
+func (s *Server) recoverUnary(
+	ctx context.Context,
+	req any,
+	info *grpc.UnaryServerInfo,
+	handler grpc.UnaryHandler,
+) (resp any, err error) {
+	defer func() {
+		if rec := recover(); rec != nil {
+			s.logPanic(ctx, info.FullMethod, rec)
+			resp, err = nil, withReason(codes.Internal, "internal server error", ReasonInternal)
+		}
+	}()
+
+	return handler(ctx, req)
+}
+
+// recoverStream перехватывает панику потокового обработчика так же, как recoverUnary.
+func (s *Server) recoverStream(
+	srv any,
+	ss grpc.ServerStream,
+	info *grpc.StreamServerInfo,
+	handler grpc.StreamHandler,
+) (err error) {
+	defer func() {
+		if rec := recover(); rec != nil {
+			s.logPanic(ss.Context(), info.FullMethod, rec)
+			err = withReason(codes.Internal, "internal server error", ReasonInternal)
+		}
+	}()
+
+	return handler(srv, ss)
+}
+
+// logPanic логирует панику обработчика method со стеком.
+func (s *Server) logPanic(ctx context.Context, method string, rec any) {
+	logger := s.logger
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	logger.ErrorContext(ctx, "grpc handler panic",
+		slog.Any("panic", rec),
+		slog.String("method", method),
+		slog.String("stack", string(debug.Stack())),
+	)
+}
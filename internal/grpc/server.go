@@ -0,0 +1,60 @@
+// Package grpcserver содержит gRPC-сервер сервиса назначения ревьюеров. Он повторяет HTTP API
+// поверх того же сервисного слоя: аутентификация, организация вызывающего и права API-ключей
+// проверяются так же, как в HTTP-слое.
+package grpcserver
+
+import (
+	"log/slog"
+
+	"google.golang.org/grpc"
+
+	"github.com/dixitix/pr-reviewer-service/internal/authn"
+	"github.com/dixitix/pr-reviewer-service/internal/domain"
+	"github.com/dixitix/pr-reviewer-service/internal/grpc/reviewerv1"
+	"github.com/dixitix/pr-reviewer-service/internal/service"
+)
+
+// Config описывает настройки gRPC-сервера.
+type Config struct {
+	// DefaultOrganization — организация запросов без метаданных x-organization-id.
+	DefaultOrganization domain.OrganizationID
+	// Auth — аутентификация запросов по API-ключам и JWT, как в HTTP-слое.
+	Auth authn.Config
+}
+
+// Server реализует gRPC-сервисы TeamService, UserService, PullRequestService и StatsService.
+type Server struct {
+	reviewerv1.UnimplementedTeamServiceServer
+	reviewerv1.UnimplementedUserServiceServer
+	reviewerv1.UnimplementedPullRequestServiceServer
+	reviewerv1.UnimplementedStatsServiceServer
+
+	svc        service.Service
+	auth       *authn.Authenticator
+	defaultOrg domain.OrganizationID
+	logger     *slog.Logger
+}
+
+// New создаёт gRPC-сервер с зарегистрированными сервисами. Паника в обработчике
+// перехватывается и возвращается клиенту как Internal. Дополнительные opts передаются в grpc.NewServer.
+func New(svc service.Service, cfg Config, logger *slog.Logger, opts ...grpc.ServerOption) *grpc.Server {
+	s := &Server{
+		svc:        svc,
+		auth:       authn.New(svc, cfg.Auth, logger),
+		defaultOrg: cfg.DefaultOrganization,
+		logger:     logger,
+	}
+
+	opts = append([]grpc.ServerOption{
+		grpc.ChainUnaryInterceptor(s.recoverUnary, s.unaryInterceptor),
+		grpc.ChainStreamInterceptor(s.recoverStream),
+	}, opts...)
+
+	srv := grpc.NewServer(opts...)
+	reviewerv1.RegisterTeamServiceServer(srv, s)
+	reviewerv1.RegisterUserServiceServer(srv, s)
+	reviewerv1.RegisterPullRequestServiceServer(srv, s)
+	reviewerv1.RegisterStatsServiceServer(srv, s)
+
+	return srv
+}
@@ -0,0 +1,292 @@
+package grpcserver
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+
+	"github.com/dixitix/pr-reviewer-service/internal/authn"
+	"github.com/dixitix/pr-reviewer-service/internal/domain"
+	"github.com/dixitix/pr-reviewer-service/internal/grpc/reviewerv1"
+	"github.com/dixitix/pr-reviewer-service/internal/precondition"
+	"github.com/dixitix/pr-reviewer-service/internal/service"
+	"github.com/dixitix/pr-reviewer-service/internal/tenant"
+)
+
+// fakeService реализует нужные тестам методы сервиса; остальные методы паникуют через nil-интерфейс.
+type fakeService struct {
+	service.Service
+
+	orgs       map[domain.OrganizationID]bool
+	keys       map[string]domain.APIKey
+	reassign   error
+	lastOrg    domain.OrganizationID
+	lastExpect int64
+}
+
+func (f *fakeService) OrganizationExists(_ context.Context, id domain.OrganizationID) (bool, error) {
+	return f.orgs[id], nil
+}
+
+func (f *fakeService) AuthenticateAPIKey(_ context.Context, secret string) (domain.APIKey, error) {
+	key, ok := f.keys[secret]
+	if !ok {
+		return domain.APIKey{}, service.ErrUnauthenticated
+	}
+
+	return key, nil
+}
+
+func (f *fakeService) GetPullRequest(ctx context.Context, id domain.PullRequestID) (domain.PullRequest, error) {
+	f.lastOrg, _ = tenant.Organization(ctx)
+
+	return domain.PullRequest{ID: id, Status: domain.PullRequestStatusOpen, Version: 3}, nil
+}
+
+func (f *fakeService) MergePullRequest(ctx context.Context, id domain.PullRequestID, _ service.MergeOptions) (domain.PullRequest, error) {
+	f.lastExpect, _ = precondition.Version(ctx)
+
+	return domain.PullRequest{ID: id, Status: domain.PullRequestStatusMerged, Version: 4}, nil
+}
+
+func (f *fakeService) ReassignReviewer(context.Context, domain.PullRequestID, domain.UserID) (domain.PullRequest, domain.UserID, error) {
+	return domain.PullRequest{}, "", f.reassign
+}
+
+// dial запускает сервер на bufconn и возвращает клиентское соединение.
+func dial(t *testing.T, svc service.Service, cfg Config) *grpc.ClientConn {
+	t.Helper()
+
+	lis := bufconn.Listen(1 << 20)
+	srv := New(svc, cfg, nil)
+
+	go func() {
+		_ = srv.Serve(lis)
+	}()
+	t.Cleanup(srv.Stop)
+
+	conn, err := grpc.NewClient(
+		"passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatalf("dial bufconn: %v", err)
+	}
+	t.Cleanup(func() { _ = conn.Close() })
+
+	return conn
+}
+
+// withKey возвращает контекст вызова с API-ключом и, если задана, организацией.
+func withKey(key string, org domain.OrganizationID) context.Context {
+	md := metadata.Pairs(MetadataAuthorization, "Bearer "+key)
+	if org != "" {
+		md.Append(MetadataOrganization, string(org))
+	}
+
+	return metadata.NewOutgoingContext(context.Background(), md)
+}
+
+// reason возвращает Reason из errdetails.ErrorInfo статуса или пустую строку.
+func reason(err error) string {
+	for _, d := range status.Convert(err).Details() {
+		if info, ok := d.(*errdetails.ErrorInfo); ok {
+			return info.GetReason()
+		}
+	}
+
+	return ""
+}
+
+func TestServer_Auth(t *testing.T) {
+	svc := &fakeService{
+		orgs: map[domain.OrganizationID]bool{"default": true, "acme": true},
+		keys: map[string]domain.APIKey{
+			"reader": {ID: "k1", OrganizationID: "acme", Scopes: []domain.APIScope{domain.APIScopeRead}},
+		},
+	}
+	conn := dial(t, svc, Config{
+		DefaultOrganization: "default",
+		Auth:                authn.Config{Enabled: true, RootKey: "root-key"},
+	})
+	client := reviewerv1.NewPullRequestServiceClient(conn)
+
+	tests := []struct {
+		name    string
+		ctx     context.Context
+		call    func(ctx context.Context) error
+		code    codes.Code
+		wantOrg domain.OrganizationID
+	}{
+		{
+			name: "no key",
+			ctx:  context.Background(),
+			call: func(ctx context.Context) error {
+				_, err := client.GetPullRequest(ctx, &reviewerv1.GetPullRequestRequest{PullRequestId: "pr-1"})
+				return err
+			},
+			code: codes.Unauthenticated,
+		},
+		{
+			name: "unknown key",
+			ctx:  withKey("nope", ""),
+			call: func(ctx context.Context) error {
+				_, err := client.GetPullRequest(ctx, &reviewerv1.GetPullRequestRequest{PullRequestId: "pr-1"})
+				return err
+			},
+			code: codes.Unauthenticated,
+		},
+		{
+			name: "key reads in its organization",
+			ctx:  withKey("reader", ""),
+			call: func(ctx context.Context) error {
+				_, err := client.GetPullRequest(ctx, &reviewerv1.GetPullRequestRequest{PullRequestId: "pr-1"})
+				return err
+			},
+			code:    codes.OK,
+			wantOrg: "acme",
+		},
+		{
+			name: "key lacks pr:write",
+			ctx:  withKey("reader", ""),
+			call: func(ctx context.Context) error {
+				_, err := client.MergePullRequest(ctx, &reviewerv1.MergePullRequestRequest{PullRequestId: "pr-1"})
+				return err
+			},
+			code: codes.PermissionDenied,
+		},
+		{
+			name: "key in foreign organization",
+			ctx:  withKey("reader", "default"),
+			call: func(ctx context.Context) error {
+				_, err := client.GetPullRequest(ctx, &reviewerv1.GetPullRequestRequest{PullRequestId: "pr-1"})
+				return err
+			},
+			code: codes.NotFound,
+		},
+		{
+			name: "root key picks organization",
+			ctx:  withKey("root-key", "acme"),
+			call: func(ctx context.Context) error {
+				_, err := client.GetPullRequest(ctx, &reviewerv1.GetPullRequestRequest{PullRequestId: "pr-1"})
+				return err
+			},
+			code:    codes.OK,
+			wantOrg: "acme",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc.lastOrg = ""
+
+			err := tt.call(tt.ctx)
+			if got := status.Code(err); got != tt.code {
+				t.Fatalf("code = %s, want %s (err: %v)", got, tt.code, err)
+			}
+
+			if tt.wantOrg != "" && svc.lastOrg != tt.wantOrg {
+				t.Fatalf("organization = %q, want %q", svc.lastOrg, tt.wantOrg)
+			}
+		})
+	}
+}
+
+func TestServer_ErrorMapping(t *testing.T) {
+	svc := &fakeService{orgs: map[domain.OrganizationID]bool{"default": true}}
+	client := reviewerv1.NewPullRequestServiceClient(dial(t, svc, Config{DefaultOrganization: "default"}))
+	ctx := context.Background()
+
+	tests := []struct {
+		err    error
+		code   codes.Code
+		reason string
+	}{
+		{service.ErrNoCandidate, codes.FailedPrecondition, "NO_CANDIDATE"},
+		{service.ErrPullRequestMerged, codes.FailedPrecondition, "PR_MERGED"},
+		{service.ErrReviewerNotAssigned, codes.FailedPrecondition, "NOT_ASSIGNED"},
+		{service.ErrPreconditionFailed, codes.FailedPrecondition, "PRECONDITION_FAILED"},
+		{service.ErrConflict, codes.Aborted, "CONFLICT"},
+		{service.ErrNotFound, codes.NotFound, "NOT_FOUND"},
+		{service.ErrForbidden, codes.PermissionDenied, "FORBIDDEN"},
+		{context.DeadlineExceeded, codes.Internal, "INTERNAL_ERROR"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.reason, func(t *testing.T) {
+			svc.reassign = tt.err
+
+			_, err := client.ReassignReviewer(ctx, &reviewerv1.ReassignReviewerRequest{PullRequestId: "pr-1", OldUserId: "u1"})
+			if got := status.Code(err); got != tt.code {
+				t.Fatalf("code = %s, want %s (err: %v)", got, tt.code, err)
+			}
+
+			if got := reason(err); got != tt.reason {
+				t.Fatalf("reason = %q, want %q", got, tt.reason)
+			}
+		})
+	}
+
+	_, err := client.ReassignReviewer(ctx, &reviewerv1.ReassignReviewerRequest{PullRequestId: "pr-1"})
+	if status.Code(err) != codes.InvalidArgument || reason(err) != "VALIDATION_ERROR" {
+		t.Fatalf("missing old_user_id: err = %v, want InvalidArgument VALIDATION_ERROR", err)
+	}
+}
+
+func TestServer_MergeExpectedVersion(t *testing.T) {
+	svc := &fakeService{orgs: map[domain.OrganizationID]bool{"default": true}}
+	client := reviewerv1.NewPullRequestServiceClient(dial(t, svc, Config{DefaultOrganization: "default"}))
+
+	pr, err := client.MergePullRequest(context.Background(), &reviewerv1.MergePullRequestRequest{
+		PullRequestId:   "pr-1",
+		ExpectedVersion: 3,
+	})
+	if err != nil {
+		t.Fatalf("MergePullRequest: %v", err)
+	}
+
+	if svc.lastExpect != 3 {
+		t.Fatalf("expected version in context = %d, want 3", svc.lastExpect)
+	}
+
+	if pr.GetStatus() != string(domain.PullRequestStatusMerged) || pr.GetVersion() != 4 {
+		t.Fatalf("pr = %+v, want MERGED version 4", pr)
+	}
+
+	svc.lastExpect = 0
+
+	if _, err := client.MergePullRequest(context.Background(), &reviewerv1.MergePullRequestRequest{PullRequestId: "pr-1"}); err != nil {
+		t.Fatalf("MergePullRequest without version: %v", err)
+	}
+
+	if svc.lastExpect != 0 {
+		t.Fatalf("expected version in context = %d, want none", svc.lastExpect)
+	}
+}
+
+// TestServer_RecoverPanic проверяет, что паника обработчика возвращается как Internal
+// и не останавливает сервер.
+func TestServer_RecoverPanic(t *testing.T) {
+	svc := &fakeService{orgs: map[domain.OrganizationID]bool{"default": true}}
+	conn := dial(t, svc, Config{DefaultOrganization: "default"})
+	ctx := context.Background()
+
+	// GetUser не реализован в fakeService: вызов через nil-интерфейс паникует.
+	_, err := reviewerv1.NewUserServiceClient(conn).GetUser(ctx, &reviewerv1.GetUserRequest{UserId: "u1"})
+	if status.Code(err) != codes.Internal || reason(err) != "INTERNAL_ERROR" {
+		t.Fatalf("err = %v, want Internal INTERNAL_ERROR", err)
+	}
+
+	if _, err := reviewerv1.NewPullRequestServiceClient(conn).GetPullRequest(ctx, &reviewerv1.GetPullRequestRequest{PullRequestId: "pr-1"}); err != nil {
+		t.Fatalf("GetPullRequest after panic: %v", err)
+	}
+}
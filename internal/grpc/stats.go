@@ -0,0 +1,41 @@
+// Package grpcserver содержит gRPC-сервер сервиса назначения ревьюеров.
+package grpcserver
+
+import (
+	"context"
+
+	"github.com/dixitix/pr-reviewer-service/internal/grpc/reviewerv1"
+)
+
+// AssignmentsByUser возвращает число назначений по пользователям.
+func (s *Server) AssignmentsByUser(ctx context.Context, _ *reviewerv1.AssignmentsByUserRequest) (*reviewerv1.AssignmentsByUserResponse, error) {
+	stats, err := s.svc.GetAssignmentsByUser(ctx)
+	if err != nil {
+		return nil, s.toStatus("AssignmentsByUser", err)
+	}
+
+	return &reviewerv1.AssignmentsByUserResponse{Stats: userStatsToProto(stats)}, nil
+}
+
+// AssignmentsByPullRequest возвращает число назначений по PR.
+func (s *Server) AssignmentsByPullRequest(
+	ctx context.Context,
+	_ *reviewerv1.AssignmentsByPullRequestRequest,
+) (*reviewerv1.AssignmentsByPullRequestResponse, error) {
+	stats, err := s.svc.GetAssignmentsByPullRequest(ctx)
+	if err != nil {
+		return nil, s.toStatus("AssignmentsByPullRequest", err)
+	}
+
+	return &reviewerv1.AssignmentsByPullRequestResponse{Stats: pullRequestStatsToProto(stats)}, nil
+}
+
+// AssignmentsByTeam возвращает число назначений по командам с суммами по иерархии.
+func (s *Server) AssignmentsByTeam(ctx context.Context, _ *reviewerv1.AssignmentsByTeamRequest) (*reviewerv1.AssignmentsByTeamResponse, error) {
+	stats, err := s.svc.GetAssignmentsByTeam(ctx)
+	if err != nil {
+		return nil, s.toStatus("AssignmentsByTeam", err)
+	}
+
+	return &reviewerv1.AssignmentsByTeamResponse{Stats: teamStatsToProto(stats)}, nil
+}
@@ -0,0 +1,234 @@
+// Package grpcserver содержит gRPC-сервер сервиса назначения ревьюеров.
+package grpcserver
+
+import (
+	"context"
+
+	"github.com/dixitix/pr-reviewer-service/internal/domain"
+	"github.com/dixitix/pr-reviewer-service/internal/grpc/reviewerv1"
+	"github.com/dixitix/pr-reviewer-service/internal/precondition"
+)
+
+// CreateTeam создаёт команду с участниками и возвращает её.
+func (s *Server) CreateTeam(ctx context.Context, req *reviewerv1.CreateTeamRequest) (*reviewerv1.CreateTeamResponse, error) {
+	t := req.GetTeam()
+	if t.GetTeamName() == "" {
+		return nil, invalidArgument("team.team_name is required")
+	}
+
+	for _, m := range t.GetMembers() {
+		if m.GetUserId() == "" || m.GetUsername() == "" {
+			return nil, invalidArgument("member.user_id and member.username are required")
+		}
+	}
+
+	for _, id := range t.GetLeads() {
+		if id == "" {
+			return nil, invalidArgument("leads must not contain empty user_id")
+		}
+	}
+
+	if t.GetParentName() == t.GetTeamName() {
+		return nil, invalidArgument("parent_name must differ from team_name")
+	}
+
+	team, members := teamFromProto(t)
+
+	if err := s.svc.CreateTeam(ctx, team, members); err != nil {
+		return nil, s.toStatus("CreateTeam", err)
+	}
+
+	created, users, err := s.svc.GetTeam(ctx, team.Name)
+	if err != nil {
+		return nil, s.toStatus("CreateTeam", err)
+	}
+
+	return &reviewerv1.CreateTeamResponse{Team: teamToProto(created, users)}, nil
+}
+
+// GetTeam возвращает команду со всеми участниками.
+func (s *Server) GetTeam(ctx context.Context, req *reviewerv1.GetTeamRequest) (*reviewerv1.GetTeamResponse, error) {
+	if req.GetTeamName() == "" {
+		return nil, invalidArgument("team_name is required")
+	}
+
+	team, users, err := s.svc.GetTeam(ctx, domain.TeamName(req.GetTeamName()))
+	if err != nil {
+		return nil, s.toStatus("GetTeam", err)
+	}
+
+	return &reviewerv1.GetTeamResponse{Team: teamToProto(team, users)}, nil
+}
+
+// ListTeams возвращает все команды организации.
+func (s *Server) ListTeams(ctx context.Context, _ *reviewerv1.ListTeamsRequest) (*reviewerv1.ListTeamsResponse, error) {
+	teams, err := s.svc.ListTeams(ctx)
+	if err != nil {
+		return nil, s.toStatus("ListTeams", err)
+	}
+
+	resp := &reviewerv1.ListTeamsResponse{Teams: make([]*reviewerv1.Team, len(teams))}
+	for i, t := range teams {
+		resp.Teams[i] = teamToProto(t, nil)
+	}
+
+	return resp, nil
+}
+
+// SetTeamLeads заменяет список лидов команды.
+func (s *Server) SetTeamLeads(ctx context.Context, req *reviewerv1.SetTeamLeadsRequest) (*reviewerv1.SetTeamLeadsResponse, error) {
+	if req.GetTeamName() == "" {
+		return nil, invalidArgument("team_name is required")
+	}
+
+	for _, id := range req.GetLeads() {
+		if id == "" {
+			return nil, invalidArgument("leads must not contain empty user_id")
+		}
+	}
+
+	ctx = withExpectedVersion(ctx, req.GetExpectedVersion())
+
+	team, err := s.svc.SetTeamLeads(ctx, domain.TeamName(req.GetTeamName()), toUserIDs(req.GetLeads()))
+	if err != nil {
+		return nil, s.toStatus("SetTeamLeads", err)
+	}
+
+	return &reviewerv1.SetTeamLeadsResponse{
+		TeamName: string(team.Name),
+		Leads:    fromUserIDs(team.Leads),
+		Version:  team.Version,
+	}, nil
+}
+
+// SetTeamParent меняет положение команды в иерархии.
+func (s *Server) SetTeamParent(ctx context.Context, req *reviewerv1.SetTeamParentRequest) (*reviewerv1.SetTeamParentResponse, error) {
+	if req.GetTeamName() == "" {
+		return nil, invalidArgument("team_name is required")
+	}
+
+	ctx = withExpectedVersion(ctx, req.GetExpectedVersion())
+
+	team, err := s.svc.SetTeamParent(ctx, domain.TeamName(req.GetTeamName()), domain.TeamName(req.GetParentName()), req.GetFallbackToParent())
+	if err != nil {
+		return nil, s.toStatus("SetTeamParent", err)
+	}
+
+	return &reviewerv1.SetTeamParentResponse{
+		TeamName:         string(team.Name),
+		ParentName:       string(team.Parent),
+		FallbackToParent: team.FallbackToParent,
+		Version:          team.Version,
+	}, nil
+}
+
+// ListTeamTreeMembers возвращает участников команды и всех её дочерних команд.
+func (s *Server) ListTeamTreeMembers(
+	ctx context.Context,
+	req *reviewerv1.ListTeamTreeMembersRequest,
+) (*reviewerv1.ListTeamTreeMembersResponse, error) {
+	if req.GetTeamName() == "" {
+		return nil, invalidArgument("team_name is required")
+	}
+
+	users, err := s.svc.ListTeamTreeMembers(ctx, domain.TeamName(req.GetTeamName()))
+	if err != nil {
+		return nil, s.toStatus("ListTeamTreeMembers", err)
+	}
+
+	resp := &reviewerv1.ListTeamTreeMembersResponse{
+		TeamName: req.GetTeamName(),
+		Members:  make([]*reviewerv1.User, len(users)),
+	}
+	for i, u := range users {
+		resp.Members[i] = userToProto(u)
+	}
+
+	return resp, nil
+}
+
+// AddTeamMember добавляет пользователя в команду.
+func (s *Server) AddTeamMember(ctx context.Context, req *reviewerv1.AddTeamMemberRequest) (*reviewerv1.TeamMembershipsResponse, error) {
+	if req.GetTeamName() == "" || req.GetUserId() == "" {
+		return nil, invalidArgument("team_name and user_id are required")
+	}
+
+	ctx = withExpectedVersion(ctx, req.GetExpectedVersion())
+	userID := domain.UserID(req.GetUserId())
+
+	memberships, err := s.svc.AddTeamMember(ctx, domain.TeamName(req.GetTeamName()), userID, req.GetIsPrimary())
+	if err != nil {
+		return nil, s.toStatus("AddTeamMember", err)
+	}
+
+	return membershipsToProto(userID, memberships), nil
+}
+
+// RemoveTeamMember исключает пользователя из дополнительной команды.
+func (s *Server) RemoveTeamMember(
+	ctx context.Context,
+	req *reviewerv1.RemoveTeamMemberRequest,
+) (*reviewerv1.TeamMembershipsResponse, error) {
+	if req.GetTeamName() == "" || req.GetUserId() == "" {
+		return nil, invalidArgument("team_name and user_id are required")
+	}
+
+	ctx = withExpectedVersion(ctx, req.GetExpectedVersion())
+	userID := domain.UserID(req.GetUserId())
+
+	memberships, err := s.svc.RemoveTeamMember(ctx, domain.TeamName(req.GetTeamName()), userID)
+	if err != nil {
+		return nil, s.toStatus("RemoveTeamMember", err)
+	}
+
+	return membershipsToProto(userID, memberships), nil
+}
+
+// GetReviewPolicy возвращает SLA ревью команды.
+func (s *Server) GetReviewPolicy(ctx context.Context, req *reviewerv1.GetReviewPolicyRequest) (*reviewerv1.ReviewPolicy, error) {
+	if req.GetTeamName() == "" {
+		return nil, invalidArgument("team_name is required")
+	}
+
+	policy, err := s.svc.GetReviewPolicy(ctx, domain.TeamName(req.GetTeamName()))
+	if err != nil {
+		return nil, s.toStatus("GetReviewPolicy", err)
+	}
+
+	return reviewPolicyToProto(policy), nil
+}
+
+// SetReviewPolicy создаёт или обновляет SLA ревью команды.
+func (s *Server) SetReviewPolicy(ctx context.Context, req *reviewerv1.ReviewPolicy) (*reviewerv1.ReviewPolicy, error) {
+	if req.GetTeamName() == "" {
+		return nil, invalidArgument("team_name is required")
+	}
+
+	if req.GetReminderAfterMinutes() < 0 || req.GetEscalateAfterMinutes() < 0 {
+		return nil, invalidArgument("thresholds must not be negative")
+	}
+
+	policy := reviewPolicyFromProto(req)
+	if policy.Action == "" {
+		policy.Action = domain.EscalationActionNone
+	}
+
+	if !policy.Action.Valid() {
+		return nil, invalidArgument("escalation_action must be one of none, reassign, escalate")
+	}
+
+	if err := s.svc.SetReviewPolicy(ctx, policy); err != nil {
+		return nil, s.toStatus("SetReviewPolicy", err)
+	}
+
+	return reviewPolicyToProto(policy), nil
+}
+
+// withExpectedVersion добавляет в контекст ожидаемую версию ресурса, если клиент её передал.
+func withExpectedVersion(ctx context.Context, version int64) context.Context {
+	if version <= 0 {
+		return ctx
+	}
+
+	return precondition.WithVersion(ctx, version)
+}
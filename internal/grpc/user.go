@@ -0,0 +1,76 @@
+// Package grpcserver содержит gRPC-сервер сервиса назначения ревьюеров.
+package grpcserver
+
+import (
+	"context"
+	"errors"
+
+	"github.com/dixitix/pr-reviewer-service/internal/domain"
+	"github.com/dixitix/pr-reviewer-service/internal/grpc/reviewerv1"
+	"github.com/dixitix/pr-reviewer-service/internal/service"
+)
+
+// GetUser возвращает пользователя.
+func (s *Server) GetUser(ctx context.Context, req *reviewerv1.GetUserRequest) (*reviewerv1.User, error) {
+	if req.GetUserId() == "" {
+		return nil, invalidArgument("user_id is required")
+	}
+
+	user, err := s.svc.GetUser(ctx, domain.UserID(req.GetUserId()))
+	if err != nil {
+		return nil, s.toStatus("GetUser", err)
+	}
+
+	return userToProto(user), nil
+}
+
+// SetUserActive включает или выключает пользователя.
+func (s *Server) SetUserActive(ctx context.Context, req *reviewerv1.SetUserActiveRequest) (*reviewerv1.User, error) {
+	if req.GetUserId() == "" {
+		return nil, invalidArgument("user_id is required")
+	}
+
+	user, err := s.svc.SetUserActive(ctx, domain.UserID(req.GetUserId()), req.GetIsActive())
+	if err != nil {
+		return nil, s.toStatus("SetUserActive", err)
+	}
+
+	return userToProto(user), nil
+}
+
+// ListUserReviews возвращает PR'ы, где пользователь назначен ревьювером. Для неизвестного
+// пользователя, как и в HTTP API, возвращается пустой список.
+func (s *Server) ListUserReviews(
+	ctx context.Context,
+	req *reviewerv1.ListUserReviewsRequest,
+) (*reviewerv1.ListUserReviewsResponse, error) {
+	if req.GetUserId() == "" {
+		return nil, invalidArgument("user_id is required")
+	}
+
+	prs, err := s.svc.GetUserReviewPullRequests(ctx, domain.UserID(req.GetUserId()))
+	if err != nil && !errors.Is(err, service.ErrNotFound) {
+		return nil, s.toStatus("ListUserReviews", err)
+	}
+
+	return &reviewerv1.ListUserReviewsResponse{
+		UserId:       req.GetUserId(),
+		PullRequests: pullRequestsToShort(prs),
+	}, nil
+}
+
+// ListUserTeams возвращает команды пользователя, основную первой.
+func (s *Server) ListUserTeams(ctx context.Context, req *reviewerv1.ListUserTeamsRequest) (*reviewerv1.TeamMembershipsResponse, error) {
+	if req.GetUserId() == "" {
+		return nil, invalidArgument("user_id is required")
+	}
+
+	userID := domain.UserID(req.GetUserId())
+
+	memberships, err := s.svc.GetUserTeams(ctx, userID)
+	if err != nil {
+		return nil, s.toStatus("ListUserTeams", err)
+	}
+
+	return membershipsToProto(userID, memberships), nil
+}
@@ -0,0 +1,22 @@
+// Package apikey содержит обработчики API-ключей и аутентификацию запросов по ним.
+package apikey
+
+import "github.com/dixitix/pr-reviewer-service/internal/domain"
+
+// mapKeyToDTO конвертирует API-ключ в HTTP-DTO.
+func mapKeyToDTO(key domain.APIKey) DTO {
+	scopes := make([]string, len(key.Scopes))
+	for i, s := range key.Scopes {
+		scopes[i] = string(s)
+	}
+
+	return DTO{
+		KeyID:      string(key.ID),
+		Name:       key.Name,
+		Prefix:     key.Prefix,
+		Scopes:     scopes,
+		CreatedAt:  key.CreatedAt,
+		LastUsedAt: key.LastUsedAt,
+		RevokedAt:  key.RevokedAt,
+	}
+}
@@ -0,0 +1,31 @@
+// Package apikey содержит обработчики API-ключей и аутентификацию запросов по ним.
+package apikey
+
+import "time"
+
+// DTO представляет API-ключ в HTTP-слое. Значение ключа возвращается только при выпуске.
+type DTO struct {
+	KeyID      string     `json:"key_id"`
+	Name       string     `json:"name"`
+	Prefix     string     `json:"prefix"`
+	Scopes     []string   `json:"scopes"`
+	Key        string     `json:"key,omitempty"`
+	CreatedAt  time.Time  `json:"createdAt"`
+	LastUsedAt *time.Time `json:"lastUsedAt"`
+	RevokedAt  *time.Time `json:"revokedAt"`
+}
+
+// Envelope оборачивает ключ в поле "api_key".
+type Envelope struct {
+	APIKey DTO `json:"api_key"`
+}
+
+// ListResponse описывает ответ на /apiKeys/list.
+type ListResponse struct {
+	APIKeys []DTO `json:"api_keys"`
+}
+
+// RevokeResponse описывает ответ на /apiKeys/revoke.
+type RevokeResponse struct {
+	KeyID string `json:"key_id"`
+}
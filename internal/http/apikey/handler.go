@@ -0,0 +1,190 @@
+// Package apikey содержит обработчики API-ключей и аутентификацию запросов по ним.
+package apikey
+
+import (
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/dixitix/pr-reviewer-service/internal/domain"
+	"github.com/dixitix/pr-reviewer-service/internal/http/httperr"
+	"github.com/dixitix/pr-reviewer-service/internal/service"
+)
+
+// Handler обрабатывает HTTP-запросы управления API-ключами.
+type Handler struct {
+	svc    service.APIKeyService
+	logger *slog.Logger
+}
+
+// NewHandler создаёт обработчик API-ключей.
+func NewHandler(svc service.APIKeyService, logger *slog.Logger) *Handler {
+	return &Handler{
+		svc:    svc,
+		logger: logger,
+	}
+}
+
+// Add обрабатывает выпуск API-ключа.
+func (h *Handler) Add(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		httperr.WriteJSONError(w, http.StatusMethodNotAllowed, httperr.ErrorCodeMethodNotAllowed, "method not allowed", h.logger)
+		return
+	}
+
+	var req CreateRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httperr.WriteJSONError(w, http.StatusBadRequest, httperr.ErrorCodeInvalidJSON, "invalid JSON body", h.logger)
+		return
+	}
+
+	if strings.TrimSpace(req.Name) == "" {
+		httperr.WriteJSONError(w, http.StatusBadRequest, httperr.ErrorCodeValidation, "name is required", h.logger)
+		return
+	}
+
+	if len(req.Scopes) == 0 {
+		httperr.WriteJSONError(w, http.StatusBadRequest, httperr.ErrorCodeValidation, "scopes must not be empty", h.logger)
+		return
+	}
+
+	scopes := make([]domain.APIScope, len(req.Scopes))
+	for i, s := range req.Scopes {
+		scopes[i] = domain.APIScope(s)
+		if !scopes[i].Valid() {
+			httperr.WriteJSONError(w, http.StatusBadRequest, httperr.ErrorCodeValidation, "unknown scope: "+s, h.logger)
+			return
+		}
+	}
+
+	ctx := r.Context()
+
+	if h.logger != nil {
+		h.logger.InfoContext(r.Context(), "handleAPIKeyAdd", slog.String("name", req.Name), slog.Any("scopes", req.Scopes))
+	}
+
+	key, secret, err := h.svc.CreateAPIKey(ctx, req.Name, scopes)
+	if err != nil {
+		if errors.Is(err, service.ErrForbidden) {
+			httperr.WriteJSONError(w, http.StatusForbidden, httperr.ErrorCodeForbidden, "only an admin may create api keys, and only with scopes the admin has", h.logger)
+			return
+		}
+
+		if h.logger != nil {
+			h.logger.ErrorContext(r.Context(), "handleAPIKeyAdd: CreateAPIKey error", slog.Any("error", err))
+		}
+
+		httperr.WriteJSONError(w, http.StatusInternalServerError, httperr.ErrorCodeInternal, "internal server error", h.logger)
+		return
+	}
+
+	dto := mapKeyToDTO(key)
+	dto.Key = secret
+
+	resp := Envelope{
+		APIKey: dto,
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		if h.logger != nil {
+			h.logger.ErrorContext(r.Context(), "handleAPIKeyAdd: failed to write response", slog.Any("error", err))
+		}
+	}
+}
+
+// List обрабатывает получение API-ключей организации.
+func (h *Handler) List(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		httperr.WriteJSONError(w, http.StatusMethodNotAllowed, httperr.ErrorCodeMethodNotAllowed, "method not allowed", h.logger)
+		return
+	}
+
+	keys, err := h.svc.ListAPIKeys(r.Context())
+	if err != nil {
+		if h.logger != nil {
+			h.logger.ErrorContext(r.Context(), "handleAPIKeyList: ListAPIKeys error", slog.Any("error", err))
+		}
+
+		httperr.WriteJSONError(w, http.StatusInternalServerError, httperr.ErrorCodeInternal, "internal server error", h.logger)
+		return
+	}
+
+	resp := ListResponse{
+		APIKeys: make([]DTO, len(keys)),
+	}
+
+	for i, key := range keys {
+		resp.APIKeys[i] = mapKeyToDTO(key)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		if h.logger != nil {
+			h.logger.ErrorContext(r.Context(), "handleAPIKeyList: failed to write response", slog.Any("error", err))
+		}
+	}
+}
+
+// Revoke обрабатывает отзыв API-ключа.
+func (h *Handler) Revoke(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		httperr.WriteJSONError(w, http.StatusMethodNotAllowed, httperr.ErrorCodeMethodNotAllowed, "method not allowed", h.logger)
+		return
+	}
+
+	var req RevokeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httperr.WriteJSONError(w, http.StatusBadRequest, httperr.ErrorCodeInvalidJSON, "invalid JSON body", h.logger)
+		return
+	}
+
+	if req.KeyID == "" {
+		httperr.WriteJSONError(w, http.StatusBadRequest, httperr.ErrorCodeValidation, "key_id is required", h.logger)
+		return
+	}
+
+	ctx := r.Context()
+
+	if h.logger != nil {
+		h.logger.InfoContext(r.Context(), "handleAPIKeyRevoke", slog.String("key_id", req.KeyID))
+	}
+
+	if err := h.svc.RevokeAPIKey(ctx, domain.APIKeyID(req.KeyID)); err != nil {
+		if errors.Is(err, service.ErrForbidden) {
+			httperr.WriteJSONError(w, http.StatusForbidden, httperr.ErrorCodeForbidden, "only an admin may revoke api keys", h.logger)
+			return
+		}
+
+		if errors.Is(err, service.ErrNotFound) {
+			httperr.WriteJSONError(w, http.StatusNotFound, httperr.ErrorCodeNotFound, "api key not found", h.logger)
+			return
+		}
+
+		if h.logger != nil {
+			h.logger.ErrorContext(r.Context(), "handleAPIKeyRevoke: RevokeAPIKey error", slog.Any("error", err))
+		}
+
+		httperr.WriteJSONError(w, http.StatusInternalServerError, httperr.ErrorCodeInternal, "internal server error", h.logger)
+		return
+	}
+
+	resp := RevokeResponse{
+		KeyID: req.KeyID,
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		if h.logger != nil {
+			h.logger.ErrorContext(r.Context(), "handleAPIKeyRevoke: failed to write response", slog.Any("error", err))
+		}
+	}
+}
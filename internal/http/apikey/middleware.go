@@ -0,0 +1,126 @@
+// Package apikey содержит обработчики API-ключей и аутентификацию запросов по ним и по JWT.
+package apikey
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/dixitix/pr-reviewer-service/internal/auth"
+	"github.com/dixitix/pr-reviewer-service/internal/authn"
+	"github.com/dixitix/pr-reviewer-service/internal/domain"
+	"github.com/dixitix/pr-reviewer-service/internal/http/httperr"
+	"github.com/dixitix/pr-reviewer-service/internal/service"
+)
+
+// HeaderAPIKey — альтернативный заголовку Authorization заголовок с API-ключом.
+const HeaderAPIKey = "X-API-Key"
+
+// Authenticator проверяет API-ключи HTTP-запросов и права на маршруты.
+type Authenticator struct {
+	*authn.Authenticator
+
+	logger *slog.Logger
+}
+
+// NewAuthenticator создаёт проверку API-ключей.
+func NewAuthenticator(svc service.APIKeyService, cfg authn.Config, logger *slog.Logger) *Authenticator {
+	return &Authenticator{
+		Authenticator: authn.New(svc, cfg, logger),
+		logger:        logger,
+	}
+}
+
+// Middleware аутентифицирует запрос по ключу из заголовка Authorization: Bearer или X-API-Key
+// (или по JWT в Authorization: Bearer, если задан Tokens) и кладёт личность вызывающего в контекст.
+// Запросы без действующего ключа или токена отклоняются с 401.
+func (a *Authenticator) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !a.Enabled() {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		secret := requestKey(r)
+		if secret == "" {
+			a.unauthorized(w, "api key is required")
+			return
+		}
+
+		id, err := a.Authenticate(r.Context(), secret)
+		if err != nil {
+			if errors.Is(err, auth.ErrInvalidToken) {
+				a.unauthorized(w, "invalid token")
+				return
+			}
+
+			if errors.Is(err, service.ErrUnauthenticated) {
+				a.unauthorized(w, "invalid api key")
+				return
+			}
+
+			httperr.WriteJSONError(w, http.StatusInternalServerError, httperr.ErrorCodeInternal, "internal server error", a.logger)
+
+			return
+		}
+
+		next.ServeHTTP(w, r.WithContext(auth.WithIdentity(r.Context(), id)))
+	})
+}
+
+// Require пропускает запрос, только если у вызывающего есть право scope.
+func (a *Authenticator) Require(scope domain.APIScope, next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !a.Enabled() {
+			next(w, r)
+			return
+		}
+
+		id, ok := auth.FromContext(r.Context())
+		if !ok {
+			a.unauthorized(w, "api key is required")
+			return
+		}
+
+		if err := a.Authorize(id, scope); err != nil {
+			httperr.WriteJSONError(w, http.StatusForbidden, httperr.ErrorCodeInsufficientScope, "api key lacks scope "+string(scope), a.logger)
+			return
+		}
+
+		next(w, r)
+	}
+}
+
+// RequireRoot пропускает запрос, только если он выполнен корневым ключом.
+func (a *Authenticator) RequireRoot(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !a.Enabled() {
+			next(w, r)
+			return
+		}
+
+		id, ok := auth.FromContext(r.Context())
+		if !ok {
+			a.unauthorized(w, "api key is required")
+			return
+		}
+
+		if !id.Root {
+			httperr.WriteJSONError(w, http.StatusForbidden, httperr.ErrorCodeInsufficientScope, "root api key is required", a.logger)
+			return
+		}
+
+		next(w, r)
+	}
+}
+
+// unauthorized отвечает 401 с указанием схемы аутентификации.
+func (a *Authenticator) unauthorized(w http.ResponseWriter, message string) {
+	w.Header().Set("WWW-Authenticate", `Bearer realm="pr-reviewer"`)
+	httperr.WriteJSONError(w, http.StatusUnauthorized, httperr.ErrorCodeUnauthorized, message, a.logger)
+}
+
+// requestKey возвращает API-ключ из заголовков запроса.
+func requestKey(r *http.Request) string {
+	return authn.Secret(r.Header.Get("Authorization"), r.Header.Get(HeaderAPIKey))
+}
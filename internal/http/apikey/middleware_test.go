@@ -0,0 +1,110 @@
+package apikey
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dixitix/pr-reviewer-service/internal/auth"
+	"github.com/dixitix/pr-reviewer-service/internal/authn"
+	"github.com/dixitix/pr-reviewer-service/internal/domain"
+	"github.com/dixitix/pr-reviewer-service/internal/http/httperr"
+)
+
+// newTestTokens возвращает проверку JWT по одному RSA-ключу и функцию выпуска токенов с claims.
+func newTestTokens(t *testing.T) (authn.TokenVerifier, func(claims map[string]any) string) {
+	t.Helper()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("generate rsa key: %v", err)
+	}
+
+	b64 := base64.RawURLEncoding.EncodeToString
+
+	jwks, _ := json.Marshal(map[string]any{"keys": []map[string]string{{
+		"kty": "RSA", "kid": "k1", "alg": "RS256",
+		"n": b64(key.N.Bytes()), "e": b64(big.NewInt(int64(key.E)).Bytes()),
+	}}})
+
+	file := filepath.Join(t.TempDir(), "jwks.json")
+	if err := os.WriteFile(file, jwks, 0o600); err != nil {
+		t.Fatalf("write jwks: %v", err)
+	}
+
+	sign := func(claims map[string]any) string {
+		header, _ := json.Marshal(map[string]string{"alg": "RS256", "kid": "k1", "typ": "JWT"})
+		payload, _ := json.Marshal(claims)
+
+		input := b64(header) + "." + b64(payload)
+		digest := sha256.Sum256([]byte(input))
+
+		sig, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, digest[:])
+		if err != nil {
+			t.Fatalf("sign token: %v", err)
+		}
+
+		return input + "." + b64(sig)
+	}
+
+	return auth.NewJWTVerifier(auth.NewJWKS(auth.JWKSConfig{File: file}, nil), auth.JWTConfig{}), sign
+}
+
+// TestAuthenticator_UnscopedTokenScopes проверяет, что JWT без claim scope открывает маршруты
+// чтения и работы с PR, а маршруты team:admin — только администратору.
+func TestAuthenticator_UnscopedTokenScopes(t *testing.T) {
+	tokens, sign := newTestTokens(t)
+	a := NewAuthenticator(nil, authn.Config{Enabled: true, Tokens: tokens}, nil)
+
+	ok := func(w http.ResponseWriter, _ *http.Request) { w.WriteHeader(http.StatusNoContent) }
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/read", a.Require(domain.APIScopeRead, ok))
+	mux.HandleFunc("/pr", a.Require(domain.APIScopePRWrite, ok))
+	mux.HandleFunc("/admin", a.Require(domain.APIScopeTeamAdmin, ok))
+
+	handler := a.Middleware(mux)
+
+	token := func(roles ...string) string {
+		return sign(map[string]any{"sub": "u1", "exp": time.Now().Add(time.Hour).Unix(), "roles": roles})
+	}
+
+	cases := []struct {
+		name, path, token string
+		want              int
+	}{
+		{"engineer reads", "/read", token("engineer"), http.StatusNoContent},
+		{"engineer writes pr", "/pr", token("engineer"), http.StatusNoContent},
+		{"engineer is not team admin", "/admin", token("engineer"), http.StatusForbidden},
+		{"no roles is not team admin", "/admin", token(), http.StatusForbidden},
+		{"admin is team admin", "/admin", token("admin"), http.StatusNoContent},
+	}
+
+	for _, tc := range cases {
+		req := httptest.NewRequestWithContext(context.Background(), http.MethodGet, tc.path, nil)
+		req.Header.Set("Authorization", "Bearer "+tc.token)
+
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+
+		if rec.Code != tc.want {
+			t.Fatalf("%s: expected %d, got %d: %s", tc.name, tc.want, rec.Code, rec.Body.String())
+		}
+
+		if tc.want == http.StatusForbidden && !strings.Contains(rec.Body.String(), httperr.ErrorCodeInsufficientScope) {
+			t.Fatalf("%s: expected %s, got %s", tc.name, httperr.ErrorCodeInsufficientScope, rec.Body.String())
+		}
+	}
+}
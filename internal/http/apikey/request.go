@@ -0,0 +1,13 @@
+// Package apikey содержит обработчики API-ключей и аутентификацию запросов по ним.
+package apikey
+
+// CreateRequest описывает тело запроса /apiKeys/add.
+type CreateRequest struct {
+	Name   string   `json:"name"`
+	Scopes []string `json:"scopes"`
+}
+
+// RevokeRequest описывает тело запроса /apiKeys/revoke.
+type RevokeRequest struct {
+	KeyID string `json:"key_id"`
+}
@@ -0,0 +1,459 @@
+package httpserver
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dixitix/pr-reviewer-service/api"
+	"github.com/dixitix/pr-reviewer-service/internal/domain"
+	"github.com/dixitix/pr-reviewer-service/internal/http/httperr"
+	"github.com/dixitix/pr-reviewer-service/internal/http/openapi"
+	"github.com/dixitix/pr-reviewer-service/internal/service"
+)
+
+// contractService отвечает на все вызовы HTTP-обработчиков заполненными объектами,
+// чтобы в ответах были все поля, которые могут отдать обработчики.
+type contractService struct {
+	service.Service
+}
+
+var contractTime = time.Date(2025, 11, 1, 12, 0, 0, 0, time.UTC)
+
+func contractPullRequest(id domain.PullRequestID) domain.PullRequest {
+	created := contractTime
+	merged := contractTime.Add(time.Hour)
+
+	return domain.PullRequest{
+		ID:                id,
+		Name:              "Add search",
+		AuthorID:          "u1",
+		Status:            domain.PullRequestStatusMerged,
+		AssignedReviewers: []domain.UserID{"u2", "u3"},
+		CreatedAt:         &created,
+		MergedAt:          &merged,
+		CreatedBy:         "u1",
+		MergedBy:          "u1",
+		ReassignedBy:      domain.ActorSystem,
+		Version:           3,
+	}
+}
+
+func contractTeam(name domain.TeamName) domain.Team {
+	return domain.Team{Name: name, Parent: "platform", FallbackToParent: true, Leads: []domain.UserID{"u1"}, Version: 2}
+}
+
+func contractMembers(name domain.TeamName) []domain.User {
+	return []domain.User{
+		{ID: "u1", Username: "Alice", TeamName: name, IsActive: true},
+		{ID: "u2", Username: "Bob", TeamName: "platform", IsActive: false},
+	}
+}
+
+func contractMemberships(userID domain.UserID) []domain.TeamMembership {
+	return []domain.TeamMembership{
+		{TeamName: "backend", UserID: userID, IsPrimary: true},
+		{TeamName: "frontend", UserID: userID},
+	}
+}
+
+func (contractService) CreateOrganization(_ context.Context, id domain.OrganizationID, name string) (domain.Organization, error) {
+	return domain.Organization{ID: id, Name: name, CreatedAt: contractTime}, nil
+}
+
+func (contractService) OrganizationExists(context.Context, domain.OrganizationID) (bool, error) {
+	return true, nil
+}
+
+func (contractService) CreateAPIKey(_ context.Context, name string, scopes []domain.APIScope) (domain.APIKey, string, error) {
+	return domain.APIKey{ID: "k1", OrganizationID: "default", Name: name, Prefix: "prk_abcd", Scopes: scopes, CreatedAt: contractTime}, "prk_abcd_secret", nil
+}
+
+func (contractService) ListAPIKeys(context.Context) ([]domain.APIKey, error) {
+	used := contractTime
+
+	return []domain.APIKey{{
+		ID:             "k1",
+		OrganizationID: "default",
+		Name:           "ci",
+		Prefix:         "prk_abcd",
+		Scopes:         []domain.APIScope{domain.APIScopeRead},
+		CreatedAt:      contractTime,
+		LastUsedAt:     &used,
+		RevokedAt:      &used,
+	}}, nil
+}
+
+func (contractService) RevokeAPIKey(context.Context, domain.APIKeyID) error {
+	return nil
+}
+
+func (contractService) CreateTeam(context.Context, domain.Team, []domain.User) error {
+	return nil
+}
+
+func (contractService) SetTeamParent(_ context.Context, name, _ domain.TeamName, _ bool) (domain.Team, error) {
+	return contractTeam(name), nil
+}
+
+func (contractService) ListTeamTreeMembers(_ context.Context, name domain.TeamName) ([]domain.User, error) {
+	return contractMembers(name), nil
+}
+
+func (contractService) SetTeamLeads(_ context.Context, name domain.TeamName, _ []domain.UserID) (domain.Team, error) {
+	return contractTeam(name), nil
+}
+
+func (contractService) AddTeamMember(_ context.Context, _ domain.TeamName, userID domain.UserID, _ bool) ([]domain.TeamMembership, error) {
+	return contractMemberships(userID), nil
+}
+
+func (contractService) RemoveTeamMember(_ context.Context, _ domain.TeamName, userID domain.UserID) ([]domain.TeamMembership, error) {
+	return contractMemberships(userID)[:1], nil
+}
+
+func (contractService) GetTeam(_ context.Context, name domain.TeamName) (domain.Team, []domain.User, error) {
+	return contractTeam(name), contractMembers(name), nil
+}
+
+func (contractService) ListTeams(context.Context) ([]domain.Team, error) {
+	return []domain.Team{contractTeam("backend"), {Name: "platform"}}, nil
+}
+
+func (contractService) GetReviewPolicy(_ context.Context, name domain.TeamName) (domain.ReviewPolicy, error) {
+	return domain.ReviewPolicy{TeamName: name, ReminderAfter: time.Hour, EscalateAfter: 4 * time.Hour, Action: domain.EscalationActionReassign}, nil
+}
+
+func (contractService) SetReviewPolicy(context.Context, domain.ReviewPolicy) error {
+	return nil
+}
+
+func (contractService) GetUser(_ context.Context, userID domain.UserID) (domain.User, error) {
+	return domain.User{ID: userID, Username: "Alice", TeamName: "backend", IsActive: true}, nil
+}
+
+func (contractService) SetUserActive(_ context.Context, userID domain.UserID, isActive bool) (domain.User, error) {
+	return domain.User{ID: userID, Username: "Alice", TeamName: "backend", IsActive: isActive}, nil
+}
+
+func (contractService) GetUserReviewPullRequests(context.Context, domain.UserID) ([]domain.PullRequest, error) {
+	return []domain.PullRequest{contractPullRequest("pr-1")}, nil
+}
+
+func (contractService) GetUserTeams(_ context.Context, userID domain.UserID) ([]domain.TeamMembership, error) {
+	return contractMemberships(userID), nil
+}
+
+func (contractService) CreatePullRequest(_ context.Context, id domain.PullRequestID, _ string, _ domain.UserID) (domain.PullRequest, error) {
+	return contractPullRequest(id), nil
+}
+
+func (contractService) MergePullRequest(_ context.Context, id domain.PullRequestID, _ service.MergeOptions) (domain.PullRequest, error) {
+	return contractPullRequest(id), nil
+}
+
+func (contractService) ReassignReviewer(_ context.Context, id domain.PullRequestID, _ domain.UserID) (domain.PullRequest, domain.UserID, error) {
+	return contractPullRequest(id), "u4", nil
+}
+
+func (contractService) GetPullRequest(_ context.Context, id domain.PullRequestID) (domain.PullRequest, error) {
+	return contractPullRequest(id), nil
+}
+
+func (contractService) GetPullRequestHistory(_ context.Context, id domain.PullRequestID) ([]domain.Event, error) {
+	waiting := contractTime
+
+	return []domain.Event{
+		{Type: domain.EventReviewersAssigned, PullRequest: contractPullRequest(id), AddedReviewers: []domain.UserID{"u2"}, Actor: "u1", OccurredAt: contractTime},
+		{Type: domain.EventReviewEscalated, PullRequest: contractPullRequest(id), Reviewer: "u2", WaitingSince: &waiting, Reason: "sla", Actor: domain.ActorSystem, OccurredAt: contractTime},
+	}, nil
+}
+
+func (contractService) ListEvents(context.Context, service.EventFilter) ([]domain.Event, error) {
+	return nil, nil
+}
+
+func (contractService) LastEventSequence(context.Context) (int64, error) {
+	return 0, nil
+}
+
+func (contractService) GetAssignmentsByUser(context.Context) (map[domain.UserID]int, error) {
+	return map[domain.UserID]int{"u1": 2}, nil
+}
+
+func (contractService) GetAssignmentsByPullRequest(context.Context) (map[domain.PullRequestID]int, error) {
+	return map[domain.PullRequestID]int{"pr-1": 2}, nil
+}
+
+func (contractService) GetAssignmentsByTeam(context.Context) ([]domain.TeamAssignmentStat, error) {
+	return []domain.TeamAssignmentStat{{TeamName: "backend", Parent: "platform", Assignments: 1, TotalAssignments: 3}}, nil
+}
+
+func (contractService) CountOpenReviewsByTeam(context.Context) (map[domain.OrganizationID]map[domain.TeamName]int, error) {
+	return map[domain.OrganizationID]map[domain.TeamName]int{"default": {"backend": 2}}, nil
+}
+
+func (contractService) CreateWebhookSubscription(_ context.Context, url string, events []domain.EventType, _ string) (domain.WebhookSubscription, error) {
+	return domain.WebhookSubscription{ID: "s1", URL: url, Events: events, Secret: "secret", CreatedAt: contractTime}, nil
+}
+
+func (contractService) ListWebhookSubscriptions(context.Context) ([]domain.WebhookSubscription, error) {
+	return []domain.WebhookSubscription{{ID: "s1", URL: "https://example.com/hook", Events: []domain.EventType{domain.EventPullRequestMerged}, CreatedAt: contractTime}}, nil
+}
+
+func (contractService) DeleteWebhookSubscription(context.Context, domain.WebhookSubscriptionID) error {
+	return nil
+}
+
+func (contractService) ListFailedWebhookDeliveries(context.Context, *domain.WebhookSubscriptionID, int) ([]domain.WebhookDelivery, error) {
+	return []domain.WebhookDelivery{{
+		ID:             "d1",
+		SubscriptionID: "s1",
+		EventType:      domain.EventPullRequestMerged,
+		Payload:        []byte(`{"event":"pull_request.merged"}`),
+		Attempts:       5,
+		LastError:      "timeout",
+		LastStatusCode: 502,
+		FailedAt:       contractTime,
+	}}, nil
+}
+
+func (contractService) GetNotificationPreferences(_ context.Context, userID domain.UserID) (domain.NotificationPreferences, error) {
+	return domain.NotificationPreferences{UserID: userID, ChatEnabled: true, Email: "alice@example.com", EmailMode: domain.EmailModeDigest}, nil
+}
+
+func (contractService) UpdateNotificationPreferences(
+	_ context.Context,
+	userID domain.UserID,
+	_ service.NotificationPreferencesUpdate,
+) (domain.NotificationPreferences, error) {
+	return domain.NotificationPreferences{UserID: userID, ChatEnabled: true, Email: "alice@example.com", EmailMode: domain.EmailModeImmediate}, nil
+}
+
+func (contractService) GetTeamChatChannel(_ context.Context, name domain.TeamName) (domain.TeamChatChannel, error) {
+	return domain.TeamChatChannel{TeamName: name, WebhookURL: "https://hooks.slack.com/services/T/B/X", Channel: "#backend"}, nil
+}
+
+func (contractService) SetTeamChatChannel(context.Context, domain.TeamChatChannel) error {
+	return nil
+}
+
+func (contractService) DeleteTeamChatChannel(context.Context, domain.TeamName) error {
+	return nil
+}
+
+// contractCase — запрос к операции спецификации и ожидаемый статус ответа.
+type contractCase struct {
+	op     string
+	path   string
+	body   string
+	status int
+}
+
+// contractCases покрывают каждую операцию api/openapi.yml запросом, корректным по спецификации.
+var contractCases = []contractCase{
+	{"POST /organizations/add", "/organizations/add", `{"organization_id":"acme","name":"Acme"}`, http.StatusCreated},
+	{"POST /apiKeys/add", "/apiKeys/add", `{"name":"ci","scopes":["read","pr:write"]}`, http.StatusCreated},
+	{"GET /apiKeys/list", "/apiKeys/list", "", http.StatusOK},
+	{"POST /apiKeys/revoke", "/apiKeys/revoke", `{"key_id":"k1"}`, http.StatusOK},
+	{"POST /team/add", "/team/add", `{"team_name":"backend","members":[{"user_id":"u1","username":"Alice","is_active":true}],"parent_name":"platform","fallback_to_parent":true,"leads":["u1"]}`, http.StatusCreated},
+	{"GET /team/get", "/team/get?team_name=backend", "", http.StatusOK},
+	{"POST /team/setLeads", "/team/setLeads", `{"team_name":"backend","leads":["u1"]}`, http.StatusOK},
+	{"POST /team/setParent", "/team/setParent", `{"team_name":"backend","parent_name":"platform","fallback_to_parent":true}`, http.StatusOK},
+	{"GET /team/getAllMembers", "/team/getAllMembers?team_name=backend", "", http.StatusOK},
+	{"POST /team/addMember", "/team/addMember", `{"team_name":"frontend","user_id":"u1","is_primary":false}`, http.StatusOK},
+	{"POST /team/removeMember", "/team/removeMember", `{"team_name":"frontend","user_id":"u1"}`, http.StatusOK},
+	{"GET /team/getReviewPolicy", "/team/getReviewPolicy?team_name=backend", "", http.StatusOK},
+	{"POST /team/setReviewPolicy", "/team/setReviewPolicy", `{"team_name":"backend","reminder_after_minutes":60,"escalate_after_minutes":240,"escalation_action":"reassign"}`, http.StatusOK},
+	{"POST /users/setIsActive", "/users/setIsActive", `{"user_id":"u1","is_active":false}`, http.StatusOK},
+	{"POST /pullRequest/create", "/pullRequest/create", `{"pull_request_id":"pr-1","pull_request_name":"Add search","author_id":"u1"}`, http.StatusCreated},
+	{"POST /pullRequest/merge", "/pullRequest/merge", `{"pull_request_id":"pr-1","force":true}`, http.StatusOK},
+	{"POST /pullRequest/reassign", "/pullRequest/reassign", `{"pull_request_id":"pr-1","old_user_id":"u2"}`, http.StatusOK},
+	{"GET /pullRequest/get", "/pullRequest/get?pull_request_id=pr-1", "", http.StatusOK},
+	{"GET /pullRequest/history", "/pullRequest/history?pull_request_id=pr-1", "", http.StatusOK},
+	{"GET /users/getReview", "/users/getReview?user_id=u2", "", http.StatusOK},
+	{"GET /users/getTeams", "/users/getTeams?user_id=u1", "", http.StatusOK},
+	{"GET /stats/byUser", "/stats/byUser", "", http.StatusOK},
+	{"GET /stats/byPullRequest", "/stats/byPullRequest", "", http.StatusOK},
+	{"GET /stats/byTeam", "/stats/byTeam", "", http.StatusOK},
+	{"GET /events/stream", "/events/stream?team_name=backend", "", http.StatusOK},
+	{"POST /webhooks/add", "/webhooks/add", `{"url":"https://example.com/hook","events":["pull_request.merged"],"secret":"s"}`, http.StatusCreated},
+	{"GET /webhooks/list", "/webhooks/list", "", http.StatusOK},
+	{"POST /webhooks/remove", "/webhooks/remove", `{"subscription_id":"s1"}`, http.StatusOK},
+	{"GET /webhooks/deliveries", "/webhooks/deliveries?subscription_id=s1&limit=10", "", http.StatusOK},
+	{"GET /notifications/getPreferences", "/notifications/getPreferences?user_id=u1", "", http.StatusOK},
+	{"POST /notifications/setPreferences", "/notifications/setPreferences", `{"user_id":"u1","chat_enabled":true,"email":"alice@example.com","email_mode":"immediate"}`, http.StatusOK},
+	{"GET /notifications/getTeamChannel", "/notifications/getTeamChannel?team_name=backend", "", http.StatusOK},
+	{"POST /notifications/setTeamChannel", "/notifications/setTeamChannel", `{"team_name":"backend","webhook_url":"https://hooks.slack.com/services/T/B/X","channel":"#backend"}`, http.StatusOK},
+	{"POST /notifications/removeTeamChannel", "/notifications/removeTeamChannel", `{"team_name":"backend"}`, http.StatusOK},
+	{"GET /v2/teams", "/v2/teams", "", http.StatusOK},
+	{"POST /v2/teams", "/v2/teams", `{"team_name":"backend","members":[{"user_id":"u1","username":"Alice","is_active":true}]}`, http.StatusCreated},
+	{"GET /v2/teams/{name}", "/v2/teams/backend", "", http.StatusOK},
+	{"PUT /v2/teams/{name}/leads", "/v2/teams/backend/leads", `{"leads":["u1"]}`, http.StatusOK},
+	{"PUT /v2/teams/{name}/parent", "/v2/teams/backend/parent", `{"parent_name":"platform","fallback_to_parent":false}`, http.StatusOK},
+	{"GET /v2/teams/{name}/all-members", "/v2/teams/backend/all-members", "", http.StatusOK},
+	{"POST /v2/teams/{name}/members", "/v2/teams/frontend/members", `{"user_id":"u1","is_primary":false}`, http.StatusOK},
+	{"DELETE /v2/teams/{name}/members/{userId}", "/v2/teams/frontend/members/u1", "", http.StatusOK},
+	{"GET /v2/teams/{name}/review-policy", "/v2/teams/backend/review-policy", "", http.StatusOK},
+	{"PUT /v2/teams/{name}/review-policy", "/v2/teams/backend/review-policy", `{"reminder_after_minutes":60,"escalate_after_minutes":240,"escalation_action":"escalate"}`, http.StatusOK},
+	{"GET /v2/users/{id}", "/v2/users/u1", "", http.StatusOK},
+	{"PATCH /v2/users/{id}", "/v2/users/u1", `{"is_active":true}`, http.StatusOK},
+	{"GET /v2/users/{id}/reviews", "/v2/users/u2/reviews", "", http.StatusOK},
+	{"GET /v2/users/{id}/teams", "/v2/users/u1/teams", "", http.StatusOK},
+	{"POST /v2/pull-requests", "/v2/pull-requests", `{"pull_request_id":"pr-1","pull_request_name":"Add search","author_id":"u1"}`, http.StatusCreated},
+	{"GET /v2/pull-requests/{id}", "/v2/pull-requests/pr-1", "", http.StatusOK},
+	{"POST /v2/pull-requests/{id}/merge", "/v2/pull-requests/pr-1/merge", "", http.StatusOK},
+	{"POST /v2/pull-requests/{id}/reassign", "/v2/pull-requests/pr-1/reassign", `{"old_user_id":"u2"}`, http.StatusOK},
+	{"GET /v2/pull-requests/{id}/history", "/v2/pull-requests/pr-1/history", "", http.StatusOK},
+	{"GET /v2/stats/users", "/v2/stats/users", "", http.StatusOK},
+	{"GET /v2/stats/pull-requests", "/v2/stats/pull-requests", "", http.StatusOK},
+	{"GET /v2/stats/teams", "/v2/stats/teams", "", http.StatusOK},
+}
+
+// newContractMux собирает маршруты с проверкой запросов и ответов по спецификации.
+// Расхождения ответов со спецификацией проваливают тест.
+func newContractMux(t *testing.T) *http.ServeMux {
+	t.Helper()
+
+	doc, err := openapi.Load(api.OpenAPI)
+	if err != nil {
+		t.Fatalf("load openapi: %v", err)
+	}
+
+	validation := openapi.New(doc, openapi.Config{
+		ValidateResponses: true,
+		OnInvalidResponse: func(r *http.Request, status int, errs []httperr.FieldError) {
+			t.Errorf("%s %s: response %d does not match openapi.yml: %+v", r.Method, r.URL.Path, status, errs)
+		},
+	}, nil)
+
+	h := NewHandler(contractService{}, Config{DefaultOrganization: "default", Validation: validation}, nil)
+
+	mux := http.NewServeMux()
+	h.RegisterRoutes(mux)
+
+	return mux
+}
+
+// TestHandlers_MatchOpenAPI проверяет, что обработчики принимают запросы, корректные по api/openapi.yml,
+// и отвечают по ней, а у каждой операции спецификации есть обработчик.
+func TestHandlers_MatchOpenAPI(t *testing.T) {
+	mux := newContractMux(t)
+
+	doc, err := openapi.Load(api.OpenAPI)
+	if err != nil {
+		t.Fatalf("load openapi: %v", err)
+	}
+
+	covered := make(map[string]bool, len(contractCases))
+
+	for _, tc := range contractCases {
+		covered[tc.op] = true
+
+		method, _, _ := strings.Cut(tc.op, " ")
+
+		// Поток событий не завершается сам, поэтому запрос ограничен по времени.
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+
+		req := httptest.NewRequestWithContext(ctx, method, tc.path, strings.NewReader(tc.body))
+		rec := httptest.NewRecorder()
+
+		mux.ServeHTTP(rec, req)
+		cancel()
+
+		if rec.Code != tc.status {
+			t.Errorf("%s: expected %d, got %d: %s", tc.op, tc.status, rec.Code, rec.Body.String())
+		}
+	}
+
+	for op := range doc.Operations() {
+		if !covered[op] {
+			t.Errorf("operation %s from openapi.yml has no contract case", op)
+		}
+	}
+}
+
+// TestHandlers_RejectRequestsOutsideSpec проверяет, что запросы, не соответствующие спецификации,
+// отклоняются с ошибками по полям.
+func TestHandlers_RejectRequestsOutsideSpec(t *testing.T) {
+	mux := newContractMux(t)
+
+	cases := []struct {
+		name  string
+		path  string
+		body  string
+		field string
+	}{
+		{"missing field", "/pullRequest/create", `{"pull_request_id":"pr-1","author_id":"u1"}`, "pull_request_name"},
+		{"empty field", "/pullRequest/create", `{"pull_request_id":"","pull_request_name":"x","author_id":"u1"}`, "pull_request_id"},
+		{"unknown field", "/pullRequest/merge", `{"pull_request_id":"pr-1","forse":true}`, "forse"},
+		{"wrong type", "/users/setIsActive", `{"user_id":"u1","is_active":"yes"}`, "is_active"},
+		{"nested field", "/team/add", `{"team_name":"backend","members":[{"user_id":"u1","is_active":true}]}`, "members[0].username"},
+		{"enum", "/webhooks/add", `{"url":"https://example.com/hook","events":["pull_request.closed"]}`, "events[0]"},
+		{"pattern", "/webhooks/add", `{"url":"ftp://example.com/hook"}`, "url"},
+	}
+
+	for _, tc := range cases {
+		req := httptest.NewRequest(http.MethodPost, tc.path, strings.NewReader(tc.body))
+		rec := httptest.NewRecorder()
+
+		mux.ServeHTTP(rec, req)
+
+		if rec.Code != http.StatusBadRequest || !strings.Contains(rec.Body.String(), `"field":"`+tc.field+`"`) {
+			t.Errorf("%s: expected 400 with field %s, got %d: %s", tc.name, tc.field, rec.Code, rec.Body.String())
+		}
+	}
+}
+
+// TestHandlers_ProblemDetails проверяет, что клиент с application/problem+json в Accept получает
+// ошибку по схеме Problem, а остальные клиенты — ErrorResponse.
+func TestHandlers_ProblemDetails(t *testing.T) {
+	mux := newContractMux(t)
+
+	doc, err := openapi.Load(api.OpenAPI)
+	if err != nil {
+		t.Fatalf("load openapi: %v", err)
+	}
+
+	body := `{"team_name":"backend","members":[{"user_id":"u1","is_active":true}]}`
+
+	req := httptest.NewRequest(http.MethodPost, "/team/add", strings.NewReader(body))
+	req.Header.Set("Accept", "application/problem+json")
+	rec := httptest.NewRecorder()
+
+	mux.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusBadRequest || rec.Header().Get("Content-Type") != "application/problem+json" {
+		t.Fatalf("expected 400 application/problem+json, got %d %s", rec.Code, rec.Header().Get("Content-Type"))
+	}
+
+	dec := json.NewDecoder(rec.Body)
+	dec.UseNumber()
+
+	var v any
+	if err := dec.Decode(&v); err != nil {
+		t.Fatalf("decode problem: %v", err)
+	}
+
+	if errs := doc.Components.Schemas["Problem"].Validate(v, ""); len(errs) > 0 {
+		t.Fatalf("problem does not match openapi.yml: %+v", errs)
+	}
+
+	if fields, _ := v.(map[string]any)["errors"].([]any); len(fields) != 1 {
+		t.Fatalf("expected one field error, got %v", v)
+	}
+
+	req = httptest.NewRequest(http.MethodPost, "/team/add", strings.NewReader(body))
+	req.Header.Set("Accept", "application/json")
+	rec = httptest.NewRecorder()
+
+	mux.ServeHTTP(rec, req)
+
+	if rec.Header().Get("Content-Type") != "application/json" || !strings.Contains(rec.Body.String(), `"code":"VALIDATION_ERROR"`) {
+		t.Fatalf("expected legacy ErrorResponse, got %s: %s", rec.Header().Get("Content-Type"), rec.Body.String())
+	}
+}
@@ -0,0 +1,57 @@
+// Package etag содержит ETag ресурсов с версией и разбор условных заголовков If-Match и If-None-Match.
+package etag
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/dixitix/pr-reviewer-service/internal/precondition"
+)
+
+// Format возвращает ETag версии ресурса.
+func Format(version int64) string {
+	return `"` + strconv.FormatInt(version, 10) + `"`
+}
+
+// Set выставляет заголовок ETag ответа.
+func Set(w http.ResponseWriter, version int64) {
+	w.Header().Set("ETag", Format(version))
+}
+
+// NotModified сообщает, совпадает ли версия с одним из ETag в If-None-Match. Слабые ETag
+// сравниваются без учёта W/, "*" совпадает с любой версией.
+func NotModified(r *http.Request, version int64) bool {
+	header := r.Header.Get("If-None-Match")
+	if header == "" {
+		return false
+	}
+
+	current := Format(version)
+
+	for _, tag := range strings.Split(header, ",") {
+		tag = strings.TrimPrefix(strings.TrimSpace(tag), "W/")
+		if tag == "*" || tag == current {
+			return true
+		}
+	}
+
+	return false
+}
+
+// IfMatch возвращает контекст запроса, в котором изменение допускается только для версии из If-Match.
+// "*" и отсутствие заголовка условий не задают. Нераспознанный ETag не совпадает ни с одной версией.
+func IfMatch(r *http.Request) context.Context {
+	header := strings.TrimSpace(r.Header.Get("If-Match"))
+	if header == "" || header == "*" {
+		return r.Context()
+	}
+
+	version, err := strconv.ParseInt(strings.Trim(header, `"`), 10, 64)
+	if err != nil || !strings.HasPrefix(header, `"`) || !strings.HasSuffix(header, `"`) {
+		version = -1
+	}
+
+	return precondition.WithVersion(r.Context(), version)
+}
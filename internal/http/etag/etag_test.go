@@ -0,0 +1,61 @@
+package etag
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/dixitix/pr-reviewer-service/internal/precondition"
+)
+
+// TestNotModified проверяет сравнение версии с If-None-Match.
+func TestNotModified(t *testing.T) {
+	tests := []struct {
+		header string
+		want   bool
+	}{
+		{header: "", want: false},
+		{header: `"3"`, want: true},
+		{header: `W/"3"`, want: true},
+		{header: `"1", "3"`, want: true},
+		{header: `"4"`, want: false},
+		{header: "*", want: true},
+	}
+
+	for _, tt := range tests {
+		r := httptest.NewRequest("GET", "/team/get", nil)
+		if tt.header != "" {
+			r.Header.Set("If-None-Match", tt.header)
+		}
+
+		if got := NotModified(r, 3); got != tt.want {
+			t.Errorf("NotModified(%q) = %v, want %v", tt.header, got, tt.want)
+		}
+	}
+}
+
+// TestIfMatch проверяет, что If-Match задаёт ожидаемую версию, а "*" и пустой заголовок — нет.
+func TestIfMatch(t *testing.T) {
+	tests := []struct {
+		header string
+		want   int64
+		ok     bool
+	}{
+		{header: "", ok: false},
+		{header: "*", ok: false},
+		{header: `"7"`, want: 7, ok: true},
+		{header: `W/"7"`, want: -1, ok: true},
+		{header: "7", want: -1, ok: true},
+	}
+
+	for _, tt := range tests {
+		r := httptest.NewRequest("POST", "/pullRequest/merge", nil)
+		if tt.header != "" {
+			r.Header.Set("If-Match", tt.header)
+		}
+
+		got, ok := precondition.Version(IfMatch(r))
+		if ok != tt.ok || got != tt.want {
+			t.Errorf("IfMatch(%q) = %d, %v; want %d, %v", tt.header, got, ok, tt.want, tt.ok)
+		}
+	}
+}
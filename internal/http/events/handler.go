@@ -0,0 +1,240 @@
+// Package events содержит обработчик потока событий назначений (server-sent events).
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/dixitix/pr-reviewer-service/internal/domain"
+	"github.com/dixitix/pr-reviewer-service/internal/event"
+	"github.com/dixitix/pr-reviewer-service/internal/http/httperr"
+	"github.com/dixitix/pr-reviewer-service/internal/service"
+)
+
+const (
+	// streamBatchSize — сколько событий читается из истории за один запрос.
+	streamBatchSize = 100
+
+	defaultPollInterval      = time.Second
+	defaultHeartbeatInterval = 15 * time.Second
+)
+
+// Config описывает настройки потока событий.
+type Config struct {
+	// PollInterval — как часто проверяются новые события.
+	PollInterval time.Duration
+	// HeartbeatInterval — как часто в простаивающий поток пишется комментарий,
+	// чтобы прокси и балансировщики не закрывали соединение.
+	HeartbeatInterval time.Duration
+}
+
+// Handler обрабатывает подписки на поток событий.
+type Handler struct {
+	svc    service.EventService
+	cfg    Config
+	logger *slog.Logger
+
+	stop     chan struct{}
+	stopOnce sync.Once
+}
+
+// NewHandler создаёт новый обработчик потока событий.
+func NewHandler(svc service.EventService, cfg Config, logger *slog.Logger) *Handler {
+	if cfg.PollInterval <= 0 {
+		cfg.PollInterval = defaultPollInterval
+	}
+
+	if cfg.HeartbeatInterval <= 0 {
+		cfg.HeartbeatInterval = defaultHeartbeatInterval
+	}
+
+	return &Handler{
+		svc:    svc,
+		cfg:    cfg,
+		logger: logger,
+		stop:   make(chan struct{}),
+	}
+}
+
+// Shutdown завершает все открытые потоки. Клиенты переподключатся с Last-Event-ID.
+func (h *Handler) Shutdown() {
+	h.stopOnce.Do(func() {
+		close(h.stop)
+	})
+}
+
+// Stream отдаёт события создания, переназначения и merge PR в формате text/event-stream.
+// Поток возобновляется после события из заголовка Last-Event-ID или параметра last_event_id;
+// без них клиент получает только события, записанные после подключения.
+func (h *Handler) Stream(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		httperr.WriteJSONError(w, http.StatusMethodNotAllowed, httperr.ErrorCodeMethodNotAllowed, "method not allowed", h.logger)
+		return
+	}
+
+	ctx := r.Context()
+	query := r.URL.Query()
+
+	filter := service.EventFilter{
+		TeamName: domain.TeamName(query.Get("team_name")),
+		UserID:   domain.UserID(query.Get("user_id")),
+		Limit:    streamBatchSize,
+	}
+
+	after, resumed, err := lastEventID(r)
+	if err != nil {
+		httperr.WriteJSONError(w, http.StatusBadRequest, httperr.ErrorCodeValidation, "Last-Event-ID must be a non-negative integer", h.logger)
+		return
+	}
+
+	if !resumed {
+		after, err = h.svc.LastEventSequence(ctx)
+		if err != nil {
+			if h.logger != nil {
+				h.logger.ErrorContext(r.Context(), "handleEventsStream: LastEventSequence error", slog.Any("error", err))
+			}
+			httperr.WriteJSONError(w, http.StatusInternalServerError, httperr.ErrorCodeInternal, "internal server error", h.logger)
+			return
+		}
+	}
+
+	filter.AfterSequence = after
+
+	// Первая выборка делается до заголовков ответа, чтобы ошибки фильтра вернулись обычным JSON.
+	events, err := h.svc.ListEvents(ctx, filter)
+	if err != nil {
+		if errors.Is(err, service.ErrNotFound) {
+			httperr.WriteJSONError(w, http.StatusNotFound, httperr.ErrorCodeNotFound, "team or user not found", h.logger)
+			return
+		}
+
+		if h.logger != nil {
+			h.logger.ErrorContext(r.Context(), "handleEventsStream: ListEvents error", slog.Any("error", err))
+		}
+		httperr.WriteJSONError(w, http.StatusInternalServerError, httperr.ErrorCodeInternal, "internal server error", h.logger)
+		return
+	}
+
+	rc := http.NewResponseController(w)
+
+	// Поток живёт дольше WriteTimeout сервера.
+	if err := rc.SetWriteDeadline(time.Time{}); err != nil && !errors.Is(err, http.ErrNotSupported) {
+		if h.logger != nil {
+			h.logger.ErrorContext(r.Context(), "handleEventsStream: failed to reset write deadline", slog.Any("error", err))
+		}
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+
+	poll := time.NewTicker(h.cfg.PollInterval)
+	defer poll.Stop()
+
+	heartbeat := time.NewTicker(h.cfg.HeartbeatInterval)
+	defer heartbeat.Stop()
+
+	for {
+		for {
+			for _, e := range events {
+				if err := writeEvent(w, e); err != nil {
+					if h.logger != nil {
+						h.logger.WarnContext(r.Context(), "handleEventsStream: failed to write event", slog.Any("error", err))
+					}
+					return
+				}
+
+				filter.AfterSequence = e.Sequence
+			}
+
+			if len(events) < streamBatchSize {
+				break
+			}
+
+			if events, err = h.svc.ListEvents(ctx, filter); err != nil {
+				h.logListError(ctx, err)
+				return
+			}
+		}
+
+		if err := rc.Flush(); err != nil {
+			if h.logger != nil {
+				h.logger.WarnContext(r.Context(), "handleEventsStream: failed to flush", slog.Any("error", err))
+			}
+			return
+		}
+
+		events = nil
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-h.stop:
+			return
+		case <-heartbeat.C:
+			if _, err := io.WriteString(w, ": heartbeat\n\n"); err != nil {
+				return
+			}
+		case <-poll.C:
+			if events, err = h.svc.ListEvents(ctx, filter); err != nil {
+				h.logListError(ctx, err)
+				return
+			}
+		}
+	}
+}
+
+// logListError логирует ошибку чтения событий в открытом потоке. Отмена запроса не логируется:
+// клиент отключился сам.
+func (h *Handler) logListError(ctx context.Context, err error) {
+	if h.logger == nil || errors.Is(err, context.Canceled) {
+		return
+	}
+
+	h.logger.ErrorContext(ctx, "handleEventsStream: ListEvents error", slog.Any("error", err))
+}
+
+// lastEventID возвращает номер последнего полученного клиентом события из заголовка Last-Event-ID
+// или параметра last_event_id. Параметр нужен для первого подключения EventSource,
+// который не умеет задавать заголовки.
+func lastEventID(r *http.Request) (int64, bool, error) {
+	raw := r.Header.Get("Last-Event-ID")
+	if raw == "" {
+		raw = r.URL.Query().Get("last_event_id")
+	}
+
+	if raw == "" {
+		return 0, false, nil
+	}
+
+	seq, err := strconv.ParseInt(raw, 10, 64)
+	if err != nil || seq < 0 {
+		return 0, false, fmt.Errorf("invalid last event id %q", raw)
+	}
+
+	return seq, true, nil
+}
+
+// writeEvent пишет событие в формате server-sent events: id — номер события в истории,
+// event — тип события, data — то же JSON-представление, что у вебхуков.
+func writeEvent(w io.Writer, e domain.Event) error {
+	data, err := json.Marshal(event.NewMessage(e))
+	if err != nil {
+		return fmt.Errorf("marshal event %d: %w", e.Sequence, err)
+	}
+
+	if _, err := fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", e.Sequence, e.Type, data); err != nil {
+		return fmt.Errorf("write event %d: %w", e.Sequence, err)
+	}
+
+	return nil
+}
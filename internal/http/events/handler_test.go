@@ -0,0 +1,185 @@
+package events
+
+import (
+	"bufio"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/dixitix/pr-reviewer-service/internal/domain"
+	"github.com/dixitix/pr-reviewer-service/internal/service"
+)
+
+// memoryEvents — реализация service.EventService в памяти.
+type memoryEvents struct {
+	mu     sync.Mutex
+	events []domain.Event
+}
+
+func (m *memoryEvents) append(e domain.Event) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	e.Sequence = int64(len(m.events) + 1)
+	m.events = append(m.events, e)
+}
+
+func (m *memoryEvents) ListEvents(_ context.Context, filter service.EventFilter) ([]domain.Event, error) {
+	if filter.TeamName == "unknown" {
+		return nil, service.ErrNotFound
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	result := make([]domain.Event, 0)
+
+	for _, e := range m.events {
+		if e.Sequence > filter.AfterSequence && len(result) < filter.Limit {
+			result = append(result, e)
+		}
+	}
+
+	return result, nil
+}
+
+func (m *memoryEvents) LastEventSequence(context.Context) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return int64(len(m.events)), nil
+}
+
+func newTestStream(t *testing.T, svc *memoryEvents) *httptest.Server {
+	t.Helper()
+
+	h := NewHandler(svc, Config{PollInterval: 10 * time.Millisecond}, nil)
+	srv := httptest.NewServer(http.HandlerFunc(h.Stream))
+	t.Cleanup(func() {
+		h.Shutdown()
+		srv.Close()
+	})
+
+	return srv
+}
+
+// readIDs читает из потока значения полей id, пока не наберёт n штук.
+func readIDs(t *testing.T, resp *http.Response, n int) []string {
+	t.Helper()
+
+	ids := make([]string, 0, n)
+	scanner := bufio.NewScanner(resp.Body)
+
+	for len(ids) < n && scanner.Scan() {
+		if id, ok := strings.CutPrefix(scanner.Text(), "id: "); ok {
+			ids = append(ids, id)
+		}
+	}
+
+	if len(ids) < n {
+		t.Fatalf("stream ended after ids %v: %v", ids, scanner.Err())
+	}
+
+	return ids
+}
+
+func openStream(t *testing.T, ctx context.Context, url, lastEventID string) *http.Response {
+	t.Helper()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		t.Fatalf("new request: %v", err)
+	}
+
+	if lastEventID != "" {
+		req.Header.Set("Last-Event-ID", lastEventID)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("open stream: %v", err)
+	}
+
+	t.Cleanup(func() {
+		_ = resp.Body.Close()
+	})
+
+	return resp
+}
+
+// TestStream_ResumesAfterLastEventID проверяет, что клиент с Last-Event-ID получает пропущенные события,
+// а затем новые.
+func TestStream_ResumesAfterLastEventID(t *testing.T) {
+	svc := &memoryEvents{}
+	for range 3 {
+		svc.append(domain.Event{Type: domain.EventReviewersAssigned})
+	}
+
+	srv := newTestStream(t, svc)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	resp := openStream(t, ctx, srv.URL, "1")
+
+	if resp.StatusCode != http.StatusOK || resp.Header.Get("Content-Type") != "text/event-stream" {
+		t.Fatalf("unexpected response: %d %s", resp.StatusCode, resp.Header.Get("Content-Type"))
+	}
+
+	if ids := readIDs(t, resp, 2); ids[0] != "2" || ids[1] != "3" {
+		t.Fatalf("expected missed events 2 and 3, got %v", ids)
+	}
+
+	svc.append(domain.Event{Type: domain.EventPullRequestMerged})
+
+	if ids := readIDs(t, resp, 1); ids[0] != "4" {
+		t.Fatalf("expected new event 4, got %v", ids)
+	}
+}
+
+// TestStream_StartsFromLatestEvent проверяет, что новый клиент без Last-Event-ID не получает историю.
+func TestStream_StartsFromLatestEvent(t *testing.T) {
+	svc := &memoryEvents{}
+	svc.append(domain.Event{Type: domain.EventReviewersAssigned})
+
+	srv := newTestStream(t, svc)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	resp := openStream(t, ctx, srv.URL, "")
+
+	svc.append(domain.Event{Type: domain.EventReviewerReassigned})
+
+	if ids := readIDs(t, resp, 1); ids[0] != "2" {
+		t.Fatalf("expected only new event 2, got %v", ids)
+	}
+}
+
+// TestStream_InvalidRequest проверяет ошибки, которые возвращаются до открытия потока.
+func TestStream_InvalidRequest(t *testing.T) {
+	srv := newTestStream(t, &memoryEvents{})
+
+	cases := []struct {
+		name        string
+		query       string
+		lastEventID string
+		status      int
+	}{
+		{"negative last event id", "", "-1", http.StatusBadRequest},
+		{"malformed last event id", "?last_event_id=abc", "", http.StatusBadRequest},
+		{"unknown team", "?team_name=unknown", "", http.StatusNotFound},
+	}
+
+	for _, tc := range cases {
+		resp := openStream(t, context.Background(), srv.URL+tc.query, tc.lastEventID)
+
+		if resp.StatusCode != tc.status {
+			t.Fatalf("%s: expected %d, got %d", tc.name, tc.status, resp.StatusCode)
+		}
+	}
+}
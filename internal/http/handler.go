@@ -5,27 +5,74 @@ package httpserver
 import (
 	"log/slog"
 
+	"github.com/dixitix/pr-reviewer-service/internal/authn"
+	"github.com/dixitix/pr-reviewer-service/internal/domain"
+	"github.com/dixitix/pr-reviewer-service/internal/http/apikey"
+	"github.com/dixitix/pr-reviewer-service/internal/http/events"
+	"github.com/dixitix/pr-reviewer-service/internal/http/idempotency"
+	"github.com/dixitix/pr-reviewer-service/internal/http/notification"
+	"github.com/dixitix/pr-reviewer-service/internal/http/openapi"
+	"github.com/dixitix/pr-reviewer-service/internal/http/organization"
 	"github.com/dixitix/pr-reviewer-service/internal/http/pullrequest"
 	"github.com/dixitix/pr-reviewer-service/internal/http/stats"
 	"github.com/dixitix/pr-reviewer-service/internal/http/team"
 	"github.com/dixitix/pr-reviewer-service/internal/http/user"
+	"github.com/dixitix/pr-reviewer-service/internal/http/webhook"
 	"github.com/dixitix/pr-reviewer-service/internal/service"
 )
 
+// Config описывает настройки HTTP-слоя.
+type Config struct {
+	// DefaultOrganization — организация запросов без заголовка X-Organization-ID.
+	DefaultOrganization domain.OrganizationID
+	// Auth — аутентификация запросов по API-ключам.
+	Auth authn.Config
+	// Idempotency — поддержка Idempotency-Key в POST-запросах; nil — заголовок игнорируется.
+	Idempotency *idempotency.Middleware
+	// Validation — проверка запросов и ответов по спецификации API; nil — не проверяются.
+	Validation *openapi.Middleware
+	// Events — настройки потока событий /events/stream.
+	Events events.Config
+}
+
 // Handler агрегирует обработчики HTTP-запросов.
 type Handler struct {
+	auth               *apikey.Authenticator
+	idempotency        *idempotency.Middleware
+	validation         *openapi.Middleware
+	orgHandler         *organization.Handler
+	apiKeyHandler      *apikey.Handler
 	teamHandler        *team.Handler
 	userHandler        *user.Handler
 	pullRequestHandler *pullrequest.Handler
 	statsHandler       *stats.Handler
+	eventsHandler      *events.Handler
+	webhookHandler     *webhook.Handler
+	notifyHandler      *notification.Handler
+	logger             *slog.Logger
 }
 
 // NewHandler создаёт новый HTTP-обработчик.
-func NewHandler(svc service.Service, logger *slog.Logger) *Handler {
+func NewHandler(svc service.Service, cfg Config, logger *slog.Logger) *Handler {
 	return &Handler{
+		auth:               apikey.NewAuthenticator(svc, cfg.Auth, logger),
+		idempotency:        cfg.Idempotency,
+		validation:         cfg.Validation,
+		orgHandler:         organization.NewHandler(svc, cfg.DefaultOrganization, logger),
+		apiKeyHandler:      apikey.NewHandler(svc, logger),
 		teamHandler:        team.NewHandler(svc, logger),
 		userHandler:        user.NewHandler(svc, logger),
 		pullRequestHandler: pullrequest.NewHandler(svc, logger),
 		statsHandler:       stats.NewHandler(svc, logger),
+		eventsHandler:      events.NewHandler(svc, cfg.Events, logger),
+		webhookHandler:     webhook.NewHandler(svc, logger),
+		notifyHandler:      notification.NewHandler(svc, logger),
+		logger:             logger,
 	}
 }
+
+// Shutdown завершает долгоживущие ответы (потоки событий), чтобы http.Server.Shutdown
+// не ждал их до истечения таймаута. Регистрируется через http.Server.RegisterOnShutdown.
+func (h *Handler) Shutdown() {
+	h.eventsHandler.Shutdown()
+}
@@ -9,21 +9,38 @@ import (
 
 // HTTP-коды ошибок.
 const (
-	ErrorCodeTeamExists       = "TEAM_EXISTS"
-	ErrorCodePRExists         = "PR_EXISTS"
-	ErrorCodePRMerged         = "PR_MERGED"
-	ErrorCodeNotAssigned      = "NOT_ASSIGNED"
-	ErrorCodeNoCandidate      = "NO_CANDIDATE"
-	ErrorCodeInvalidJSON      = "INVALID_JSON"
-	ErrorCodeValidation       = "VALIDATION_ERROR"
-	ErrorCodeInternal         = "INTERNAL_ERROR"
-	ErrorCodeMethodNotAllowed = "METHOD_NOT_ALLOWED"
-	ErrorCodeNotFound         = "NOT_FOUND"
+	ErrorCodeTeamExists           = "TEAM_EXISTS"
+	ErrorCodePRExists             = "PR_EXISTS"
+	ErrorCodePRMerged             = "PR_MERGED"
+	ErrorCodeNotAssigned          = "NOT_ASSIGNED"
+	ErrorCodeNoCandidate          = "NO_CANDIDATE"
+	ErrorCodeInvalidJSON          = "INVALID_JSON"
+	ErrorCodeValidation           = "VALIDATION_ERROR"
+	ErrorCodeInternal             = "INTERNAL_ERROR"
+	ErrorCodeMethodNotAllowed     = "METHOD_NOT_ALLOWED"
+	ErrorCodeNotFound             = "NOT_FOUND"
+	ErrorCodeOrganizationExists   = "ORGANIZATION_EXISTS"
+	ErrorCodeUnauthorized         = "UNAUTHORIZED"
+	ErrorCodeInsufficientScope    = "INSUFFICIENT_SCOPE"
+	ErrorCodeForbidden            = "FORBIDDEN"
+	ErrorCodeIdempotencyKeyReused = "IDEMPOTENCY_KEY_REUSED"
+	ErrorCodeRequestInProgress    = "REQUEST_IN_PROGRESS"
+	ErrorCodeConflict             = "CONFLICT"
+	ErrorCodePreconditionFailed   = "PRECONDITION_FAILED"
 )
 
 // ErrorResponseBody описывает тело ошибки в формате ErrorResponse.
 type ErrorResponseBody struct {
-	Code    string `json:"code"`
+	Code    string       `json:"code"`
+	Message string       `json:"message"`
+	Details []FieldError `json:"details,omitempty"`
+}
+
+// FieldError описывает ошибку в конкретном поле запроса или ответа.
+type FieldError struct {
+	// Field — путь к полю: members[0].user_id для тела, query.team_name, path.name и header.If-Match для параметров.
+	Field string `json:"field"`
+	// Message — что не так со значением.
 	Message string `json:"message"`
 }
 
@@ -54,3 +71,32 @@ func WriteJSONError(w http.ResponseWriter, statusCode int, code, message string,
 		slog.Default().Error("failed to write error response", slog.Any("error", err))
 	}
 }
+
+// WriteValidationError отправляет 400 VALIDATION_ERROR с ошибками по полям в details.
+// Сообщение повторяет первую ошибку, чтобы клиенты, читающие только message, видели причину.
+func WriteValidationError(w http.ResponseWriter, fields []FieldError, logger *slog.Logger) {
+	message := "request validation failed"
+	if len(fields) > 0 {
+		message = fields[0].Field + ": " + fields[0].Message
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusBadRequest)
+
+	resp := ErrorResponse{
+		Error: ErrorResponseBody{
+			Code:    ErrorCodeValidation,
+			Message: message,
+			Details: fields,
+		},
+	}
+
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		if logger != nil {
+			logger.Error("failed to write error response", slog.Any("error", err))
+			return
+		}
+
+		slog.Default().Error("failed to write error response", slog.Any("error", err))
+	}
+}
@@ -0,0 +1,242 @@
+// Package idempotency содержит поддержку заголовка Idempotency-Key для изменяющих POST-запросов.
+package idempotency
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/dixitix/pr-reviewer-service/internal/auth"
+	"github.com/dixitix/pr-reviewer-service/internal/http/httperr"
+	"github.com/dixitix/pr-reviewer-service/internal/repository"
+)
+
+// Заголовки идемпотентных запросов.
+const (
+	// HeaderKey — ключ идемпотентности, выбираемый клиентом для каждой логической операции.
+	HeaderKey = "Idempotency-Key"
+	// HeaderReplayed выставляется в ответах, повторённых из сохранённых.
+	HeaderReplayed = "Idempotent-Replayed"
+)
+
+const (
+	// maxKeyLength — максимальная длина ключа идемпотентности.
+	maxKeyLength = 255
+	// maxBodySize — максимальный размер тела идемпотентного запроса.
+	maxBodySize = 1 << 20
+)
+
+// Config описывает хранение ответов.
+type Config struct {
+	// TTL — сколько хранить ответ. По умолчанию сутки.
+	TTL time.Duration
+	// CleanupInterval — как часто удалять истёкшие ключи. По умолчанию час.
+	CleanupInterval time.Duration
+}
+
+// Middleware сохраняет первый ответ на POST-запрос с заголовком Idempotency-Key и повторяет его
+// для запросов с тем же ключом, не выполняя их заново.
+type Middleware struct {
+	repo   repository.IdempotencyRepository
+	cfg    Config
+	logger *slog.Logger
+	now    func() time.Time
+}
+
+// New создаёт middleware идемпотентности.
+func New(repo repository.IdempotencyRepository, cfg Config, logger *slog.Logger) *Middleware {
+	if cfg.TTL <= 0 {
+		cfg.TTL = 24 * time.Hour
+	}
+
+	if cfg.CleanupInterval <= 0 {
+		cfg.CleanupInterval = time.Hour
+	}
+
+	return &Middleware{
+		repo:   repo,
+		cfg:    cfg,
+		logger: logger,
+		now:    time.Now,
+	}
+}
+
+// Wrap применяет идемпотентность к POST-запросам с заголовком Idempotency-Key. Ключи действуют
+// в организации из контекста. Повтор с тем же ключом и другим телом, путём или вызывающим
+// отклоняется с 409 IDEMPOTENCY_KEY_REUSED, повтор во время выполнения первого запроса —
+// с 409 REQUEST_IN_PROGRESS. Ответы 5xx не сохраняются, такой запрос можно повторить.
+func (m *Middleware) Wrap(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key := r.Header.Get(HeaderKey)
+		if r.Method != http.MethodPost || key == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		if len(key) > maxKeyLength {
+			httperr.WriteJSONError(w, http.StatusBadRequest, httperr.ErrorCodeValidation, "Idempotency-Key is too long", m.logger)
+			return
+		}
+
+		body, err := io.ReadAll(io.LimitReader(r.Body, maxBodySize+1))
+		if err != nil {
+			httperr.WriteJSONError(w, http.StatusBadRequest, httperr.ErrorCodeInvalidJSON, "failed to read request body", m.logger)
+			return
+		}
+
+		if len(body) > maxBodySize {
+			httperr.WriteJSONError(w, http.StatusRequestEntityTooLarge, httperr.ErrorCodeValidation, "request body is too large", m.logger)
+			return
+		}
+
+		r.Body = io.NopCloser(bytes.NewReader(body))
+
+		ctx := r.Context()
+		now := m.now()
+		hash := requestHash(r, body)
+
+		rec, reserved, err := m.repo.Reserve(ctx, key, hash, now, now.Add(m.cfg.TTL))
+		if err != nil {
+			if m.logger != nil {
+				m.logger.ErrorContext(r.Context(), "idempotency middleware: Reserve error", slog.Any("error", err))
+			}
+
+			httperr.WriteJSONError(w, http.StatusInternalServerError, httperr.ErrorCodeInternal, "internal server error", m.logger)
+
+			return
+		}
+
+		if !reserved {
+			m.replay(w, r, rec, hash)
+			return
+		}
+
+		m.serve(w, r, next, key)
+	})
+}
+
+// replay отвечает сохранённым ответом или ошибкой, если ключ нельзя использовать для запроса.
+func (m *Middleware) replay(w http.ResponseWriter, r *http.Request, rec repository.IdempotencyRecord, hash string) {
+	if rec.RequestHash != hash {
+		httperr.WriteJSONError(w, http.StatusConflict, httperr.ErrorCodeIdempotencyKeyReused, "Idempotency-Key was used for a different request", m.logger)
+		return
+	}
+
+	if !rec.Completed {
+		httperr.WriteJSONError(w, http.StatusConflict, httperr.ErrorCodeRequestInProgress, "request with this Idempotency-Key is in progress", m.logger)
+		return
+	}
+
+	if rec.ContentType != "" {
+		w.Header().Set("Content-Type", rec.ContentType)
+	}
+
+	w.Header().Set(HeaderReplayed, "true")
+	w.WriteHeader(rec.StatusCode)
+
+	if _, err := w.Write(rec.Body); err != nil {
+		if m.logger != nil {
+			m.logger.ErrorContext(r.Context(), "idempotency middleware: failed to write response", slog.Any("error", err))
+		}
+	}
+}
+
+// serve выполняет запрос и сохраняет ответ. Если ответ не сохраняется, ключ освобождается.
+func (m *Middleware) serve(w http.ResponseWriter, r *http.Request, next http.Handler, key string) {
+	rec := &recorder{ResponseWriter: w, status: http.StatusOK}
+	completed := false
+
+	// Ключ освобождается и при панике обработчика, иначе повтор получал бы REQUEST_IN_PROGRESS до истечения TTL.
+	defer func() {
+		if completed {
+			return
+		}
+
+		if err := m.repo.Release(context.WithoutCancel(r.Context()), key); err != nil && m.logger != nil {
+			m.logger.ErrorContext(r.Context(), "idempotency middleware: Release error", slog.Any("error", err))
+		}
+	}()
+
+	next.ServeHTTP(rec, r)
+
+	if rec.status >= http.StatusInternalServerError {
+		return
+	}
+
+	err := m.repo.Complete(context.WithoutCancel(r.Context()), key, rec.status, rec.Header().Get("Content-Type"), rec.body.Bytes())
+	if err != nil {
+		if m.logger != nil {
+			m.logger.ErrorContext(r.Context(), "idempotency middleware: Complete error", slog.Any("error", err))
+		}
+
+		return
+	}
+
+	completed = true
+}
+
+// Run периодически удаляет истёкшие ключи до отмены контекста.
+func (m *Middleware) Run(ctx context.Context) {
+	ticker := time.NewTicker(m.cfg.CleanupInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			n, err := m.repo.DeleteExpired(ctx, m.now())
+			if err != nil {
+				if m.logger != nil {
+					m.logger.ErrorContext(ctx, "idempotency cleanup failed", slog.Any("error", err))
+				}
+
+				continue
+			}
+
+			if n > 0 && m.logger != nil {
+				m.logger.InfoContext(ctx, "idempotency keys expired", slog.Int64("deleted", n))
+			}
+		}
+	}
+}
+
+// requestHash возвращает хэш запроса: вызывающий, путь и тело. Повтор ключа другим вызывающим
+// считается другим запросом, поэтому чужой ответ не раскрывается.
+func requestHash(r *http.Request, body []byte) string {
+	h := sha256.New()
+
+	_, _ = io.WriteString(h, string(auth.Actor(r.Context()))+"\n"+r.URL.RequestURI()+"\n")
+	_, _ = h.Write(body)
+
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// recorder передаёт ответ клиенту и запоминает его статус и тело.
+type recorder struct {
+	http.ResponseWriter
+	status      int
+	wroteHeader bool
+	body        bytes.Buffer
+}
+
+func (r *recorder) WriteHeader(status int) {
+	if !r.wroteHeader {
+		r.status = status
+		r.wroteHeader = true
+	}
+
+	r.ResponseWriter.WriteHeader(status)
+}
+
+func (r *recorder) Write(p []byte) (int, error) {
+	r.wroteHeader = true
+	r.body.Write(p)
+
+	return r.ResponseWriter.Write(p)
+}
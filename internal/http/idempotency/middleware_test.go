@@ -0,0 +1,190 @@
+package idempotency
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dixitix/pr-reviewer-service/internal/repository"
+)
+
+// memoryKeys — реализация repository.IdempotencyRepository в памяти для тестов middleware.
+type memoryKeys struct {
+	records map[string]repository.IdempotencyRecord
+}
+
+func newMemoryKeys() *memoryKeys {
+	return &memoryKeys{records: map[string]repository.IdempotencyRecord{}}
+}
+
+func (m *memoryKeys) Reserve(_ context.Context, key, requestHash string, _, _ time.Time) (repository.IdempotencyRecord, bool, error) {
+	if rec, ok := m.records[key]; ok {
+		return rec, false, nil
+	}
+
+	rec := repository.IdempotencyRecord{Key: key, RequestHash: requestHash}
+	m.records[key] = rec
+
+	return rec, true, nil
+}
+
+func (m *memoryKeys) Complete(_ context.Context, key string, statusCode int, contentType string, body []byte) error {
+	rec := m.records[key]
+	rec.Completed = true
+	rec.StatusCode = statusCode
+	rec.ContentType = contentType
+	rec.Body = append([]byte(nil), body...)
+	m.records[key] = rec
+
+	return nil
+}
+
+func (m *memoryKeys) Release(_ context.Context, key string) error {
+	if !m.records[key].Completed {
+		delete(m.records, key)
+	}
+
+	return nil
+}
+
+func (m *memoryKeys) DeleteExpired(context.Context, time.Time) (int64, error) {
+	return 0, nil
+}
+
+// countingHandler считает вызовы и отвечает статусом status с телом запроса.
+func countingHandler(calls *int, status int) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		*calls++
+
+		body, _ := io.ReadAll(r.Body)
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		_, _ = w.Write(body)
+	})
+}
+
+func post(h http.Handler, key, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, "/pullRequest/create", strings.NewReader(body))
+	if key != "" {
+		req.Header.Set(HeaderKey, key)
+	}
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+
+	return rec
+}
+
+func newTestMiddleware(repo repository.IdempotencyRepository) *Middleware {
+	return New(repo, Config{}, slog.New(slog.NewTextHandler(io.Discard, nil)))
+}
+
+// TestWrap_ReplaysFirstResponse проверяет, что повтор с тем же ключом не выполняет запрос заново.
+func TestWrap_ReplaysFirstResponse(t *testing.T) {
+	var calls int
+
+	h := newTestMiddleware(newMemoryKeys()).Wrap(countingHandler(&calls, http.StatusCreated))
+
+	first := post(h, "key-1", `{"id":"pr-1"}`)
+	second := post(h, "key-1", `{"id":"pr-1"}`)
+
+	if calls != 1 {
+		t.Fatalf("expected handler to run once, got %d", calls)
+	}
+
+	if second.Code != http.StatusCreated || second.Body.String() != first.Body.String() {
+		t.Fatalf("unexpected replay: %d %q", second.Code, second.Body.String())
+	}
+
+	if second.Header().Get(HeaderReplayed) != "true" || second.Header().Get("Content-Type") != "application/json" {
+		t.Fatalf("unexpected replay headers: %v", second.Header())
+	}
+
+	if first.Header().Get(HeaderReplayed) != "" {
+		t.Fatalf("first response must not be marked as replayed")
+	}
+}
+
+// TestWrap_RejectsDifferentBody проверяет конфликт при повторе ключа с другим телом.
+func TestWrap_RejectsDifferentBody(t *testing.T) {
+	var calls int
+
+	h := newTestMiddleware(newMemoryKeys()).Wrap(countingHandler(&calls, http.StatusCreated))
+
+	post(h, "key-1", `{"id":"pr-1"}`)
+	rec := post(h, "key-1", `{"id":"pr-2"}`)
+
+	if rec.Code != http.StatusConflict || !strings.Contains(rec.Body.String(), "IDEMPOTENCY_KEY_REUSED") {
+		t.Fatalf("expected 409 IDEMPOTENCY_KEY_REUSED, got %d %q", rec.Code, rec.Body.String())
+	}
+
+	if calls != 1 {
+		t.Fatalf("expected handler to run once, got %d", calls)
+	}
+}
+
+// TestWrap_InProgress проверяет конфликт при повторе до завершения первого запроса.
+func TestWrap_InProgress(t *testing.T) {
+	repo := newMemoryKeys()
+	m := newTestMiddleware(repo)
+
+	var second *httptest.ResponseRecorder
+
+	h := m.Wrap(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if second == nil {
+			second = post(m.Wrap(http.NotFoundHandler()), "key-1", "{}")
+		}
+
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	post(h, "key-1", "{}")
+
+	if second.Code != http.StatusConflict || !strings.Contains(second.Body.String(), "REQUEST_IN_PROGRESS") {
+		t.Fatalf("expected 409 REQUEST_IN_PROGRESS, got %d %q", second.Code, second.Body.String())
+	}
+}
+
+// TestWrap_ServerErrorIsNotStored проверяет, что после ответа 5xx запрос можно повторить.
+func TestWrap_ServerErrorIsNotStored(t *testing.T) {
+	var calls int
+
+	repo := newMemoryKeys()
+	m := newTestMiddleware(repo)
+
+	post(m.Wrap(countingHandler(&calls, http.StatusInternalServerError)), "key-1", "{}")
+
+	if _, ok := repo.records["key-1"]; ok {
+		t.Fatalf("key must be released after 5xx response")
+	}
+
+	rec := post(m.Wrap(countingHandler(&calls, http.StatusOK)), "key-1", "{}")
+	if rec.Code != http.StatusOK || calls != 2 {
+		t.Fatalf("expected retry to run handler, got %d after %d calls", rec.Code, calls)
+	}
+}
+
+// TestWrap_IgnoresRequestsWithoutKey проверяет, что запросы без ключа и не-POST проходят как есть.
+func TestWrap_IgnoresRequestsWithoutKey(t *testing.T) {
+	var calls int
+
+	repo := newMemoryKeys()
+	h := newTestMiddleware(repo).Wrap(countingHandler(&calls, http.StatusOK))
+
+	post(h, "", "{}")
+	post(h, "", "{}")
+
+	req := httptest.NewRequest(http.MethodGet, "/team/get", nil)
+	req.Header.Set(HeaderKey, "key-1")
+	h.ServeHTTP(httptest.NewRecorder(), req)
+
+	if calls != 3 || len(repo.records) != 0 {
+		t.Fatalf("expected 3 unrecorded calls, got %d calls and %d records", calls, len(repo.records))
+	}
+}
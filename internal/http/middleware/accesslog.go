@@ -0,0 +1,39 @@
+// Package middleware содержит общие middleware HTTP-сервера.
+package middleware
+
+import (
+	"log/slog"
+	"net/http"
+	"time"
+)
+
+// AccessLog записывает в лог каждый запрос: метод, путь, статус, размер ответа и время обработки.
+// Запросы с ответом 5xx записываются с уровнем Error, остальные — Info.
+func AccessLog(next http.Handler, logger *slog.Logger) http.Handler {
+	if logger == nil {
+		return next
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		rw := &responseWriter{ResponseWriter: w, status: http.StatusOK}
+
+		// Запись делается и при прерывании ответа паникой http.ErrAbortHandler.
+		defer func() {
+			level := slog.LevelInfo
+			if rw.status >= http.StatusInternalServerError {
+				level = slog.LevelError
+			}
+
+			logger.LogAttrs(r.Context(), level, "http request",
+				slog.String("method", r.Method),
+				slog.String("path", r.URL.Path),
+				slog.Int("status", rw.status),
+				slog.Int64("bytes", rw.bytes),
+				slog.Duration("latency", time.Since(start)),
+			)
+		}()
+
+		next.ServeHTTP(rw, r)
+	})
+}
@@ -0,0 +1,54 @@
+// Package middleware содержит общие middleware HTTP-сервера: идентификатор запроса,
+// журнал запросов и восстановление после паник.
+package middleware
+
+import (
+	"log/slog"
+	"net/http"
+)
+
+// Wrap оборачивает обработчик общими middleware. Идентификатор запроса назначается первым,
+// чтобы он был в журнале запросов и в логах паник; паника превращается в ответ до записи
+// в журнал, чтобы в нём был итоговый статус. inner применяются между журналом и восстановлением
+// после паник (первый — ближе к журналу) и тоже видят итоговый статус.
+func Wrap(next http.Handler, logger *slog.Logger, inner ...func(http.Handler) http.Handler) http.Handler {
+	h := Recover(next, logger)
+	for i := len(inner) - 1; i >= 0; i-- {
+		h = inner[i](h)
+	}
+
+	return RequestID(AccessLog(h, logger))
+}
+
+// responseWriter запоминает статус и размер ответа.
+type responseWriter struct {
+	http.ResponseWriter
+	status      int
+	wroteHeader bool
+	bytes       int64
+}
+
+func (w *responseWriter) WriteHeader(status int) {
+	if !w.wroteHeader {
+		w.status = status
+		w.wroteHeader = true
+	}
+
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *responseWriter) Write(p []byte) (int, error) {
+	if !w.wroteHeader {
+		w.WriteHeader(http.StatusOK)
+	}
+
+	n, err := w.ResponseWriter.Write(p)
+	w.bytes += int64(n)
+
+	return n, err
+}
+
+// Unwrap даёт http.ResponseController доступ к Flush и дедлайнам исходного ResponseWriter.
+func (w *responseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
@@ -0,0 +1,137 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/dixitix/pr-reviewer-service/internal/http/httperr"
+	"github.com/dixitix/pr-reviewer-service/internal/logger"
+)
+
+func newTestLogger(buf *bytes.Buffer) *slog.Logger {
+	return slog.New(logger.NewContextHandler(slog.NewJSONHandler(buf, nil)))
+}
+
+// logEntries разбирает записи JSON-лога.
+func logEntries(t *testing.T, buf *bytes.Buffer) []map[string]any {
+	t.Helper()
+
+	var entries []map[string]any
+
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		var entry map[string]any
+		if err := json.Unmarshal([]byte(line), &entry); err != nil {
+			t.Fatalf("decode log line %q: %v", line, err)
+		}
+
+		entries = append(entries, entry)
+	}
+
+	return entries
+}
+
+// TestWrap_RequestID проверяет, что идентификатор клиента передаётся в ответ и в логи обработчика,
+// а некорректный заменяется новым.
+func TestWrap_RequestID(t *testing.T) {
+	var buf bytes.Buffer
+	log := newTestLogger(&buf)
+
+	h := Wrap(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		log.InfoContext(r.Context(), "handled")
+		w.WriteHeader(http.StatusCreated)
+	}), log)
+
+	req := httptest.NewRequest(http.MethodPost, "/team/add", nil)
+	req.Header.Set(HeaderRequestID, "req-1")
+	rec := httptest.NewRecorder()
+
+	h.ServeHTTP(rec, req)
+
+	if got := rec.Header().Get(HeaderRequestID); got != "req-1" {
+		t.Fatalf("expected request id req-1 in response, got %q", got)
+	}
+
+	entries := logEntries(t, &buf)
+	if len(entries) != 2 {
+		t.Fatalf("expected handler and access log entries, got %v", entries)
+	}
+
+	for _, entry := range entries {
+		if entry[logger.RequestIDKey] != "req-1" {
+			t.Fatalf("expected request_id in every entry, got %v", entry)
+		}
+	}
+
+	access := entries[1]
+	if access["msg"] != "http request" || access["method"] != http.MethodPost || access["path"] != "/team/add" ||
+		access["status"] != float64(http.StatusCreated) || access["latency"] == nil {
+		t.Fatalf("unexpected access log entry: %v", access)
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/team/get", nil)
+	req.Header.Set(HeaderRequestID, "bad id\n")
+	rec = httptest.NewRecorder()
+
+	h.ServeHTTP(rec, req)
+
+	if got := rec.Header().Get(HeaderRequestID); len(got) != 32 {
+		t.Fatalf("expected generated request id, got %q", got)
+	}
+}
+
+// TestRecover проверяет ответ на панику обработчика в обоих форматах ошибок.
+func TestRecover(t *testing.T) {
+	var buf bytes.Buffer
+
+	h := Recover(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {
+		panic("boom")
+	}), newTestLogger(&buf))
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/team/get", nil))
+
+	var resp httperr.ErrorResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+
+	if rec.Code != http.StatusInternalServerError || resp.Error.Code != httperr.ErrorCodeInternal {
+		t.Fatalf("expected 500 INTERNAL_ERROR, got %d %s", rec.Code, rec.Body.String())
+	}
+
+	if !strings.Contains(buf.String(), `"panic":"boom"`) {
+		t.Fatalf("expected panic to be logged, got %s", buf.String())
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/team/get", nil)
+	req.Header.Set("Accept", "application/problem+json")
+	rec = httptest.NewRecorder()
+
+	h.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusInternalServerError || rec.Header().Get("Content-Type") != "application/problem+json" {
+		t.Fatalf("expected 500 problem, got %d %s", rec.Code, rec.Header().Get("Content-Type"))
+	}
+}
+
+// TestRecover_AfterWrite проверяет, что паника после начала ответа разрывает соединение.
+func TestRecover_AfterWrite(t *testing.T) {
+	h := Recover(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = io.WriteString(w, "partial")
+		panic("boom")
+	}), slog.New(slog.NewJSONHandler(io.Discard, nil)))
+
+	defer func() {
+		if rec := recover(); rec != http.ErrAbortHandler {
+			t.Fatalf("expected http.ErrAbortHandler, got %v", rec)
+		}
+	}()
+
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+}
@@ -0,0 +1,51 @@
+// Package middleware содержит общие middleware HTTP-сервера.
+package middleware
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+	"runtime/debug"
+
+	"github.com/dixitix/pr-reviewer-service/internal/http/httperr"
+	"github.com/dixitix/pr-reviewer-service/internal/http/problem"
+)
+
+// Recover перехватывает панику обработчика, логирует её со стеком и отвечает 500 INTERNAL_ERROR
+// в формате, который предпочитает клиент. Если ответ уже начат, соединение разрывается через
+// http.ErrAbortHandler, чтобы клиент не принял обрезанный ответ за полный.
+func Recover(next http.Handler, logger *slog.Logger) http.Handler {
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		rw := &responseWriter{ResponseWriter: w, status: http.StatusOK}
+
+		defer func() {
+			rec := recover()
+			if rec == nil {
+				return
+			}
+
+			if err, ok := rec.(error); ok && errors.Is(err, http.ErrAbortHandler) {
+				panic(rec)
+			}
+
+			logger.ErrorContext(r.Context(), "http handler panic",
+				slog.Any("panic", rec),
+				slog.String("method", r.Method),
+				slog.String("path", r.URL.Path),
+				slog.String("stack", string(debug.Stack())),
+			)
+
+			if rw.wroteHeader {
+				panic(http.ErrAbortHandler)
+			}
+
+			problem.WriteError(rw, r, http.StatusInternalServerError, httperr.ErrorCodeInternal, "internal server error", logger)
+		}()
+
+		next.ServeHTTP(rw, r)
+	})
+}
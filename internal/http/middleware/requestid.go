@@ -0,0 +1,48 @@
+// Package middleware содержит общие middleware HTTP-сервера.
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/dixitix/pr-reviewer-service/internal/idgen"
+	"github.com/dixitix/pr-reviewer-service/internal/logger"
+)
+
+// HeaderRequestID — заголовок с идентификатором запроса.
+const HeaderRequestID = "X-Request-ID"
+
+// maxRequestIDLength — максимальная длина идентификатора, принимаемого от клиента.
+const maxRequestIDLength = 128
+
+// RequestID берёт идентификатор запроса из заголовка X-Request-ID или назначает новый, кладёт его
+// в контекст запроса и возвращает в ответе. Записи лога с контекстом запроса получают поле request_id.
+// Идентификатор клиента принимается, если он не длиннее 128 символов из букв, цифр и -_.:/+=.
+func RequestID(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := r.Header.Get(HeaderRequestID)
+		if !validRequestID(id) {
+			id = idgen.New()
+		}
+
+		w.Header().Set(HeaderRequestID, id)
+
+		next.ServeHTTP(w, r.WithContext(logger.WithRequestID(r.Context(), id)))
+	})
+}
+
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+
+	for _, c := range id {
+		switch {
+		case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c >= '0' && c <= '9':
+		case c == '-', c == '_', c == '.', c == ':', c == '/', c == '+', c == '=':
+		default:
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,23 @@
+// Package notification содержит обработчики и DTO для настроек уведомлений.
+package notification
+
+import "github.com/dixitix/pr-reviewer-service/internal/domain"
+
+// mapPreferencesToDTO конвертирует доменные настройки уведомлений в HTTP-DTO.
+func mapPreferencesToDTO(prefs domain.NotificationPreferences) PreferencesDTO {
+	return PreferencesDTO{
+		UserID:      string(prefs.UserID),
+		ChatEnabled: prefs.ChatEnabled,
+		Email:       prefs.Email,
+		EmailMode:   string(prefs.EmailMode),
+	}
+}
+
+// mapTeamChannelToDTO конвертирует канал чата команды в HTTP-DTO.
+func mapTeamChannelToDTO(channel domain.TeamChatChannel) TeamChannelDTO {
+	return TeamChannelDTO{
+		TeamName:   string(channel.TeamName),
+		WebhookURL: channel.WebhookURL,
+		Channel:    channel.Channel,
+	}
+}
@@ -0,0 +1,32 @@
+// Package notification содержит обработчики и DTO для настроек уведомлений.
+package notification
+
+// PreferencesDTO представляет настройки уведомлений пользователя в HTTP-слое.
+type PreferencesDTO struct {
+	UserID      string `json:"user_id"`
+	ChatEnabled bool   `json:"chat_enabled"`
+	Email       string `json:"email"`
+	EmailMode   string `json:"email_mode"`
+}
+
+// PreferencesEnvelope оборачивает настройки в поле "preferences".
+type PreferencesEnvelope struct {
+	Preferences PreferencesDTO `json:"preferences"`
+}
+
+// TeamChannelDTO представляет канал чата команды в HTTP-слое.
+type TeamChannelDTO struct {
+	TeamName   string `json:"team_name"`
+	WebhookURL string `json:"webhook_url"`
+	Channel    string `json:"channel"`
+}
+
+// TeamChannelEnvelope оборачивает канал чата в поле "team_channel".
+type TeamChannelEnvelope struct {
+	TeamChannel TeamChannelDTO `json:"team_channel"`
+}
+
+// RemoveTeamChannelResponse описывает ответ на /notifications/removeTeamChannel.
+type RemoveTeamChannelResponse struct {
+	TeamName string `json:"team_name"`
+}
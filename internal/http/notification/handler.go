@@ -0,0 +1,317 @@
+// Package notification содержит обработчики и DTO для настроек уведомлений.
+package notification
+
+import (
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/mail"
+	"net/url"
+
+	"github.com/dixitix/pr-reviewer-service/internal/domain"
+	"github.com/dixitix/pr-reviewer-service/internal/http/httperr"
+	"github.com/dixitix/pr-reviewer-service/internal/service"
+)
+
+// Handler обрабатывает HTTP-запросы настроек уведомлений.
+type Handler struct {
+	svc    service.NotificationService
+	logger *slog.Logger
+}
+
+// NewHandler создаёт обработчик настроек уведомлений.
+func NewHandler(svc service.NotificationService, logger *slog.Logger) *Handler {
+	return &Handler{
+		svc:    svc,
+		logger: logger,
+	}
+}
+
+// GetPreferences обрабатывает получение настроек уведомлений пользователя.
+func (h *Handler) GetPreferences(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		httperr.WriteJSONError(w, http.StatusMethodNotAllowed, httperr.ErrorCodeMethodNotAllowed, "method not allowed", h.logger)
+		return
+	}
+
+	userID := r.URL.Query().Get("user_id")
+	if userID == "" {
+		httperr.WriteJSONError(w, http.StatusBadRequest, httperr.ErrorCodeValidation, "user_id is required", h.logger)
+		return
+	}
+
+	prefs, err := h.svc.GetNotificationPreferences(r.Context(), domain.UserID(userID))
+	if err != nil {
+		if errors.Is(err, service.ErrNotFound) {
+			httperr.WriteJSONError(w, http.StatusNotFound, httperr.ErrorCodeNotFound, "user not found", h.logger)
+			return
+		}
+
+		if h.logger != nil {
+			h.logger.ErrorContext(r.Context(), "handleNotificationGetPreferences: GetNotificationPreferences error", slog.Any("error", err))
+		}
+
+		httperr.WriteJSONError(w, http.StatusInternalServerError, httperr.ErrorCodeInternal, "internal server error", h.logger)
+		return
+	}
+
+	resp := PreferencesEnvelope{
+		Preferences: mapPreferencesToDTO(prefs),
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		if h.logger != nil {
+			h.logger.ErrorContext(r.Context(), "handleNotificationGetPreferences: failed to write response", slog.Any("error", err))
+		}
+	}
+}
+
+// SetPreferences обрабатывает изменение настроек уведомлений пользователя.
+func (h *Handler) SetPreferences(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		httperr.WriteJSONError(w, http.StatusMethodNotAllowed, httperr.ErrorCodeMethodNotAllowed, "method not allowed", h.logger)
+		return
+	}
+
+	var req SetPreferencesRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httperr.WriteJSONError(w, http.StatusBadRequest, httperr.ErrorCodeInvalidJSON, "invalid JSON body", h.logger)
+		return
+	}
+
+	if req.UserID == "" {
+		httperr.WriteJSONError(w, http.StatusBadRequest, httperr.ErrorCodeValidation, "user_id is required", h.logger)
+		return
+	}
+
+	update := service.NotificationPreferencesUpdate{
+		ChatEnabled: req.ChatEnabled,
+		Email:       req.Email,
+	}
+
+	if req.Email != nil && *req.Email != "" {
+		if _, err := mail.ParseAddress(*req.Email); err != nil {
+			httperr.WriteJSONError(w, http.StatusBadRequest, httperr.ErrorCodeValidation, "email is invalid", h.logger)
+			return
+		}
+	}
+
+	if req.EmailMode != nil {
+		mode := domain.EmailMode(*req.EmailMode)
+		if !mode.Valid() {
+			httperr.WriteJSONError(w, http.StatusBadRequest, httperr.ErrorCodeValidation, "email_mode must be one of off, immediate, digest", h.logger)
+			return
+		}
+
+		update.EmailMode = &mode
+	}
+
+	if h.logger != nil {
+		h.logger.InfoContext(r.Context(), "handleNotificationSetPreferences", slog.String("user_id", req.UserID))
+	}
+
+	prefs, err := h.svc.UpdateNotificationPreferences(r.Context(), domain.UserID(req.UserID), update)
+	if err != nil {
+		if errors.Is(err, service.ErrEmailRequired) {
+			httperr.WriteJSONError(w, http.StatusBadRequest, httperr.ErrorCodeValidation, "email is required for email_mode", h.logger)
+			return
+		}
+
+		if errors.Is(err, service.ErrNotFound) {
+			httperr.WriteJSONError(w, http.StatusNotFound, httperr.ErrorCodeNotFound, "user not found", h.logger)
+			return
+		}
+
+		if errors.Is(err, service.ErrForbidden) {
+			httperr.WriteJSONError(w, http.StatusForbidden, httperr.ErrorCodeForbidden, "only the user or a lead of the user's team may change notification preferences", h.logger)
+			return
+		}
+
+		if h.logger != nil {
+			h.logger.ErrorContext(r.Context(), "handleNotificationSetPreferences: UpdateNotificationPreferences error", slog.Any("error", err))
+		}
+
+		httperr.WriteJSONError(w, http.StatusInternalServerError, httperr.ErrorCodeInternal, "internal server error", h.logger)
+		return
+	}
+
+	resp := PreferencesEnvelope{
+		Preferences: mapPreferencesToDTO(prefs),
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		if h.logger != nil {
+			h.logger.ErrorContext(r.Context(), "handleNotificationSetPreferences: failed to write response", slog.Any("error", err))
+		}
+	}
+}
+
+// GetTeamChannel обрабатывает получение канала чата команды.
+func (h *Handler) GetTeamChannel(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		httperr.WriteJSONError(w, http.StatusMethodNotAllowed, httperr.ErrorCodeMethodNotAllowed, "method not allowed", h.logger)
+		return
+	}
+
+	teamName := r.URL.Query().Get("team_name")
+	if teamName == "" {
+		httperr.WriteJSONError(w, http.StatusBadRequest, httperr.ErrorCodeValidation, "team_name is required", h.logger)
+		return
+	}
+
+	channel, err := h.svc.GetTeamChatChannel(r.Context(), domain.TeamName(teamName))
+	if err != nil {
+		if errors.Is(err, service.ErrNotFound) {
+			httperr.WriteJSONError(w, http.StatusNotFound, httperr.ErrorCodeNotFound, "team channel not found", h.logger)
+			return
+		}
+
+		if h.logger != nil {
+			h.logger.ErrorContext(r.Context(), "handleNotificationGetTeamChannel: GetTeamChatChannel error", slog.Any("error", err))
+		}
+
+		httperr.WriteJSONError(w, http.StatusInternalServerError, httperr.ErrorCodeInternal, "internal server error", h.logger)
+		return
+	}
+
+	resp := TeamChannelEnvelope{
+		TeamChannel: mapTeamChannelToDTO(channel),
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		if h.logger != nil {
+			h.logger.ErrorContext(r.Context(), "handleNotificationGetTeamChannel: failed to write response", slog.Any("error", err))
+		}
+	}
+}
+
+// SetTeamChannel обрабатывает настройку канала чата команды.
+func (h *Handler) SetTeamChannel(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		httperr.WriteJSONError(w, http.StatusMethodNotAllowed, httperr.ErrorCodeMethodNotAllowed, "method not allowed", h.logger)
+		return
+	}
+
+	var req SetTeamChannelRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httperr.WriteJSONError(w, http.StatusBadRequest, httperr.ErrorCodeInvalidJSON, "invalid JSON body", h.logger)
+		return
+	}
+
+	if req.TeamName == "" {
+		httperr.WriteJSONError(w, http.StatusBadRequest, httperr.ErrorCodeValidation, "team_name is required", h.logger)
+		return
+	}
+
+	if u, err := url.Parse(req.WebhookURL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		httperr.WriteJSONError(w, http.StatusBadRequest, httperr.ErrorCodeValidation, "webhook_url must be an absolute http(s) URL", h.logger)
+		return
+	}
+
+	if h.logger != nil {
+		h.logger.InfoContext(r.Context(), "handleNotificationSetTeamChannel", slog.String("team_name", req.TeamName), slog.String("channel", req.Channel))
+	}
+
+	channel := domain.TeamChatChannel{
+		TeamName:   domain.TeamName(req.TeamName),
+		WebhookURL: req.WebhookURL,
+		Channel:    req.Channel,
+	}
+
+	if err := h.svc.SetTeamChatChannel(r.Context(), channel); err != nil {
+		if errors.Is(err, service.ErrNotFound) {
+			httperr.WriteJSONError(w, http.StatusNotFound, httperr.ErrorCodeNotFound, "team not found", h.logger)
+			return
+		}
+
+		if errors.Is(err, service.ErrForbidden) {
+			httperr.WriteJSONError(w, http.StatusForbidden, httperr.ErrorCodeForbidden, "only a lead of the team or an admin may change its chat channel", h.logger)
+			return
+		}
+
+		if h.logger != nil {
+			h.logger.ErrorContext(r.Context(), "handleNotificationSetTeamChannel: SetTeamChatChannel error", slog.Any("error", err))
+		}
+
+		httperr.WriteJSONError(w, http.StatusInternalServerError, httperr.ErrorCodeInternal, "internal server error", h.logger)
+		return
+	}
+
+	resp := TeamChannelEnvelope{
+		TeamChannel: mapTeamChannelToDTO(channel),
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		if h.logger != nil {
+			h.logger.ErrorContext(r.Context(), "handleNotificationSetTeamChannel: failed to write response", slog.Any("error", err))
+		}
+	}
+}
+
+// RemoveTeamChannel обрабатывает удаление канала чата команды.
+func (h *Handler) RemoveTeamChannel(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		httperr.WriteJSONError(w, http.StatusMethodNotAllowed, httperr.ErrorCodeMethodNotAllowed, "method not allowed", h.logger)
+		return
+	}
+
+	var req RemoveTeamChannelRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httperr.WriteJSONError(w, http.StatusBadRequest, httperr.ErrorCodeInvalidJSON, "invalid JSON body", h.logger)
+		return
+	}
+
+	if req.TeamName == "" {
+		httperr.WriteJSONError(w, http.StatusBadRequest, httperr.ErrorCodeValidation, "team_name is required", h.logger)
+		return
+	}
+
+	if h.logger != nil {
+		h.logger.InfoContext(r.Context(), "handleNotificationRemoveTeamChannel", slog.String("team_name", req.TeamName))
+	}
+
+	if err := h.svc.DeleteTeamChatChannel(r.Context(), domain.TeamName(req.TeamName)); err != nil {
+		if errors.Is(err, service.ErrNotFound) {
+			httperr.WriteJSONError(w, http.StatusNotFound, httperr.ErrorCodeNotFound, "team channel not found", h.logger)
+			return
+		}
+
+		if errors.Is(err, service.ErrForbidden) {
+			httperr.WriteJSONError(w, http.StatusForbidden, httperr.ErrorCodeForbidden, "only a lead of the team or an admin may change its chat channel", h.logger)
+			return
+		}
+
+		if h.logger != nil {
+			h.logger.ErrorContext(r.Context(), "handleNotificationRemoveTeamChannel: DeleteTeamChatChannel error", slog.Any("error", err))
+		}
+
+		httperr.WriteJSONError(w, http.StatusInternalServerError, httperr.ErrorCodeInternal, "internal server error", h.logger)
+		return
+	}
+
+	resp := RemoveTeamChannelResponse{
+		TeamName: req.TeamName,
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		if h.logger != nil {
+			h.logger.ErrorContext(r.Context(), "handleNotificationRemoveTeamChannel: failed to write response", slog.Any("error", err))
+		}
+	}
+}
@@ -0,0 +1,23 @@
+// Package notification содержит обработчики и DTO для настроек уведомлений.
+package notification
+
+// SetPreferencesRequest описывает тело запроса /notifications/setPreferences.
+// Не переданные поля не меняются.
+type SetPreferencesRequest struct {
+	UserID      string  `json:"user_id"`
+	ChatEnabled *bool   `json:"chat_enabled"`
+	Email       *string `json:"email"`
+	EmailMode   *string `json:"email_mode"`
+}
+
+// SetTeamChannelRequest описывает тело запроса /notifications/setTeamChannel.
+type SetTeamChannelRequest struct {
+	TeamName   string `json:"team_name"`
+	WebhookURL string `json:"webhook_url"`
+	Channel    string `json:"channel"`
+}
+
+// RemoveTeamChannelRequest описывает тело запроса /notifications/removeTeamChannel.
+type RemoveTeamChannelRequest struct {
+	TeamName string `json:"team_name"`
+}
@@ -0,0 +1,286 @@
+// Package openapi проверяет HTTP-запросы и ответы по спецификации API (api/openapi.yml).
+package openapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"mime"
+	"net/http"
+	"strconv"
+
+	"github.com/dixitix/pr-reviewer-service/internal/http/httperr"
+)
+
+const (
+	// maxBodySize — максимальный размер проверяемого тела запроса.
+	maxBodySize = 1 << 20
+	// jsonContentType — тип тел запросов и ответов, которые проверяются по схеме.
+	jsonContentType = "application/json"
+)
+
+// Config описывает, что проверяет middleware.
+type Config struct {
+	// ValidateResponses включает проверку JSON-ответов. Ответ клиенту не меняется,
+	// расхождения передаются в OnInvalidResponse.
+	ValidateResponses bool
+	// OnInvalidResponse получает расхождения ответа со спецификацией. По умолчанию они логируются.
+	OnInvalidResponse func(r *http.Request, status int, errs []httperr.FieldError)
+}
+
+// Middleware проверяет запросы и ответы по спецификации.
+type Middleware struct {
+	doc    *Document
+	cfg    Config
+	logger *slog.Logger
+}
+
+// New создаёт middleware проверки по разобранной спецификации.
+func New(doc *Document, cfg Config, logger *slog.Logger) *Middleware {
+	m := &Middleware{
+		doc:    doc,
+		cfg:    cfg,
+		logger: logger,
+	}
+
+	if m.cfg.OnInvalidResponse == nil {
+		m.cfg.OnInvalidResponse = m.logInvalidResponse
+	}
+
+	return m
+}
+
+// Wrap проверяет параметры и JSON-тело запроса: обязательные поля, типы, enum, ограничения длины,
+// шаблоны и диапазоны; поля, которых нет в схеме, отклоняются. Ошибки возвращаются как
+// 400 VALIDATION_ERROR с details по полям. Запросы к путям и методам, которых нет в спецификации,
+// и тела с некорректным JSON передаются обработчику как есть: на них он отвечает сам.
+func (m *Middleware) Wrap(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		op, pathValues, ok := m.doc.Find(r.Method, r.URL.Path)
+		if !ok || op == nil {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		errs := validateParameters(op, r, pathValues)
+
+		if op.RequestBody != nil {
+			if mt := op.RequestBody.Content[jsonContentType]; mt != nil {
+				body, err := io.ReadAll(io.LimitReader(r.Body, maxBodySize+1))
+				if err != nil {
+					httperr.WriteJSONError(w, http.StatusBadRequest, httperr.ErrorCodeInvalidJSON, "failed to read request body", m.logger)
+					return
+				}
+
+				if len(body) > maxBodySize {
+					httperr.WriteJSONError(w, http.StatusRequestEntityTooLarge, httperr.ErrorCodeValidation, "request body is too large", m.logger)
+					return
+				}
+
+				r.Body = io.NopCloser(bytes.NewReader(body))
+
+				errs = append(errs, validateBody(op.RequestBody.Required, mt.Schema, body)...)
+			}
+		}
+
+		if len(errs) > 0 {
+			httperr.WriteValidationError(w, errs, m.logger)
+			return
+		}
+
+		if !m.cfg.ValidateResponses {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		rec := &recorder{ResponseWriter: w, status: http.StatusOK}
+		next.ServeHTTP(rec, r)
+
+		if errs := m.validateResponse(op, rec); len(errs) > 0 {
+			m.cfg.OnInvalidResponse(r, rec.status, errs)
+		}
+	})
+}
+
+// validateParameters проверяет параметры пути, строки запроса и заголовки.
+func validateParameters(op *Operation, r *http.Request, pathValues map[string]string) []httperr.FieldError {
+	var errs []httperr.FieldError
+
+	for _, p := range op.Parameters {
+		var raw string
+
+		switch p.In {
+		case "path":
+			raw = pathValues[p.Name]
+		case "query":
+			raw = r.URL.Query().Get(p.Name)
+		case "header":
+			raw = r.Header.Get(p.Name)
+		default:
+			continue
+		}
+
+		field := p.In + "." + p.Name
+
+		if raw == "" {
+			if p.Required {
+				errs = append(errs, httperr.FieldError{Field: field, Message: "is required"})
+			}
+
+			continue
+		}
+
+		v, ok := p.Schema.parse(raw)
+		if !ok {
+			errs = append(errs, httperr.FieldError{Field: field, Message: "must be a " + schemaType(p.Schema)})
+			continue
+		}
+
+		errs = append(errs, p.Schema.Validate(v, field)...)
+	}
+
+	return errs
+}
+
+// validateBody проверяет JSON-тело. Тело с некорректным JSON не проверяется: обработчик ответит INVALID_JSON.
+func validateBody(required bool, schema *Schema, body []byte) []httperr.FieldError {
+	if len(bytes.TrimSpace(body)) == 0 {
+		if required {
+			return []httperr.FieldError{{Field: "body", Message: "is required"}}
+		}
+
+		return nil
+	}
+
+	v, ok := decodeJSON(body)
+	if !ok {
+		return nil
+	}
+
+	return schema.Validate(v, "")
+}
+
+// validateResponse проверяет JSON-ответ по схеме его статуса. Ошибки без описанного статуса
+// проверяются по схеме ErrorResponse, успешные ответы без описанного статуса считаются расхождением.
+func (m *Middleware) validateResponse(op *Operation, rec *recorder) []httperr.FieldError {
+	resp, ok := op.Responses[strconv.Itoa(rec.status)]
+	if !ok {
+		resp, ok = op.Responses["default"]
+	}
+
+	if !ok {
+		if rec.status < http.StatusBadRequest {
+			return []httperr.FieldError{{Field: "status", Message: "status " + strconv.Itoa(rec.status) + " is not documented"}}
+		}
+
+		resp = &Response{Content: map[string]*MediaType{
+			jsonContentType: {Schema: m.doc.Components.Schemas["ErrorResponse"]},
+		}}
+	}
+
+	mediaType := rec.mediaType()
+
+	if len(resp.Content) == 0 {
+		if rec.body.Len() > 0 {
+			return []httperr.FieldError{{Field: "body", Message: "must be empty"}}
+		}
+
+		return nil
+	}
+
+	mt, ok := resp.Content[mediaType]
+	if !ok {
+		return []httperr.FieldError{{Field: "Content-Type", Message: "content type " + strconv.Quote(mediaType) + " is not documented"}}
+	}
+
+	if mediaType != jsonContentType {
+		return nil
+	}
+
+	v, ok := decodeJSON(rec.body.Bytes())
+	if !ok {
+		return []httperr.FieldError{{Field: "body", Message: "must be valid JSON"}}
+	}
+
+	return mt.Schema.Validate(v, "")
+}
+
+func (m *Middleware) logInvalidResponse(r *http.Request, status int, errs []httperr.FieldError) {
+	if m.logger == nil {
+		return
+	}
+
+	m.logger.WarnContext(r.Context(), "openapi middleware: response does not match the specification",
+		slog.String("method", r.Method),
+		slog.String("path", r.URL.Path),
+		slog.Int("status", status),
+		slog.Any("errors", errs),
+	)
+}
+
+func decodeJSON(body []byte) (any, bool) {
+	dec := json.NewDecoder(bytes.NewReader(body))
+	dec.UseNumber()
+
+	var v any
+	if err := dec.Decode(&v); err != nil {
+		return nil, false
+	}
+
+	return v, true
+}
+
+func schemaType(s *Schema) string {
+	for s != nil && s.target != nil {
+		s = s.target
+	}
+
+	if s == nil || s.Type == "" {
+		return "string"
+	}
+
+	return s.Type
+}
+
+// recorder передаёт ответ клиенту и запоминает статус и JSON-тело для проверки.
+// Тела других типов (например, потока событий) не запоминаются.
+type recorder struct {
+	http.ResponseWriter
+	status      int
+	wroteHeader bool
+	body        bytes.Buffer
+}
+
+func (r *recorder) WriteHeader(status int) {
+	if !r.wroteHeader {
+		r.status = status
+		r.wroteHeader = true
+	}
+
+	r.ResponseWriter.WriteHeader(status)
+}
+
+func (r *recorder) Write(p []byte) (int, error) {
+	r.wroteHeader = true
+
+	if r.mediaType() == jsonContentType {
+		r.body.Write(p)
+	}
+
+	return r.ResponseWriter.Write(p)
+}
+
+// Unwrap даёт http.ResponseController доступ к Flush и дедлайнам исходного ResponseWriter.
+func (r *recorder) Unwrap() http.ResponseWriter {
+	return r.ResponseWriter
+}
+
+func (r *recorder) mediaType() string {
+	mediaType, _, err := mime.ParseMediaType(r.Header().Get("Content-Type"))
+	if err != nil {
+		return r.Header().Get("Content-Type")
+	}
+
+	return mediaType
+}
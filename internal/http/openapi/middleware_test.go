@@ -0,0 +1,158 @@
+package openapi
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/dixitix/pr-reviewer-service/internal/http/httperr"
+)
+
+const testSpec = `
+openapi: 3.0.3
+paths:
+  /items/{id}:
+    post:
+      parameters:
+        - name: id
+          in: path
+          required: true
+          schema: { type: string, pattern: '^[a-z]+$' }
+        - name: limit
+          in: query
+          schema: { type: integer, minimum: 1, maximum: 10 }
+      requestBody:
+        required: true
+        content:
+          application/json:
+            schema:
+              $ref: '#/components/schemas/Item'
+      responses:
+        '200':
+          content:
+            application/json:
+              schema:
+                $ref: '#/components/schemas/Item'
+components:
+  schemas:
+    Item:
+      type: object
+      required: [ name ]
+      properties:
+        name: { type: string, minLength: 1, maxLength: 5 }
+        tags:
+          type: array
+          items: { type: string, enum: [a, b] }
+        meta:
+          type: object
+`
+
+// echo отвечает телом запроса.
+var echo = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+	body, _ := io.ReadAll(r.Body)
+
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write(body)
+})
+
+func newTestMiddleware(t *testing.T, cfg Config) *Middleware {
+	t.Helper()
+
+	doc, err := Load([]byte(testSpec))
+	if err != nil {
+		t.Fatalf("Load returned error: %v", err)
+	}
+
+	return New(doc, cfg, nil)
+}
+
+// TestWrap_Request проверяет проверку параметров и тела запроса.
+func TestWrap_Request(t *testing.T) {
+	h := newTestMiddleware(t, Config{}).Wrap(echo)
+
+	cases := []struct {
+		name   string
+		target string
+		body   string
+		status int
+		fields []string
+	}{
+		{"valid", "/items/abc?limit=5", `{"name":"x","tags":["a"],"meta":{"any":1}}`, http.StatusOK, nil},
+		{"path pattern", "/items/ABC", `{"name":"x"}`, http.StatusBadRequest, []string{"path.id"}},
+		{"query type", "/items/abc?limit=many", `{"name":"x"}`, http.StatusBadRequest, []string{"query.limit"}},
+		{"query range", "/items/abc?limit=11", `{"name":"x"}`, http.StatusBadRequest, []string{"query.limit"}},
+		{"required body", "/items/abc", ``, http.StatusBadRequest, []string{"body"}},
+		{"body fields", "/items/abc", `{"name":"toolong","tags":["c"],"extra":true}`, http.StatusBadRequest, []string{"extra", "name", "tags[0]"}},
+		{"invalid json is left to handler", "/items/abc", `{"name":`, http.StatusOK, nil},
+		{"undocumented path", "/other", `{}`, http.StatusOK, nil},
+	}
+
+	for _, tc := range cases {
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, tc.target, strings.NewReader(tc.body)))
+
+		if rec.Code != tc.status {
+			t.Fatalf("%s: expected %d, got %d: %s", tc.name, tc.status, rec.Code, rec.Body.String())
+		}
+
+		for _, field := range tc.fields {
+			if !strings.Contains(rec.Body.String(), `"field":"`+field+`"`) {
+				t.Fatalf("%s: expected error for %s, got %s", tc.name, field, rec.Body.String())
+			}
+		}
+	}
+
+	// Тело, прочитанное при проверке, доходит до обработчика.
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/items/abc", strings.NewReader(`{"name":"x"}`)))
+
+	if rec.Body.String() != `{"name":"x"}` {
+		t.Fatalf("expected request body to reach handler, got %q", rec.Body.String())
+	}
+}
+
+// TestWrap_Response проверяет, что расхождения ответа передаются в OnInvalidResponse, а сам ответ не меняется.
+func TestWrap_Response(t *testing.T) {
+	var got []httperr.FieldError
+
+	h := newTestMiddleware(t, Config{
+		ValidateResponses: true,
+		OnInvalidResponse: func(_ *http.Request, _ int, errs []httperr.FieldError) {
+			got = errs
+		},
+	}).Wrap(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = io.WriteString(w, `{"title":"x"}`)
+	}))
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/items/abc", strings.NewReader(`{"name":"x"}`)))
+
+	if rec.Code != http.StatusOK || rec.Body.String() != `{"title":"x"}` {
+		t.Fatalf("response must be passed through, got %d %q", rec.Code, rec.Body.String())
+	}
+
+	if len(got) != 2 || got[0].Field != "name" || got[1].Field != "title" {
+		t.Fatalf("unexpected response errors: %+v", got)
+	}
+}
+
+// TestLoad_UnsupportedKeyword проверяет, что спецификация с неподдерживаемым ключевым словом схемы
+// не загружается, а аннотации и расширения x-* допускаются.
+func TestLoad_UnsupportedKeyword(t *testing.T) {
+	for _, keyword := range []string{"allOf", "oneOf", "anyOf", "not", "additionalProperties"} {
+		spec := strings.Replace(testSpec, "        meta:\n", "        meta:\n          "+keyword+": []\n", 1)
+
+		_, err := Load([]byte(spec))
+		if err == nil || !strings.Contains(err.Error(), `unsupported schema keyword "`+keyword+`"`) {
+			t.Fatalf("%s: expected unsupported keyword error, got %v", keyword, err)
+		}
+	}
+
+	spec := strings.Replace(testSpec, "        meta:\n", "        meta:\n          description: any\n          readOnly: true\n          x-internal: true\n", 1)
+	if _, err := Load([]byte(spec)); err != nil {
+		t.Fatalf("annotations must be accepted, got %v", err)
+	}
+}
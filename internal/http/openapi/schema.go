@@ -0,0 +1,248 @@
+// Package openapi проверяет HTTP-запросы и ответы по спецификации API (api/openapi.yml).
+package openapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/dixitix/pr-reviewer-service/internal/http/httperr"
+)
+
+// Validate проверяет значение, разобранное json.Decoder с UseNumber, и возвращает ошибки по полям.
+// path — путь к значению, для корня тела — пустая строка.
+func (s *Schema) Validate(v any, path string) []httperr.FieldError {
+	var errs []httperr.FieldError
+	s.validate(v, path, &errs)
+
+	return errs
+}
+
+func (s *Schema) validate(v any, path string, errs *[]httperr.FieldError) {
+	if s == nil {
+		return
+	}
+
+	if s.target != nil {
+		s.target.validate(v, path, errs)
+		return
+	}
+
+	fail := func(format string, args ...any) {
+		*errs = append(*errs, httperr.FieldError{Field: fieldName(path), Message: fmt.Sprintf(format, args...)})
+	}
+
+	if v == nil {
+		if !s.Nullable && s.Type != "" {
+			fail("must not be null")
+		}
+
+		return
+	}
+
+	switch s.Type {
+	case "object":
+		obj, ok := v.(map[string]any)
+		if !ok {
+			fail("must be an object")
+			return
+		}
+
+		s.validateObject(obj, path, errs)
+	case "array":
+		arr, ok := v.([]any)
+		if !ok {
+			fail("must be an array")
+			return
+		}
+
+		if s.MinItems != nil && len(arr) < *s.MinItems {
+			fail("must contain at least %d items", *s.MinItems)
+		}
+
+		if s.MaxItems != nil && len(arr) > *s.MaxItems {
+			fail("must contain at most %d items", *s.MaxItems)
+		}
+
+		for i, item := range arr {
+			s.Items.validate(item, fmt.Sprintf("%s[%d]", path, i), errs)
+		}
+	case "string":
+		str, ok := v.(string)
+		if !ok {
+			fail("must be a string")
+			return
+		}
+
+		s.validateString(str, fail)
+	case "integer", "number":
+		num, ok := v.(json.Number)
+		if !ok {
+			fail("must be a %s", s.Type)
+			return
+		}
+
+		s.validateNumber(num, fail)
+	case "boolean":
+		if _, ok := v.(bool); !ok {
+			fail("must be a boolean")
+			return
+		}
+	}
+
+	if len(s.Enum) > 0 && !s.allows(v) {
+		fail("must be one of %s", s.enumList())
+	}
+}
+
+// validateObject проверяет обязательные поля, поля из properties и отклоняет неописанные поля.
+func (s *Schema) validateObject(obj map[string]any, path string, errs *[]httperr.FieldError) {
+	for _, name := range s.Required {
+		if _, ok := obj[name]; !ok {
+			*errs = append(*errs, httperr.FieldError{Field: join(path, name), Message: "is required"})
+		}
+	}
+
+	names := make([]string, 0, len(obj))
+	for name := range obj {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	for _, name := range names {
+		prop, ok := s.Properties[name]
+		if !ok && s.Properties == nil {
+			// Объект без properties — произвольный JSON, например тело события.
+			continue
+		}
+
+		if !ok {
+			*errs = append(*errs, httperr.FieldError{Field: join(path, name), Message: "unknown field"})
+			continue
+		}
+
+		prop.validate(obj[name], join(path, name), errs)
+	}
+}
+
+func (s *Schema) validateString(str string, fail func(string, ...any)) {
+	length := utf8.RuneCountInString(str)
+
+	if s.MinLength != nil && length < *s.MinLength {
+		if *s.MinLength == 1 {
+			fail("must not be empty")
+		} else {
+			fail("must be at least %d characters long", *s.MinLength)
+		}
+	}
+
+	if s.MaxLength != nil && length > *s.MaxLength {
+		fail("must be at most %d characters long", *s.MaxLength)
+	}
+
+	if s.pattern != nil && !s.pattern.MatchString(str) {
+		fail("must match pattern %s", s.Pattern)
+	}
+
+	if s.Format == "date-time" {
+		if _, err := time.Parse(time.RFC3339, str); err != nil {
+			fail("must be an RFC 3339 date-time")
+		}
+	}
+}
+
+func (s *Schema) validateNumber(num json.Number, fail func(string, ...any)) {
+	if s.Type == "integer" {
+		if _, err := num.Int64(); err != nil {
+			fail("must be an integer")
+			return
+		}
+	}
+
+	f, err := num.Float64()
+	if err != nil {
+		fail("must be a number")
+		return
+	}
+
+	if s.Minimum != nil && f < *s.Minimum {
+		fail("must be at least %s", formatFloat(*s.Minimum))
+	}
+
+	if s.Maximum != nil && f > *s.Maximum {
+		fail("must be at most %s", formatFloat(*s.Maximum))
+	}
+}
+
+// parse приводит строковое значение параметра к типу схемы, чтобы проверить его как JSON-значение.
+func (s *Schema) parse(raw string) (any, bool) {
+	if s == nil {
+		return raw, true
+	}
+
+	if s.target != nil {
+		return s.target.parse(raw)
+	}
+
+	switch s.Type {
+	case "integer", "number":
+		if _, err := strconv.ParseFloat(raw, 64); err != nil {
+			return nil, false
+		}
+
+		return json.Number(raw), true
+	case "boolean":
+		b, err := strconv.ParseBool(raw)
+		if err != nil {
+			return nil, false
+		}
+
+		return b, true
+	default:
+		return raw, true
+	}
+}
+
+func (s *Schema) allows(v any) bool {
+	for _, e := range s.Enum {
+		if fmt.Sprint(e) == fmt.Sprint(v) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (s *Schema) enumList() string {
+	values := make([]string, len(s.Enum))
+	for i, e := range s.Enum {
+		values[i] = fmt.Sprint(e)
+	}
+
+	return strings.Join(values, ", ")
+}
+
+func join(path, name string) string {
+	if path == "" {
+		return name
+	}
+
+	return path + "." + name
+}
+
+func fieldName(path string) string {
+	if path == "" {
+		return "body"
+	}
+
+	return path
+}
+
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'f', -1, 64)
+}
@@ -0,0 +1,348 @@
+// Package openapi проверяет HTTP-запросы и ответы по спецификации API (api/openapi.yml).
+// Поддерживается подмножество OpenAPI 3.0, которое используется в спецификации сервиса;
+// спецификацию с другими ключевыми словами схем Load отклоняет.
+package openapi
+
+import (
+	"fmt"
+	"net/http"
+	"regexp"
+	"sort"
+	"strings"
+
+	"go.yaml.in/yaml/v3"
+)
+
+// Document — разобранная спецификация.
+type Document struct {
+	Paths      map[string]*PathItem `yaml:"paths"`
+	Components Components           `yaml:"components"`
+
+	routes []route
+}
+
+// Components — переиспользуемые схемы и параметры.
+type Components struct {
+	Schemas    map[string]*Schema    `yaml:"schemas"`
+	Parameters map[string]*Parameter `yaml:"parameters"`
+}
+
+// PathItem — операции одного пути.
+type PathItem struct {
+	Get    *Operation `yaml:"get"`
+	Post   *Operation `yaml:"post"`
+	Put    *Operation `yaml:"put"`
+	Patch  *Operation `yaml:"patch"`
+	Delete *Operation `yaml:"delete"`
+}
+
+// Operation — операция: параметры, тело запроса и ответы по статусам.
+type Operation struct {
+	Parameters  []*Parameter         `yaml:"parameters"`
+	RequestBody *RequestBody         `yaml:"requestBody"`
+	Responses   map[string]*Response `yaml:"responses"`
+}
+
+// Parameter — параметр запроса в пути, строке запроса или заголовке.
+type Parameter struct {
+	Ref      string  `yaml:"$ref"`
+	Name     string  `yaml:"name"`
+	In       string  `yaml:"in"`
+	Required bool    `yaml:"required"`
+	Schema   *Schema `yaml:"schema"`
+}
+
+// RequestBody — тело запроса.
+type RequestBody struct {
+	Required bool                  `yaml:"required"`
+	Content  map[string]*MediaType `yaml:"content"`
+}
+
+// Response — ответ с определённым статусом.
+type Response struct {
+	Content map[string]*MediaType `yaml:"content"`
+}
+
+// MediaType — схема тела определённого типа.
+type MediaType struct {
+	Schema *Schema `yaml:"schema"`
+}
+
+// Schema — JSON-схема значения. Объекты с properties не допускают других полей.
+type Schema struct {
+	Ref        string             `yaml:"$ref"`
+	Type       string             `yaml:"type"`
+	Format     string             `yaml:"format"`
+	Nullable   bool               `yaml:"nullable"`
+	Enum       []any              `yaml:"enum"`
+	Required   []string           `yaml:"required"`
+	Properties map[string]*Schema `yaml:"properties"`
+	Items      *Schema            `yaml:"items"`
+	MinLength  *int               `yaml:"minLength"`
+	MaxLength  *int               `yaml:"maxLength"`
+	Pattern    string             `yaml:"pattern"`
+	Minimum    *float64           `yaml:"minimum"`
+	Maximum    *float64           `yaml:"maximum"`
+	MinItems   *int               `yaml:"minItems"`
+	MaxItems   *int               `yaml:"maxItems"`
+
+	target  *Schema
+	pattern *regexp.Regexp
+}
+
+// schemaKeywords — ключевые слова схемы, которые проверяет Validate.
+var schemaKeywords = map[string]bool{
+	"$ref": true, "type": true, "format": true, "nullable": true, "enum": true, "required": true,
+	"properties": true, "items": true, "minLength": true, "maxLength": true, "pattern": true,
+	"minimum": true, "maximum": true, "minItems": true, "maxItems": true,
+}
+
+// annotationKeywords — ключевые слова схемы, которые только описывают значение и не проверяются.
+var annotationKeywords = map[string]bool{
+	"description": true, "example": true, "default": true, "title": true,
+	"deprecated": true, "readOnly": true, "writeOnly": true,
+}
+
+// UnmarshalYAML разбирает схему и отклоняет неподдерживаемые ключевые слова (allOf, oneOf,
+// additionalProperties и т. п.), чтобы изменение спецификации не отключило проверку незаметно.
+// Расширения x-* допускаются.
+func (s *Schema) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.MappingNode {
+		for i := 0; i < len(node.Content); i += 2 {
+			key := node.Content[i].Value
+			if !schemaKeywords[key] && !annotationKeywords[key] && !strings.HasPrefix(key, "x-") {
+				return fmt.Errorf("line %d: unsupported schema keyword %q", node.Content[i].Line, key)
+			}
+		}
+	}
+
+	// plain не наследует UnmarshalYAML, иначе Decode вызвал бы этот метод рекурсивно.
+	type plain Schema
+
+	return node.Decode((*plain)(s))
+}
+
+// route — шаблон пути спецификации, разбитый на сегменты.
+type route struct {
+	path     string
+	segments []string
+	params   int
+	item     *PathItem
+}
+
+// Load разбирает спецификацию, разрешает ссылки $ref и компилирует шаблоны pattern.
+// Схема с неподдерживаемым ключевым словом — ошибка (см. Schema.UnmarshalYAML).
+func Load(data []byte) (*Document, error) {
+	var doc Document
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("parse openapi: %w", err)
+	}
+
+	for name, s := range doc.Components.Schemas {
+		if err := doc.resolveSchema(s); err != nil {
+			return nil, fmt.Errorf("schema %s: %w", name, err)
+		}
+	}
+
+	for path, item := range doc.Paths {
+		for method, op := range item.operations() {
+			if err := doc.resolveOperation(op); err != nil {
+				return nil, fmt.Errorf("%s %s: %w", method, path, err)
+			}
+		}
+
+		segments := strings.Split(strings.Trim(path, "/"), "/")
+
+		params := 0
+		for _, seg := range segments {
+			if isTemplate(seg) {
+				params++
+			}
+		}
+
+		doc.routes = append(doc.routes, route{path: path, segments: segments, params: params, item: item})
+	}
+
+	// Пути без параметров проверяются раньше шаблонных, как в http.ServeMux.
+	sort.Slice(doc.routes, func(i, j int) bool {
+		if doc.routes[i].params != doc.routes[j].params {
+			return doc.routes[i].params < doc.routes[j].params
+		}
+
+		return strings.Join(doc.routes[i].segments, "/") < strings.Join(doc.routes[j].segments, "/")
+	})
+
+	return &doc, nil
+}
+
+// Find возвращает операцию для метода и пути запроса и значения параметров пути.
+// Если путь не описан, возвращается false; если путь описан без этого метода — nil операция и true.
+func (d *Document) Find(method, path string) (*Operation, map[string]string, bool) {
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+
+	for _, rt := range d.routes {
+		values, ok := rt.match(segments)
+		if !ok {
+			continue
+		}
+
+		return rt.item.operations()[method], values, true
+	}
+
+	return nil, nil, false
+}
+
+// Route возвращает шаблон пути спецификации для метода и пути запроса, например /v2/teams/{name}.
+// Если такой операции нет, возвращается false.
+func (d *Document) Route(method, path string) (string, bool) {
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+
+	for _, rt := range d.routes {
+		if _, ok := rt.match(segments); !ok {
+			continue
+		}
+
+		_, ok := rt.item.operations()[method]
+
+		return rt.path, ok
+	}
+
+	return "", false
+}
+
+// Operations возвращает все операции спецификации по ключу "METHOD /path".
+func (d *Document) Operations() map[string]*Operation {
+	result := make(map[string]*Operation)
+
+	for path, item := range d.Paths {
+		for method, op := range item.operations() {
+			result[method+" "+path] = op
+		}
+	}
+
+	return result
+}
+
+func (rt route) match(segments []string) (map[string]string, bool) {
+	if len(segments) != len(rt.segments) {
+		return nil, false
+	}
+
+	values := make(map[string]string, rt.params)
+
+	for i, seg := range rt.segments {
+		if isTemplate(seg) {
+			if segments[i] == "" {
+				return nil, false
+			}
+
+			values[seg[1:len(seg)-1]] = segments[i]
+
+			continue
+		}
+
+		if seg != segments[i] {
+			return nil, false
+		}
+	}
+
+	return values, true
+}
+
+// operations возвращает операции пути по HTTP-методу.
+func (p *PathItem) operations() map[string]*Operation {
+	result := make(map[string]*Operation)
+
+	for method, op := range map[string]*Operation{
+		http.MethodGet:    p.Get,
+		http.MethodPost:   p.Post,
+		http.MethodPut:    p.Put,
+		http.MethodPatch:  p.Patch,
+		http.MethodDelete: p.Delete,
+	} {
+		if op != nil {
+			result[method] = op
+		}
+	}
+
+	return result
+}
+
+func (d *Document) resolveOperation(op *Operation) error {
+	for i, p := range op.Parameters {
+		if p.Ref != "" {
+			target, ok := d.Components.Parameters[strings.TrimPrefix(p.Ref, "#/components/parameters/")]
+			if !ok {
+				return fmt.Errorf("unknown parameter %s", p.Ref)
+			}
+
+			op.Parameters[i] = target
+			p = target
+		}
+
+		if err := d.resolveSchema(p.Schema); err != nil {
+			return fmt.Errorf("parameter %s: %w", p.Name, err)
+		}
+	}
+
+	if op.RequestBody != nil {
+		for _, mt := range op.RequestBody.Content {
+			if err := d.resolveSchema(mt.Schema); err != nil {
+				return fmt.Errorf("request body: %w", err)
+			}
+		}
+	}
+
+	for status, resp := range op.Responses {
+		for _, mt := range resp.Content {
+			if err := d.resolveSchema(mt.Schema); err != nil {
+				return fmt.Errorf("response %s: %w", status, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// resolveSchema связывает $ref со схемой из components и компилирует pattern во всём дереве схемы.
+func (d *Document) resolveSchema(s *Schema) error {
+	if s == nil {
+		return nil
+	}
+
+	if s.Ref != "" {
+		if s.target != nil {
+			return nil
+		}
+
+		target, ok := d.Components.Schemas[strings.TrimPrefix(s.Ref, "#/components/schemas/")]
+		if !ok {
+			return fmt.Errorf("unknown schema %s", s.Ref)
+		}
+
+		s.target = target
+
+		return nil
+	}
+
+	if s.Pattern != "" && s.pattern == nil {
+		re, err := regexp.Compile(s.Pattern)
+		if err != nil {
+			return fmt.Errorf("pattern %q: %w", s.Pattern, err)
+		}
+
+		s.pattern = re
+	}
+
+	for name, prop := range s.Properties {
+		if err := d.resolveSchema(prop); err != nil {
+			return fmt.Errorf("property %s: %w", name, err)
+		}
+	}
+
+	return d.resolveSchema(s.Items)
+}
+
+func isTemplate(segment string) bool {
+	return strings.HasPrefix(segment, "{") && strings.HasSuffix(segment, "}")
+}
@@ -0,0 +1,16 @@
+// Package organization содержит обработчики организаций и определение организации запроса.
+package organization
+
+import "time"
+
+// DTO представляет организацию в HTTP-слое.
+type DTO struct {
+	OrganizationID string    `json:"organization_id"`
+	Name           string    `json:"name"`
+	CreatedAt      time.Time `json:"createdAt"`
+}
+
+// Envelope оборачивает организацию в поле "organization".
+type Envelope struct {
+	Organization DTO `json:"organization"`
+}
@@ -0,0 +1,156 @@
+// Package organization содержит обработчики организаций и определение организации запроса.
+package organization
+
+import (
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"regexp"
+	"strings"
+
+	"github.com/dixitix/pr-reviewer-service/internal/auth"
+	"github.com/dixitix/pr-reviewer-service/internal/domain"
+	"github.com/dixitix/pr-reviewer-service/internal/http/httperr"
+	"github.com/dixitix/pr-reviewer-service/internal/service"
+	"github.com/dixitix/pr-reviewer-service/internal/tenant"
+)
+
+// HeaderOrganization — заголовок с идентификатором организации вызывающего.
+const HeaderOrganization = "X-Organization-ID"
+
+// idPattern ограничивает идентификаторы организаций безопасными для заголовков и логов символами.
+var idPattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9_.-]{0,63}$`)
+
+// Handler обрабатывает HTTP-запросы управления организациями.
+type Handler struct {
+	svc        service.OrganizationService
+	defaultOrg domain.OrganizationID
+	logger     *slog.Logger
+}
+
+// NewHandler создаёт обработчик организаций. defaultOrg используется для запросов без заголовка организации.
+func NewHandler(svc service.OrganizationService, defaultOrg domain.OrganizationID, logger *slog.Logger) *Handler {
+	return &Handler{
+		svc:        svc,
+		defaultOrg: defaultOrg,
+		logger:     logger,
+	}
+}
+
+// Add обрабатывает создание организации.
+func (h *Handler) Add(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		httperr.WriteJSONError(w, http.StatusMethodNotAllowed, httperr.ErrorCodeMethodNotAllowed, "method not allowed", h.logger)
+		return
+	}
+
+	var req CreateRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httperr.WriteJSONError(w, http.StatusBadRequest, httperr.ErrorCodeInvalidJSON, "invalid JSON body", h.logger)
+		return
+	}
+
+	if !idPattern.MatchString(req.OrganizationID) {
+		httperr.WriteJSONError(
+			w,
+			http.StatusBadRequest,
+			httperr.ErrorCodeValidation,
+			"organization_id must be 1-64 letters, digits, '.', '_' or '-'",
+			h.logger,
+		)
+
+		return
+	}
+
+	if strings.TrimSpace(req.Name) == "" {
+		req.Name = req.OrganizationID
+	}
+
+	ctx := r.Context()
+
+	if h.logger != nil {
+		h.logger.InfoContext(r.Context(), "handleOrganizationAdd", slog.String("organization_id", req.OrganizationID))
+	}
+
+	org, err := h.svc.CreateOrganization(ctx, domain.OrganizationID(req.OrganizationID), req.Name)
+	if err != nil {
+		if errors.Is(err, service.ErrOrganizationExists) {
+			httperr.WriteJSONError(w, http.StatusConflict, httperr.ErrorCodeOrganizationExists, "organization already exists", h.logger)
+			return
+		}
+
+		if h.logger != nil {
+			h.logger.ErrorContext(r.Context(), "handleOrganizationAdd: CreateOrganization error", slog.Any("error", err))
+		}
+
+		httperr.WriteJSONError(w, http.StatusInternalServerError, httperr.ErrorCodeInternal, "internal server error", h.logger)
+
+		return
+	}
+
+	resp := Envelope{
+		Organization: DTO{
+			OrganizationID: string(org.ID),
+			Name:           org.Name,
+			CreatedAt:      org.CreatedAt,
+		},
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		if h.logger != nil {
+			h.logger.ErrorContext(r.Context(), "handleOrganizationAdd: failed to write response", slog.Any("error", err))
+		}
+	}
+}
+
+// Middleware определяет организацию вызывающего и кладёт её в контекст запроса. Это организация
+// его API-ключа или токена (для токена без организации — организация по умолчанию), а для корневого
+// ключа и без аутентификации — заголовок X-Organization-ID или организация по умолчанию.
+// Запросы к несуществующей организации отклоняются, поэтому обработчики видят только данные
+// организации вызывающего.
+func (h *Handler) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := domain.OrganizationID(strings.TrimSpace(r.Header.Get(HeaderOrganization)))
+
+		// Ключ или токен работает только в своей организации; чужая организация для него не существует.
+		if caller, ok := auth.FromContext(r.Context()); ok && !caller.Root {
+			own := caller.Organization
+			if own == "" {
+				own = h.defaultOrg
+			}
+
+			if id != "" && id != own {
+				httperr.WriteJSONError(w, http.StatusNotFound, httperr.ErrorCodeNotFound, "organization not found", h.logger)
+				return
+			}
+
+			id = own
+		}
+
+		if id == "" {
+			id = h.defaultOrg
+		}
+
+		ok, err := h.svc.OrganizationExists(r.Context(), id)
+		if err != nil {
+			if h.logger != nil {
+				h.logger.ErrorContext(r.Context(), "organization middleware: OrganizationExists error", slog.Any("error", err))
+			}
+
+			httperr.WriteJSONError(w, http.StatusInternalServerError, httperr.ErrorCodeInternal, "internal server error", h.logger)
+
+			return
+		}
+
+		if !ok {
+			httperr.WriteJSONError(w, http.StatusNotFound, httperr.ErrorCodeNotFound, "organization not found", h.logger)
+			return
+		}
+
+		next.ServeHTTP(w, r.WithContext(tenant.WithOrganization(r.Context(), id)))
+	})
+}
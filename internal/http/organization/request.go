@@ -0,0 +1,8 @@
+// Package organization содержит обработчики организаций и определение организации запроса.
+package organization
+
+// CreateRequest описывает тело запроса /organizations/add.
+type CreateRequest struct {
+	OrganizationID string `json:"organization_id"`
+	Name           string `json:"name"`
+}
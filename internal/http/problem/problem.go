@@ -0,0 +1,241 @@
+// Package problem отдаёт ошибки в формате RFC 7807 (application/problem+json) клиентам,
+// которые запросили его в заголовке Accept. Остальные клиенты получают ErrorResponse, как раньше.
+package problem
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/dixitix/pr-reviewer-service/internal/http/httperr"
+)
+
+const (
+	// ContentType — тип ответов об ошибках в формате RFC 7807.
+	ContentType = "application/problem+json"
+	// TypePrefix — префикс URI типа ошибки; за ним следует код ошибки в нижнем регистре через дефис.
+	TypePrefix = "urn:pr-reviewer-service:problem:"
+	// jsonContentType — тип ответов об ошибках в формате ErrorResponse.
+	jsonContentType = "application/json"
+)
+
+// Problem — ответ об ошибке в формате RFC 7807.
+type Problem struct {
+	// Type — URI типа ошибки: TypePrefix и код, например urn:pr-reviewer-service:problem:not-found.
+	Type string `json:"type"`
+	// Title — краткое описание типа ошибки, одинаковое для всех ошибок этого типа.
+	Title string `json:"title"`
+	// Status — HTTP-статус ответа.
+	Status int `json:"status"`
+	// Detail — описание конкретной ошибки, совпадает с error.message в ErrorResponse.
+	Detail string `json:"detail,omitempty"`
+	// Instance — путь запроса, на который получена ошибка.
+	Instance string `json:"instance,omitempty"`
+	// Code — код ошибки из ErrorResponse, чтобы клиентам не приходилось разбирать type.
+	Code string `json:"code,omitempty"`
+	// Errors — ошибки по полям, как error.details в ErrorResponse.
+	Errors []httperr.FieldError `json:"errors,omitempty"`
+}
+
+// titles — описания типов ошибок по кодам.
+var titles = map[string]string{
+	httperr.ErrorCodeTeamExists:           "Team already exists",
+	httperr.ErrorCodePRExists:             "Pull request already exists",
+	httperr.ErrorCodePRMerged:             "Pull request is merged",
+	httperr.ErrorCodeNotAssigned:          "Reviewer is not assigned",
+	httperr.ErrorCodeNoCandidate:          "No replacement candidate",
+	httperr.ErrorCodeInvalidJSON:          "Malformed JSON body",
+	httperr.ErrorCodeValidation:           "Request validation failed",
+	httperr.ErrorCodeInternal:             "Internal server error",
+	httperr.ErrorCodeMethodNotAllowed:     "Method not allowed",
+	httperr.ErrorCodeNotFound:             "Resource not found",
+	httperr.ErrorCodeOrganizationExists:   "Organization already exists",
+	httperr.ErrorCodeUnauthorized:         "Authentication required",
+	httperr.ErrorCodeInsufficientScope:    "Insufficient API key scope",
+	httperr.ErrorCodeForbidden:            "Access forbidden",
+	httperr.ErrorCodeIdempotencyKeyReused: "Idempotency key reused",
+	httperr.ErrorCodeRequestInProgress:    "Request in progress",
+	httperr.ErrorCodeConflict:             "Conflict",
+	httperr.ErrorCodePreconditionFailed:   "Precondition failed",
+}
+
+// statusCodes — коды для ошибок без тела ErrorResponse, например ответов ServeMux.
+var statusCodes = map[int]string{
+	http.StatusNotFound:         httperr.ErrorCodeNotFound,
+	http.StatusMethodNotAllowed: httperr.ErrorCodeMethodNotAllowed,
+}
+
+// Wrap отвечает на ошибки (статусы 4xx и 5xx) в формате application/problem+json, если клиент
+// предпочитает его в заголовке Accept. Обработчики по-прежнему пишут ErrorResponse: middleware
+// буферизует тело ошибки и переводит его в Problem, код и error.details переходят в code и errors.
+// Успешные ответы и ответы клиентам без problem+json в Accept передаются без изменений.
+func Wrap(next http.Handler, logger *slog.Logger) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Add("Vary", "Accept")
+
+		if !Preferred(r) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		rec := &recorder{ResponseWriter: w}
+		next.ServeHTTP(rec, r)
+
+		if !rec.buffering {
+			return
+		}
+
+		writeProblem(w, fromResponse(rec.status, rec.Header().Get("Content-Type"), rec.body.Bytes(), r.URL.Path), logger)
+	})
+}
+
+// Preferred сообщает, предпочитает ли клиент application/problem+json обычному JSON.
+// При равном q выбирается problem+json как более конкретный тип.
+func Preferred(r *http.Request) bool {
+	problemQ, jsonQ := -1.0, -1.0
+
+	for _, part := range strings.Split(strings.Join(r.Header.Values("Accept"), ","), ",") {
+		mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err != nil {
+			continue
+		}
+
+		q := 1.0
+		if raw, ok := params["q"]; ok {
+			if q, err = strconv.ParseFloat(raw, 64); err != nil {
+				continue
+			}
+		}
+
+		switch mediaType {
+		case ContentType:
+			problemQ = max(problemQ, q)
+		case jsonContentType:
+			jsonQ = max(jsonQ, q)
+		}
+	}
+
+	return problemQ > 0 && problemQ >= jsonQ
+}
+
+// WriteError отправляет ошибку в формате, который предпочитает клиент: Problem или ErrorResponse.
+// Нужна там, где ответ пишется в обход Wrap, например при восстановлении после паники.
+func WriteError(w http.ResponseWriter, r *http.Request, status int, code, message string, logger *slog.Logger) {
+	if !Preferred(r) {
+		httperr.WriteJSONError(w, status, code, message, logger)
+		return
+	}
+
+	writeProblem(w, newProblem(status, code, message, r.URL.Path, nil), logger)
+}
+
+// fromResponse строит Problem по статусу и телу ответа об ошибке. Тело ErrorResponse даёт код,
+// сообщение и ошибки по полям, тело другого типа (например, text/plain от ServeMux) — только detail.
+func fromResponse(status int, contentType string, body []byte, instance string) Problem {
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+
+	var resp httperr.ErrorResponse
+	if mediaType == jsonContentType && json.Unmarshal(body, &resp) == nil && resp.Error.Code != "" {
+		return newProblem(status, resp.Error.Code, resp.Error.Message, instance, resp.Error.Details)
+	}
+
+	return newProblem(status, statusCodes[status], strings.TrimSpace(string(body)), instance, nil)
+}
+
+// newProblem строит Problem по коду ошибки. Для пустого кода тип — about:blank, заголовок — текст статуса.
+func newProblem(status int, code, detail, instance string, errs []httperr.FieldError) Problem {
+	p := Problem{
+		Type:     "about:blank",
+		Title:    http.StatusText(status),
+		Status:   status,
+		Detail:   detail,
+		Instance: instance,
+		Code:     code,
+		Errors:   errs,
+	}
+
+	if code != "" {
+		p.Type = TypePrefix + strings.ReplaceAll(strings.ToLower(code), "_", "-")
+
+		if title, ok := titles[code]; ok {
+			p.Title = title
+		}
+	}
+
+	return p
+}
+
+func writeProblem(w http.ResponseWriter, p Problem, logger *slog.Logger) {
+	w.Header().Set("Content-Type", ContentType)
+	w.Header().Del("Content-Length")
+	w.WriteHeader(p.Status)
+
+	if err := json.NewEncoder(w).Encode(p); err != nil {
+		if logger != nil {
+			logger.Error("failed to write problem response", slog.Any("error", err))
+			return
+		}
+
+		slog.Default().Error("failed to write problem response", slog.Any("error", err))
+	}
+}
+
+// recorder передаёт успешные ответы клиенту сразу, а статус и тело ошибки запоминает,
+// чтобы Wrap записал их как Problem после завершения обработчика.
+type recorder struct {
+	http.ResponseWriter
+	status      int
+	wroteHeader bool
+	buffering   bool
+	body        bytes.Buffer
+}
+
+func (r *recorder) WriteHeader(status int) {
+	if r.wroteHeader {
+		return
+	}
+
+	r.wroteHeader = true
+	r.status = status
+
+	if status >= http.StatusBadRequest {
+		r.buffering = true
+		return
+	}
+
+	r.ResponseWriter.WriteHeader(status)
+}
+
+func (r *recorder) Write(p []byte) (int, error) {
+	if !r.wroteHeader {
+		r.WriteHeader(http.StatusOK)
+	}
+
+	if r.buffering {
+		return r.body.Write(p)
+	}
+
+	return r.ResponseWriter.Write(p)
+}
+
+// Flush передаёт буферизованные данные только для успешных ответов: ошибка записывается целиком.
+func (r *recorder) Flush() {
+	if r.buffering {
+		return
+	}
+
+	if !r.wroteHeader {
+		r.WriteHeader(http.StatusOK)
+	}
+
+	_ = http.NewResponseController(r.ResponseWriter).Flush()
+}
+
+// Unwrap даёт http.ResponseController доступ к дедлайнам исходного ResponseWriter.
+func (r *recorder) Unwrap() http.ResponseWriter {
+	return r.ResponseWriter
+}
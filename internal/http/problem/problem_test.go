@@ -0,0 +1,93 @@
+package problem
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/dixitix/pr-reviewer-service/internal/http/httperr"
+)
+
+// TestPreferred проверяет выбор формата по заголовку Accept.
+func TestPreferred(t *testing.T) {
+	cases := []struct {
+		accept string
+		want   bool
+	}{
+		{"", false},
+		{"*/*", false},
+		{"application/json", false},
+		{"application/problem+json", true},
+		{"application/json, application/problem+json", true},
+		{"application/json, application/problem+json;q=0.5", false},
+		{"application/json;q=0.5, application/problem+json", true},
+		{"application/problem+json;q=0", false},
+	}
+
+	for _, tc := range cases {
+		r := httptest.NewRequest(http.MethodGet, "/", nil)
+		if tc.accept != "" {
+			r.Header.Set("Accept", tc.accept)
+		}
+
+		if got := Preferred(r); got != tc.want {
+			t.Errorf("Accept %q: expected %v, got %v", tc.accept, tc.want, got)
+		}
+	}
+}
+
+// TestWrap проверяет перевод ошибок в Problem и передачу остальных ответов без изменений.
+func TestWrap(t *testing.T) {
+	h := Wrap(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/ok":
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = io.WriteString(w, `{"ok":true}`)
+		case "/invalid":
+			httperr.WriteValidationError(w, []httperr.FieldError{{Field: "query.team_name", Message: "is required"}}, nil)
+		default:
+			http.NotFound(w, r)
+		}
+	}), nil)
+
+	serve := func(path string) *httptest.ResponseRecorder {
+		r := httptest.NewRequest(http.MethodGet, path, nil)
+		r.Header.Set("Accept", ContentType)
+
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, r)
+
+		return rec
+	}
+
+	if rec := serve("/ok"); rec.Code != http.StatusOK || rec.Body.String() != `{"ok":true}` {
+		t.Fatalf("success must be passed through, got %d %q", rec.Code, rec.Body.String())
+	}
+
+	rec := serve("/invalid")
+
+	var p Problem
+	if err := json.Unmarshal(rec.Body.Bytes(), &p); err != nil {
+		t.Fatalf("decode problem: %v", err)
+	}
+
+	if rec.Code != http.StatusBadRequest || rec.Header().Get("Content-Type") != ContentType ||
+		p.Type != TypePrefix+"validation-error" || p.Status != http.StatusBadRequest || p.Code != httperr.ErrorCodeValidation ||
+		p.Instance != "/invalid" || p.Detail != "query.team_name: is required" ||
+		len(p.Errors) != 1 || p.Errors[0].Field != "query.team_name" {
+		t.Fatalf("unexpected problem: %d %+v", rec.Code, p)
+	}
+
+	rec = serve("/missing")
+
+	p = Problem{}
+	if err := json.Unmarshal(rec.Body.Bytes(), &p); err != nil {
+		t.Fatalf("decode problem: %v", err)
+	}
+
+	if rec.Code != http.StatusNotFound || p.Code != httperr.ErrorCodeNotFound || p.Detail != "404 page not found" {
+		t.Fatalf("unexpected problem for plain text error: %d %+v", rec.Code, p)
+	}
+}
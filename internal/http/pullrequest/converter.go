@@ -53,5 +53,43 @@ func mapPullRequestDomainToDTO(pr domain.PullRequest) DTO {
 		AssignedReviewers: reviewers,
 		CreatedAt:         createdAt,
 		MergedAt:          mergedAt,
+		CreatedBy:         string(pr.CreatedBy),
+		MergedBy:          string(pr.MergedBy),
+		ReassignedBy:      string(pr.ReassignedBy),
 	}
 }
+
+// mapHistoryToDTO конвертирует события PR в записи истории.
+func mapHistoryToDTO(events []domain.Event) []HistoryEntryDTO {
+	result := make([]HistoryEntryDTO, len(events))
+
+	for i, e := range events {
+		result[i] = HistoryEntryDTO{
+			EventType:        string(e.Type),
+			OccurredAt:       e.OccurredAt,
+			Status:           string(e.PullRequest.Status),
+			AddedReviewers:   userIDsToStrings(e.AddedReviewers),
+			RemovedReviewers: userIDsToStrings(e.RemovedReviewers),
+			ReviewerID:       string(e.Reviewer),
+			WaitingSince:     e.WaitingSince,
+			Reason:           e.Reason,
+			Actor:            string(e.Actor),
+		}
+	}
+
+	return result
+}
+
+// userIDsToStrings конвертирует ID пользователей в строки; для пустого списка возвращает nil.
+func userIDsToStrings(ids []domain.UserID) []string {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	result := make([]string, len(ids))
+	for i, id := range ids {
+		result[i] = string(id)
+	}
+
+	return result
+}
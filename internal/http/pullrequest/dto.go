@@ -12,6 +12,9 @@ type DTO struct {
 	AssignedReviewers []string   `json:"assigned_reviewers"`
 	CreatedAt         *time.Time `json:"createdAt"`
 	MergedAt          *time.Time `json:"mergedAt"`
+	CreatedBy         string     `json:"createdBy,omitempty"`
+	MergedBy          string     `json:"mergedBy,omitempty"`
+	ReassignedBy      string     `json:"reassignedBy,omitempty"`
 }
 
 // Short представляет укороченное представление PR для списков.
@@ -32,3 +35,22 @@ type ReassignResponse struct {
 	PullRequest DTO    `json:"pr"`
 	ReplacedBy  string `json:"replaced_by"`
 }
+
+// HistoryEntryDTO представляет событие из истории PR.
+type HistoryEntryDTO struct {
+	EventType        string     `json:"event_type"`
+	OccurredAt       time.Time  `json:"occurred_at"`
+	Status           string     `json:"status"`
+	AddedReviewers   []string   `json:"added_reviewers,omitempty"`
+	RemovedReviewers []string   `json:"removed_reviewers,omitempty"`
+	ReviewerID       string     `json:"reviewer_id,omitempty"`
+	WaitingSince     *time.Time `json:"waiting_since,omitempty"`
+	Reason           string     `json:"reason,omitempty"`
+	Actor            string     `json:"actor,omitempty"`
+}
+
+// HistoryResponse описывает ответ с историей PR.
+type HistoryResponse struct {
+	PullRequestID string            `json:"pull_request_id"`
+	History       []HistoryEntryDTO `json:"history"`
+}
@@ -8,6 +8,7 @@ import (
 	"net/http"
 
 	"github.com/dixitix/pr-reviewer-service/internal/domain"
+	"github.com/dixitix/pr-reviewer-service/internal/http/etag"
 	"github.com/dixitix/pr-reviewer-service/internal/http/httperr"
 	"github.com/dixitix/pr-reviewer-service/internal/service"
 )
@@ -39,6 +40,11 @@ func (h *Handler) Create(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	h.create(w, r, req)
+}
+
+// create проверяет req и создаёт PR; общая часть /pullRequest/create и POST /v2/pull-requests.
+func (h *Handler) create(w http.ResponseWriter, r *http.Request, req CreatePullRequestRequest) {
 	if req.PullRequestID == "" || req.PullRequestName == "" || req.AuthorID == "" {
 		httperr.WriteJSONError(w, http.StatusBadRequest, httperr.ErrorCodeValidation, "pull_request_id, pull_request_name and author_id are required", h.logger)
 		return
@@ -47,7 +53,8 @@ func (h *Handler) Create(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
 	if h.logger != nil {
-		h.logger.Info(
+		h.logger.InfoContext(
+			r.Context(),
 			"handlePullRequestCreate",
 			slog.String("pull_request_id", req.PullRequestID),
 			slog.String("author_id", req.AuthorID),
@@ -60,12 +67,15 @@ func (h *Handler) Create(w http.ResponseWriter, r *http.Request) {
 		case errors.Is(err, service.ErrPullRequestAlreadyExists):
 			httperr.WriteJSONError(w, http.StatusConflict, httperr.ErrorCodePRExists, "pull_request_id already exists", h.logger)
 			return
+		case errors.Is(err, service.ErrForbidden):
+			httperr.WriteJSONError(w, http.StatusForbidden, httperr.ErrorCodeForbidden, "only the author, their team lead or an admin may create this pull request", h.logger)
+			return
 		case errors.Is(err, service.ErrNotFound):
 			httperr.WriteJSONError(w, http.StatusNotFound, httperr.ErrorCodeNotFound, "author or team not found", h.logger)
 			return
 		default:
 			if h.logger != nil {
-				h.logger.Error("handlePullRequestCreate: CreatePullRequest error", slog.Any("error", err))
+				h.logger.ErrorContext(r.Context(), "handlePullRequestCreate: CreatePullRequest error", slog.Any("error", err))
 			}
 			httperr.WriteJSONError(w, http.StatusInternalServerError, httperr.ErrorCodeInternal, "internal server error", h.logger)
 			return
@@ -81,7 +91,7 @@ func (h *Handler) Create(w http.ResponseWriter, r *http.Request) {
 
 	if err := json.NewEncoder(w).Encode(resp); err != nil {
 		if h.logger != nil {
-			h.logger.Error("handlePullRequestCreate: failed to write response", slog.Any("error", err))
+			h.logger.ErrorContext(r.Context(), "handlePullRequestCreate: failed to write response", slog.Any("error", err))
 		}
 	}
 }
@@ -99,26 +109,46 @@ func (h *Handler) Merge(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	h.merge(w, r, req)
+}
+
+// merge проверяет req и выполняет merge PR с учётом If-Match.
+func (h *Handler) merge(w http.ResponseWriter, r *http.Request, req MergePullRequestRequest) {
 	if req.PullRequestID == "" {
 		httperr.WriteJSONError(w, http.StatusBadRequest, httperr.ErrorCodeValidation, "pull_request_id is required", h.logger)
 		return
 	}
 
-	ctx := r.Context()
+	ctx := etag.IfMatch(r)
 
 	if h.logger != nil {
-		h.logger.Info("handlePullRequestMerge", slog.String("pull_request_id", req.PullRequestID))
+		h.logger.InfoContext(r.Context(), "handlePullRequestMerge", slog.String("pull_request_id", req.PullRequestID), slog.Bool("force", req.Force))
 	}
 
-	pr, err := h.svc.MergePullRequest(ctx, domain.PullRequestID(req.PullRequestID))
+	pr, err := h.svc.MergePullRequest(ctx, domain.PullRequestID(req.PullRequestID), service.MergeOptions{Force: req.Force})
 	if err != nil {
+		if errors.Is(err, service.ErrForbidden) {
+			httperr.WriteJSONError(w, http.StatusForbidden, httperr.ErrorCodeForbidden, "only the author, their team lead or an admin may merge; forced merge requires a lead", h.logger)
+			return
+		}
+
+		if errors.Is(err, service.ErrPreconditionFailed) {
+			httperr.WriteJSONError(w, http.StatusPreconditionFailed, httperr.ErrorCodePreconditionFailed, "pull request was modified, If-Match does not match its ETag", h.logger)
+			return
+		}
+
 		if errors.Is(err, service.ErrNotFound) {
 			httperr.WriteJSONError(w, http.StatusNotFound, httperr.ErrorCodeNotFound, "pull request not found", h.logger)
 			return
 		}
 
+		if errors.Is(err, service.ErrConflict) {
+			httperr.WriteJSONError(w, http.StatusConflict, httperr.ErrorCodeConflict, "pull request was modified concurrently, retry the request", h.logger)
+			return
+		}
+
 		if h.logger != nil {
-			h.logger.Error("handlePullRequestMerge: MergePullRequest error", slog.Any("error", err))
+			h.logger.ErrorContext(r.Context(), "handlePullRequestMerge: MergePullRequest error", slog.Any("error", err))
 		}
 
 		httperr.WriteJSONError(w, http.StatusInternalServerError, httperr.ErrorCodeInternal, "internal server error", h.logger)
@@ -129,12 +159,13 @@ func (h *Handler) Merge(w http.ResponseWriter, r *http.Request) {
 		PullRequest: mapPullRequestDomainToDTO(pr),
 	}
 
+	etag.Set(w, pr.Version)
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusOK)
 
 	if err := json.NewEncoder(w).Encode(resp); err != nil {
 		if h.logger != nil {
-			h.logger.Error("handlePullRequestMerge: failed to write response", slog.Any("error", err))
+			h.logger.ErrorContext(r.Context(), "handlePullRequestMerge: failed to write response", slog.Any("error", err))
 		}
 	}
 }
@@ -152,15 +183,21 @@ func (h *Handler) Reassign(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	h.reassign(w, r, req)
+}
+
+// reassign проверяет req и переназначает ревьювера с учётом If-Match.
+func (h *Handler) reassign(w http.ResponseWriter, r *http.Request, req ReassignPullRequestRequest) {
 	if req.PullRequestID == "" || req.OldUserID == "" {
 		httperr.WriteJSONError(w, http.StatusBadRequest, httperr.ErrorCodeValidation, "pull_request_id and old_user_id are required", h.logger)
 		return
 	}
 
-	ctx := r.Context()
+	ctx := etag.IfMatch(r)
 
 	if h.logger != nil {
-		h.logger.Info(
+		h.logger.InfoContext(
+			r.Context(),
 			"handlePullRequestReassign",
 			slog.String("pull_request_id", req.PullRequestID),
 			slog.String("old_user_id", req.OldUserID),
@@ -179,12 +216,21 @@ func (h *Handler) Reassign(w http.ResponseWriter, r *http.Request) {
 		case errors.Is(err, service.ErrNoCandidate):
 			httperr.WriteJSONError(w, http.StatusConflict, httperr.ErrorCodeNoCandidate, "no candidate for reviewer reassignment", h.logger)
 			return
+		case errors.Is(err, service.ErrForbidden):
+			httperr.WriteJSONError(w, http.StatusForbidden, httperr.ErrorCodeForbidden, "only the author, their team lead or an admin may reassign reviewers", h.logger)
+			return
+		case errors.Is(err, service.ErrPreconditionFailed):
+			httperr.WriteJSONError(w, http.StatusPreconditionFailed, httperr.ErrorCodePreconditionFailed, "pull request was modified, If-Match does not match its ETag", h.logger)
+			return
 		case errors.Is(err, service.ErrNotFound):
 			httperr.WriteJSONError(w, http.StatusNotFound, httperr.ErrorCodeNotFound, "pull request or user not found", h.logger)
 			return
+		case errors.Is(err, service.ErrConflict):
+			httperr.WriteJSONError(w, http.StatusConflict, httperr.ErrorCodeConflict, "pull request was modified concurrently, retry the request", h.logger)
+			return
 		default:
 			if h.logger != nil {
-				h.logger.Error("handlePullRequestReassign: ReassignReviewer error", slog.Any("error", err))
+				h.logger.ErrorContext(r.Context(), "handlePullRequestReassign: ReassignReviewer error", slog.Any("error", err))
 			}
 			httperr.WriteJSONError(w, http.StatusInternalServerError, httperr.ErrorCodeInternal, "internal server error", h.logger)
 			return
@@ -196,12 +242,116 @@ func (h *Handler) Reassign(w http.ResponseWriter, r *http.Request) {
 		ReplacedBy:  string(newReviewerID),
 	}
 
+	etag.Set(w, pr.Version)
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		if h.logger != nil {
+			h.logger.ErrorContext(r.Context(), "handlePullRequestReassign: failed to write response", slog.Any("error", err))
+		}
+	}
+}
+
+// Get обрабатывает получение PR. Ответ содержит ETag версии PR; при совпадении с If-None-Match
+// возвращается 304 без тела.
+func (h *Handler) Get(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		httperr.WriteJSONError(w, http.StatusMethodNotAllowed, httperr.ErrorCodeMethodNotAllowed, "method not allowed", h.logger)
+		return
+	}
+
+	prID := r.URL.Query().Get("pull_request_id")
+	if prID == "" {
+		httperr.WriteJSONError(w, http.StatusBadRequest, httperr.ErrorCodeValidation, "pull_request_id is required", h.logger)
+		return
+	}
+
+	h.get(w, r, prID)
+}
+
+// get отдаёт PR с ETag либо 304 по If-None-Match.
+func (h *Handler) get(w http.ResponseWriter, r *http.Request, prID string) {
+	pr, err := h.svc.GetPullRequest(r.Context(), domain.PullRequestID(prID))
+	if err != nil {
+		if errors.Is(err, service.ErrNotFound) {
+			httperr.WriteJSONError(w, http.StatusNotFound, httperr.ErrorCodeNotFound, "pull request not found", h.logger)
+			return
+		}
+
+		if h.logger != nil {
+			h.logger.ErrorContext(r.Context(), "handlePullRequestGet: GetPullRequest error", slog.Any("error", err))
+		}
+
+		httperr.WriteJSONError(w, http.StatusInternalServerError, httperr.ErrorCodeInternal, "internal server error", h.logger)
+		return
+	}
+
+	etag.Set(w, pr.Version)
+
+	if etag.NotModified(r, pr.Version) {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	resp := Envelope{
+		PullRequest: mapPullRequestDomainToDTO(pr),
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		if h.logger != nil {
+			h.logger.ErrorContext(r.Context(), "handlePullRequestGet: failed to write response", slog.Any("error", err))
+		}
+	}
+}
+
+// History обрабатывает получение истории PR: назначений, напоминаний, эскалаций и merge.
+func (h *Handler) History(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		httperr.WriteJSONError(w, http.StatusMethodNotAllowed, httperr.ErrorCodeMethodNotAllowed, "method not allowed", h.logger)
+		return
+	}
+
+	prID := r.URL.Query().Get("pull_request_id")
+	if prID == "" {
+		httperr.WriteJSONError(w, http.StatusBadRequest, httperr.ErrorCodeValidation, "pull_request_id is required", h.logger)
+		return
+	}
+
+	h.history(w, r, prID)
+}
+
+// history отдаёт историю PR prID.
+func (h *Handler) history(w http.ResponseWriter, r *http.Request, prID string) {
+	history, err := h.svc.GetPullRequestHistory(r.Context(), domain.PullRequestID(prID))
+	if err != nil {
+		if errors.Is(err, service.ErrNotFound) {
+			httperr.WriteJSONError(w, http.StatusNotFound, httperr.ErrorCodeNotFound, "pull request not found", h.logger)
+			return
+		}
+
+		if h.logger != nil {
+			h.logger.ErrorContext(r.Context(), "handlePullRequestHistory: GetPullRequestHistory error", slog.Any("error", err))
+		}
+
+		httperr.WriteJSONError(w, http.StatusInternalServerError, httperr.ErrorCodeInternal, "internal server error", h.logger)
+		return
+	}
+
+	resp := HistoryResponse{
+		PullRequestID: prID,
+		History:       mapHistoryToDTO(history),
+	}
+
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusOK)
 
 	if err := json.NewEncoder(w).Encode(resp); err != nil {
 		if h.logger != nil {
-			h.logger.Error("handlePullRequestReassign: failed to write response", slog.Any("error", err))
+			h.logger.ErrorContext(r.Context(), "handlePullRequestHistory: failed to write response", slog.Any("error", err))
 		}
 	}
 }
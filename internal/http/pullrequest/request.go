@@ -9,8 +9,10 @@ type CreatePullRequestRequest struct {
 }
 
 // MergePullRequestRequest описывает тело запроса /pullRequest/merge.
+// Force — merge без завершённого ревью, о нём сообщается лидам команды автора.
 type MergePullRequestRequest struct {
 	PullRequestID string `json:"pull_request_id"`
+	Force         bool   `json:"force"`
 }
 
 // ReassignPullRequestRequest описывает тело запроса /pullRequest/reassign.
@@ -18,3 +20,13 @@ type ReassignPullRequestRequest struct {
 	PullRequestID string `json:"pull_request_id"`
 	OldUserID     string `json:"old_user_id"`
 }
+
+// MergePullRequestV2Request описывает необязательное тело запроса POST /v2/pull-requests/{id}/merge.
+type MergePullRequestV2Request struct {
+	Force bool `json:"force"`
+}
+
+// ReassignPullRequestV2Request описывает тело запроса POST /v2/pull-requests/{id}/reassign.
+type ReassignPullRequestV2Request struct {
+	OldUserID string `json:"old_user_id"`
+}
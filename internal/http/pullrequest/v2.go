@@ -0,0 +1,57 @@
+// Package pullrequest содержит обработчики и DTO для работы с Pull Request'ами.
+package pullrequest
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/dixitix/pr-reviewer-service/internal/http/httperr"
+)
+
+// Обработчики API v2. ID PR берётся из шаблона пути {id}; проверка, вызов сервиса
+// и ответ общие с обработчиками v1.
+
+// CreateV2 обрабатывает POST /v2/pull-requests.
+func (h *Handler) CreateV2(w http.ResponseWriter, r *http.Request) {
+	var req CreatePullRequestRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httperr.WriteJSONError(w, http.StatusBadRequest, httperr.ErrorCodeInvalidJSON, "invalid JSON body", h.logger)
+		return
+	}
+
+	h.create(w, r, req)
+}
+
+// GetV2 обрабатывает GET /v2/pull-requests/{id}.
+func (h *Handler) GetV2(w http.ResponseWriter, r *http.Request) {
+	h.get(w, r, r.PathValue("id"))
+}
+
+// MergeV2 обрабатывает POST /v2/pull-requests/{id}/merge. Тело запроса можно не передавать.
+func (h *Handler) MergeV2(w http.ResponseWriter, r *http.Request) {
+	var body MergePullRequestV2Request
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil && !errors.Is(err, io.EOF) {
+		httperr.WriteJSONError(w, http.StatusBadRequest, httperr.ErrorCodeInvalidJSON, "invalid JSON body", h.logger)
+		return
+	}
+
+	h.merge(w, r, MergePullRequestRequest{PullRequestID: r.PathValue("id"), Force: body.Force})
+}
+
+// ReassignV2 обрабатывает POST /v2/pull-requests/{id}/reassign.
+func (h *Handler) ReassignV2(w http.ResponseWriter, r *http.Request) {
+	var body ReassignPullRequestV2Request
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		httperr.WriteJSONError(w, http.StatusBadRequest, httperr.ErrorCodeInvalidJSON, "invalid JSON body", h.logger)
+		return
+	}
+
+	h.reassign(w, r, ReassignPullRequestRequest{PullRequestID: r.PathValue("id"), OldUserID: body.OldUserID})
+}
+
+// HistoryV2 обрабатывает GET /v2/pull-requests/{id}/history.
+func (h *Handler) HistoryV2(w http.ResponseWriter, r *http.Request) {
+	h.history(w, r, r.PathValue("id"))
+}
@@ -2,17 +2,103 @@
 // для HTTP-слоя сервиса назначения ревьюеров.
 package httpserver
 
-import "net/http"
+import (
+	"net/http"
+
+	"github.com/dixitix/pr-reviewer-service/internal/domain"
+	"github.com/dixitix/pr-reviewer-service/internal/http/problem"
+)
 
 // RegisterRoutes регистрирует HTTP-маршруты сервиса на переданном ServeMux.
+// Все маршруты требуют API-ключ с правом, указанным при регистрации; создание организаций —
+// корневой ключ. Все маршруты, кроме управления организациями, работают с данными организации вызывающего
+// и поддерживают заголовок Idempotency-Key в POST-запросах. Если задана проверка по спецификации,
+// запросы, не соответствующие api/openapi.yml, отклоняются до обработчиков. Клиенты с
+// application/problem+json в Accept получают ошибки в формате RFC 7807.
 func (h *Handler) RegisterRoutes(mux *http.ServeMux) {
-	mux.HandleFunc("/team/add", h.teamHandler.Add)
-	mux.HandleFunc("/team/get", h.teamHandler.Get)
-	mux.HandleFunc("/users/setIsActive", h.userHandler.SetIsActive)
-	mux.HandleFunc("/users/getReview", h.userHandler.GetReview)
-	mux.HandleFunc("/pullRequest/create", h.pullRequestHandler.Create)
-	mux.HandleFunc("/pullRequest/merge", h.pullRequestHandler.Merge)
-	mux.HandleFunc("/pullRequest/reassign", h.pullRequestHandler.Reassign)
-	mux.HandleFunc("/stats/byUser", h.statsHandler.AssignmentsByUser)
-	mux.HandleFunc("/stats/byPullRequest", h.statsHandler.AssignmentsByPullRequest)
+	var addOrganization http.Handler = h.auth.RequireRoot(h.orgHandler.Add)
+	if h.validation != nil {
+		addOrganization = h.validation.Wrap(addOrganization)
+	}
+
+	mux.Handle("/organizations/add", problem.Wrap(h.auth.Middleware(addOrganization), h.logger))
+
+	scoped := http.NewServeMux()
+
+	var inner http.Handler = scoped
+	if h.validation != nil {
+		inner = h.validation.Wrap(inner)
+	}
+
+	if h.idempotency != nil {
+		inner = h.idempotency.Wrap(inner)
+	}
+
+	mux.Handle("/", problem.Wrap(h.auth.Middleware(h.orgHandler.Middleware(inner)), h.logger))
+
+	var (
+		read      = func(f http.HandlerFunc) http.HandlerFunc { return h.auth.Require(domain.APIScopeRead, f) }
+		prWrite   = func(f http.HandlerFunc) http.HandlerFunc { return h.auth.Require(domain.APIScopePRWrite, f) }
+		teamAdmin = func(f http.HandlerFunc) http.HandlerFunc { return h.auth.Require(domain.APIScopeTeamAdmin, f) }
+		statsRead = func(f http.HandlerFunc) http.HandlerFunc { return h.auth.Require(domain.APIScopeStatsRead, f) }
+	)
+
+	scoped.HandleFunc("/apiKeys/add", teamAdmin(h.apiKeyHandler.Add))
+	scoped.HandleFunc("/apiKeys/list", teamAdmin(h.apiKeyHandler.List))
+	scoped.HandleFunc("/apiKeys/revoke", teamAdmin(h.apiKeyHandler.Revoke))
+	scoped.HandleFunc("/team/add", teamAdmin(h.teamHandler.Add))
+	scoped.HandleFunc("/team/get", read(h.teamHandler.Get))
+	scoped.HandleFunc("/team/setLeads", teamAdmin(h.teamHandler.SetLeads))
+	scoped.HandleFunc("/team/setParent", teamAdmin(h.teamHandler.SetParent))
+	scoped.HandleFunc("/team/getAllMembers", read(h.teamHandler.GetAllMembers))
+	scoped.HandleFunc("/team/addMember", teamAdmin(h.teamHandler.AddMember))
+	scoped.HandleFunc("/team/removeMember", teamAdmin(h.teamHandler.RemoveMember))
+	scoped.HandleFunc("/team/getReviewPolicy", read(h.teamHandler.GetReviewPolicy))
+	scoped.HandleFunc("/team/setReviewPolicy", teamAdmin(h.teamHandler.SetReviewPolicy))
+	scoped.HandleFunc("/users/setIsActive", teamAdmin(h.userHandler.SetIsActive))
+	scoped.HandleFunc("/users/getReview", read(h.userHandler.GetReview))
+	scoped.HandleFunc("/users/getTeams", read(h.userHandler.GetTeams))
+	scoped.HandleFunc("/pullRequest/create", prWrite(h.pullRequestHandler.Create))
+	scoped.HandleFunc("/pullRequest/merge", prWrite(h.pullRequestHandler.Merge))
+	scoped.HandleFunc("/pullRequest/reassign", prWrite(h.pullRequestHandler.Reassign))
+	scoped.HandleFunc("/pullRequest/get", read(h.pullRequestHandler.Get))
+	scoped.HandleFunc("/pullRequest/history", read(h.pullRequestHandler.History))
+	scoped.HandleFunc("/stats/byUser", statsRead(h.statsHandler.AssignmentsByUser))
+	scoped.HandleFunc("/stats/byPullRequest", statsRead(h.statsHandler.AssignmentsByPullRequest))
+	scoped.HandleFunc("/stats/byTeam", statsRead(h.statsHandler.AssignmentsByTeam))
+	scoped.HandleFunc("/events/stream", read(h.eventsHandler.Stream))
+	scoped.HandleFunc("/webhooks/add", teamAdmin(h.webhookHandler.Add))
+	scoped.HandleFunc("/webhooks/list", teamAdmin(h.webhookHandler.List))
+	scoped.HandleFunc("/webhooks/remove", teamAdmin(h.webhookHandler.Remove))
+	scoped.HandleFunc("/webhooks/deliveries", teamAdmin(h.webhookHandler.Deliveries))
+	scoped.HandleFunc("/notifications/getPreferences", read(h.notifyHandler.GetPreferences))
+	scoped.HandleFunc("/notifications/setPreferences", teamAdmin(h.notifyHandler.SetPreferences))
+	scoped.HandleFunc("/notifications/getTeamChannel", read(h.notifyHandler.GetTeamChannel))
+	scoped.HandleFunc("/notifications/setTeamChannel", teamAdmin(h.notifyHandler.SetTeamChannel))
+	scoped.HandleFunc("/notifications/removeTeamChannel", teamAdmin(h.notifyHandler.RemoveTeamChannel))
+
+	// API v2: ресурсные маршруты с методом и шаблоном пути. Обработчики разделяют с v1
+	// проверки и сервисный слой; на неподходящий метод ServeMux отвечает 405 с заголовком Allow.
+	scoped.HandleFunc("GET /v2/teams", read(h.teamHandler.ListV2))
+	scoped.HandleFunc("POST /v2/teams", teamAdmin(h.teamHandler.CreateV2))
+	scoped.HandleFunc("GET /v2/teams/{name}", read(h.teamHandler.GetV2))
+	scoped.HandleFunc("PUT /v2/teams/{name}/leads", teamAdmin(h.teamHandler.SetLeadsV2))
+	scoped.HandleFunc("PUT /v2/teams/{name}/parent", teamAdmin(h.teamHandler.SetParentV2))
+	scoped.HandleFunc("GET /v2/teams/{name}/all-members", read(h.teamHandler.GetAllMembersV2))
+	scoped.HandleFunc("POST /v2/teams/{name}/members", teamAdmin(h.teamHandler.AddMemberV2))
+	scoped.HandleFunc("DELETE /v2/teams/{name}/members/{userId}", teamAdmin(h.teamHandler.RemoveMemberV2))
+	scoped.HandleFunc("GET /v2/teams/{name}/review-policy", read(h.teamHandler.GetReviewPolicyV2))
+	scoped.HandleFunc("PUT /v2/teams/{name}/review-policy", teamAdmin(h.teamHandler.SetReviewPolicyV2))
+	scoped.HandleFunc("GET /v2/users/{id}", read(h.userHandler.GetV2))
+	scoped.HandleFunc("PATCH /v2/users/{id}", teamAdmin(h.userHandler.UpdateV2))
+	scoped.HandleFunc("GET /v2/users/{id}/reviews", read(h.userHandler.GetReviewV2))
+	scoped.HandleFunc("GET /v2/users/{id}/teams", read(h.userHandler.GetTeamsV2))
+	scoped.HandleFunc("POST /v2/pull-requests", prWrite(h.pullRequestHandler.CreateV2))
+	scoped.HandleFunc("GET /v2/pull-requests/{id}", read(h.pullRequestHandler.GetV2))
+	scoped.HandleFunc("POST /v2/pull-requests/{id}/merge", prWrite(h.pullRequestHandler.MergeV2))
+	scoped.HandleFunc("POST /v2/pull-requests/{id}/reassign", prWrite(h.pullRequestHandler.ReassignV2))
+	scoped.HandleFunc("GET /v2/pull-requests/{id}/history", read(h.pullRequestHandler.HistoryV2))
+	scoped.HandleFunc("GET /v2/stats/users", statsRead(h.statsHandler.AssignmentsByUser))
+	scoped.HandleFunc("GET /v2/stats/pull-requests", statsRead(h.statsHandler.AssignmentsByPullRequest))
+	scoped.HandleFunc("GET /v2/stats/teams", statsRead(h.statsHandler.AssignmentsByTeam))
 }
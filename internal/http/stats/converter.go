@@ -54,3 +54,22 @@ func mapPullRequestStatsToDTO(stats map[domain.PullRequestID]int) []PullRequestS
 
 	return result
 }
+
+// mapTeamStatsToDTO конвертирует статистику по командам в список DTO, отсортированный по имени команды.
+func mapTeamStatsToDTO(stats []domain.TeamAssignmentStat) []TeamStat {
+	result := make([]TeamStat, len(stats))
+	for i, st := range stats {
+		result[i] = TeamStat{
+			TeamName:         string(st.TeamName),
+			ParentName:       string(st.Parent),
+			Assignments:      st.Assignments,
+			TotalAssignments: st.TotalAssignments,
+		}
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].TeamName < result[j].TeamName
+	})
+
+	return result
+}
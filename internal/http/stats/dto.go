@@ -22,3 +22,16 @@ type UserStatsResponse struct {
 type PullRequestStatsResponse struct {
 	Stats []PullRequestStat `json:"stats"`
 }
+
+// TeamStat описывает количество назначений команды: собственных и вместе с дочерними командами.
+type TeamStat struct {
+	TeamName         string `json:"team_name"`
+	ParentName       string `json:"parent_name,omitempty"`
+	Assignments      int    `json:"assignments"`
+	TotalAssignments int    `json:"total_assignments"`
+}
+
+// TeamStatsResponse описывает ответ на запрос о статистике по командам.
+type TeamStatsResponse struct {
+	Stats []TeamStat `json:"stats"`
+}
@@ -34,7 +34,7 @@ func (h *Handler) AssignmentsByUser(w http.ResponseWriter, r *http.Request) {
 	stats, err := h.svc.GetAssignmentsByUser(r.Context())
 	if err != nil {
 		if h.logger != nil {
-			h.logger.Error("handleStatsByUser: GetAssignmentsByUser error", slog.Any("error", err))
+			h.logger.ErrorContext(r.Context(), "handleStatsByUser: GetAssignmentsByUser error", slog.Any("error", err))
 		}
 		httperr.WriteJSONError(w, http.StatusInternalServerError, httperr.ErrorCodeInternal, "internal server error", h.logger)
 		return
@@ -49,7 +49,7 @@ func (h *Handler) AssignmentsByUser(w http.ResponseWriter, r *http.Request) {
 
 	if err := json.NewEncoder(w).Encode(resp); err != nil {
 		if h.logger != nil {
-			h.logger.Error("handleStatsByUser: failed to write response", slog.Any("error", err))
+			h.logger.ErrorContext(r.Context(), "handleStatsByUser: failed to write response", slog.Any("error", err))
 		}
 	}
 }
@@ -64,7 +64,7 @@ func (h *Handler) AssignmentsByPullRequest(w http.ResponseWriter, r *http.Reques
 	stats, err := h.svc.GetAssignmentsByPullRequest(r.Context())
 	if err != nil {
 		if h.logger != nil {
-			h.logger.Error("handleStatsByPullRequest: GetAssignmentsByPullRequest error", slog.Any("error", err))
+			h.logger.ErrorContext(r.Context(), "handleStatsByPullRequest: GetAssignmentsByPullRequest error", slog.Any("error", err))
 		}
 		httperr.WriteJSONError(w, http.StatusInternalServerError, httperr.ErrorCodeInternal, "internal server error", h.logger)
 		return
@@ -79,7 +79,37 @@ func (h *Handler) AssignmentsByPullRequest(w http.ResponseWriter, r *http.Reques
 
 	if err := json.NewEncoder(w).Encode(resp); err != nil {
 		if h.logger != nil {
-			h.logger.Error("handleStatsByPullRequest: failed to write response", slog.Any("error", err))
+			h.logger.ErrorContext(r.Context(), "handleStatsByPullRequest: failed to write response", slog.Any("error", err))
+		}
+	}
+}
+
+// AssignmentsByTeam отдаёт статистику назначений по командам с суммами по иерархии.
+func (h *Handler) AssignmentsByTeam(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		httperr.WriteJSONError(w, http.StatusMethodNotAllowed, httperr.ErrorCodeMethodNotAllowed, "method not allowed", h.logger)
+		return
+	}
+
+	stats, err := h.svc.GetAssignmentsByTeam(r.Context())
+	if err != nil {
+		if h.logger != nil {
+			h.logger.ErrorContext(r.Context(), "handleStatsByTeam: GetAssignmentsByTeam error", slog.Any("error", err))
+		}
+		httperr.WriteJSONError(w, http.StatusInternalServerError, httperr.ErrorCodeInternal, "internal server error", h.logger)
+		return
+	}
+
+	resp := TeamStatsResponse{
+		Stats: mapTeamStatsToDTO(stats),
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		if h.logger != nil {
+			h.logger.ErrorContext(r.Context(), "handleStatsByTeam: failed to write response", slog.Any("error", err))
 		}
 	}
 }
@@ -1,12 +1,19 @@
 // Package team содержит обработчики и DTO для работы с командами.
 package team
 
-import "github.com/dixitix/pr-reviewer-service/internal/domain"
+import (
+	"time"
+
+	"github.com/dixitix/pr-reviewer-service/internal/domain"
+)
 
 // mapTeamDTOToDomain конвертирует HTTP-DTO команды в доменную команду и её участников.
 func mapTeamDTOToDomain(dto DTO) (domain.Team, []domain.User) {
 	team := domain.Team{
-		Name: domain.TeamName(dto.TeamName),
+		Name:             domain.TeamName(dto.TeamName),
+		Parent:           domain.TeamName(dto.ParentName),
+		FallbackToParent: dto.FallbackToParent,
+		Leads:            stringsToUserIDs(dto.Leads),
 	}
 
 	members := make([]domain.User, len(dto.Members))
@@ -32,10 +39,107 @@ func mapTeamDomainToDTO(team domain.Team, users []domain.User) DTO {
 			Username: u.Username,
 			IsActive: u.IsActive,
 		}
+
+		if u.TeamName != team.Name {
+			members[i].PrimaryTeamName = string(u.TeamName)
+		}
 	}
 
 	return DTO{
-		TeamName: string(team.Name),
-		Members:  members,
+		TeamName:         string(team.Name),
+		ParentName:       string(team.Parent),
+		FallbackToParent: team.FallbackToParent,
+		Members:          members,
+		Leads:            userIDsToStrings(team.Leads),
+	}
+}
+
+// mapTreeMembersToDTO конвертирует участников иерархии команд в HTTP-DTO.
+func mapTreeMembersToDTO(users []domain.User) []TreeMemberDTO {
+	result := make([]TreeMemberDTO, len(users))
+	for i, u := range users {
+		result[i] = TreeMemberDTO{
+			UserID:   string(u.ID),
+			Username: u.Username,
+			TeamName: string(u.TeamName),
+			IsActive: u.IsActive,
+		}
+	}
+
+	return result
+}
+
+// userIDsToStrings конвертирует ID пользователей в строки.
+func userIDsToStrings(ids []domain.UserID) []string {
+	result := make([]string, len(ids))
+	for i, id := range ids {
+		result[i] = string(id)
+	}
+
+	return result
+}
+
+// stringsToUserIDs конвертирует строки в ID пользователей.
+func stringsToUserIDs(ids []string) []domain.UserID {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	result := make([]domain.UserID, len(ids))
+	for i, id := range ids {
+		result[i] = domain.UserID(id)
+	}
+
+	return result
+}
+
+// mapReviewPolicyDTOToDomain преобразует DTO SLA ревью в доменную модель.
+func mapReviewPolicyDTOToDomain(dto ReviewPolicyDTO) domain.ReviewPolicy {
+	return domain.ReviewPolicy{
+		TeamName:      domain.TeamName(dto.TeamName),
+		ReminderAfter: time.Duration(dto.ReminderAfterMinutes) * time.Minute,
+		EscalateAfter: time.Duration(dto.EscalateAfterMinutes) * time.Minute,
+		Action:        domain.EscalationAction(dto.EscalationAction),
+	}
+}
+
+// mapReviewPolicyDomainToDTO преобразует доменную модель SLA ревью в DTO.
+func mapReviewPolicyDomainToDTO(policy domain.ReviewPolicy) ReviewPolicyDTO {
+	return ReviewPolicyDTO{
+		TeamName:             string(policy.TeamName),
+		ReminderAfterMinutes: int(policy.ReminderAfter / time.Minute),
+		EscalateAfterMinutes: int(policy.EscalateAfter / time.Minute),
+		EscalationAction:     string(policy.Action),
+	}
+}
+
+// MapMembershipsToResponse конвертирует команды пользователя в HTTP-ответ.
+func MapMembershipsToResponse(userID domain.UserID, memberships []domain.TeamMembership) MembershipsResponse {
+	teams := make([]MembershipDTO, len(memberships))
+	for i, m := range memberships {
+		teams[i] = MembershipDTO{
+			TeamName:  string(m.TeamName),
+			IsPrimary: m.IsPrimary,
+		}
+	}
+
+	return MembershipsResponse{
+		UserID: string(userID),
+		Teams:  teams,
+	}
+}
+
+// mapTeamsToSummaries конвертирует доменные команды в элементы списка GET /v2/teams.
+func mapTeamsToSummaries(teams []domain.Team) []SummaryDTO {
+	res := make([]SummaryDTO, len(teams))
+
+	for i, t := range teams {
+		res[i] = SummaryDTO{
+			TeamName:         string(t.Name),
+			ParentName:       string(t.Parent),
+			FallbackToParent: t.FallbackToParent,
+		}
 	}
+
+	return res
 }
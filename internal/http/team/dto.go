@@ -2,19 +2,134 @@
 package team
 
 // MemberDTO представляет участника команды в HTTP-слое.
+// PrimaryTeamName заполняется в ответах, если команда для участника не основная.
 type MemberDTO struct {
-	UserID   string `json:"user_id"`
-	Username string `json:"username"`
-	IsActive bool   `json:"is_active"`
+	UserID          string `json:"user_id"`
+	Username        string `json:"username"`
+	IsActive        bool   `json:"is_active"`
+	PrimaryTeamName string `json:"primary_team_name,omitempty"`
 }
 
-// DTO представляет команду и её участников в HTTP-слое.
+// DTO представляет команду, её участников и лидов в HTTP-слое.
+// ParentName пуст для корневой команды.
 type DTO struct {
-	TeamName string      `json:"team_name"`
-	Members  []MemberDTO `json:"members"`
+	TeamName         string      `json:"team_name"`
+	ParentName       string      `json:"parent_name,omitempty"`
+	FallbackToParent bool        `json:"fallback_to_parent"`
+	Members          []MemberDTO `json:"members"`
+	Leads            []string    `json:"leads"`
 }
 
 // GetTeamResponse описывает ответ на запрос получения команды.
 type GetTeamResponse struct {
 	Team DTO `json:"team"`
 }
+
+// ReviewPolicyDTO представляет SLA ревью команды в HTTP-слое. Пороги задаются в минутах, 0 — отключено.
+type ReviewPolicyDTO struct {
+	TeamName             string `json:"team_name"`
+	ReminderAfterMinutes int    `json:"reminder_after_minutes"`
+	EscalateAfterMinutes int    `json:"escalate_after_minutes"`
+	EscalationAction     string `json:"escalation_action"`
+}
+
+// ReviewPolicyEnvelope описывает ответ с SLA ревью команды.
+type ReviewPolicyEnvelope struct {
+	ReviewPolicy ReviewPolicyDTO `json:"review_policy"`
+}
+
+// SetLeadsRequest описывает тело запроса /team/setLeads.
+type SetLeadsRequest struct {
+	TeamName string   `json:"team_name"`
+	Leads    []string `json:"leads"`
+}
+
+// LeadsResponse описывает ответ на изменение лидов команды.
+type LeadsResponse struct {
+	TeamName string   `json:"team_name"`
+	Leads    []string `json:"leads"`
+}
+
+// SetParentRequest описывает тело запроса /team/setParent. Пустой parent_name делает команду корневой.
+type SetParentRequest struct {
+	TeamName         string `json:"team_name"`
+	ParentName       string `json:"parent_name"`
+	FallbackToParent bool   `json:"fallback_to_parent"`
+}
+
+// HierarchyResponse описывает положение команды в иерархии.
+type HierarchyResponse struct {
+	TeamName         string `json:"team_name"`
+	ParentName       string `json:"parent_name,omitempty"`
+	FallbackToParent bool   `json:"fallback_to_parent"`
+}
+
+// TreeMemberDTO представляет участника команды или одной из её дочерних команд.
+type TreeMemberDTO struct {
+	UserID   string `json:"user_id"`
+	Username string `json:"username"`
+	TeamName string `json:"team_name"`
+	IsActive bool   `json:"is_active"`
+}
+
+// TreeMembersResponse описывает ответ /team/getAllMembers.
+type TreeMembersResponse struct {
+	TeamName string          `json:"team_name"`
+	Members  []TreeMemberDTO `json:"members"`
+}
+
+// MemberRequest описывает тело запросов /team/addMember и /team/removeMember.
+// IsPrimary учитывается только при добавлении и делает команду основной для пользователя.
+type MemberRequest struct {
+	TeamName  string `json:"team_name"`
+	UserID    string `json:"user_id"`
+	IsPrimary bool   `json:"is_primary"`
+}
+
+// MembershipDTO представляет членство пользователя в команде.
+type MembershipDTO struct {
+	TeamName  string `json:"team_name"`
+	IsPrimary bool   `json:"is_primary"`
+}
+
+// MembershipsResponse описывает команды пользователя, основную первой.
+type MembershipsResponse struct {
+	UserID string          `json:"user_id"`
+	Teams  []MembershipDTO `json:"teams"`
+}
+
+// SummaryDTO описывает команду в списке GET /v2/teams: без участников и лидов.
+type SummaryDTO struct {
+	TeamName         string `json:"team_name"`
+	ParentName       string `json:"parent_name,omitempty"`
+	FallbackToParent bool   `json:"fallback_to_parent"`
+}
+
+// ListResponse описывает ответ GET /v2/teams.
+type ListResponse struct {
+	Teams []SummaryDTO `json:"teams"`
+}
+
+// SetLeadsV2Request описывает тело запроса PUT /v2/teams/{name}/leads.
+type SetLeadsV2Request struct {
+	Leads []string `json:"leads"`
+}
+
+// SetParentV2Request описывает тело запроса PUT /v2/teams/{name}/parent.
+type SetParentV2Request struct {
+	ParentName       string `json:"parent_name"`
+	FallbackToParent bool   `json:"fallback_to_parent"`
+}
+
+// AddMemberV2Request описывает тело запроса POST /v2/teams/{name}/members.
+type AddMemberV2Request struct {
+	UserID    string `json:"user_id"`
+	IsPrimary bool   `json:"is_primary"`
+}
+
+// ReviewPolicyV2Request описывает тело запроса PUT /v2/teams/{name}/review-policy.
+type ReviewPolicyV2Request struct {
+	ReminderAfterMinutes int    `json:"reminder_after_minutes"`
+	EscalateAfterMinutes int    `json:"escalate_after_minutes"`
+	EscalationAction     string `json:"escalation_action"`
+}
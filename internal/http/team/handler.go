@@ -8,6 +8,7 @@ import (
 	"net/http"
 
 	"github.com/dixitix/pr-reviewer-service/internal/domain"
+	"github.com/dixitix/pr-reviewer-service/internal/http/etag"
 	"github.com/dixitix/pr-reviewer-service/internal/http/httperr"
 	"github.com/dixitix/pr-reviewer-service/internal/service"
 )
@@ -39,6 +40,11 @@ func (h *Handler) Add(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	h.create(w, r, req)
+}
+
+// create проверяет и создаёт команду; общая часть /team/add и POST /v2/teams.
+func (h *Handler) create(w http.ResponseWriter, r *http.Request, req DTO) {
 	if req.TeamName == "" {
 		httperr.WriteJSONError(w, http.StatusBadRequest, httperr.ErrorCodeValidation, "team_name is required", h.logger)
 		return
@@ -51,28 +57,45 @@ func (h *Handler) Add(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
+	for _, id := range req.Leads {
+		if id == "" {
+			httperr.WriteJSONError(w, http.StatusBadRequest, httperr.ErrorCodeValidation, "leads must not contain empty user_id", h.logger)
+			return
+		}
+	}
+
+	if req.ParentName == req.TeamName {
+		httperr.WriteJSONError(w, http.StatusBadRequest, httperr.ErrorCodeValidation, "parent_name must differ from team_name", h.logger)
+		return
+	}
+
 	team, members := mapTeamDTOToDomain(req)
 
 	ctx := r.Context()
 
 	if h.logger != nil {
-		h.logger.Info("handleTeamAdd: creating team", slog.String("team_name", req.TeamName), slog.Int("members_count", len(req.Members)))
+		h.logger.InfoContext(r.Context(), "handleTeamAdd: creating team", slog.String("team_name", req.TeamName), slog.Int("members_count", len(req.Members)))
 	}
 
-	err := h.svc.CreateTeam(ctx, team.Name, members)
+	err := h.svc.CreateTeam(ctx, team, members)
 	if err != nil {
 		if errors.Is(err, service.ErrTeamAlreadyExists) {
 			httperr.WriteJSONError(w, http.StatusBadRequest, httperr.ErrorCodeTeamExists, "team_name already exists", h.logger)
 			return
 		}
 
+		if errors.Is(err, service.ErrForbidden) {
+			httperr.WriteJSONError(w, http.StatusForbidden, httperr.ErrorCodeForbidden, "only a lead of the parent team or an admin may create this team", h.logger)
+			return
+		}
+
 		if errors.Is(err, service.ErrNotFound) {
-			httperr.WriteJSONError(w, http.StatusNotFound, httperr.ErrorCodeNotFound, "resource not found", h.logger)
+			httperr.WriteJSONError(w, http.StatusNotFound, httperr.ErrorCodeNotFound, "parent team or lead user not found", h.logger)
 			return
 		}
 
 		if h.logger != nil {
-			h.logger.Error("handleTeamAdd: CreateTeam error", slog.Any("error", err))
+			h.logger.ErrorContext(r.Context(), "handleTeamAdd: CreateTeam error", slog.Any("error", err))
 		}
 
 		httperr.WriteJSONError(w, http.StatusInternalServerError, httperr.ErrorCodeInternal, "internal server error", h.logger)
@@ -81,8 +104,11 @@ func (h *Handler) Add(w http.ResponseWriter, r *http.Request) {
 
 	resp := GetTeamResponse{
 		Team: DTO{
-			TeamName: req.TeamName,
-			Members:  req.Members,
+			TeamName:         req.TeamName,
+			ParentName:       req.ParentName,
+			FallbackToParent: req.FallbackToParent,
+			Members:          req.Members,
+			Leads:            userIDsToStrings(team.Leads),
 		},
 	}
 
@@ -91,12 +117,13 @@ func (h *Handler) Add(w http.ResponseWriter, r *http.Request) {
 
 	if err := json.NewEncoder(w).Encode(resp); err != nil {
 		if h.logger != nil {
-			h.logger.Error("handleTeamAdd: failed to write response", slog.Any("error", err))
+			h.logger.ErrorContext(r.Context(), "handleTeamAdd: failed to write response", slog.Any("error", err))
 		}
 	}
 }
 
-// Get обрабатывает получение информации о команде.
+// Get обрабатывает получение информации о команде. Ответ содержит ETag версии команды;
+// при совпадении с If-None-Match возвращается 304 без тела.
 func (h *Handler) Get(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
 		httperr.WriteJSONError(w, http.StatusMethodNotAllowed, httperr.ErrorCodeMethodNotAllowed, "method not allowed", h.logger)
@@ -109,6 +136,11 @@ func (h *Handler) Get(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	h.get(w, r, teamNameParam)
+}
+
+// get отдаёт команду с ETag; общая часть /team/get и GET /v2/teams/{name}.
+func (h *Handler) get(w http.ResponseWriter, r *http.Request, teamNameParam string) {
 	ctx := r.Context()
 	team, members, err := h.svc.GetTeam(ctx, domain.TeamName(teamNameParam))
 	if err != nil {
@@ -118,13 +150,20 @@ func (h *Handler) Get(w http.ResponseWriter, r *http.Request) {
 		}
 
 		if h.logger != nil {
-			h.logger.Error("handleTeamGet: GetTeam error", slog.Any("error", err))
+			h.logger.ErrorContext(r.Context(), "handleTeamGet: GetTeam error", slog.Any("error", err))
 		}
 
 		httperr.WriteJSONError(w, http.StatusInternalServerError, httperr.ErrorCodeInternal, "internal server error", h.logger)
 		return
 	}
 
+	etag.Set(w, team.Version)
+
+	if etag.NotModified(r, team.Version) {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
 	resp := mapTeamDomainToDTO(team, members)
 
 	w.Header().Set("Content-Type", "application/json")
@@ -132,7 +171,460 @@ func (h *Handler) Get(w http.ResponseWriter, r *http.Request) {
 
 	if err := json.NewEncoder(w).Encode(resp); err != nil {
 		if h.logger != nil {
-			h.logger.Error("handleTeamGet: failed to write response", slog.Any("error", err))
+			h.logger.ErrorContext(r.Context(), "handleTeamGet: failed to write response", slog.Any("error", err))
+		}
+	}
+}
+
+// GetReviewPolicy обрабатывает получение SLA ревью команды.
+func (h *Handler) GetReviewPolicy(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		httperr.WriteJSONError(w, http.StatusMethodNotAllowed, httperr.ErrorCodeMethodNotAllowed, "method not allowed", h.logger)
+		return
+	}
+
+	teamNameParam := r.URL.Query().Get("team_name")
+	if teamNameParam == "" {
+		httperr.WriteJSONError(w, http.StatusBadRequest, httperr.ErrorCodeValidation, "team_name is required", h.logger)
+		return
+	}
+
+	h.getReviewPolicy(w, r, teamNameParam)
+}
+
+// getReviewPolicy отдаёт SLA ревью команды teamNameParam.
+func (h *Handler) getReviewPolicy(w http.ResponseWriter, r *http.Request, teamNameParam string) {
+	policy, err := h.svc.GetReviewPolicy(r.Context(), domain.TeamName(teamNameParam))
+	if err != nil {
+		if errors.Is(err, service.ErrNotFound) {
+			httperr.WriteJSONError(w, http.StatusNotFound, httperr.ErrorCodeNotFound, "review policy not found", h.logger)
+			return
+		}
+
+		if h.logger != nil {
+			h.logger.ErrorContext(r.Context(), "handleTeamGetReviewPolicy: GetReviewPolicy error", slog.Any("error", err))
+		}
+
+		httperr.WriteJSONError(w, http.StatusInternalServerError, httperr.ErrorCodeInternal, "internal server error", h.logger)
+		return
+	}
+
+	resp := ReviewPolicyEnvelope{
+		ReviewPolicy: mapReviewPolicyDomainToDTO(policy),
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		if h.logger != nil {
+			h.logger.ErrorContext(r.Context(), "handleTeamGetReviewPolicy: failed to write response", slog.Any("error", err))
+		}
+	}
+}
+
+// SetReviewPolicy обрабатывает создание или обновление SLA ревью команды.
+func (h *Handler) SetReviewPolicy(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		httperr.WriteJSONError(w, http.StatusMethodNotAllowed, httperr.ErrorCodeMethodNotAllowed, "method not allowed", h.logger)
+		return
+	}
+
+	var req ReviewPolicyDTO
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httperr.WriteJSONError(w, http.StatusBadRequest, httperr.ErrorCodeInvalidJSON, "invalid JSON body", h.logger)
+		return
+	}
+
+	h.setReviewPolicy(w, r, req)
+}
+
+// setReviewPolicy проверяет и сохраняет SLA ревью команды из req.
+func (h *Handler) setReviewPolicy(w http.ResponseWriter, r *http.Request, req ReviewPolicyDTO) {
+	if req.TeamName == "" {
+		httperr.WriteJSONError(w, http.StatusBadRequest, httperr.ErrorCodeValidation, "team_name is required", h.logger)
+		return
+	}
+
+	if req.ReminderAfterMinutes < 0 || req.EscalateAfterMinutes < 0 {
+		httperr.WriteJSONError(w, http.StatusBadRequest, httperr.ErrorCodeValidation, "thresholds must not be negative", h.logger)
+		return
+	}
+
+	if req.EscalationAction == "" {
+		req.EscalationAction = string(domain.EscalationActionNone)
+	}
+
+	policy := mapReviewPolicyDTOToDomain(req)
+	if !policy.Action.Valid() {
+		httperr.WriteJSONError(w, http.StatusBadRequest, httperr.ErrorCodeValidation, "escalation_action must be one of none, reassign, escalate", h.logger)
+		return
+	}
+
+	if err := h.svc.SetReviewPolicy(r.Context(), policy); err != nil {
+		if errors.Is(err, service.ErrForbidden) {
+			httperr.WriteJSONError(w, http.StatusForbidden, httperr.ErrorCodeForbidden, "only a team lead or an admin may change the review policy", h.logger)
+			return
+		}
+
+		if errors.Is(err, service.ErrNotFound) {
+			httperr.WriteJSONError(w, http.StatusNotFound, httperr.ErrorCodeNotFound, "team not found", h.logger)
+			return
+		}
+
+		if h.logger != nil {
+			h.logger.ErrorContext(r.Context(), "handleTeamSetReviewPolicy: SetReviewPolicy error", slog.Any("error", err))
+		}
+
+		httperr.WriteJSONError(w, http.StatusInternalServerError, httperr.ErrorCodeInternal, "internal server error", h.logger)
+		return
+	}
+
+	resp := ReviewPolicyEnvelope{
+		ReviewPolicy: mapReviewPolicyDomainToDTO(policy),
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		if h.logger != nil {
+			h.logger.ErrorContext(r.Context(), "handleTeamSetReviewPolicy: failed to write response", slog.Any("error", err))
+		}
+	}
+}
+
+// SetLeads обрабатывает замену списка лидов команды.
+func (h *Handler) SetLeads(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		httperr.WriteJSONError(w, http.StatusMethodNotAllowed, httperr.ErrorCodeMethodNotAllowed, "method not allowed", h.logger)
+		return
+	}
+
+	var req SetLeadsRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httperr.WriteJSONError(w, http.StatusBadRequest, httperr.ErrorCodeInvalidJSON, "invalid JSON body", h.logger)
+		return
+	}
+
+	h.setLeads(w, r, req)
+}
+
+// setLeads проверяет и заменяет список лидов команды из req.
+func (h *Handler) setLeads(w http.ResponseWriter, r *http.Request, req SetLeadsRequest) {
+	if req.TeamName == "" {
+		httperr.WriteJSONError(w, http.StatusBadRequest, httperr.ErrorCodeValidation, "team_name is required", h.logger)
+		return
+	}
+
+	for _, id := range req.Leads {
+		if id == "" {
+			httperr.WriteJSONError(w, http.StatusBadRequest, httperr.ErrorCodeValidation, "leads must not contain empty user_id", h.logger)
+			return
+		}
+	}
+
+	if h.logger != nil {
+		h.logger.InfoContext(r.Context(), "handleTeamSetLeads", slog.String("team_name", req.TeamName), slog.Int("leads_count", len(req.Leads)))
+	}
+
+	team, err := h.svc.SetTeamLeads(etag.IfMatch(r), domain.TeamName(req.TeamName), stringsToUserIDs(req.Leads))
+	if err != nil {
+		if errors.Is(err, service.ErrForbidden) {
+			httperr.WriteJSONError(w, http.StatusForbidden, httperr.ErrorCodeForbidden, "only a team lead or an admin may change team leads", h.logger)
+			return
+		}
+
+		if errors.Is(err, service.ErrPreconditionFailed) {
+			httperr.WriteJSONError(w, http.StatusPreconditionFailed, httperr.ErrorCodePreconditionFailed, "team was modified, If-Match does not match its ETag", h.logger)
+			return
+		}
+
+		if errors.Is(err, service.ErrNotFound) {
+			httperr.WriteJSONError(w, http.StatusNotFound, httperr.ErrorCodeNotFound, "team or lead user not found", h.logger)
+			return
+		}
+
+		if h.logger != nil {
+			h.logger.ErrorContext(r.Context(), "handleTeamSetLeads: SetTeamLeads error", slog.Any("error", err))
+		}
+
+		httperr.WriteJSONError(w, http.StatusInternalServerError, httperr.ErrorCodeInternal, "internal server error", h.logger)
+		return
+	}
+
+	resp := LeadsResponse{
+		TeamName: string(team.Name),
+		Leads:    userIDsToStrings(team.Leads),
+	}
+
+	etag.Set(w, team.Version)
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		if h.logger != nil {
+			h.logger.ErrorContext(r.Context(), "handleTeamSetLeads: failed to write response", slog.Any("error", err))
+		}
+	}
+}
+
+// SetParent обрабатывает изменение положения команды в иерархии.
+func (h *Handler) SetParent(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		httperr.WriteJSONError(w, http.StatusMethodNotAllowed, httperr.ErrorCodeMethodNotAllowed, "method not allowed", h.logger)
+		return
+	}
+
+	var req SetParentRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httperr.WriteJSONError(w, http.StatusBadRequest, httperr.ErrorCodeInvalidJSON, "invalid JSON body", h.logger)
+		return
+	}
+
+	h.setParent(w, r, req)
+}
+
+// setParent проверяет и меняет родителя команды из req.
+func (h *Handler) setParent(w http.ResponseWriter, r *http.Request, req SetParentRequest) {
+	if req.TeamName == "" {
+		httperr.WriteJSONError(w, http.StatusBadRequest, httperr.ErrorCodeValidation, "team_name is required", h.logger)
+		return
+	}
+
+	if h.logger != nil {
+		h.logger.InfoContext(r.Context(), "handleTeamSetParent", slog.String("team_name", req.TeamName), slog.String("parent_name", req.ParentName))
+	}
+
+	team, err := h.svc.SetTeamParent(
+		etag.IfMatch(r),
+		domain.TeamName(req.TeamName),
+		domain.TeamName(req.ParentName),
+		req.FallbackToParent,
+	)
+	if err != nil {
+		if errors.Is(err, service.ErrForbidden) {
+			httperr.WriteJSONError(w, http.StatusForbidden, httperr.ErrorCodeForbidden, "only a lead of both teams or an admin may move the team", h.logger)
+			return
+		}
+
+		if errors.Is(err, service.ErrTeamHierarchyCycle) {
+			httperr.WriteJSONError(w, http.StatusBadRequest, httperr.ErrorCodeValidation, "parent_name must not be the team itself or its descendant", h.logger)
+			return
+		}
+
+		if errors.Is(err, service.ErrPreconditionFailed) {
+			httperr.WriteJSONError(w, http.StatusPreconditionFailed, httperr.ErrorCodePreconditionFailed, "team was modified, If-Match does not match its ETag", h.logger)
+			return
+		}
+
+		if errors.Is(err, service.ErrNotFound) {
+			httperr.WriteJSONError(w, http.StatusNotFound, httperr.ErrorCodeNotFound, "team or parent team not found", h.logger)
+			return
+		}
+
+		if h.logger != nil {
+			h.logger.ErrorContext(r.Context(), "handleTeamSetParent: SetTeamParent error", slog.Any("error", err))
+		}
+
+		httperr.WriteJSONError(w, http.StatusInternalServerError, httperr.ErrorCodeInternal, "internal server error", h.logger)
+		return
+	}
+
+	resp := HierarchyResponse{
+		TeamName:         string(team.Name),
+		ParentName:       string(team.Parent),
+		FallbackToParent: team.FallbackToParent,
+	}
+
+	etag.Set(w, team.Version)
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		if h.logger != nil {
+			h.logger.ErrorContext(r.Context(), "handleTeamSetParent: failed to write response", slog.Any("error", err))
+		}
+	}
+}
+
+// GetAllMembers обрабатывает получение участников команды и всех её дочерних команд.
+func (h *Handler) GetAllMembers(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		httperr.WriteJSONError(w, http.StatusMethodNotAllowed, httperr.ErrorCodeMethodNotAllowed, "method not allowed", h.logger)
+		return
+	}
+
+	teamNameParam := r.URL.Query().Get("team_name")
+	if teamNameParam == "" {
+		httperr.WriteJSONError(w, http.StatusBadRequest, httperr.ErrorCodeValidation, "team_name is required", h.logger)
+		return
+	}
+
+	h.getAllMembers(w, r, teamNameParam)
+}
+
+// getAllMembers отдаёт участников поддерева команды teamNameParam.
+func (h *Handler) getAllMembers(w http.ResponseWriter, r *http.Request, teamNameParam string) {
+	members, err := h.svc.ListTeamTreeMembers(r.Context(), domain.TeamName(teamNameParam))
+	if err != nil {
+		if errors.Is(err, service.ErrNotFound) {
+			httperr.WriteJSONError(w, http.StatusNotFound, httperr.ErrorCodeNotFound, "team not found", h.logger)
+			return
+		}
+
+		if h.logger != nil {
+			h.logger.ErrorContext(r.Context(), "handleTeamGetAllMembers: ListTeamTreeMembers error", slog.Any("error", err))
+		}
+
+		httperr.WriteJSONError(w, http.StatusInternalServerError, httperr.ErrorCodeInternal, "internal server error", h.logger)
+		return
+	}
+
+	resp := TreeMembersResponse{
+		TeamName: teamNameParam,
+		Members:  mapTreeMembersToDTO(members),
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		if h.logger != nil {
+			h.logger.ErrorContext(r.Context(), "handleTeamGetAllMembers: failed to write response", slog.Any("error", err))
+		}
+	}
+}
+
+// AddMember обрабатывает добавление пользователя в команду.
+func (h *Handler) AddMember(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		httperr.WriteJSONError(w, http.StatusMethodNotAllowed, httperr.ErrorCodeMethodNotAllowed, "method not allowed", h.logger)
+		return
+	}
+
+	var req MemberRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httperr.WriteJSONError(w, http.StatusBadRequest, httperr.ErrorCodeInvalidJSON, "invalid JSON body", h.logger)
+		return
+	}
+
+	h.addMember(w, r, req)
+}
+
+// addMember проверяет req и добавляет пользователя в команду.
+func (h *Handler) addMember(w http.ResponseWriter, r *http.Request, req MemberRequest) {
+	if req.TeamName == "" || req.UserID == "" {
+		httperr.WriteJSONError(w, http.StatusBadRequest, httperr.ErrorCodeValidation, "team_name and user_id are required", h.logger)
+		return
+	}
+
+	if h.logger != nil {
+		h.logger.InfoContext(r.Context(), "handleTeamAddMember", slog.String("team_name", req.TeamName), slog.String("user_id", req.UserID))
+	}
+
+	memberships, err := h.svc.AddTeamMember(etag.IfMatch(r), domain.TeamName(req.TeamName), domain.UserID(req.UserID), req.IsPrimary)
+	if err != nil {
+		if errors.Is(err, service.ErrForbidden) {
+			httperr.WriteJSONError(w, http.StatusForbidden, httperr.ErrorCodeForbidden, "only a team lead or an admin may add members", h.logger)
+			return
+		}
+
+		if errors.Is(err, service.ErrPreconditionFailed) {
+			httperr.WriteJSONError(w, http.StatusPreconditionFailed, httperr.ErrorCodePreconditionFailed, "team was modified, If-Match does not match its ETag", h.logger)
+			return
+		}
+
+		if errors.Is(err, service.ErrNotFound) {
+			httperr.WriteJSONError(w, http.StatusNotFound, httperr.ErrorCodeNotFound, "team or user not found", h.logger)
+			return
+		}
+
+		if h.logger != nil {
+			h.logger.ErrorContext(r.Context(), "handleTeamAddMember: AddTeamMember error", slog.Any("error", err))
+		}
+
+		httperr.WriteJSONError(w, http.StatusInternalServerError, httperr.ErrorCodeInternal, "internal server error", h.logger)
+		return
+	}
+
+	resp := MapMembershipsToResponse(domain.UserID(req.UserID), memberships)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		if h.logger != nil {
+			h.logger.ErrorContext(r.Context(), "handleTeamAddMember: failed to write response", slog.Any("error", err))
+		}
+	}
+}
+
+// RemoveMember обрабатывает исключение пользователя из дополнительной команды.
+func (h *Handler) RemoveMember(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		httperr.WriteJSONError(w, http.StatusMethodNotAllowed, httperr.ErrorCodeMethodNotAllowed, "method not allowed", h.logger)
+		return
+	}
+
+	var req MemberRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httperr.WriteJSONError(w, http.StatusBadRequest, httperr.ErrorCodeInvalidJSON, "invalid JSON body", h.logger)
+		return
+	}
+
+	h.removeMember(w, r, req)
+}
+
+// removeMember проверяет req и исключает пользователя из команды.
+func (h *Handler) removeMember(w http.ResponseWriter, r *http.Request, req MemberRequest) {
+	if req.TeamName == "" || req.UserID == "" {
+		httperr.WriteJSONError(w, http.StatusBadRequest, httperr.ErrorCodeValidation, "team_name and user_id are required", h.logger)
+		return
+	}
+
+	if h.logger != nil {
+		h.logger.InfoContext(r.Context(), "handleTeamRemoveMember", slog.String("team_name", req.TeamName), slog.String("user_id", req.UserID))
+	}
+
+	memberships, err := h.svc.RemoveTeamMember(etag.IfMatch(r), domain.TeamName(req.TeamName), domain.UserID(req.UserID))
+	if err != nil {
+		if errors.Is(err, service.ErrForbidden) {
+			httperr.WriteJSONError(w, http.StatusForbidden, httperr.ErrorCodeForbidden, "only a team lead, the user or an admin may remove the membership", h.logger)
+			return
+		}
+
+		if errors.Is(err, service.ErrPrimaryTeamMembership) {
+			httperr.WriteJSONError(w, http.StatusBadRequest, httperr.ErrorCodeValidation, "primary team membership cannot be removed", h.logger)
+			return
+		}
+
+		if errors.Is(err, service.ErrPreconditionFailed) {
+			httperr.WriteJSONError(w, http.StatusPreconditionFailed, httperr.ErrorCodePreconditionFailed, "team was modified, If-Match does not match its ETag", h.logger)
+			return
+		}
+
+		if errors.Is(err, service.ErrNotFound) {
+			httperr.WriteJSONError(w, http.StatusNotFound, httperr.ErrorCodeNotFound, "user is not a member of the team", h.logger)
+			return
+		}
+
+		if h.logger != nil {
+			h.logger.ErrorContext(r.Context(), "handleTeamRemoveMember: RemoveTeamMember error", slog.Any("error", err))
+		}
+
+		httperr.WriteJSONError(w, http.StatusInternalServerError, httperr.ErrorCodeInternal, "internal server error", h.logger)
+		return
+	}
+
+	resp := MapMembershipsToResponse(domain.UserID(req.UserID), memberships)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		if h.logger != nil {
+			h.logger.ErrorContext(r.Context(), "handleTeamRemoveMember: failed to write response", slog.Any("error", err))
 		}
 	}
 }
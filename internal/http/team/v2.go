@@ -0,0 +1,131 @@
+// Package team содержит обработчики и DTO для работы с командами.
+package team
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+
+	"github.com/dixitix/pr-reviewer-service/internal/http/httperr"
+)
+
+// Обработчики API v2. Маршруты регистрируются с методом и шаблоном пути ServeMux,
+// поэтому проверка метода не нужна, а имя команды берётся из {name}.
+// Проверка, вызов сервиса и ответ общие с обработчиками v1.
+
+// CreateV2 обрабатывает POST /v2/teams.
+func (h *Handler) CreateV2(w http.ResponseWriter, r *http.Request) {
+	var req DTO
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httperr.WriteJSONError(w, http.StatusBadRequest, httperr.ErrorCodeInvalidJSON, "invalid JSON body", h.logger)
+		return
+	}
+
+	h.create(w, r, req)
+}
+
+// ListV2 обрабатывает GET /v2/teams.
+func (h *Handler) ListV2(w http.ResponseWriter, r *http.Request) {
+	teams, err := h.svc.ListTeams(r.Context())
+	if err != nil {
+		if h.logger != nil {
+			h.logger.ErrorContext(r.Context(), "handleTeamListV2: ListTeams error", slog.Any("error", err))
+		}
+
+		httperr.WriteJSONError(w, http.StatusInternalServerError, httperr.ErrorCodeInternal, "internal server error", h.logger)
+		return
+	}
+
+	resp := ListResponse{
+		Teams: mapTeamsToSummaries(teams),
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		if h.logger != nil {
+			h.logger.ErrorContext(r.Context(), "handleTeamListV2: failed to write response", slog.Any("error", err))
+		}
+	}
+}
+
+// GetV2 обрабатывает GET /v2/teams/{name}.
+func (h *Handler) GetV2(w http.ResponseWriter, r *http.Request) {
+	h.get(w, r, r.PathValue("name"))
+}
+
+// SetLeadsV2 обрабатывает PUT /v2/teams/{name}/leads.
+func (h *Handler) SetLeadsV2(w http.ResponseWriter, r *http.Request) {
+	var body SetLeadsV2Request
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		httperr.WriteJSONError(w, http.StatusBadRequest, httperr.ErrorCodeInvalidJSON, "invalid JSON body", h.logger)
+		return
+	}
+
+	h.setLeads(w, r, SetLeadsRequest{TeamName: r.PathValue("name"), Leads: body.Leads})
+}
+
+// SetParentV2 обрабатывает PUT /v2/teams/{name}/parent.
+func (h *Handler) SetParentV2(w http.ResponseWriter, r *http.Request) {
+	var body SetParentV2Request
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		httperr.WriteJSONError(w, http.StatusBadRequest, httperr.ErrorCodeInvalidJSON, "invalid JSON body", h.logger)
+		return
+	}
+
+	h.setParent(w, r, SetParentRequest{
+		TeamName:         r.PathValue("name"),
+		ParentName:       body.ParentName,
+		FallbackToParent: body.FallbackToParent,
+	})
+}
+
+// GetAllMembersV2 обрабатывает GET /v2/teams/{name}/all-members.
+func (h *Handler) GetAllMembersV2(w http.ResponseWriter, r *http.Request) {
+	h.getAllMembers(w, r, r.PathValue("name"))
+}
+
+// AddMemberV2 обрабатывает POST /v2/teams/{name}/members.
+func (h *Handler) AddMemberV2(w http.ResponseWriter, r *http.Request) {
+	var body AddMemberV2Request
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		httperr.WriteJSONError(w, http.StatusBadRequest, httperr.ErrorCodeInvalidJSON, "invalid JSON body", h.logger)
+		return
+	}
+
+	h.addMember(w, r, MemberRequest{
+		TeamName:  r.PathValue("name"),
+		UserID:    body.UserID,
+		IsPrimary: body.IsPrimary,
+	})
+}
+
+// RemoveMemberV2 обрабатывает DELETE /v2/teams/{name}/members/{userId}.
+func (h *Handler) RemoveMemberV2(w http.ResponseWriter, r *http.Request) {
+	h.removeMember(w, r, MemberRequest{
+		TeamName: r.PathValue("name"),
+		UserID:   r.PathValue("userId"),
+	})
+}
+
+// GetReviewPolicyV2 обрабатывает GET /v2/teams/{name}/review-policy.
+func (h *Handler) GetReviewPolicyV2(w http.ResponseWriter, r *http.Request) {
+	h.getReviewPolicy(w, r, r.PathValue("name"))
+}
+
+// SetReviewPolicyV2 обрабатывает PUT /v2/teams/{name}/review-policy.
+func (h *Handler) SetReviewPolicyV2(w http.ResponseWriter, r *http.Request) {
+	var body ReviewPolicyV2Request
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		httperr.WriteJSONError(w, http.StatusBadRequest, httperr.ErrorCodeInvalidJSON, "invalid JSON body", h.logger)
+		return
+	}
+
+	h.setReviewPolicy(w, r, ReviewPolicyDTO{
+		TeamName:             r.PathValue("name"),
+		ReminderAfterMinutes: body.ReminderAfterMinutes,
+		EscalateAfterMinutes: body.EscalateAfterMinutes,
+		EscalationAction:     body.EscalationAction,
+	})
+}
@@ -16,6 +16,11 @@ type SetUserActiveResponse struct {
 	User DTO `json:"user"`
 }
 
+// Envelope описывает ответ GET /v2/users/{id}.
+type Envelope struct {
+	User DTO `json:"user"`
+}
+
 // GetUserReviewResponse описывает ответ на запрос списка PR'ов пользователя-ревьювера.
 type GetUserReviewResponse struct {
 	UserID       string              `json:"user_id"`
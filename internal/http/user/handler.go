@@ -10,6 +10,7 @@ import (
 	"github.com/dixitix/pr-reviewer-service/internal/domain"
 	"github.com/dixitix/pr-reviewer-service/internal/http/httperr"
 	"github.com/dixitix/pr-reviewer-service/internal/http/pullrequest"
+	"github.com/dixitix/pr-reviewer-service/internal/http/team"
 	"github.com/dixitix/pr-reviewer-service/internal/service"
 )
 
@@ -40,6 +41,11 @@ func (h *Handler) SetIsActive(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	h.setIsActive(w, r, req)
+}
+
+// setIsActive проверяет req и меняет активность пользователя; общая часть v1 и v2.
+func (h *Handler) setIsActive(w http.ResponseWriter, r *http.Request, req SetUserActiveRequest) {
 	if req.UserID == "" {
 		httperr.WriteJSONError(w, http.StatusBadRequest, httperr.ErrorCodeValidation, "user_id is required", h.logger)
 		return
@@ -48,18 +54,23 @@ func (h *Handler) SetIsActive(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
 	if h.logger != nil {
-		h.logger.Info("handleUserSetIsActive", slog.String("user_id", req.UserID), slog.Bool("is_active", req.IsActive))
+		h.logger.InfoContext(r.Context(), "handleUserSetIsActive", slog.String("user_id", req.UserID), slog.Bool("is_active", req.IsActive))
 	}
 
 	user, err := h.svc.SetUserActive(ctx, domain.UserID(req.UserID), req.IsActive)
 	if err != nil {
+		if errors.Is(err, service.ErrForbidden) {
+			httperr.WriteJSONError(w, http.StatusForbidden, httperr.ErrorCodeForbidden, "only the user, their team lead or an admin may change activity", h.logger)
+			return
+		}
+
 		if errors.Is(err, service.ErrNotFound) {
 			httperr.WriteJSONError(w, http.StatusNotFound, httperr.ErrorCodeNotFound, "user not found", h.logger)
 			return
 		}
 
 		if h.logger != nil {
-			h.logger.Error("handleUserSetIsActive: SetUserActive error", slog.Any("error", err))
+			h.logger.ErrorContext(r.Context(), "handleUserSetIsActive: SetUserActive error", slog.Any("error", err))
 		}
 
 		httperr.WriteJSONError(w, http.StatusInternalServerError, httperr.ErrorCodeInternal, "internal server error", h.logger)
@@ -75,7 +86,7 @@ func (h *Handler) SetIsActive(w http.ResponseWriter, r *http.Request) {
 
 	if err := json.NewEncoder(w).Encode(resp); err != nil {
 		if h.logger != nil {
-			h.logger.Error("handleUserSetIsActive: failed to write response", slog.Any("error", err))
+			h.logger.ErrorContext(r.Context(), "handleUserSetIsActive: failed to write response", slog.Any("error", err))
 		}
 	}
 }
@@ -93,16 +104,21 @@ func (h *Handler) GetReview(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	h.getReview(w, r, userIDParam)
+}
+
+// getReview отдаёт PR'ы, где userIDParam назначен ревьювером.
+func (h *Handler) getReview(w http.ResponseWriter, r *http.Request, userIDParam string) {
 	ctx := r.Context()
 
 	if h.logger != nil {
-		h.logger.Info("handleUserGetReview", slog.String("user_id", userIDParam))
+		h.logger.InfoContext(r.Context(), "handleUserGetReview", slog.String("user_id", userIDParam))
 	}
 
 	prs, err := h.svc.GetUserReviewPullRequests(ctx, domain.UserID(userIDParam))
 	if err != nil && !errors.Is(err, service.ErrNotFound) {
 		if h.logger != nil {
-			h.logger.Error("handleUserGetReview: GetUserReviewPullRequests error", slog.Any("error", err))
+			h.logger.ErrorContext(r.Context(), "handleUserGetReview: GetUserReviewPullRequests error", slog.Any("error", err))
 		}
 
 		httperr.WriteJSONError(w, http.StatusInternalServerError, httperr.ErrorCodeInternal, "internal server error", h.logger)
@@ -126,7 +142,52 @@ func (h *Handler) GetReview(w http.ResponseWriter, r *http.Request) {
 
 	if err := json.NewEncoder(w).Encode(resp); err != nil {
 		if h.logger != nil {
-			h.logger.Error("handleUserGetReview: failed to write response", slog.Any("error", err))
+			h.logger.ErrorContext(r.Context(), "handleUserGetReview: failed to write response", slog.Any("error", err))
+		}
+	}
+}
+
+// GetTeams обрабатывает получение команд пользователя.
+func (h *Handler) GetTeams(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		httperr.WriteJSONError(w, http.StatusMethodNotAllowed, httperr.ErrorCodeMethodNotAllowed, "method not allowed", h.logger)
+		return
+	}
+
+	userIDParam := r.URL.Query().Get("user_id")
+	if userIDParam == "" {
+		httperr.WriteJSONError(w, http.StatusBadRequest, httperr.ErrorCodeValidation, "user_id is required", h.logger)
+		return
+	}
+
+	h.getTeams(w, r, userIDParam)
+}
+
+// getTeams отдаёт команды пользователя userIDParam.
+func (h *Handler) getTeams(w http.ResponseWriter, r *http.Request, userIDParam string) {
+	memberships, err := h.svc.GetUserTeams(r.Context(), domain.UserID(userIDParam))
+	if err != nil {
+		if errors.Is(err, service.ErrNotFound) {
+			httperr.WriteJSONError(w, http.StatusNotFound, httperr.ErrorCodeNotFound, "user not found", h.logger)
+			return
+		}
+
+		if h.logger != nil {
+			h.logger.ErrorContext(r.Context(), "handleUserGetTeams: GetUserTeams error", slog.Any("error", err))
+		}
+
+		httperr.WriteJSONError(w, http.StatusInternalServerError, httperr.ErrorCodeInternal, "internal server error", h.logger)
+		return
+	}
+
+	resp := team.MapMembershipsToResponse(domain.UserID(userIDParam), memberships)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		if h.logger != nil {
+			h.logger.ErrorContext(r.Context(), "handleUserGetTeams: failed to write response", slog.Any("error", err))
 		}
 	}
 }
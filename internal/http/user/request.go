@@ -6,3 +6,9 @@ type SetUserActiveRequest struct {
 	UserID   string `json:"user_id"`
 	IsActive bool   `json:"is_active"`
 }
+
+// UpdateUserV2Request описывает тело запроса PATCH /v2/users/{id}.
+// Поля, не переданные в запросе, не меняются.
+type UpdateUserV2Request struct {
+	IsActive *bool `json:"is_active"`
+}
@@ -0,0 +1,72 @@
+// Package user содержит обработчики и DTO для работы с пользователями.
+package user
+
+import (
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/dixitix/pr-reviewer-service/internal/domain"
+	"github.com/dixitix/pr-reviewer-service/internal/http/httperr"
+	"github.com/dixitix/pr-reviewer-service/internal/service"
+)
+
+// Обработчики API v2. ID пользователя берётся из шаблона пути {id}.
+
+// GetV2 обрабатывает GET /v2/users/{id}.
+func (h *Handler) GetV2(w http.ResponseWriter, r *http.Request) {
+	user, err := h.svc.GetUser(r.Context(), domain.UserID(r.PathValue("id")))
+	if err != nil {
+		if errors.Is(err, service.ErrNotFound) {
+			httperr.WriteJSONError(w, http.StatusNotFound, httperr.ErrorCodeNotFound, "user not found", h.logger)
+			return
+		}
+
+		if h.logger != nil {
+			h.logger.ErrorContext(r.Context(), "handleUserGetV2: GetUser error", slog.Any("error", err))
+		}
+
+		httperr.WriteJSONError(w, http.StatusInternalServerError, httperr.ErrorCodeInternal, "internal server error", h.logger)
+		return
+	}
+
+	resp := Envelope{
+		User: mapUserDomainToDTO(user),
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		if h.logger != nil {
+			h.logger.ErrorContext(r.Context(), "handleUserGetV2: failed to write response", slog.Any("error", err))
+		}
+	}
+}
+
+// UpdateV2 обрабатывает PATCH /v2/users/{id}. Сейчас изменяемо только поле is_active.
+func (h *Handler) UpdateV2(w http.ResponseWriter, r *http.Request) {
+	var body UpdateUserV2Request
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		httperr.WriteJSONError(w, http.StatusBadRequest, httperr.ErrorCodeInvalidJSON, "invalid JSON body", h.logger)
+		return
+	}
+
+	if body.IsActive == nil {
+		httperr.WriteJSONError(w, http.StatusBadRequest, httperr.ErrorCodeValidation, "is_active is required", h.logger)
+		return
+	}
+
+	h.setIsActive(w, r, SetUserActiveRequest{UserID: r.PathValue("id"), IsActive: *body.IsActive})
+}
+
+// GetReviewV2 обрабатывает GET /v2/users/{id}/reviews.
+func (h *Handler) GetReviewV2(w http.ResponseWriter, r *http.Request) {
+	h.getReview(w, r, r.PathValue("id"))
+}
+
+// GetTeamsV2 обрабатывает GET /v2/users/{id}/teams.
+func (h *Handler) GetTeamsV2(w http.ResponseWriter, r *http.Request) {
+	h.getTeams(w, r, r.PathValue("id"))
+}
@@ -0,0 +1,59 @@
+// Package webhook содержит обработчики и DTO для управления исходящими вебхуками.
+package webhook
+
+import (
+	"encoding/json"
+
+	"github.com/dixitix/pr-reviewer-service/internal/domain"
+)
+
+// mapSubscriptionToDTO конвертирует доменную подписку в HTTP-DTO без секрета.
+func mapSubscriptionToDTO(sub domain.WebhookSubscription) SubscriptionDTO {
+	events := make([]string, len(sub.Events))
+	for i, e := range sub.Events {
+		events[i] = string(e)
+	}
+
+	return SubscriptionDTO{
+		SubscriptionID: string(sub.ID),
+		URL:            sub.URL,
+		Events:         events,
+		CreatedAt:      sub.CreatedAt,
+	}
+}
+
+// mapSubscriptionsToDTO конвертирует список подписок в список HTTP-DTO.
+func mapSubscriptionsToDTO(subs []domain.WebhookSubscription) []SubscriptionDTO {
+	result := make([]SubscriptionDTO, len(subs))
+	for i, sub := range subs {
+		result[i] = mapSubscriptionToDTO(sub)
+	}
+
+	return result
+}
+
+// mapDeliveriesToDTO конвертирует недоставленные вебхуки в HTTP-DTO.
+func mapDeliveriesToDTO(deliveries []domain.WebhookDelivery) []DeliveryDTO {
+	result := make([]DeliveryDTO, len(deliveries))
+
+	for i, d := range deliveries {
+		var statusCode *int
+		if d.LastStatusCode != 0 {
+			code := d.LastStatusCode
+			statusCode = &code
+		}
+
+		result[i] = DeliveryDTO{
+			DeliveryID:     d.ID,
+			SubscriptionID: string(d.SubscriptionID),
+			Event:          string(d.EventType),
+			Payload:        json.RawMessage(d.Payload),
+			Attempts:       d.Attempts,
+			LastError:      d.LastError,
+			LastStatusCode: statusCode,
+			FailedAt:       d.FailedAt,
+		}
+	}
+
+	return result
+}
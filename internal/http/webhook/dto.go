@@ -0,0 +1,49 @@
+// Package webhook содержит обработчики и DTO для управления исходящими вебхуками.
+package webhook
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// SubscriptionDTO представляет подписку на вебхуки в HTTP-слое.
+// Секрет возвращается только при создании подписки.
+type SubscriptionDTO struct {
+	SubscriptionID string    `json:"subscription_id"`
+	URL            string    `json:"url"`
+	Events         []string  `json:"events"`
+	Secret         string    `json:"secret,omitempty"`
+	CreatedAt      time.Time `json:"createdAt"`
+}
+
+// SubscriptionEnvelope оборачивает подписку в поле "subscription".
+type SubscriptionEnvelope struct {
+	Subscription SubscriptionDTO `json:"subscription"`
+}
+
+// ListSubscriptionsResponse описывает ответ на /webhooks/list.
+type ListSubscriptionsResponse struct {
+	Subscriptions []SubscriptionDTO `json:"subscriptions"`
+}
+
+// RemoveSubscriptionResponse описывает ответ на /webhooks/remove.
+type RemoveSubscriptionResponse struct {
+	SubscriptionID string `json:"subscription_id"`
+}
+
+// DeliveryDTO представляет недоставленный вебхук.
+type DeliveryDTO struct {
+	DeliveryID     string          `json:"delivery_id"`
+	SubscriptionID string          `json:"subscription_id"`
+	Event          string          `json:"event"`
+	Payload        json.RawMessage `json:"payload"`
+	Attempts       int             `json:"attempts"`
+	LastError      string          `json:"last_error"`
+	LastStatusCode *int            `json:"last_status_code"`
+	FailedAt       time.Time       `json:"failedAt"`
+}
+
+// ListDeliveriesResponse описывает ответ на /webhooks/deliveries.
+type ListDeliveriesResponse struct {
+	Deliveries []DeliveryDTO `json:"deliveries"`
+}
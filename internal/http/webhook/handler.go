@@ -0,0 +1,238 @@
+// Package webhook содержит обработчики и DTO для управления исходящими вебхуками.
+package webhook
+
+import (
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/dixitix/pr-reviewer-service/internal/domain"
+	"github.com/dixitix/pr-reviewer-service/internal/http/httperr"
+	"github.com/dixitix/pr-reviewer-service/internal/service"
+)
+
+const (
+	defaultDeliveriesLimit = 50
+	maxDeliveriesLimit     = 500
+)
+
+// Handler обрабатывает HTTP-запросы управления вебхуками.
+type Handler struct {
+	svc    service.WebhookService
+	logger *slog.Logger
+}
+
+// NewHandler создаёт обработчик вебхуков.
+func NewHandler(svc service.WebhookService, logger *slog.Logger) *Handler {
+	return &Handler{
+		svc:    svc,
+		logger: logger,
+	}
+}
+
+// Add обрабатывает регистрацию подписчика вебхуков.
+func (h *Handler) Add(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		httperr.WriteJSONError(w, http.StatusMethodNotAllowed, httperr.ErrorCodeMethodNotAllowed, "method not allowed", h.logger)
+		return
+	}
+
+	var req CreateSubscriptionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httperr.WriteJSONError(w, http.StatusBadRequest, httperr.ErrorCodeInvalidJSON, "invalid JSON body", h.logger)
+		return
+	}
+
+	if u, err := url.Parse(req.URL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		httperr.WriteJSONError(w, http.StatusBadRequest, httperr.ErrorCodeValidation, "url must be an absolute http(s) URL", h.logger)
+		return
+	}
+
+	events := make([]domain.EventType, len(req.Events))
+	for i, e := range req.Events {
+		events[i] = domain.EventType(e)
+		if !events[i].Valid() {
+			httperr.WriteJSONError(w, http.StatusBadRequest, httperr.ErrorCodeValidation, "unknown event: "+e, h.logger)
+			return
+		}
+	}
+
+	ctx := r.Context()
+
+	if h.logger != nil {
+		h.logger.InfoContext(r.Context(), "handleWebhookAdd", slog.String("url", req.URL), slog.Any("events", req.Events))
+	}
+
+	sub, err := h.svc.CreateWebhookSubscription(ctx, req.URL, events, req.Secret)
+	if err != nil {
+		if errors.Is(err, service.ErrForbidden) {
+			httperr.WriteJSONError(w, http.StatusForbidden, httperr.ErrorCodeForbidden, "only an admin may manage webhooks", h.logger)
+			return
+		}
+
+		if h.logger != nil {
+			h.logger.ErrorContext(r.Context(), "handleWebhookAdd: CreateWebhookSubscription error", slog.Any("error", err))
+		}
+
+		httperr.WriteJSONError(w, http.StatusInternalServerError, httperr.ErrorCodeInternal, "internal server error", h.logger)
+		return
+	}
+
+	dto := mapSubscriptionToDTO(sub)
+	dto.Secret = sub.Secret
+
+	resp := SubscriptionEnvelope{
+		Subscription: dto,
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		if h.logger != nil {
+			h.logger.ErrorContext(r.Context(), "handleWebhookAdd: failed to write response", slog.Any("error", err))
+		}
+	}
+}
+
+// List обрабатывает получение всех подписок.
+func (h *Handler) List(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		httperr.WriteJSONError(w, http.StatusMethodNotAllowed, httperr.ErrorCodeMethodNotAllowed, "method not allowed", h.logger)
+		return
+	}
+
+	subs, err := h.svc.ListWebhookSubscriptions(r.Context())
+	if err != nil {
+		if h.logger != nil {
+			h.logger.ErrorContext(r.Context(), "handleWebhookList: ListWebhookSubscriptions error", slog.Any("error", err))
+		}
+
+		httperr.WriteJSONError(w, http.StatusInternalServerError, httperr.ErrorCodeInternal, "internal server error", h.logger)
+		return
+	}
+
+	resp := ListSubscriptionsResponse{
+		Subscriptions: mapSubscriptionsToDTO(subs),
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		if h.logger != nil {
+			h.logger.ErrorContext(r.Context(), "handleWebhookList: failed to write response", slog.Any("error", err))
+		}
+	}
+}
+
+// Remove обрабатывает удаление подписки.
+func (h *Handler) Remove(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		httperr.WriteJSONError(w, http.StatusMethodNotAllowed, httperr.ErrorCodeMethodNotAllowed, "method not allowed", h.logger)
+		return
+	}
+
+	var req RemoveSubscriptionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httperr.WriteJSONError(w, http.StatusBadRequest, httperr.ErrorCodeInvalidJSON, "invalid JSON body", h.logger)
+		return
+	}
+
+	if req.SubscriptionID == "" {
+		httperr.WriteJSONError(w, http.StatusBadRequest, httperr.ErrorCodeValidation, "subscription_id is required", h.logger)
+		return
+	}
+
+	ctx := r.Context()
+
+	if h.logger != nil {
+		h.logger.InfoContext(r.Context(), "handleWebhookRemove", slog.String("subscription_id", req.SubscriptionID))
+	}
+
+	err := h.svc.DeleteWebhookSubscription(ctx, domain.WebhookSubscriptionID(req.SubscriptionID))
+	if err != nil {
+		if errors.Is(err, service.ErrNotFound) {
+			httperr.WriteJSONError(w, http.StatusNotFound, httperr.ErrorCodeNotFound, "subscription not found", h.logger)
+			return
+		}
+
+		if errors.Is(err, service.ErrForbidden) {
+			httperr.WriteJSONError(w, http.StatusForbidden, httperr.ErrorCodeForbidden, "only an admin may manage webhooks", h.logger)
+			return
+		}
+
+		if h.logger != nil {
+			h.logger.ErrorContext(r.Context(), "handleWebhookRemove: DeleteWebhookSubscription error", slog.Any("error", err))
+		}
+
+		httperr.WriteJSONError(w, http.StatusInternalServerError, httperr.ErrorCodeInternal, "internal server error", h.logger)
+		return
+	}
+
+	resp := RemoveSubscriptionResponse{
+		SubscriptionID: req.SubscriptionID,
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		if h.logger != nil {
+			h.logger.ErrorContext(r.Context(), "handleWebhookRemove: failed to write response", slog.Any("error", err))
+		}
+	}
+}
+
+// Deliveries обрабатывает получение недоставленных вебхуков.
+func (h *Handler) Deliveries(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		httperr.WriteJSONError(w, http.StatusMethodNotAllowed, httperr.ErrorCodeMethodNotAllowed, "method not allowed", h.logger)
+		return
+	}
+
+	query := r.URL.Query()
+
+	limit := defaultDeliveriesLimit
+	if raw := query.Get("limit"); raw != "" {
+		v, err := strconv.Atoi(raw)
+		if err != nil || v <= 0 || v > maxDeliveriesLimit {
+			httperr.WriteJSONError(w, http.StatusBadRequest, httperr.ErrorCodeValidation, "limit must be between 1 and 500", h.logger)
+			return
+		}
+
+		limit = v
+	}
+
+	var subscriptionID *domain.WebhookSubscriptionID
+	if raw := query.Get("subscription_id"); raw != "" {
+		id := domain.WebhookSubscriptionID(raw)
+		subscriptionID = &id
+	}
+
+	deliveries, err := h.svc.ListFailedWebhookDeliveries(r.Context(), subscriptionID, limit)
+	if err != nil {
+		if h.logger != nil {
+			h.logger.ErrorContext(r.Context(), "handleWebhookDeliveries: ListFailedWebhookDeliveries error", slog.Any("error", err))
+		}
+
+		httperr.WriteJSONError(w, http.StatusInternalServerError, httperr.ErrorCodeInternal, "internal server error", h.logger)
+		return
+	}
+
+	resp := ListDeliveriesResponse{
+		Deliveries: mapDeliveriesToDTO(deliveries),
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		if h.logger != nil {
+			h.logger.ErrorContext(r.Context(), "handleWebhookDeliveries: failed to write response", slog.Any("error", err))
+		}
+	}
+}
@@ -0,0 +1,14 @@
+// Package webhook содержит обработчики и DTO для управления исходящими вебхуками.
+package webhook
+
+// CreateSubscriptionRequest описывает тело запроса /webhooks/add.
+type CreateSubscriptionRequest struct {
+	URL    string   `json:"url"`
+	Events []string `json:"events"`
+	Secret string   `json:"secret"`
+}
+
+// RemoveSubscriptionRequest описывает тело запроса /webhooks/remove.
+type RemoveSubscriptionRequest struct {
+	SubscriptionID string `json:"subscription_id"`
+}
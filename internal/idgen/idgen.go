@@ -0,0 +1,17 @@
+// Package idgen содержит генерацию случайных идентификаторов.
+package idgen
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+)
+
+// New возвращает случайный идентификатор из 32 шестнадцатеричных символов.
+func New() string {
+	var b [16]byte
+
+	// crypto/rand.Read не возвращает ошибок на поддерживаемых платформах.
+	_, _ = rand.Read(b[:])
+
+	return hex.EncodeToString(b[:])
+}
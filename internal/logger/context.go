@@ -0,0 +1,52 @@
+// Package logger содержит инициализацию логгера приложения.
+package logger
+
+import (
+	"context"
+	"log/slog"
+)
+
+// RequestIDKey — имя поля с идентификатором запроса в записях лога.
+const RequestIDKey = "request_id"
+
+type requestIDKey struct{}
+
+// WithRequestID возвращает контекст с идентификатором запроса.
+func WithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, id)
+}
+
+// RequestID возвращает идентификатор запроса из контекста. ok == false, если он не задан.
+func RequestID(ctx context.Context) (id string, ok bool) {
+	id, ok = ctx.Value(requestIDKey{}).(string)
+	return id, ok && id != ""
+}
+
+// ContextHandler добавляет к записям лога идентификатор запроса из контекста записи.
+type ContextHandler struct {
+	slog.Handler
+}
+
+// NewContextHandler оборачивает обработчик записей.
+func NewContextHandler(h slog.Handler) *ContextHandler {
+	return &ContextHandler{Handler: h}
+}
+
+// Handle добавляет request_id, если он есть в контексте, и передаёт запись дальше.
+func (h *ContextHandler) Handle(ctx context.Context, record slog.Record) error {
+	if id, ok := RequestID(ctx); ok {
+		record.AddAttrs(slog.String(RequestIDKey, id))
+	}
+
+	return h.Handler.Handle(ctx, record)
+}
+
+// WithAttrs возвращает обработчик с дополнительными полями, сохраняя добавление request_id.
+func (h *ContextHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &ContextHandler{Handler: h.Handler.WithAttrs(attrs)}
+}
+
+// WithGroup возвращает обработчик с группой полей, сохраняя добавление request_id.
+func (h *ContextHandler) WithGroup(name string) slog.Handler {
+	return &ContextHandler{Handler: h.Handler.WithGroup(name)}
+}
@@ -12,10 +12,11 @@ func New() *slog.Logger {
 }
 
 // NewWithLevel создает логгер с заданным уровнем логирования.
+// Записи, сделанные с контекстом запроса (InfoContext, ErrorContext и т.д.), получают поле request_id.
 func NewWithLevel(level slog.Level) *slog.Logger {
 	handler := slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{
 		Level: level,
 	})
 
-	return slog.New(handler)
+	return slog.New(NewContextHandler(handler))
 }
@@ -0,0 +1,42 @@
+// Package metrics собирает метрики сервиса и отдаёт их в текстовом формате Prometheus.
+package metrics
+
+import (
+	"context"
+	"database/sql"
+)
+
+// RegisterDBStats регистрирует метрики пула соединений db по sql.DB.Stats: открытые, занятые
+// и свободные соединения, ожидания свободного соединения и закрытые по лимитам соединения.
+func RegisterDBStats(reg *Registry, db *sql.DB) {
+	gauge := func(name, help string, value func(sql.DBStats) float64) {
+		reg.NewGaugeFunc(name, help, nil, func(context.Context) ([]Sample, error) {
+			return []Sample{{Value: value(db.Stats())}}, nil
+		})
+	}
+
+	counter := func(name, help string, value func(sql.DBStats) float64) {
+		reg.NewCounterFunc(name, help, nil, func(context.Context) ([]Sample, error) {
+			return []Sample{{Value: value(db.Stats())}}, nil
+		})
+	}
+
+	gauge("db_max_open_connections", "Maximum number of open connections to the database.",
+		func(s sql.DBStats) float64 { return float64(s.MaxOpenConnections) })
+	gauge("db_open_connections", "Number of established connections, in use and idle.",
+		func(s sql.DBStats) float64 { return float64(s.OpenConnections) })
+	gauge("db_in_use_connections", "Number of connections currently in use.",
+		func(s sql.DBStats) float64 { return float64(s.InUse) })
+	gauge("db_idle_connections", "Number of idle connections.",
+		func(s sql.DBStats) float64 { return float64(s.Idle) })
+	counter("db_wait_count_total", "Total number of connections waited for.",
+		func(s sql.DBStats) float64 { return float64(s.WaitCount) })
+	counter("db_wait_duration_seconds_total", "Total time blocked waiting for a new connection.",
+		func(s sql.DBStats) float64 { return s.WaitDuration.Seconds() })
+	counter("db_max_idle_closed_total", "Total number of connections closed due to SetMaxIdleConns.",
+		func(s sql.DBStats) float64 { return float64(s.MaxIdleClosed) })
+	counter("db_max_idle_time_closed_total", "Total number of connections closed due to SetConnMaxIdleTime.",
+		func(s sql.DBStats) float64 { return float64(s.MaxIdleTimeClosed) })
+	counter("db_max_lifetime_closed_total", "Total number of connections closed due to SetConnMaxLifetime.",
+		func(s sql.DBStats) float64 { return float64(s.MaxLifetimeClosed) })
+}
@@ -0,0 +1,82 @@
+// Package metrics собирает метрики сервиса и отдаёт их в текстовом формате Prometheus.
+package metrics
+
+import (
+	"context"
+	"sort"
+
+	"github.com/dixitix/pr-reviewer-service/internal/domain"
+)
+
+// Domain собирает метрики назначений ревьюверов. Реализует service.Metrics.
+type Domain struct {
+	created      *Counter
+	merged       *Counter
+	reassigned   *Counter
+	noCandidates *Counter
+}
+
+// NewDomain регистрирует счётчики созданных и смёрженных PR, переназначений и случаев NO_CANDIDATE.
+func NewDomain(reg *Registry) *Domain {
+	return &Domain{
+		created:      reg.NewCounter("pr_reviewer_pull_requests_created_total", "Pull requests created."),
+		merged:       reg.NewCounter("pr_reviewer_pull_requests_merged_total", "Pull requests merged."),
+		reassigned:   reg.NewCounter("pr_reviewer_reassignments_total", "Reviewer reassignments, including SLA reassignments."),
+		noCandidates: reg.NewCounter("pr_reviewer_no_candidate_total", "Reassignments that failed with NO_CANDIDATE."),
+	}
+}
+
+// PullRequestCreated учитывает созданный PR.
+func (d *Domain) PullRequestCreated() {
+	d.created.Inc()
+}
+
+// PullRequestMerged учитывает смёрженный PR.
+func (d *Domain) PullRequestMerged() {
+	d.merged.Inc()
+}
+
+// ReviewerReassigned учитывает переназначение ревьювера.
+func (d *Domain) ReviewerReassigned() {
+	d.reassigned.Inc()
+}
+
+// NoCandidate учитывает переназначение, для которого не нашлось кандидата.
+func (d *Domain) NoCandidate() {
+	d.noCandidates.Inc()
+}
+
+// OpenReviewCounter считает назначения на открытые PR по командам во всех организациях.
+type OpenReviewCounter interface {
+	CountOpenReviewsByTeam(ctx context.Context) (map[domain.OrganizationID]map[domain.TeamName]int, error)
+}
+
+// RegisterOpenReviews регистрирует pr_reviewer_open_reviews — текущее число назначений на открытые PR
+// по организации и основной команде ревьювера. Значения читаются из БД при каждом запросе /metrics.
+func RegisterOpenReviews(reg *Registry, counter OpenReviewCounter) {
+	reg.NewGaugeFunc("pr_reviewer_open_reviews", "Reviewer assignments on open pull requests by organization and team.",
+		[]string{"organization", "team"}, func(ctx context.Context) ([]Sample, error) {
+			counts, err := counter.CountOpenReviewsByTeam(ctx)
+			if err != nil {
+				return nil, err
+			}
+
+			var samples []Sample
+
+			for org, teams := range counts {
+				for team, n := range teams {
+					samples = append(samples, Sample{Labels: []string{string(org), string(team)}, Value: float64(n)})
+				}
+			}
+
+			sort.Slice(samples, func(i, j int) bool {
+				if samples[i].Labels[0] != samples[j].Labels[0] {
+					return samples[i].Labels[0] < samples[j].Labels[0]
+				}
+
+				return samples[i].Labels[1] < samples[j].Labels[1]
+			})
+
+			return samples, nil
+		})
+}
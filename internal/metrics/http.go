@@ -0,0 +1,121 @@
+// Package metrics собирает метрики сервиса и отдаёт их в текстовом формате Prometheus.
+package metrics
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// maxErrorBodySize — сколько байт тела ошибки читается, чтобы узнать код ошибки.
+const maxErrorBodySize = 4 << 10
+
+// otherRoute — метка маршрута для путей, которых нет в спецификации, чтобы число серий было ограничено.
+const otherRoute = "other"
+
+// RouteFunc возвращает шаблон пути запроса, например /v2/teams/{name}, или false, если путь неизвестен.
+type RouteFunc func(method, path string) (string, bool)
+
+// HTTP собирает метрики HTTP-запросов.
+type HTTP struct {
+	requests *Counter
+	duration *Histogram
+	route    RouteFunc
+}
+
+// NewHTTP регистрирует метрики HTTP-запросов: http_requests_total с методом, маршрутом, статусом
+// и кодом ошибки и http_request_duration_seconds с методом и маршрутом. route переводит путь в шаблон;
+// nil — все запросы учитываются с маршрутом other.
+func NewHTTP(reg *Registry, route RouteFunc) *HTTP {
+	return &HTTP{
+		requests: reg.NewCounter("http_requests_total",
+			"HTTP requests by method, route, status and error code.", "method", "route", "status", "code"),
+		duration: reg.NewHistogram("http_request_duration_seconds",
+			"HTTP request latency in seconds by method and route.", DefaultBuckets, "method", "route"),
+		route: route,
+	}
+}
+
+// Wrap учитывает каждый запрос после ответа. Код ошибки берётся из тела ответа 4xx и 5xx
+// (error.code в ErrorResponse или code в Problem); для успешных ответов он пустой.
+func (m *HTTP) Wrap(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		rw := &responseWriter{ResponseWriter: w, status: http.StatusOK}
+
+		// Запрос учитывается и при прерывании ответа паникой http.ErrAbortHandler.
+		defer func() {
+			route := otherRoute
+			if m.route != nil {
+				if tmpl, ok := m.route(r.Method, r.URL.Path); ok {
+					route = tmpl
+				}
+			}
+
+			m.requests.Inc(r.Method, route, strconv.Itoa(rw.status), rw.errorCode())
+			m.duration.Observe(time.Since(start).Seconds(), r.Method, route)
+		}()
+
+		next.ServeHTTP(rw, r)
+	})
+}
+
+// responseWriter запоминает статус ответа и начало тела ошибки.
+type responseWriter struct {
+	http.ResponseWriter
+	status      int
+	wroteHeader bool
+	errorBody   bytes.Buffer
+}
+
+func (w *responseWriter) WriteHeader(status int) {
+	if !w.wroteHeader {
+		w.status = status
+		w.wroteHeader = true
+	}
+
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *responseWriter) Write(p []byte) (int, error) {
+	if !w.wroteHeader {
+		w.WriteHeader(http.StatusOK)
+	}
+
+	if w.status >= http.StatusBadRequest && w.errorBody.Len() < maxErrorBodySize {
+		w.errorBody.Write(p[:min(len(p), maxErrorBodySize-w.errorBody.Len())])
+	}
+
+	return w.ResponseWriter.Write(p)
+}
+
+// Unwrap даёт http.ResponseController доступ к Flush и дедлайнам исходного ResponseWriter.
+func (w *responseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// errorCode возвращает код ошибки из тела ответа или пустую строку.
+func (w *responseWriter) errorCode() string {
+	if w.status < http.StatusBadRequest {
+		return ""
+	}
+
+	var body struct {
+		Code  string `json:"code"`
+		Error struct {
+			Code string `json:"code"`
+		} `json:"error"`
+	}
+
+	if err := json.Unmarshal(w.errorBody.Bytes(), &body); err != nil {
+		return ""
+	}
+
+	if body.Error.Code != "" {
+		return body.Error.Code
+	}
+
+	return body.Code
+}
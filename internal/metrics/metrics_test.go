@@ -0,0 +1,109 @@
+package metrics
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/dixitix/pr-reviewer-service/internal/domain"
+	"github.com/dixitix/pr-reviewer-service/internal/http/httperr"
+)
+
+func scrape(t *testing.T, reg *Registry) string {
+	t.Helper()
+
+	rec := httptest.NewRecorder()
+	reg.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+	if rec.Code != http.StatusOK || rec.Header().Get("Content-Type") != ContentType {
+		t.Fatalf("unexpected scrape response: %d %s", rec.Code, rec.Header().Get("Content-Type"))
+	}
+
+	return rec.Body.String()
+}
+
+func assertLines(t *testing.T, body string, lines ...string) {
+	t.Helper()
+
+	for _, line := range lines {
+		if !strings.Contains(body, line+"\n") {
+			t.Errorf("expected line %q in:\n%s", line, body)
+		}
+	}
+}
+
+type openReviews map[domain.OrganizationID]map[domain.TeamName]int
+
+func (o openReviews) CountOpenReviewsByTeam(context.Context) (map[domain.OrganizationID]map[domain.TeamName]int, error) {
+	return o, nil
+}
+
+// TestRegistry_Exposition проверяет текстовый формат счётчиков, гистограмм и вычисляемых показателей.
+func TestRegistry_Exposition(t *testing.T) {
+	reg := NewRegistry(nil)
+
+	d := NewDomain(reg)
+	d.PullRequestCreated()
+	d.PullRequestCreated()
+	d.NoCandidate()
+
+	h := reg.NewHistogram("test_seconds", "Test latency.", []float64{0.1, 1}, "route")
+	h.Observe(0.05, `/a"b`)
+	h.Observe(0.5, `/a"b`)
+
+	RegisterOpenReviews(reg, openReviews{"acme": {"backend": 3}})
+
+	body := scrape(t, reg)
+
+	assertLines(t, body,
+		"# HELP pr_reviewer_pull_requests_created_total Pull requests created.",
+		"# TYPE pr_reviewer_pull_requests_created_total counter",
+		"pr_reviewer_pull_requests_created_total 2",
+		"pr_reviewer_pull_requests_merged_total 0",
+		"pr_reviewer_no_candidate_total 1",
+		"# TYPE test_seconds histogram",
+		`test_seconds_bucket{route="/a\"b",le="0.1"} 1`,
+		`test_seconds_bucket{route="/a\"b",le="1"} 2`,
+		`test_seconds_bucket{route="/a\"b",le="+Inf"} 2`,
+		`test_seconds_sum{route="/a\"b"} 0.55`,
+		`test_seconds_count{route="/a\"b"} 2`,
+		"# TYPE pr_reviewer_open_reviews gauge",
+		`pr_reviewer_open_reviews{organization="acme",team="backend"} 3`,
+	)
+}
+
+// TestHTTP_Wrap проверяет метки маршрута, статуса и кода ошибки.
+func TestHTTP_Wrap(t *testing.T) {
+	reg := NewRegistry(nil)
+
+	route := func(_, path string) (string, bool) {
+		if strings.HasPrefix(path, "/v2/teams/") {
+			return "/v2/teams/{name}", true
+		}
+
+		return "", false
+	}
+
+	h := NewHTTP(reg, route).Wrap(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/v2/teams/missing" {
+			httperr.WriteJSONError(w, http.StatusNotFound, httperr.ErrorCodeNotFound, "team not found", nil)
+			return
+		}
+
+		_, _ = io.WriteString(w, "ok")
+	}))
+
+	for _, path := range []string{"/v2/teams/backend", "/v2/teams/missing", "/unknown"} {
+		h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, path, nil))
+	}
+
+	assertLines(t, scrape(t, reg),
+		`http_requests_total{method="GET",route="/v2/teams/{name}",status="200",code=""} 1`,
+		`http_requests_total{method="GET",route="/v2/teams/{name}",status="404",code="NOT_FOUND"} 1`,
+		`http_requests_total{method="GET",route="other",status="200",code=""} 1`,
+		`http_request_duration_seconds_count{method="GET",route="/v2/teams/{name}"} 2`,
+	)
+}
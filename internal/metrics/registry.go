@@ -0,0 +1,350 @@
+// Package metrics собирает метрики сервиса и отдаёт их в текстовом формате Prometheus
+// (text exposition format 0.0.4).
+package metrics
+
+import (
+	"bufio"
+	"context"
+	"log/slog"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// ContentType — тип ответа /metrics.
+const ContentType = "text/plain; version=0.0.4; charset=utf-8"
+
+// Типы метрик.
+const (
+	typeCounter   = "counter"
+	typeGauge     = "gauge"
+	typeHistogram = "histogram"
+)
+
+// DefaultBuckets — границы гистограммы времени обработки запросов в секундах.
+var DefaultBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+// Sample — значение метрики с метками в порядке, заданном при регистрации.
+type Sample struct {
+	Labels []string
+	Value  float64
+}
+
+// family — метрика с одним именем и набором меток.
+type family interface {
+	name() string
+	write(ctx context.Context, w *bufio.Writer) error
+}
+
+// Registry хранит зарегистрированные метрики.
+type Registry struct {
+	mu       sync.Mutex
+	families []family
+	logger   *slog.Logger
+}
+
+// NewRegistry создаёт пустой реестр.
+func NewRegistry(logger *slog.Logger) *Registry {
+	return &Registry{logger: logger}
+}
+
+// NewCounter регистрирует счётчик с метками labels.
+func (r *Registry) NewCounter(name, help string, labels ...string) *Counter {
+	c := &Counter{vec: newVec(name, help, labels)}
+	r.register(c)
+
+	return c
+}
+
+// NewHistogram регистрирует гистограмму с границами buckets и метками labels.
+func (r *Registry) NewHistogram(name, help string, buckets []float64, labels ...string) *Histogram {
+	h := &Histogram{vec: newVec(name, help, labels), buckets: buckets}
+	r.register(h)
+
+	return h
+}
+
+// NewGaugeFunc регистрирует показатель, значения которого вычисляет collect при каждом запросе /metrics.
+func (r *Registry) NewGaugeFunc(name, help string, labels []string, collect func(ctx context.Context) ([]Sample, error)) {
+	r.register(&funcFamily{vec: newVec(name, help, labels), typ: typeGauge, collect: collect})
+}
+
+// NewCounterFunc регистрирует счётчик, значения которого вычисляет collect при каждом запросе /metrics.
+func (r *Registry) NewCounterFunc(name, help string, labels []string, collect func(ctx context.Context) ([]Sample, error)) {
+	r.register(&funcFamily{vec: newVec(name, help, labels), typ: typeCounter, collect: collect})
+}
+
+func (r *Registry) register(f family) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.families = append(r.families, f)
+	sort.Slice(r.families, func(i, j int) bool { return r.families[i].name() < r.families[j].name() })
+}
+
+// Handler отдаёт все метрики. Метрика, значения которой не удалось получить, пропускается,
+// ошибка логируется.
+func (r *Registry) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.Method != http.MethodGet && req.Method != http.MethodHead {
+			w.Header().Set("Allow", "GET, HEAD")
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+
+			return
+		}
+
+		r.mu.Lock()
+		families := append([]family(nil), r.families...)
+		r.mu.Unlock()
+
+		w.Header().Set("Content-Type", ContentType)
+
+		bw := bufio.NewWriter(w)
+
+		for _, f := range families {
+			if err := f.write(req.Context(), bw); err != nil && r.logger != nil {
+				r.logger.ErrorContext(req.Context(), "metrics: collect error", slog.String("metric", f.name()), slog.Any("error", err))
+			}
+		}
+
+		if err := bw.Flush(); err != nil && r.logger != nil {
+			r.logger.ErrorContext(req.Context(), "metrics: failed to write response", slog.Any("error", err))
+		}
+	})
+}
+
+// vec — общая часть метрик: имя, описание и метки.
+type vec struct {
+	metricName string
+	help       string
+	labels     []string
+}
+
+func newVec(name, help string, labels []string) vec {
+	return vec{metricName: name, help: help, labels: labels}
+}
+
+func (v *vec) name() string {
+	return v.metricName
+}
+
+func (v *vec) writeHeader(w *bufio.Writer, typ string) {
+	w.WriteString("# HELP " + v.metricName + " " + escapeHelp(v.help) + "\n")
+	w.WriteString("# TYPE " + v.metricName + " " + typ + "\n")
+}
+
+// writeSample пишет строку значения; extra — дополнительная метка (le у гистограммы).
+func (v *vec) writeSample(w *bufio.Writer, suffix string, values []string, extraName, extraValue string, value float64) {
+	w.WriteString(v.metricName + suffix)
+
+	if len(v.labels) > 0 || extraName != "" {
+		w.WriteByte('{')
+
+		for i, label := range v.labels {
+			if i > 0 {
+				w.WriteByte(',')
+			}
+
+			w.WriteString(label + `="` + escapeLabel(values[i]) + `"`)
+		}
+
+		if extraName != "" {
+			if len(v.labels) > 0 {
+				w.WriteByte(',')
+			}
+
+			w.WriteString(extraName + `="` + extraValue + `"`)
+		}
+
+		w.WriteByte('}')
+	}
+
+	w.WriteString(" " + formatValue(value) + "\n")
+}
+
+// key склеивает значения меток в ключ серии.
+func key(values []string) string {
+	return strings.Join(values, "\xff")
+}
+
+// Counter — счётчик, который только растёт.
+type Counter struct {
+	vec
+
+	mu     sync.Mutex
+	series map[string]*counterSeries
+}
+
+type counterSeries struct {
+	values []string
+	value  float64
+}
+
+// Inc увеличивает счётчик серии с метками values на 1.
+func (c *Counter) Inc(values ...string) {
+	c.Add(1, values...)
+}
+
+// Add увеличивает счётчик серии с метками values на delta.
+func (c *Counter) Add(delta float64, values ...string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.series == nil {
+		c.series = make(map[string]*counterSeries)
+	}
+
+	k := key(values)
+
+	s, ok := c.series[k]
+	if !ok {
+		s = &counterSeries{values: append([]string(nil), values...)}
+		c.series[k] = s
+	}
+
+	s.value += delta
+}
+
+func (c *Counter) write(_ context.Context, w *bufio.Writer) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.writeHeader(w, typeCounter)
+
+	// Счётчик без меток показывается и до первого увеличения.
+	if len(c.labels) == 0 && len(c.series) == 0 {
+		c.writeSample(w, "", nil, "", "", 0)
+		return nil
+	}
+
+	for _, k := range sortedKeys(c.series) {
+		s := c.series[k]
+		c.writeSample(w, "", s.values, "", "", s.value)
+	}
+
+	return nil
+}
+
+// Histogram — распределение значений по корзинам.
+type Histogram struct {
+	vec
+	buckets []float64
+
+	mu     sync.Mutex
+	series map[string]*histogramSeries
+}
+
+type histogramSeries struct {
+	values []string
+	counts []uint64
+	count  uint64
+	sum    float64
+}
+
+// Observe добавляет значение в серию с метками values.
+func (h *Histogram) Observe(value float64, values ...string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.series == nil {
+		h.series = make(map[string]*histogramSeries)
+	}
+
+	k := key(values)
+
+	s, ok := h.series[k]
+	if !ok {
+		s = &histogramSeries{values: append([]string(nil), values...), counts: make([]uint64, len(h.buckets))}
+		h.series[k] = s
+	}
+
+	for i, upper := range h.buckets {
+		if value <= upper {
+			s.counts[i]++
+		}
+	}
+
+	s.count++
+	s.sum += value
+}
+
+func (h *Histogram) write(_ context.Context, w *bufio.Writer) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.writeHeader(w, typeHistogram)
+
+	for _, k := range sortedKeys(h.series) {
+		s := h.series[k]
+
+		for i, upper := range h.buckets {
+			h.writeSample(w, "_bucket", s.values, "le", formatValue(upper), float64(s.counts[i]))
+		}
+
+		h.writeSample(w, "_bucket", s.values, "le", "+Inf", float64(s.count))
+		h.writeSample(w, "_sum", s.values, "", "", s.sum)
+		h.writeSample(w, "_count", s.values, "", "", float64(s.count))
+	}
+
+	return nil
+}
+
+// funcFamily — метрика, значения которой вычисляются при запросе /metrics.
+type funcFamily struct {
+	vec
+	typ     string
+	collect func(ctx context.Context) ([]Sample, error)
+}
+
+func (f *funcFamily) write(ctx context.Context, w *bufio.Writer) error {
+	samples, err := f.collect(ctx)
+	if err != nil {
+		return err
+	}
+
+	f.writeHeader(w, f.typ)
+
+	for _, s := range samples {
+		f.writeSample(w, "", s.Labels, "", "", s.Value)
+	}
+
+	return nil
+}
+
+func sortedKeys[T any](m map[string]T) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+
+	sort.Strings(keys)
+
+	return keys
+}
+
+func formatValue(v float64) string {
+	switch {
+	case math.IsInf(v, 1):
+		return "+Inf"
+	case math.IsInf(v, -1):
+		return "-Inf"
+	default:
+		return strconv.FormatFloat(v, 'g', -1, 64)
+	}
+}
+
+var (
+	helpEscaper  = strings.NewReplacer(`\`, `\\`, "\n", `\n`)
+	labelEscaper = strings.NewReplacer(`\`, `\\`, "\n", `\n`, `"`, `\"`)
+)
+
+func escapeHelp(s string) string {
+	return helpEscaper.Replace(s)
+}
+
+func escapeLabel(s string) string {
+	return labelEscaper.Replace(s)
+}
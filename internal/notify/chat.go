@@ -0,0 +1,76 @@
+// Package notify содержит уведомления ревьюверов о назначениях в каналах,
+// за которыми они следят (чаты, почта).
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+// ChatMessage описывает сообщение для Slack-совместимого входящего вебхука.
+type ChatMessage struct {
+	// Channel переопределяет канал вебхука; пустое значение — канал по умолчанию.
+	Channel string `json:"channel,omitempty"`
+	Text    string `json:"text"`
+}
+
+// ChatClient описывает отправку сообщений в чат.
+type ChatClient interface {
+	// PostMessage отправляет сообщение во входящий вебхук по адресу webhookURL.
+	PostMessage(ctx context.Context, webhookURL string, msg ChatMessage) error
+}
+
+// SlackClient отправляет сообщения во входящие вебхуки Slack и совместимых мессенджеров
+// (Mattermost, Rocket.Chat).
+type SlackClient struct {
+	httpClient *http.Client
+}
+
+// NewSlackClient создаёт клиента входящих вебхуков. Если httpClient == nil, создаётся клиент с таймаутом timeout.
+func NewSlackClient(httpClient *http.Client, timeout time.Duration) *SlackClient {
+	if httpClient == nil {
+		if timeout <= 0 {
+			timeout = 5 * time.Second
+		}
+
+		httpClient = &http.Client{Timeout: timeout}
+	}
+
+	return &SlackClient{httpClient: httpClient}
+}
+
+// PostMessage отправляет сообщение POST-запросом с JSON-телом.
+func (c *SlackClient) PostMessage(ctx context.Context, webhookURL string, msg ChatMessage) error {
+	body, err := json.Marshal(msg)
+	if err != nil {
+		return fmt.Errorf("marshal chat message: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhookURL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("build chat request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("post chat message: %w", err)
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("chat webhook responded %d: %s", resp.StatusCode, bytes.TrimSpace(respBody))
+	}
+
+	return nil
+}
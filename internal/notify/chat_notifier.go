@@ -0,0 +1,275 @@
+// Package notify содержит уведомления ревьюверов о назначениях в каналах,
+// за которыми они следят (чаты, почта).
+package notify
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/dixitix/pr-reviewer-service/internal/domain"
+	"github.com/dixitix/pr-reviewer-service/internal/repository"
+)
+
+// ChatNotifier сообщает в чат команды ревьювера о назначении, переназначении,
+// напоминании о затянувшемся ревью и его эскалации, а в чат команды автора — о принудительном merge.
+// Реализует outbox.Sink. Ревьюверы, отключившие уведомления в чат, пропускаются
+// (кроме эскалаций — они адресованы команде, а не ревьюверу).
+// Канал выбирается по команде ревьювера; если он не настроен, используется канал по умолчанию.
+type ChatNotifier struct {
+	users      repository.UserRepository
+	prefs      repository.NotificationRepository
+	client     ChatClient
+	defaultURL string
+	logger     *slog.Logger
+}
+
+// NewChatNotifier создаёт уведомитель. Пустой defaultURL означает, что команды
+// без настроенного канала уведомления не получают.
+func NewChatNotifier(
+	users repository.UserRepository,
+	prefs repository.NotificationRepository,
+	client ChatClient,
+	defaultURL string,
+	logger *slog.Logger,
+) *ChatNotifier {
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	return &ChatNotifier{
+		users:      users,
+		prefs:      prefs,
+		client:     client,
+		defaultURL: defaultURL,
+		logger:     logger,
+	}
+}
+
+// Name возвращает имя получателя.
+func (n *ChatNotifier) Name() string {
+	return "chat"
+}
+
+// chatTarget описывает канал, в который отправляется сообщение.
+type chatTarget struct {
+	url     string
+	channel string
+}
+
+// Deliver отправляет сообщение в канал команды в зависимости от типа события.
+func (n *ChatNotifier) Deliver(ctx context.Context, e domain.Event) error {
+	switch e.Type {
+	case domain.EventReviewReminder, domain.EventReviewEscalated:
+		return n.deliverSLA(ctx, e)
+	case domain.EventPullRequestMerged:
+		return n.deliverForcedMerge(ctx, e)
+	default:
+		return n.deliverAssignment(ctx, e)
+	}
+}
+
+// deliverAssignment отправляет по одному сообщению в канал каждой команды, в которой есть назначенные ревьюверы.
+func (n *ChatNotifier) deliverAssignment(ctx context.Context, e domain.Event) error {
+	reason, ok := assignmentReason(ctx, n.users, e)
+	if !ok {
+		return nil
+	}
+
+	targets := make(map[chatTarget][]string)
+	order := make([]chatTarget, 0)
+
+	for _, id := range e.AddedReviewers {
+		reviewer, target, ok, err := n.resolve(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		if !ok {
+			continue
+		}
+
+		if _, seen := targets[target]; !seen {
+			order = append(order, target)
+		}
+
+		targets[target] = append(targets[target], reviewer.Username)
+	}
+
+	author := usernames(ctx, n.users, []domain.UserID{e.PullRequest.AuthorID})[0]
+
+	var errs error
+
+	for _, target := range order {
+		msg := ChatMessage{
+			Channel: target.channel,
+			Text:    formatChatText(e.PullRequest, author, targets[target], reason),
+		}
+
+		if err := n.client.PostMessage(ctx, target.url, msg); err != nil {
+			errs = errors.Join(errs, err)
+		}
+	}
+
+	return errs
+}
+
+// deliverSLA сообщает в канал команды ревьювера о напоминании или эскалации.
+func (n *ChatNotifier) deliverSLA(ctx context.Context, e domain.Event) error {
+	reviewer, err := n.users.GetByID(ctx, e.Reviewer)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil
+		}
+
+		return fmt.Errorf("get reviewer %s: %w", e.Reviewer, err)
+	}
+
+	if e.Type == domain.EventReviewReminder {
+		prefs, err := n.prefs.GetPreferences(ctx, e.Reviewer)
+		if err != nil {
+			return fmt.Errorf("get notification preferences for %s: %w", e.Reviewer, err)
+		}
+
+		if !prefs.ChatEnabled {
+			return nil
+		}
+	}
+
+	target, ok, err := n.teamTarget(ctx, reviewer.TeamName)
+	if err != nil || !ok {
+		return err
+	}
+
+	author := usernames(ctx, n.users, []domain.UserID{e.PullRequest.AuthorID})[0]
+
+	msg := ChatMessage{
+		Channel: target.channel,
+		Text:    formatSLAText(e, author, reviewer.Username, usernames(ctx, n.users, e.Leads)),
+	}
+
+	return n.client.PostMessage(ctx, target.url, msg)
+}
+
+// deliverForcedMerge сообщает в канал команды автора о принудительном merge.
+// Обычный merge (без адресатов-лидов) в чат не сообщается.
+func (n *ChatNotifier) deliverForcedMerge(ctx context.Context, e domain.Event) error {
+	if len(e.Leads) == 0 {
+		return nil
+	}
+
+	author, err := n.users.GetByID(ctx, e.PullRequest.AuthorID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil
+		}
+
+		return fmt.Errorf("get author %s: %w", e.PullRequest.AuthorID, err)
+	}
+
+	target, ok, err := n.teamTarget(ctx, author.TeamName)
+	if err != nil || !ok {
+		return err
+	}
+
+	msg := ChatMessage{
+		Channel: target.channel,
+		Text: fmt.Sprintf(
+			"Forced merge: *%s* (`%s`) by %s\nWhy: %s\ncc: %s",
+			e.PullRequest.Name,
+			e.PullRequest.ID,
+			author.Username,
+			e.Reason,
+			strings.Join(usernames(ctx, n.users, e.Leads), ", "),
+		),
+	}
+
+	return n.client.PostMessage(ctx, target.url, msg)
+}
+
+// resolve возвращает ревьювера и канал его команды. ok == false, если уведомлять не нужно.
+func (n *ChatNotifier) resolve(
+	ctx context.Context,
+	id domain.UserID,
+) (reviewer domain.User, target chatTarget, ok bool, err error) {
+	reviewer, err = n.users.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return domain.User{}, chatTarget{}, false, nil
+		}
+
+		return domain.User{}, chatTarget{}, false, fmt.Errorf("get reviewer %s: %w", id, err)
+	}
+
+	prefs, err := n.prefs.GetPreferences(ctx, id)
+	if err != nil {
+		return domain.User{}, chatTarget{}, false, fmt.Errorf("get notification preferences for %s: %w", id, err)
+	}
+
+	if !prefs.ChatEnabled {
+		return domain.User{}, chatTarget{}, false, nil
+	}
+
+	target, ok, err = n.teamTarget(ctx, reviewer.TeamName)
+	if err != nil || !ok {
+		return domain.User{}, chatTarget{}, false, err
+	}
+
+	return reviewer, target, true, nil
+}
+
+// teamTarget возвращает канал команды или канал по умолчанию. ok == false, если канала нет.
+func (n *ChatNotifier) teamTarget(ctx context.Context, team domain.TeamName) (target chatTarget, ok bool, err error) {
+	channel, err := n.prefs.GetTeamChatChannel(ctx, team)
+
+	switch {
+	case err == nil:
+		return chatTarget{url: channel.WebhookURL, channel: channel.Channel}, true, nil
+	case !errors.Is(err, repository.ErrNotFound):
+		return chatTarget{}, false, fmt.Errorf("get chat channel of team %s: %w", team, err)
+	case n.defaultURL == "":
+		n.logger.Debug("no chat channel for team", slog.String("team_name", string(team)))
+		return chatTarget{}, false, nil
+	default:
+		return chatTarget{url: n.defaultURL}, true, nil
+	}
+}
+
+// formatChatText формирует текст сообщения в разметке Slack.
+func formatChatText(pr domain.PullRequest, author string, reviewers []string, reason string) string {
+	return fmt.Sprintf(
+		"Review requested from %s: *%s* (`%s`) by %s\nWhy: %s",
+		strings.Join(reviewers, ", "),
+		pr.Name,
+		pr.ID,
+		author,
+		reason,
+	)
+}
+
+// formatSLAText формирует текст напоминания или эскалации в разметке Slack.
+// Лиды команды, если они есть, упоминаются отдельной строкой.
+func formatSLAText(e domain.Event, author, reviewer string, leads []string) string {
+	prefix := "Reminder"
+	if e.Type == domain.EventReviewEscalated {
+		prefix = "Escalation"
+	}
+
+	text := fmt.Sprintf(
+		"%s: %s, review of *%s* (`%s`) by %s is still pending\nWhy: %s",
+		prefix,
+		reviewer,
+		e.PullRequest.Name,
+		e.PullRequest.ID,
+		author,
+		e.Reason,
+	)
+
+	if len(leads) > 0 {
+		text += "\ncc: " + strings.Join(leads, ", ")
+	}
+
+	return text
+}
@@ -0,0 +1,200 @@
+package notify
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/dixitix/pr-reviewer-service/internal/domain"
+)
+
+// newTestChatNotifier создаёт уведомитель поверх FakeChatServer с командами backend и frontend.
+func newTestChatNotifier(t *testing.T, defaultPath string) (*ChatNotifier, *FakeChatServer, *memoryPrefs) {
+	t.Helper()
+
+	server := NewFakeChatServer()
+	t.Cleanup(server.Close)
+
+	users := newTestUsers()
+	prefs := newMemoryPrefs()
+	prefs.channels["backend"] = domain.TeamChatChannel{
+		TeamName:   "backend",
+		WebhookURL: server.URL + "/backend",
+		Channel:    "#backend-reviews",
+	}
+
+	defaultURL := ""
+	if defaultPath != "" {
+		defaultURL = server.URL + defaultPath
+	}
+
+	n := NewChatNotifier(users, prefs, NewSlackClient(nil, 0), defaultURL, slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	return n, server, prefs
+}
+
+// TestChatNotifier_Assigned проверяет сообщение о назначении в канал команды и учёт отказа от уведомлений.
+func TestChatNotifier_Assigned(t *testing.T) {
+	n, server, prefs := newTestChatNotifier(t, "")
+	prefs.prefs["u3"] = domain.NotificationPreferences{UserID: "u3", ChatEnabled: false}
+
+	err := n.Deliver(context.Background(), domain.Event{
+		Type:           domain.EventReviewersAssigned,
+		PullRequest:    domain.PullRequest{ID: "pr-1", Name: "Add search", AuthorID: "u1"},
+		AddedReviewers: []domain.UserID{"u2", "u3", "u4"},
+	})
+	if err != nil {
+		t.Fatalf("Deliver returned error: %v", err)
+	}
+
+	msgs := server.Messages()
+	if len(msgs) != 1 {
+		t.Fatalf("expected 1 message (frontend has no channel), got %d: %+v", len(msgs), msgs)
+	}
+
+	msg := msgs[0]
+	if msg.Path != "/backend" || msg.Channel != "#backend-reviews" {
+		t.Fatalf("unexpected target: %+v", msg)
+	}
+
+	for _, want := range []string{"bob", "Add search", "pr-1", "by alice", "opened"} {
+		if !strings.Contains(msg.Text, want) {
+			t.Fatalf("message %q does not contain %q", msg.Text, want)
+		}
+	}
+
+	if strings.Contains(msg.Text, "carol") {
+		t.Fatalf("opted-out reviewer mentioned: %q", msg.Text)
+	}
+}
+
+// TestChatNotifier_Reassigned проверяет сообщение о переназначении и канал по умолчанию.
+func TestChatNotifier_Reassigned(t *testing.T) {
+	n, server, _ := newTestChatNotifier(t, "/default")
+
+	err := n.Deliver(context.Background(), domain.Event{
+		Type:             domain.EventReviewerReassigned,
+		PullRequest:      domain.PullRequest{ID: "pr-2", Name: "Fix login", AuthorID: "u1"},
+		AddedReviewers:   []domain.UserID{"u4"},
+		RemovedReviewers: []domain.UserID{"u2"},
+	})
+	if err != nil {
+		t.Fatalf("Deliver returned error: %v", err)
+	}
+
+	msgs := server.Messages()
+	if len(msgs) != 1 || msgs[0].Path != "/default" || msgs[0].Channel != "" {
+		t.Fatalf("expected one message to default channel, got %+v", msgs)
+	}
+
+	if !strings.Contains(msgs[0].Text, "dave") || !strings.Contains(msgs[0].Text, "reassigned from bob") {
+		t.Fatalf("unexpected text: %q", msgs[0].Text)
+	}
+}
+
+// TestChatNotifier_Errors проверяет, что ошибка чата возвращается ретранслятору, а прочие события игнорируются.
+func TestChatNotifier_Errors(t *testing.T) {
+	n, server, _ := newTestChatNotifier(t, "")
+	server.FailNext(1)
+
+	e := domain.Event{
+		Type:           domain.EventReviewersAssigned,
+		PullRequest:    domain.PullRequest{ID: "pr-3", Name: "Refactor", AuthorID: "u1"},
+		AddedReviewers: []domain.UserID{"u2"},
+	}
+
+	if err := n.Deliver(context.Background(), e); err == nil {
+		t.Fatalf("expected error when chat is unavailable")
+	}
+
+	if err := n.Deliver(context.Background(), e); err != nil {
+		t.Fatalf("Deliver returned error on retry: %v", err)
+	}
+
+	merged := domain.Event{Type: domain.EventPullRequestMerged, PullRequest: e.PullRequest}
+	if err := n.Deliver(context.Background(), merged); err != nil {
+		t.Fatalf("Deliver returned error for merge event: %v", err)
+	}
+
+	if got := len(server.Messages()); got != 1 {
+		t.Fatalf("expected 1 delivered message, got %d", got)
+	}
+}
+
+// TestChatNotifier_SLA проверяет напоминание с учётом отказа от уведомлений и эскалацию в канал команды.
+func TestChatNotifier_SLA(t *testing.T) {
+	n, server, prefs := newTestChatNotifier(t, "")
+	prefs.prefs["u3"] = domain.NotificationPreferences{UserID: "u3", ChatEnabled: false}
+
+	pr := domain.PullRequest{ID: "pr-3", Name: "Tune cache", AuthorID: "u1"}
+
+	err := n.Deliver(context.Background(), domain.Event{
+		Type:        domain.EventReviewReminder,
+		PullRequest: pr,
+		Reviewer:    "u3",
+		Reason:      "review pending for 24h0m0s",
+	})
+	if err != nil {
+		t.Fatalf("Deliver reminder returned error: %v", err)
+	}
+
+	if msgs := server.Messages(); len(msgs) != 0 {
+		t.Fatalf("expected no reminder for opted-out reviewer, got %+v", msgs)
+	}
+
+	err = n.Deliver(context.Background(), domain.Event{
+		Type:        domain.EventReviewEscalated,
+		PullRequest: pr,
+		Reviewer:    "u3",
+		Reason:      "review pending for 72h0m0s, team SLA is 48h0m0s",
+	})
+	if err != nil {
+		t.Fatalf("Deliver escalation returned error: %v", err)
+	}
+
+	msgs := server.Messages()
+	if len(msgs) != 1 || msgs[0].Path != "/backend" {
+		t.Fatalf("expected 1 escalation in backend channel, got %+v", msgs)
+	}
+
+	for _, want := range []string{"Escalation", "carol", "Tune cache", "by alice", "team SLA is 48h"} {
+		if !strings.Contains(msgs[0].Text, want) {
+			t.Fatalf("message %q does not contain %q", msgs[0].Text, want)
+		}
+	}
+}
+
+// TestChatNotifier_ForcedMerge проверяет сообщение о принудительном merge с упоминанием лидов
+// и отсутствие сообщения об обычном merge.
+func TestChatNotifier_ForcedMerge(t *testing.T) {
+	n, server, _ := newTestChatNotifier(t, "")
+
+	pr := domain.PullRequest{ID: "pr-4", Name: "Hotfix", AuthorID: "u1", Status: domain.PullRequestStatusMerged}
+
+	if err := n.Deliver(context.Background(), domain.Event{Type: domain.EventPullRequestMerged, PullRequest: pr}); err != nil {
+		t.Fatalf("Deliver merge returned error: %v", err)
+	}
+
+	err := n.Deliver(context.Background(), domain.Event{
+		Type:        domain.EventPullRequestMerged,
+		PullRequest: pr,
+		Reason:      "forced merge without completed review",
+		Leads:       []domain.UserID{"u3"},
+	})
+	if err != nil {
+		t.Fatalf("Deliver forced merge returned error: %v", err)
+	}
+
+	msgs := server.Messages()
+	if len(msgs) != 1 || msgs[0].Path != "/backend" {
+		t.Fatalf("expected 1 message in backend channel, got %+v", msgs)
+	}
+
+	for _, want := range []string{"Forced merge", "Hotfix", "by alice", "cc: carol"} {
+		if !strings.Contains(msgs[0].Text, want) {
+			t.Fatalf("message %q does not contain %q", msgs[0].Text, want)
+		}
+	}
+}
@@ -0,0 +1,237 @@
+// Package notify содержит уведомления ревьюверов о назначениях в каналах,
+// за которыми они следят (чаты, почта).
+package notify
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/dixitix/pr-reviewer-service/internal/domain"
+	"github.com/dixitix/pr-reviewer-service/internal/repository"
+	"github.com/dixitix/pr-reviewer-service/internal/tenant"
+)
+
+// DigestConfig описывает расписание ежедневной сводки.
+type DigestConfig struct {
+	// At — время отправки от начала суток, например 9*time.Hour.
+	At time.Duration
+	// Location — часовой пояс расписания; nil означает UTC.
+	Location *time.Location
+	// Organization — организация, шаблоны которой используются.
+	Organization string
+}
+
+// Digest раз в сутки отправляет пользователям с режимом digest сводку открытых ревью.
+// Отправка за день отмечается в хранилище, поэтому перезапуск или несколько экземпляров
+// сервиса не приводят к повторным письмам. Сводки отправляются пользователям всех организаций.
+type Digest struct {
+	orgs      repository.OrganizationRepository
+	users     repository.UserRepository
+	prs       repository.PullRequestRepository
+	prefs     repository.NotificationRepository
+	mailer    Mailer
+	templates *EmailTemplates
+	cfg       DigestConfig
+	logger    *slog.Logger
+}
+
+// NewDigest создаёт отправителя ежедневной сводки.
+func NewDigest(
+	orgs repository.OrganizationRepository,
+	users repository.UserRepository,
+	prs repository.PullRequestRepository,
+	prefs repository.NotificationRepository,
+	mailer Mailer,
+	templates *EmailTemplates,
+	cfg DigestConfig,
+	logger *slog.Logger,
+) *Digest {
+	if cfg.Location == nil {
+		cfg.Location = time.UTC
+	}
+
+	if templates == nil {
+		templates = NewDefaultEmailTemplates()
+	}
+
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	return &Digest{
+		orgs:      orgs,
+		users:     users,
+		prs:       prs,
+		prefs:     prefs,
+		mailer:    mailer,
+		templates: templates,
+		cfg:       cfg,
+		logger:    logger,
+	}
+}
+
+// Run отправляет сводки по расписанию до отмены контекста. Если сервис запущен после
+// времени отправки, сводки за текущий день отправляются сразу.
+func (d *Digest) Run(ctx context.Context) {
+	next := d.todayAt(time.Now())
+	if next.Before(time.Now()) {
+		next = time.Now()
+	}
+
+	timer := time.NewTimer(time.Until(next))
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-timer.C:
+			now := time.Now()
+
+			sent, err := d.SendAll(ctx, now)
+			if err != nil && !errors.Is(err, context.Canceled) {
+				d.logger.Error("send review digests", slog.Any("err", err))
+			}
+
+			d.logger.Info("review digests sent", slog.Int("sent", sent))
+
+			timer.Reset(time.Until(d.todayAt(now).AddDate(0, 0, 1)))
+		}
+	}
+}
+
+// SendAll отправляет сводку за день, содержащий now, всем, кто её ещё не получил,
+// и возвращает число отправленных писем.
+func (d *Digest) SendAll(ctx context.Context, now time.Time) (int, error) {
+	orgs, err := d.orgs.List(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("list organizations: %w", err)
+	}
+
+	var (
+		sent int
+		errs error
+	)
+
+	for _, org := range orgs {
+		n, err := d.sendOrganization(tenant.WithOrganization(ctx, org.ID), now)
+		if err != nil {
+			errs = errors.Join(errs, fmt.Errorf("organization %s: %w", org.ID, err))
+		}
+
+		sent += n
+	}
+
+	return sent, errs
+}
+
+// sendOrganization отправляет сводки пользователям организации из контекста.
+func (d *Digest) sendOrganization(ctx context.Context, now time.Time) (int, error) {
+	subscribers, err := d.prefs.ListPreferencesByEmailMode(ctx, domain.EmailModeDigest)
+	if err != nil {
+		return 0, fmt.Errorf("list digest subscribers: %w", err)
+	}
+
+	day := d.todayAt(now).Add(-d.cfg.At)
+
+	var (
+		sent int
+		errs error
+	)
+
+	for _, prefs := range subscribers {
+		if prefs.Email == "" {
+			continue
+		}
+
+		ok, err := d.send(ctx, prefs, day, now)
+		if err != nil {
+			d.logger.Warn("send review digest", slog.String("user_id", string(prefs.UserID)), slog.Any("err", err))
+			errs = errors.Join(errs, err)
+
+			continue
+		}
+
+		if ok {
+			sent++
+		}
+	}
+
+	return sent, errs
+}
+
+// send отправляет сводку одному пользователю. Возвращает false, если письмо не отправлялось:
+// сводка за этот день уже отправлена или открытых ревью нет.
+func (d *Digest) send(ctx context.Context, prefs domain.NotificationPreferences, day, now time.Time) (bool, error) {
+	user, err := d.users.GetByID(ctx, prefs.UserID)
+	if err != nil {
+		return false, fmt.Errorf("get user %s: %w", prefs.UserID, err)
+	}
+
+	prs, err := d.prs.ListByReviewer(ctx, prefs.UserID)
+	if err != nil {
+		return false, fmt.Errorf("list reviews of %s: %w", prefs.UserID, err)
+	}
+
+	items := make([]DigestItem, 0, len(prs))
+
+	for _, pr := range prs {
+		if pr.Status != domain.PullRequestStatusOpen {
+			continue
+		}
+
+		var age time.Duration
+		if pr.CreatedAt != nil {
+			age = now.Sub(*pr.CreatedAt)
+		}
+
+		items = append(items, DigestItem{
+			PullRequest: pr,
+			Author:      usernames(ctx, d.users, []domain.UserID{pr.AuthorID})[0],
+			Age:         age,
+		})
+	}
+
+	if len(items) == 0 {
+		return false, nil
+	}
+
+	claimed, err := d.prefs.ClaimDigest(ctx, prefs.UserID, day)
+	if err != nil {
+		return false, fmt.Errorf("claim digest of %s: %w", prefs.UserID, err)
+	}
+
+	if !claimed {
+		return false, nil
+	}
+
+	subject, body, err := d.templates.RenderDigest(d.cfg.Organization, DigestMailData{
+		Reviewer: user.Username,
+		Date:     day,
+		Reviews:  items,
+	})
+	if err == nil {
+		err = d.mailer.Send(ctx, Mail{To: prefs.Email, Subject: subject, Body: body})
+	}
+
+	if err != nil {
+		if rerr := d.prefs.ReleaseDigest(ctx, prefs.UserID, day); rerr != nil {
+			err = errors.Join(err, rerr)
+		}
+
+		return false, err
+	}
+
+	return true, nil
+}
+
+// todayAt возвращает время отправки в сутки, содержащие t, в часовом поясе расписания.
+func (d *Digest) todayAt(t time.Time) time.Time {
+	t = t.In(d.cfg.Location)
+	midnight := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, d.cfg.Location)
+
+	return midnight.Add(d.cfg.At)
+}
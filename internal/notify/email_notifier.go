@@ -0,0 +1,152 @@
+// Package notify содержит уведомления ревьюверов о назначениях в каналах,
+// за которыми они следят (чаты, почта).
+package notify
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/dixitix/pr-reviewer-service/internal/domain"
+	"github.com/dixitix/pr-reviewer-service/internal/repository"
+)
+
+// EmailNotifier отправляет письмо о назначении ревьюверам, выбравшим немедленные уведомления,
+// а лидам команды — об эскалации ревью и принудительном merge. Реализует outbox.Sink.
+type EmailNotifier struct {
+	users     repository.UserRepository
+	prefs     repository.NotificationRepository
+	mailer    Mailer
+	templates *EmailTemplates
+	org       string
+	logger    *slog.Logger
+}
+
+// NewEmailNotifier создаёт уведомитель. Письма формируются по шаблонам организации org.
+func NewEmailNotifier(
+	users repository.UserRepository,
+	prefs repository.NotificationRepository,
+	mailer Mailer,
+	templates *EmailTemplates,
+	org string,
+	logger *slog.Logger,
+) *EmailNotifier {
+	if templates == nil {
+		templates = NewDefaultEmailTemplates()
+	}
+
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	return &EmailNotifier{
+		users:     users,
+		prefs:     prefs,
+		mailer:    mailer,
+		templates: templates,
+		org:       org,
+		logger:    logger,
+	}
+}
+
+// Name возвращает имя получателя.
+func (n *EmailNotifier) Name() string {
+	return "email"
+}
+
+// Deliver отправляет письмо каждому назначенному ревьюверу с режимом immediate
+// и каждому лиду, которому адресовано событие.
+func (n *EmailNotifier) Deliver(ctx context.Context, e domain.Event) error {
+	if len(e.Leads) > 0 {
+		return n.deliverToLeads(ctx, e)
+	}
+
+	reason, ok := assignmentReason(ctx, n.users, e)
+	if !ok {
+		return nil
+	}
+
+	author := usernames(ctx, n.users, []domain.UserID{e.PullRequest.AuthorID})[0]
+
+	var errs error
+
+	for _, id := range e.AddedReviewers {
+		reviewer, err := n.users.GetByID(ctx, id)
+		if err != nil {
+			if errors.Is(err, repository.ErrNotFound) {
+				continue
+			}
+
+			return fmt.Errorf("get reviewer %s: %w", id, err)
+		}
+
+		prefs, err := n.prefs.GetPreferences(ctx, id)
+		if err != nil {
+			return fmt.Errorf("get notification preferences for %s: %w", id, err)
+		}
+
+		if prefs.EmailMode != domain.EmailModeImmediate || prefs.Email == "" {
+			continue
+		}
+
+		subject, body, err := n.templates.RenderAssigned(n.org, AssignedMailData{
+			Reviewer:    reviewer.Username,
+			Author:      author,
+			PullRequest: e.PullRequest,
+			Reason:      reason,
+		})
+		if err != nil {
+			return err
+		}
+
+		if err := n.mailer.Send(ctx, Mail{To: prefs.Email, Subject: subject, Body: body}); err != nil {
+			n.logger.Warn("send assignment email", slog.String("user_id", string(id)), slog.Any("err", err))
+			errs = errors.Join(errs, err)
+		}
+	}
+
+	return errs
+}
+
+// deliverToLeads отправляет письмо лидам, указавшим адрес и не отключившим письма.
+// Эскалации важнее сводки, поэтому режим digest здесь означает немедленное письмо.
+func (n *EmailNotifier) deliverToLeads(ctx context.Context, e domain.Event) error {
+	author := usernames(ctx, n.users, []domain.UserID{e.PullRequest.AuthorID})[0]
+
+	var reviewer string
+	if e.Reviewer != "" {
+		reviewer = usernames(ctx, n.users, []domain.UserID{e.Reviewer})[0]
+	}
+
+	var errs error
+
+	for _, id := range e.Leads {
+		prefs, err := n.prefs.GetPreferences(ctx, id)
+		if err != nil {
+			return fmt.Errorf("get notification preferences for %s: %w", id, err)
+		}
+
+		if prefs.EmailMode == domain.EmailModeOff || prefs.Email == "" {
+			continue
+		}
+
+		subject, body, err := n.templates.RenderEscalation(n.org, EscalationMailData{
+			Lead:        usernames(ctx, n.users, []domain.UserID{id})[0],
+			Author:      author,
+			Reviewer:    reviewer,
+			PullRequest: e.PullRequest,
+			Reason:      e.Reason,
+		})
+		if err != nil {
+			return err
+		}
+
+		if err := n.mailer.Send(ctx, Mail{To: prefs.Email, Subject: subject, Body: body}); err != nil {
+			n.logger.Warn("send escalation email", slog.String("user_id", string(id)), slog.Any("err", err))
+			errs = errors.Join(errs, err)
+		}
+	}
+
+	return errs
+}
@@ -0,0 +1,212 @@
+package notify
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dixitix/pr-reviewer-service/internal/domain"
+)
+
+// TestEmailNotifier_Immediate проверяет, что письмо получают только ревьюверы с режимом immediate.
+func TestEmailNotifier_Immediate(t *testing.T) {
+	server, mailer := newTestSMTP(t)
+
+	prefs := newMemoryPrefs()
+	prefs.prefs["u2"] = domain.NotificationPreferences{UserID: "u2", Email: "bob@example.com", EmailMode: domain.EmailModeImmediate}
+	prefs.prefs["u3"] = domain.NotificationPreferences{UserID: "u3", Email: "carol@example.com", EmailMode: domain.EmailModeDigest}
+
+	n := NewEmailNotifier(newTestUsers(), prefs, mailer, nil, DefaultOrganization, slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	err := n.Deliver(context.Background(), domain.Event{
+		Type:           domain.EventReviewersAssigned,
+		PullRequest:    domain.PullRequest{ID: "pr-1", Name: "Add search", AuthorID: "u1"},
+		AddedReviewers: []domain.UserID{"u2", "u3", "u4"},
+	})
+	if err != nil {
+		t.Fatalf("Deliver returned error: %v", err)
+	}
+
+	mails := server.Mails()
+	if len(mails) != 1 {
+		t.Fatalf("expected 1 mail, got %d: %+v", len(mails), mails)
+	}
+
+	mail := mails[0]
+	if mail.From != "reviews@example.com" || len(mail.To) != 1 || mail.To[0] != "bob@example.com" {
+		t.Fatalf("unexpected envelope: %+v", mail)
+	}
+
+	for _, want := range []string{"Subject: Review requested: Add search", "Hi bob", "by alice", "opened"} {
+		if !strings.Contains(mail.Data, want) {
+			t.Fatalf("mail does not contain %q:\n%s", want, mail.Data)
+		}
+	}
+}
+
+// TestEmailNotifier_Escalation проверяет письмо об эскалации лидам с указанным адресом.
+func TestEmailNotifier_Escalation(t *testing.T) {
+	server, mailer := newTestSMTP(t)
+
+	prefs := newMemoryPrefs()
+	prefs.prefs["u3"] = domain.NotificationPreferences{UserID: "u3", Email: "carol@example.com", EmailMode: domain.EmailModeDigest}
+	prefs.prefs["u4"] = domain.NotificationPreferences{UserID: "u4", Email: "dave@example.com", EmailMode: domain.EmailModeOff}
+
+	n := NewEmailNotifier(newTestUsers(), prefs, mailer, nil, DefaultOrganization, slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	err := n.Deliver(context.Background(), domain.Event{
+		Type:        domain.EventReviewEscalated,
+		PullRequest: domain.PullRequest{ID: "pr-1", Name: "Add search", AuthorID: "u1"},
+		Reviewer:    "u2",
+		Reason:      "review pending for 72h0m0s",
+		Leads:       []domain.UserID{"u3", "u4"},
+	})
+	if err != nil {
+		t.Fatalf("Deliver returned error: %v", err)
+	}
+
+	mails := server.Mails()
+	if len(mails) != 1 || mails[0].To[0] != "carol@example.com" {
+		t.Fatalf("expected 1 mail to carol, got %+v", mails)
+	}
+
+	for _, want := range []string{"Subject: Needs your attention: Add search", "Hi carol", "by alice", "Reviewer: bob", "72h"} {
+		if !strings.Contains(mails[0].Data, want) {
+			t.Fatalf("mail does not contain %q:\n%s", want, mails[0].Data)
+		}
+	}
+}
+
+// TestEmailNotifier_SMTPFailure проверяет, что отказ SMTP-сервера возвращается ретранслятору.
+func TestEmailNotifier_SMTPFailure(t *testing.T) {
+	server, mailer := newTestSMTP(t)
+	server.FailNext(1)
+
+	prefs := newMemoryPrefs()
+	prefs.prefs["u2"] = domain.NotificationPreferences{UserID: "u2", Email: "bob@example.com", EmailMode: domain.EmailModeImmediate}
+
+	n := NewEmailNotifier(newTestUsers(), prefs, mailer, nil, DefaultOrganization, slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	e := domain.Event{
+		Type:             domain.EventReviewerReassigned,
+		PullRequest:      domain.PullRequest{ID: "pr-1", Name: "Add search", AuthorID: "u1"},
+		AddedReviewers:   []domain.UserID{"u2"},
+		RemovedReviewers: []domain.UserID{"u3"},
+	}
+
+	if err := n.Deliver(context.Background(), e); err == nil {
+		t.Fatalf("expected error when smtp rejects the mail")
+	}
+
+	if err := n.Deliver(context.Background(), e); err != nil {
+		t.Fatalf("Deliver returned error on retry: %v", err)
+	}
+
+	mails := server.Mails()
+	if len(mails) != 1 || !strings.Contains(mails[0].Data, "reassigned from carol") {
+		t.Fatalf("unexpected mails: %+v", mails)
+	}
+}
+
+// TestDigest_SendAll проверяет содержимое сводки и однократную отправку за день.
+func TestDigest_SendAll(t *testing.T) {
+	server, mailer := newTestSMTP(t)
+
+	now := time.Date(2024, 5, 10, 9, 0, 0, 0, time.UTC)
+	opened := now.Add(-50 * time.Hour)
+	merged := now.Add(-time.Hour)
+
+	prs := memoryPullRequests{prs: []domain.PullRequest{
+		{ID: "pr-1", Name: "Add search", AuthorID: "u1", Status: domain.PullRequestStatusOpen, AssignedReviewers: []domain.UserID{"u2"}, CreatedAt: &opened},
+		{ID: "pr-2", Name: "Fix login", AuthorID: "u1", Status: domain.PullRequestStatusMerged, AssignedReviewers: []domain.UserID{"u2"}, CreatedAt: &opened, MergedAt: &merged},
+	}}
+
+	prefs := newMemoryPrefs()
+	prefs.prefs["u2"] = domain.NotificationPreferences{UserID: "u2", Email: "bob@example.com", EmailMode: domain.EmailModeDigest}
+	prefs.prefs["u3"] = domain.NotificationPreferences{UserID: "u3", Email: "carol@example.com", EmailMode: domain.EmailModeDigest}
+
+	d := NewDigest(memoryOrgs{}, newTestUsers(), prs, prefs, mailer, nil, DigestConfig{At: 9 * time.Hour}, slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	sent, err := d.SendAll(context.Background(), now)
+	if err != nil {
+		t.Fatalf("SendAll returned error: %v", err)
+	}
+
+	if sent != 1 {
+		t.Fatalf("expected 1 digest (carol has no open reviews), got %d", sent)
+	}
+
+	mail := server.Mails()[0]
+	for _, want := range []string{"Subject: 1 open review(s)", "2024-05-10", "Add search", "waiting 2d 2h"} {
+		if !strings.Contains(mail.Data, want) {
+			t.Fatalf("digest does not contain %q:\n%s", want, mail.Data)
+		}
+	}
+
+	if strings.Contains(mail.Data, "Fix login") {
+		t.Fatalf("merged pull request listed in digest:\n%s", mail.Data)
+	}
+
+	sent, err = d.SendAll(context.Background(), now.Add(3*time.Hour))
+	if err != nil {
+		t.Fatalf("SendAll returned error: %v", err)
+	}
+
+	if sent != 0 || len(server.Mails()) != 1 {
+		t.Fatalf("expected digest to be sent once a day, got %d more", sent)
+	}
+}
+
+// TestLoadEmailTemplates проверяет шаблоны организаций и откат к шаблонам по умолчанию.
+func TestLoadEmailTemplates(t *testing.T) {
+	dir := t.TempDir()
+
+	write := func(org, name, src string) {
+		if err := os.MkdirAll(filepath.Join(dir, org), 0o755); err != nil {
+			t.Fatalf("mkdir: %v", err)
+		}
+
+		if err := os.WriteFile(filepath.Join(dir, org, name), []byte(src), 0o600); err != nil {
+			t.Fatalf("write template: %v", err)
+		}
+	}
+
+	write(DefaultOrganization, DigestTemplate, "Subject: Default digest\n{{.Reviewer}}\n")
+	write("acme", AssignedTemplate, "Subject: [ACME] {{.PullRequest.Name}}\n\nPlease review, {{.Reviewer}}.\n")
+
+	templates, err := LoadEmailTemplates(dir)
+	if err != nil {
+		t.Fatalf("LoadEmailTemplates returned error: %v", err)
+	}
+
+	data := AssignedMailData{Reviewer: "bob", Author: "alice", PullRequest: domain.PullRequest{ID: "pr-1", Name: "Add search"}}
+
+	subject, body, err := templates.RenderAssigned("acme", data)
+	if err != nil || subject != "[ACME] Add search" || body != "Please review, bob.\n" {
+		t.Fatalf("unexpected acme mail: %q %q %v", subject, body, err)
+	}
+
+	if subject, _, _ := templates.RenderDigest("acme", DigestMailData{Reviewer: "bob"}); subject != "Default digest" {
+		t.Fatalf("expected acme digest to fall back to default organization, got %q", subject)
+	}
+
+	if subject, _, _ := templates.RenderAssigned("unknown", data); subject != "Review requested: Add search" {
+		t.Fatalf("expected unknown organization to use built-in template, got %q", subject)
+	}
+
+	write("broken", AssignedTemplate, "no subject line\n")
+
+	templates, err = LoadEmailTemplates(dir)
+	if err != nil {
+		t.Fatalf("LoadEmailTemplates returned error: %v", err)
+	}
+
+	if _, _, err := templates.RenderAssigned("broken", data); err == nil {
+		t.Fatalf("expected error for template without subject")
+	}
+}
@@ -0,0 +1,74 @@
+// Package notify содержит уведомления ревьюверов о назначениях в каналах,
+// за которыми они следят (чаты, почта).
+package notify
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+)
+
+// FakeChatMessage описывает сообщение, принятое FakeChatServer.
+type FakeChatMessage struct {
+	// Path — путь вебхука, по которому пришло сообщение; позволяет различать каналы команд.
+	Path string
+	ChatMessage
+}
+
+// FakeChatServer — локальный Slack-совместимый сервер входящих вебхуков для тестов.
+// Принимает сообщения по любому пути и отвечает "ok", как Slack.
+type FakeChatServer struct {
+	*httptest.Server
+
+	mu       sync.Mutex
+	messages []FakeChatMessage
+	failures int
+}
+
+// NewFakeChatServer запускает FakeChatServer. Сервер нужно остановить через Close.
+func NewFakeChatServer() *FakeChatServer {
+	f := &FakeChatServer{}
+	f.Server = httptest.NewServer(http.HandlerFunc(f.handle))
+
+	return f
+}
+
+// FailNext заставляет сервер ответить ошибкой на n следующих сообщений.
+func (f *FakeChatServer) FailNext(n int) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.failures = n
+}
+
+// Messages возвращает копию принятых сообщений.
+func (f *FakeChatServer) Messages() []FakeChatMessage {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return append([]FakeChatMessage(nil), f.messages...)
+}
+
+// handle принимает сообщение входящего вебхука.
+func (f *FakeChatServer) handle(w http.ResponseWriter, r *http.Request) {
+	var msg ChatMessage
+	if r.Method != http.MethodPost || json.NewDecoder(r.Body).Decode(&msg) != nil || msg.Text == "" {
+		http.Error(w, "invalid_payload", http.StatusBadRequest)
+		return
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.failures > 0 {
+		f.failures--
+		http.Error(w, "service_unavailable", http.StatusServiceUnavailable)
+
+		return
+	}
+
+	f.messages = append(f.messages, FakeChatMessage{Path: r.URL.Path, ChatMessage: msg})
+
+	_, _ = w.Write([]byte("ok"))
+}
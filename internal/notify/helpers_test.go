@@ -0,0 +1,151 @@
+package notify
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/dixitix/pr-reviewer-service/internal/domain"
+	"github.com/dixitix/pr-reviewer-service/internal/repository"
+)
+
+// memoryOrgs — реализация repository.OrganizationRepository с единственной организацией по умолчанию.
+type memoryOrgs struct {
+	repository.OrganizationRepository
+}
+
+func (memoryOrgs) List(_ context.Context) ([]domain.Organization, error) {
+	return []domain.Organization{{ID: domain.DefaultOrganizationID}}, nil
+}
+
+// memoryUsers — реализация repository.UserRepository в памяти, достаточная для уведомлений.
+type memoryUsers struct {
+	repository.UserRepository
+	users map[domain.UserID]domain.User
+}
+
+func (m memoryUsers) GetByID(_ context.Context, id domain.UserID) (domain.User, error) {
+	u, ok := m.users[id]
+	if !ok {
+		return domain.User{}, repository.ErrNotFound
+	}
+
+	return u, nil
+}
+
+// newTestUsers возвращает пользователей команд backend и frontend.
+func newTestUsers() memoryUsers {
+	return memoryUsers{users: map[domain.UserID]domain.User{
+		"u1": {ID: "u1", Username: "alice", TeamName: "backend", IsActive: true},
+		"u2": {ID: "u2", Username: "bob", TeamName: "backend", IsActive: true},
+		"u3": {ID: "u3", Username: "carol", TeamName: "backend", IsActive: true},
+		"u4": {ID: "u4", Username: "dave", TeamName: "frontend", IsActive: true},
+	}}
+}
+
+// memoryPrefs — реализация repository.NotificationRepository в памяти.
+type memoryPrefs struct {
+	prefs    map[domain.UserID]domain.NotificationPreferences
+	channels map[domain.TeamName]domain.TeamChatChannel
+	digests  map[string]bool
+}
+
+func newMemoryPrefs() *memoryPrefs {
+	return &memoryPrefs{
+		prefs:    map[domain.UserID]domain.NotificationPreferences{},
+		channels: map[domain.TeamName]domain.TeamChatChannel{},
+		digests:  map[string]bool{},
+	}
+}
+
+func (m *memoryPrefs) GetPreferences(_ context.Context, id domain.UserID) (domain.NotificationPreferences, error) {
+	if p, ok := m.prefs[id]; ok {
+		return p, nil
+	}
+
+	return domain.DefaultNotificationPreferences(id), nil
+}
+
+func (m *memoryPrefs) UpsertPreferences(_ context.Context, p domain.NotificationPreferences) error {
+	m.prefs[p.UserID] = p
+	return nil
+}
+
+func (m *memoryPrefs) ListPreferencesByEmailMode(_ context.Context, mode domain.EmailMode) ([]domain.NotificationPreferences, error) {
+	var result []domain.NotificationPreferences
+	for _, p := range m.prefs {
+		if p.EmailMode == mode {
+			result = append(result, p)
+		}
+	}
+
+	return result, nil
+}
+
+func (m *memoryPrefs) ClaimDigest(_ context.Context, id domain.UserID, day time.Time) (bool, error) {
+	key := string(id) + "/" + day.Format(time.DateOnly)
+	if m.digests[key] {
+		return false, nil
+	}
+
+	m.digests[key] = true
+
+	return true, nil
+}
+
+func (m *memoryPrefs) ReleaseDigest(_ context.Context, id domain.UserID, day time.Time) error {
+	delete(m.digests, string(id)+"/"+day.Format(time.DateOnly))
+	return nil
+}
+
+func (m *memoryPrefs) GetTeamChatChannel(_ context.Context, team domain.TeamName) (domain.TeamChatChannel, error) {
+	c, ok := m.channels[team]
+	if !ok {
+		return domain.TeamChatChannel{}, repository.ErrNotFound
+	}
+
+	return c, nil
+}
+
+func (m *memoryPrefs) UpsertTeamChatChannel(_ context.Context, c domain.TeamChatChannel) error {
+	m.channels[c.TeamName] = c
+	return nil
+}
+
+func (m *memoryPrefs) DeleteTeamChatChannel(_ context.Context, team domain.TeamName) error {
+	delete(m.channels, team)
+	return nil
+}
+
+// memoryPullRequests — реализация repository.PullRequestRepository в памяти, достаточная для сводки.
+type memoryPullRequests struct {
+	repository.PullRequestRepository
+	prs []domain.PullRequest
+}
+
+func (m memoryPullRequests) ListByReviewer(_ context.Context, id domain.UserID) ([]domain.PullRequest, error) {
+	var result []domain.PullRequest
+	for _, pr := range m.prs {
+		if slices.Contains(pr.AssignedReviewers, id) {
+			result = append(result, pr)
+		}
+	}
+
+	return result, nil
+}
+
+// newTestSMTP запускает FakeSMTPServer и возвращает отправителя писем через него.
+func newTestSMTP(t *testing.T) (*FakeSMTPServer, *SMTPMailer) {
+	t.Helper()
+
+	server, err := NewFakeSMTPServer()
+	if err != nil {
+		t.Fatalf("start fake smtp: %v", err)
+	}
+	t.Cleanup(server.Close)
+
+	host, port := server.Addr()
+
+	return server, NewSMTPMailer(SMTPConfig{Host: host, Port: port, From: "reviews@example.com", Timeout: time.Second})
+}
@@ -0,0 +1,146 @@
+// Package notify содержит уведомления ревьюверов о назначениях в каналах,
+// за которыми они следят (чаты, почта).
+package notify
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"mime"
+	"net"
+	"net/smtp"
+	"strconv"
+	"time"
+)
+
+// Mail описывает текстовое письмо одному получателю.
+type Mail struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// Mailer описывает отправку писем.
+type Mailer interface {
+	// Send отправляет письмо.
+	Send(ctx context.Context, mail Mail) error
+}
+
+// SMTPConfig описывает подключение к SMTP-серверу.
+type SMTPConfig struct {
+	Host string
+	Port int
+	// Username и Password включают аутентификацию PLAIN; пустой Username — без аутентификации.
+	Username string
+	Password string
+	From     string
+	// Timeout — таймаут подключения и отправки одного письма.
+	Timeout time.Duration
+}
+
+// SMTPMailer отправляет письма через SMTP. Если сервер поддерживает STARTTLS, соединение шифруется.
+type SMTPMailer struct {
+	cfg SMTPConfig
+}
+
+// NewSMTPMailer создаёт отправителя писем через SMTP.
+func NewSMTPMailer(cfg SMTPConfig) *SMTPMailer {
+	if cfg.Port == 0 {
+		cfg.Port = 587
+	}
+
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = 10 * time.Second
+	}
+
+	return &SMTPMailer{cfg: cfg}
+}
+
+// Send отправляет письмо в рамках одного SMTP-сеанса.
+func (m *SMTPMailer) Send(ctx context.Context, mail Mail) error {
+	addr := net.JoinHostPort(m.cfg.Host, strconv.Itoa(m.cfg.Port))
+	dialer := net.Dialer{Timeout: m.cfg.Timeout}
+
+	conn, err := dialer.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return fmt.Errorf("dial smtp %s: %w", addr, err)
+	}
+
+	deadline := time.Now().Add(m.cfg.Timeout)
+	if d, ok := ctx.Deadline(); ok && d.Before(deadline) {
+		deadline = d
+	}
+
+	if err := conn.SetDeadline(deadline); err != nil {
+		_ = conn.Close()
+		return fmt.Errorf("set smtp deadline: %w", err)
+	}
+
+	client, err := smtp.NewClient(conn, m.cfg.Host)
+	if err != nil {
+		_ = conn.Close()
+		return fmt.Errorf("smtp handshake: %w", err)
+	}
+	defer func() {
+		_ = client.Close()
+	}()
+
+	if ok, _ := client.Extension("STARTTLS"); ok {
+		if err := client.StartTLS(&tls.Config{ServerName: m.cfg.Host}); err != nil {
+			return fmt.Errorf("smtp starttls: %w", err)
+		}
+	}
+
+	if m.cfg.Username != "" {
+		if ok, _ := client.Extension("AUTH"); !ok {
+			return errors.New("smtp server does not support AUTH")
+		}
+
+		if err := client.Auth(smtp.PlainAuth("", m.cfg.Username, m.cfg.Password, m.cfg.Host)); err != nil {
+			return fmt.Errorf("smtp auth: %w", err)
+		}
+	}
+
+	if err := client.Mail(m.cfg.From); err != nil {
+		return fmt.Errorf("smtp MAIL FROM: %w", err)
+	}
+
+	if err := client.Rcpt(mail.To); err != nil {
+		return fmt.Errorf("smtp RCPT TO %s: %w", mail.To, err)
+	}
+
+	w, err := client.Data()
+	if err != nil {
+		return fmt.Errorf("smtp DATA: %w", err)
+	}
+
+	if _, err := w.Write(m.message(mail)); err != nil {
+		_ = w.Close()
+		return fmt.Errorf("write smtp message: %w", err)
+	}
+
+	if err := w.Close(); err != nil {
+		return fmt.Errorf("finish smtp message: %w", err)
+	}
+
+	return client.Quit()
+}
+
+// message формирует письмо в формате RFC 5322 с телом text/plain в UTF-8.
+func (m *SMTPMailer) message(mail Mail) []byte {
+	var buf bytes.Buffer
+
+	fmt.Fprintf(&buf, "From: %s\r\n", m.cfg.From)
+	fmt.Fprintf(&buf, "To: %s\r\n", mail.To)
+	fmt.Fprintf(&buf, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", mail.Subject))
+	fmt.Fprintf(&buf, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	buf.WriteString("MIME-Version: 1.0\r\n")
+	buf.WriteString("Content-Type: text/plain; charset=utf-8\r\n")
+	buf.WriteString("Content-Transfer-Encoding: 8bit\r\n")
+	buf.WriteString("\r\n")
+	buf.WriteString(mail.Body)
+
+	return buf.Bytes()
+}
@@ -0,0 +1,48 @@
+// Package notify содержит уведомления ревьюверов о назначениях в каналах,
+// за которыми они следят (чаты, почта).
+package notify
+
+import (
+	"context"
+	"strings"
+
+	"github.com/dixitix/pr-reviewer-service/internal/domain"
+	"github.com/dixitix/pr-reviewer-service/internal/repository"
+)
+
+// assignmentReason возвращает причину назначения для событий назначения и переназначения.
+// Для остальных событий ok == false.
+func assignmentReason(ctx context.Context, users repository.UserRepository, e domain.Event) (reason string, ok bool) {
+	switch e.Type {
+	case domain.EventReviewersAssigned:
+		return "assigned automatically when the pull request was opened", true
+	case domain.EventReviewerReassigned:
+		reason = "reassigned"
+		if len(e.RemovedReviewers) > 0 {
+			reason += " from " + strings.Join(usernames(ctx, users, e.RemovedReviewers), ", ")
+		}
+
+		if e.Reason != "" {
+			reason += ": " + e.Reason
+		}
+
+		return reason, true
+	default:
+		return "", false
+	}
+}
+
+// usernames возвращает имена пользователей, подставляя ID для тех, кого не удалось найти.
+func usernames(ctx context.Context, users repository.UserRepository, ids []domain.UserID) []string {
+	result := make([]string, len(ids))
+
+	for i, id := range ids {
+		result[i] = string(id)
+
+		if user, err := users.GetByID(ctx, id); err == nil && user.Username != "" {
+			result[i] = user.Username
+		}
+	}
+
+	return result
+}
@@ -0,0 +1,187 @@
+// Package notify содержит уведомления ревьюверов о назначениях в каналах,
+// за которыми они следят (чаты, почта).
+package notify
+
+import (
+	"net"
+	"net/textproto"
+	"strings"
+	"sync"
+)
+
+// FakeMail описывает письмо, принятое FakeSMTPServer.
+type FakeMail struct {
+	From string
+	To   []string
+	// Data — письмо целиком: заголовки и тело.
+	Data string
+}
+
+// FakeSMTPServer — локальная SMTP-заглушка для тестов. Понимает минимальный набор команд
+// (HELO/EHLO, MAIL, RCPT, DATA, RSET, NOOP, QUIT), не поддерживает STARTTLS и AUTH.
+type FakeSMTPServer struct {
+	listener net.Listener
+
+	mu       sync.Mutex
+	mails    []FakeMail
+	failures int
+	wg       sync.WaitGroup
+}
+
+// NewFakeSMTPServer запускает FakeSMTPServer на свободном локальном порту.
+func NewFakeSMTPServer() (*FakeSMTPServer, error) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return nil, err
+	}
+
+	s := &FakeSMTPServer{listener: ln}
+
+	s.wg.Add(1)
+	go s.serve()
+
+	return s, nil
+}
+
+// Addr возвращает хост и порт сервера.
+func (s *FakeSMTPServer) Addr() (host string, port int) {
+	addr := s.listener.Addr().(*net.TCPAddr)
+	return addr.IP.String(), addr.Port
+}
+
+// FailNext заставляет сервер отклонить n следующих писем временной ошибкой.
+func (s *FakeSMTPServer) FailNext(n int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.failures = n
+}
+
+// Mails возвращает копию принятых писем.
+func (s *FakeSMTPServer) Mails() []FakeMail {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]FakeMail(nil), s.mails...)
+}
+
+// Close останавливает сервер.
+func (s *FakeSMTPServer) Close() {
+	_ = s.listener.Close()
+	s.wg.Wait()
+}
+
+// serve принимает подключения до закрытия сервера.
+func (s *FakeSMTPServer) serve() {
+	defer s.wg.Done()
+
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+
+		s.wg.Add(1)
+
+		go func() {
+			defer s.wg.Done()
+			s.handle(conn)
+		}()
+	}
+}
+
+// handle ведёт один SMTP-сеанс.
+func (s *FakeSMTPServer) handle(conn net.Conn) {
+	defer func() {
+		_ = conn.Close()
+	}()
+
+	tp := textproto.NewConn(conn)
+	reply := func(line string) bool {
+		return tp.PrintfLine("%s", line) == nil
+	}
+
+	if !reply("220 fake-smtp ready") {
+		return
+	}
+
+	var current FakeMail
+
+	for {
+		line, err := tp.ReadLine()
+		if err != nil {
+			return
+		}
+
+		cmd, arg, _ := strings.Cut(line, " ")
+		cmd = strings.ToUpper(cmd)
+
+		var ok bool
+
+		switch cmd {
+		case "HELO", "EHLO":
+			ok = reply("250 fake-smtp")
+		case "MAIL":
+			s.mu.Lock()
+			failing := s.failures > 0
+			if failing {
+				s.failures--
+			}
+			s.mu.Unlock()
+
+			if failing {
+				ok = reply("451 temporary failure")
+				continue
+			}
+
+			current = FakeMail{From: addressArg(arg)}
+			ok = reply("250 OK")
+		case "RCPT":
+			current.To = append(current.To, addressArg(arg))
+			ok = reply("250 OK")
+		case "DATA":
+			if !reply("354 end data with <CR><LF>.<CR><LF>") {
+				return
+			}
+
+			data, err := tp.ReadDotBytes()
+			if err != nil {
+				return
+			}
+
+			current.Data = string(data)
+
+			s.mu.Lock()
+			s.mails = append(s.mails, current)
+			s.mu.Unlock()
+
+			current = FakeMail{}
+			ok = reply("250 OK: queued")
+		case "RSET":
+			current = FakeMail{}
+			ok = reply("250 OK")
+		case "NOOP":
+			ok = reply("250 OK")
+		case "QUIT":
+			_ = reply("221 bye")
+			return
+		default:
+			ok = reply("502 command not implemented")
+		}
+
+		if !ok {
+			return
+		}
+	}
+}
+
+// addressArg извлекает адрес из аргумента "FROM:<a@b>" или "TO:<a@b>".
+func addressArg(arg string) string {
+	if i := strings.IndexByte(arg, ':'); i >= 0 {
+		arg = arg[i+1:]
+	}
+
+	arg, _, _ = strings.Cut(strings.TrimSpace(arg), " ")
+
+	return strings.Trim(arg, "<>")
+}
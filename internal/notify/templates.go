@@ -0,0 +1,239 @@
+// Package notify содержит уведомления ревьюверов о назначениях в каналах,
+// за которыми они следят (чаты, почта).
+package notify
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/dixitix/pr-reviewer-service/internal/domain"
+)
+
+// Имена файлов шаблонов писем.
+const (
+	AssignedTemplate   = "assigned.tmpl"
+	DigestTemplate     = "digest.tmpl"
+	EscalationTemplate = "escalation.tmpl"
+)
+
+// DefaultOrganization — организация, шаблоны которой используются, если у организации нет своих.
+const DefaultOrganization = "default"
+
+// defaultAssignedTemplate — встроенный шаблон письма о назначении.
+// Первая строка "Subject: ..." задаёт тему письма, остальное — тело.
+const defaultAssignedTemplate = `Subject: Review requested: {{.PullRequest.Name}}
+Hi {{.Reviewer}},
+
+you have been asked to review "{{.PullRequest.Name}}" ({{.PullRequest.ID}}) by {{.Author}}.
+Why: {{.Reason}}.
+`
+
+// defaultDigestTemplate — встроенный шаблон ежедневной сводки.
+const defaultDigestTemplate = `Subject: {{len .Reviews}} open review(s) waiting for you
+Hi {{.Reviewer}},
+
+open reviews as of {{.Date.Format "2006-01-02"}}:
+{{range .Reviews}}
+- "{{.PullRequest.Name}}" ({{.PullRequest.ID}}) by {{.Author}}, waiting {{age .Age}}
+{{- end}}
+`
+
+// defaultEscalationTemplate — встроенный шаблон письма лиду команды об эскалации или принудительном merge.
+const defaultEscalationTemplate = `Subject: Needs your attention: {{.PullRequest.Name}}
+Hi {{.Lead}},
+
+"{{.PullRequest.Name}}" ({{.PullRequest.ID}}) by {{.Author}} needs a team lead.
+{{- if .Reviewer}}
+Reviewer: {{.Reviewer}}.
+{{- end}}
+Why: {{.Reason}}.
+`
+
+// AssignedMailData — данные шаблона письма о назначении.
+type AssignedMailData struct {
+	Reviewer    string
+	Author      string
+	PullRequest domain.PullRequest
+	Reason      string
+}
+
+// EscalationMailData — данные шаблона письма лиду команды. Reviewer пуст для принудительного merge.
+type EscalationMailData struct {
+	Lead        string
+	Author      string
+	Reviewer    string
+	PullRequest domain.PullRequest
+	Reason      string
+}
+
+// DigestItem описывает одно открытое ревью в сводке.
+type DigestItem struct {
+	PullRequest domain.PullRequest
+	Author      string
+	Age         time.Duration
+}
+
+// DigestMailData — данные шаблона ежедневной сводки.
+type DigestMailData struct {
+	Reviewer string
+	Date     time.Time
+	Reviews  []DigestItem
+}
+
+// EmailTemplates хранит шаблоны писем по организациям.
+type EmailTemplates struct {
+	byOrg map[string]*template.Template
+}
+
+// templateFuncs — функции, доступные в шаблонах писем.
+var templateFuncs = template.FuncMap{
+	"age": formatAge,
+}
+
+// NewDefaultEmailTemplates возвращает набор только со встроенными шаблонами.
+func NewDefaultEmailTemplates() *EmailTemplates {
+	t, err := parseTemplates(map[string]string{
+		AssignedTemplate:   defaultAssignedTemplate,
+		DigestTemplate:     defaultDigestTemplate,
+		EscalationTemplate: defaultEscalationTemplate,
+	})
+	if err != nil {
+		panic(fmt.Sprintf("parse default email templates: %v", err))
+	}
+
+	return &EmailTemplates{byOrg: map[string]*template.Template{DefaultOrganization: t}}
+}
+
+// LoadEmailTemplates загружает шаблоны из каталога dir, в котором у каждой организации
+// свой подкаталог с файлами assigned.tmpl, digest.tmpl и escalation.tmpl. Отсутствующие файлы заменяются
+// шаблонами организации "default", а при их отсутствии — встроенными шаблонами.
+// Пустой dir означает только встроенные шаблоны.
+func LoadEmailTemplates(dir string) (*EmailTemplates, error) {
+	result := NewDefaultEmailTemplates()
+	if dir == "" {
+		return result, nil
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("read email templates dir: %w", err)
+	}
+
+	base := map[string]string{
+		AssignedTemplate:   defaultAssignedTemplate,
+		DigestTemplate:     defaultDigestTemplate,
+		EscalationTemplate: defaultEscalationTemplate,
+	}
+
+	load := func(org string) (map[string]string, error) {
+		sources := make(map[string]string, len(base))
+
+		for name, src := range base {
+			raw, err := os.ReadFile(filepath.Join(dir, org, name))
+			switch {
+			case errors.Is(err, os.ErrNotExist):
+				sources[name] = src
+			case err != nil:
+				return nil, fmt.Errorf("read template %s/%s: %w", org, name, err)
+			default:
+				sources[name] = string(raw)
+			}
+		}
+
+		t, err := parseTemplates(sources)
+		if err != nil {
+			return nil, fmt.Errorf("parse templates of %s: %w", org, err)
+		}
+
+		result.byOrg[org] = t
+
+		return sources, nil
+	}
+
+	// Шаблоны "default" загружаются первыми, чтобы служить основой для остальных организаций.
+	if base, err = load(DefaultOrganization); err != nil {
+		return nil, err
+	}
+
+	for _, entry := range entries {
+		if !entry.IsDir() || entry.Name() == DefaultOrganization {
+			continue
+		}
+
+		if _, err := load(entry.Name()); err != nil {
+			return nil, err
+		}
+	}
+
+	return result, nil
+}
+
+// RenderAssigned формирует письмо о назначении по шаблону организации.
+func (t *EmailTemplates) RenderAssigned(org string, data AssignedMailData) (subject, body string, err error) {
+	return t.render(org, AssignedTemplate, data)
+}
+
+// RenderEscalation формирует письмо лиду команды по шаблону организации.
+func (t *EmailTemplates) RenderEscalation(org string, data EscalationMailData) (subject, body string, err error) {
+	return t.render(org, EscalationTemplate, data)
+}
+
+// RenderDigest формирует ежедневную сводку по шаблону организации.
+func (t *EmailTemplates) RenderDigest(org string, data DigestMailData) (subject, body string, err error) {
+	return t.render(org, DigestTemplate, data)
+}
+
+// render выполняет шаблон и отделяет тему письма от тела.
+func (t *EmailTemplates) render(org, name string, data any) (subject, body string, err error) {
+	tmpl, ok := t.byOrg[org]
+	if !ok {
+		tmpl = t.byOrg[DefaultOrganization]
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.ExecuteTemplate(&buf, name, data); err != nil {
+		return "", "", fmt.Errorf("execute email template %s: %w", name, err)
+	}
+
+	first, rest, _ := strings.Cut(buf.String(), "\n")
+
+	subject, ok = strings.CutPrefix(first, "Subject:")
+	if !ok {
+		return "", "", fmt.Errorf("email template %s must start with a Subject: line", name)
+	}
+
+	return strings.TrimSpace(subject), strings.TrimLeft(rest, "\n"), nil
+}
+
+// parseTemplates разбирает шаблоны писем одной организации.
+func parseTemplates(sources map[string]string) (*template.Template, error) {
+	root := template.New("email").Funcs(templateFuncs)
+
+	for name, src := range sources {
+		if _, err := root.New(name).Parse(src); err != nil {
+			return nil, fmt.Errorf("parse %s: %w", name, err)
+		}
+	}
+
+	return root, nil
+}
+
+// formatAge форматирует длительность ожидания с точностью до часа: "2d 3h", "5h", "<1h".
+func formatAge(d time.Duration) string {
+	hours := int(d.Hours())
+
+	switch {
+	case hours < 1:
+		return "<1h"
+	case hours < 24:
+		return fmt.Sprintf("%dh", hours)
+	default:
+		return fmt.Sprintf("%dd %dh", hours/24, hours%24)
+	}
+}
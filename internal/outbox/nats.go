@@ -0,0 +1,193 @@
+// Package outbox содержит фоновый ретранслятор событий из таблицы outbox
+// и получателей (sinks), которым эти события доставляются.
+package outbox
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dixitix/pr-reviewer-service/internal/domain"
+	"github.com/dixitix/pr-reviewer-service/internal/event"
+)
+
+// NATSConfig описывает настройки публикации событий в NATS-совместимый брокер.
+type NATSConfig struct {
+	// URL — адрес брокера: "nats://host:4222" или "host:4222".
+	URL string
+	// SubjectPrefix — префикс темы, к которому добавляется тип события.
+	SubjectPrefix string
+	// Timeout — таймаут подключения и подтверждения публикации.
+	Timeout time.Duration
+}
+
+// NATSSink публикует события в NATS-совместимый брокер по текстовому протоколу NATS.
+// Публикация подтверждается через PING/PONG, поэтому ошибка брокера видна ретранслятору.
+type NATSSink struct {
+	cfg NATSConfig
+
+	mu     sync.Mutex
+	conn   net.Conn
+	reader *bufio.Reader
+}
+
+// NewNATSSink создаёт получателя для NATS-совместимого брокера. Подключение устанавливается лениво.
+func NewNATSSink(cfg NATSConfig) *NATSSink {
+	if cfg.SubjectPrefix == "" {
+		cfg.SubjectPrefix = "pr-reviewer.events"
+	}
+
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = 5 * time.Second
+	}
+
+	return &NATSSink{cfg: cfg}
+}
+
+// Name возвращает имя получателя.
+func (s *NATSSink) Name() string {
+	return "nats"
+}
+
+// Deliver публикует событие в тему "<prefix>.<тип события>".
+func (s *NATSSink) Deliver(ctx context.Context, e domain.Event) error {
+	payload, err := json.Marshal(event.NewMessage(e))
+	if err != nil {
+		return fmt.Errorf("marshal nats message: %w", err)
+	}
+
+	subject := s.cfg.SubjectPrefix + "." + string(e.Type)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.publish(ctx, subject, payload); err != nil {
+		s.closeLocked()
+		return err
+	}
+
+	return nil
+}
+
+// Close закрывает подключение к брокеру.
+func (s *NATSSink) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.closeLocked()
+}
+
+// publish отправляет PUB и ждёт PONG в ответ на PING.
+func (s *NATSSink) publish(ctx context.Context, subject string, payload []byte) error {
+	if err := s.connectLocked(ctx); err != nil {
+		return err
+	}
+
+	deadline := time.Now().Add(s.cfg.Timeout)
+	if d, ok := ctx.Deadline(); ok && d.Before(deadline) {
+		deadline = d
+	}
+
+	if err := s.conn.SetDeadline(deadline); err != nil {
+		return fmt.Errorf("set nats deadline: %w", err)
+	}
+
+	frame := fmt.Sprintf("PUB %s %d\r\n%s\r\nPING\r\n", subject, len(payload), payload)
+	if _, err := s.conn.Write([]byte(frame)); err != nil {
+		return fmt.Errorf("write nats PUB: %w", err)
+	}
+
+	return s.awaitPong()
+}
+
+// connectLocked подключается к брокеру и выполняет рукопожатие INFO/CONNECT.
+func (s *NATSSink) connectLocked(ctx context.Context) error {
+	if s.conn != nil {
+		return nil
+	}
+
+	addr := s.cfg.URL
+	if u, err := url.Parse(addr); err == nil && u.Host != "" {
+		addr = u.Host
+	}
+
+	dialer := net.Dialer{Timeout: s.cfg.Timeout}
+
+	conn, err := dialer.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return fmt.Errorf("dial nats %s: %w", addr, err)
+	}
+
+	s.conn = conn
+	s.reader = bufio.NewReader(conn)
+
+	if err := conn.SetDeadline(time.Now().Add(s.cfg.Timeout)); err != nil {
+		return fmt.Errorf("set nats deadline: %w", err)
+	}
+
+	line, err := s.readLine()
+	if err != nil {
+		return fmt.Errorf("read nats INFO: %w", err)
+	}
+
+	if !strings.HasPrefix(line, "INFO") {
+		return fmt.Errorf("unexpected nats greeting: %q", line)
+	}
+
+	if _, err := conn.Write([]byte(`CONNECT {"verbose":false,"pedantic":false,"name":"pr-reviewer-service"}` + "\r\n")); err != nil {
+		return fmt.Errorf("write nats CONNECT: %w", err)
+	}
+
+	return nil
+}
+
+// awaitPong читает ответы брокера до PONG, отвечая на его PING.
+func (s *NATSSink) awaitPong() error {
+	for {
+		line, err := s.readLine()
+		if err != nil {
+			return fmt.Errorf("read nats reply: %w", err)
+		}
+
+		switch {
+		case line == "PONG":
+			return nil
+		case line == "PING":
+			if _, err := s.conn.Write([]byte("PONG\r\n")); err != nil {
+				return fmt.Errorf("write nats PONG: %w", err)
+			}
+		case strings.HasPrefix(line, "-ERR"):
+			return errors.New("nats error: " + strings.TrimSpace(strings.TrimPrefix(line, "-ERR")))
+		}
+	}
+}
+
+// readLine читает строку протокола без завершающего CRLF.
+func (s *NATSSink) readLine() (string, error) {
+	line, err := s.reader.ReadString('\n')
+	if err != nil {
+		return "", err
+	}
+
+	return strings.TrimRight(line, "\r\n"), nil
+}
+
+// closeLocked закрывает текущее подключение, если оно есть.
+func (s *NATSSink) closeLocked() error {
+	if s.conn == nil {
+		return nil
+	}
+
+	err := s.conn.Close()
+	s.conn = nil
+	s.reader = nil
+
+	return err
+}
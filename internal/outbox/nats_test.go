@@ -0,0 +1,130 @@
+package outbox
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dixitix/pr-reviewer-service/internal/domain"
+	"github.com/dixitix/pr-reviewer-service/internal/event"
+)
+
+// natsPublish описывает сообщение, принятое тестовым NATS-сервером.
+type natsPublish struct {
+	subject string
+	payload []byte
+}
+
+// startFakeNATS запускает минимальный NATS-сервер, который принимает PUB и отвечает на PING.
+func startFakeNATS(t *testing.T) (string, <-chan natsPublish) {
+	t.Helper()
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	t.Cleanup(func() { _ = ln.Close() })
+
+	published := make(chan natsPublish, 8)
+
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer func() { _ = conn.Close() }()
+
+		_, _ = conn.Write([]byte(`INFO {"server_id":"fake"}` + "\r\n"))
+
+		r := bufio.NewReader(conn)
+		for {
+			line, err := r.ReadString('\n')
+			if err != nil {
+				return
+			}
+
+			fields := strings.Fields(line)
+			if len(fields) == 0 {
+				continue
+			}
+
+			switch fields[0] {
+			case "PUB":
+				size, _ := strconv.Atoi(fields[len(fields)-1])
+				buf := make([]byte, size+2)
+				if _, err := io.ReadFull(r, buf); err != nil {
+					return
+				}
+				published <- natsPublish{subject: fields[1], payload: buf[:size]}
+			case "PING":
+				_, _ = conn.Write([]byte("PONG\r\n"))
+			}
+		}
+	}()
+
+	return "nats://" + ln.Addr().String(), published
+}
+
+// TestNATSSink_Deliver проверяет публикацию события в тему по типу события.
+func TestNATSSink_Deliver(t *testing.T) {
+	url, published := startFakeNATS(t)
+
+	sink := NewNATSSink(NATSConfig{URL: url, SubjectPrefix: "test.events", Timeout: time.Second})
+	defer func() { _ = sink.Close() }()
+
+	e := domain.Event{
+		Sequence:       42,
+		Type:           domain.EventReviewersAssigned,
+		PullRequest:    domain.PullRequest{ID: "pr-1", AuthorID: "u1"},
+		AddedReviewers: []domain.UserID{"u2", "u3"},
+	}
+
+	for i := 0; i < 2; i++ {
+		if err := sink.Deliver(context.Background(), e); err != nil {
+			t.Fatalf("Deliver #%d returned error: %v", i+1, err)
+		}
+	}
+
+	for i := 0; i < 2; i++ {
+		select {
+		case msg := <-published:
+			if msg.subject != "test.events.reviewers.assigned" {
+				t.Fatalf("unexpected subject: %q", msg.subject)
+			}
+
+			var m event.Message
+			if err := json.Unmarshal(msg.payload, &m); err != nil {
+				t.Fatalf("unmarshal payload: %v", err)
+			}
+
+			if m.Sequence != 42 || m.PullRequest.PullRequestID != "pr-1" || len(m.AddedReviewers) != 2 {
+				t.Fatalf("unexpected message: %+v", m)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("message #%d was not published", i+1)
+		}
+	}
+}
+
+// TestNATSSink_Unavailable проверяет, что недоступный брокер возвращает ошибку.
+func TestNATSSink_Unavailable(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+
+	addr := ln.Addr().String()
+	_ = ln.Close()
+
+	sink := NewNATSSink(NATSConfig{URL: addr, Timeout: 200 * time.Millisecond})
+
+	if err := sink.Deliver(context.Background(), domain.Event{Type: domain.EventPullRequestMerged}); err == nil {
+		t.Fatalf("expected error for unavailable broker")
+	}
+}
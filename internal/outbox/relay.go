@@ -0,0 +1,194 @@
+// Package outbox содержит фоновый ретранслятор событий из таблицы outbox
+// и получателей (sinks), которым эти события доставляются.
+package outbox
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/dixitix/pr-reviewer-service/internal/repository"
+	"github.com/dixitix/pr-reviewer-service/internal/tenant"
+)
+
+// Config описывает настройки ретранслятора.
+type Config struct {
+	// PollInterval — пауза между опросами outbox, когда событий нет.
+	PollInterval time.Duration
+	// BatchSize — число событий, захватываемых за один опрос.
+	BatchSize int
+	// Lease — время, на которое захваченные события скрываются от других ретрансляторов.
+	Lease time.Duration
+	// InitialBackoff — пауза перед повторной публикацией после первой ошибки, далее удваивается.
+	InitialBackoff time.Duration
+	// MaxBackoff — верхняя граница паузы перед повторной публикацией.
+	MaxBackoff time.Duration
+	// Retention — сколько хранить опубликованные события. Ноль отключает очистку.
+	Retention time.Duration
+}
+
+// DefaultConfig возвращает настройки ретранслятора по умолчанию.
+func DefaultConfig() Config {
+	return Config{
+		PollInterval:   500 * time.Millisecond,
+		BatchSize:      100,
+		Lease:          30 * time.Second,
+		InitialBackoff: time.Second,
+		MaxBackoff:     5 * time.Minute,
+		Retention:      7 * 24 * time.Hour,
+	}
+}
+
+// Relay забирает неопубликованные события из outbox и доставляет их всем получателям.
+// Событие отмечается опубликованным только после успешной доставки всем получателям,
+// иначе оно будет передано повторно всем получателям после паузы.
+type Relay struct {
+	repo   repository.OutboxRepository
+	sinks  []Sink
+	cfg    Config
+	logger *slog.Logger
+}
+
+// NewRelay создаёт ретранслятор outbox.
+func NewRelay(repo repository.OutboxRepository, sinks []Sink, cfg Config, logger *slog.Logger) *Relay {
+	def := DefaultConfig()
+
+	if cfg.PollInterval <= 0 {
+		cfg.PollInterval = def.PollInterval
+	}
+
+	if cfg.BatchSize <= 0 {
+		cfg.BatchSize = def.BatchSize
+	}
+
+	if cfg.Lease <= 0 {
+		cfg.Lease = def.Lease
+	}
+
+	if cfg.InitialBackoff <= 0 {
+		cfg.InitialBackoff = def.InitialBackoff
+	}
+
+	if cfg.MaxBackoff < cfg.InitialBackoff {
+		cfg.MaxBackoff = cfg.InitialBackoff
+	}
+
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	return &Relay{
+		repo:   repo,
+		sinks:  sinks,
+		cfg:    cfg,
+		logger: logger,
+	}
+}
+
+// Run опрашивает outbox до отмены контекста.
+func (r *Relay) Run(ctx context.Context) {
+	var cleanup <-chan time.Time
+
+	if r.cfg.Retention > 0 {
+		ticker := time.NewTicker(time.Hour)
+		defer ticker.Stop()
+
+		cleanup = ticker.C
+	}
+
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-cleanup:
+			r.cleanup(ctx)
+		case <-timer.C:
+			n, err := r.RelayOnce(ctx)
+			if err != nil && !errors.Is(err, context.Canceled) {
+				r.logger.Error("relay outbox events", slog.Any("err", err))
+			}
+
+			// Если забрали полный пакет, сразу забираем следующий.
+			if n == r.cfg.BatchSize {
+				timer.Reset(0)
+			} else {
+				timer.Reset(r.cfg.PollInterval)
+			}
+		}
+	}
+}
+
+// RelayOnce захватывает и публикует один пакет событий и возвращает число захваченных событий.
+func (r *Relay) RelayOnce(ctx context.Context) (int, error) {
+	records, err := r.repo.ClaimPending(ctx, r.cfg.BatchSize, r.cfg.Lease)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, rec := range records {
+		if err := r.publish(ctx, rec); err != nil {
+			return len(records), err
+		}
+	}
+
+	return len(records), nil
+}
+
+// publish доставляет событие всем получателям и сохраняет результат.
+func (r *Relay) publish(ctx context.Context, rec repository.OutboxRecord) error {
+	seq := rec.Event.Sequence
+
+	// Получатели работают с данными организации, к которой относится событие.
+	orgCtx := tenant.WithOrganization(ctx, rec.Event.OrganizationID)
+
+	var deliveryErr error
+
+	for _, sink := range r.sinks {
+		if err := sink.Deliver(orgCtx, rec.Event); err != nil {
+			r.logger.Warn(
+				"outbox sink delivery failed",
+				slog.String("sink", sink.Name()),
+				slog.Int64("sequence", seq),
+				slog.Int("attempts", rec.Attempts+1),
+				slog.Any("err", err),
+			)
+
+			deliveryErr = errors.Join(deliveryErr, err)
+		}
+	}
+
+	if deliveryErr == nil {
+		return r.repo.MarkPublished(ctx, seq)
+	}
+
+	retryAt := time.Now().Add(r.backoff(rec.Attempts))
+
+	return r.repo.MarkFailed(ctx, seq, deliveryErr.Error(), retryAt)
+}
+
+// backoff возвращает паузу перед следующей попыткой после attempts неудачных.
+func (r *Relay) backoff(attempts int) time.Duration {
+	d := r.cfg.InitialBackoff
+	for i := 0; i < attempts && d < r.cfg.MaxBackoff; i++ {
+		d *= 2
+	}
+
+	return min(d, r.cfg.MaxBackoff)
+}
+
+// cleanup удаляет опубликованные события старше срока хранения.
+func (r *Relay) cleanup(ctx context.Context) {
+	n, err := r.repo.DeletePublishedBefore(ctx, time.Now().Add(-r.cfg.Retention))
+	if err != nil {
+		r.logger.Error("cleanup outbox events", slog.Any("err", err))
+		return
+	}
+
+	if n > 0 {
+		r.logger.Info("outbox events cleaned up", slog.Int64("deleted", n))
+	}
+}
@@ -0,0 +1,142 @@
+package outbox
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/dixitix/pr-reviewer-service/internal/domain"
+	"github.com/dixitix/pr-reviewer-service/internal/repository"
+)
+
+// memoryOutbox — реализация repository.OutboxRepository в памяти для тестов ретранслятора.
+type memoryOutbox struct {
+	records   []repository.OutboxRecord
+	published map[int64]bool
+	failed    map[int64]string
+}
+
+func newMemoryOutbox(events ...domain.Event) *memoryOutbox {
+	m := &memoryOutbox{published: map[int64]bool{}, failed: map[int64]string{}}
+	for i, e := range events {
+		e.Sequence = int64(i + 1)
+		m.records = append(m.records, repository.OutboxRecord{Event: e})
+	}
+
+	return m
+}
+
+func (m *memoryOutbox) ClaimPending(_ context.Context, limit int, _ time.Duration) ([]repository.OutboxRecord, error) {
+	var result []repository.OutboxRecord
+	for _, rec := range m.records {
+		if !m.published[rec.Event.Sequence] && len(result) < limit {
+			result = append(result, rec)
+		}
+	}
+
+	return result, nil
+}
+
+func (m *memoryOutbox) MarkPublished(_ context.Context, seq int64) error {
+	m.published[seq] = true
+	delete(m.failed, seq)
+
+	return nil
+}
+
+func (m *memoryOutbox) MarkFailed(_ context.Context, seq int64, lastErr string, _ time.Time) error {
+	m.failed[seq] = lastErr
+	for i := range m.records {
+		if m.records[i].Event.Sequence == seq {
+			m.records[i].Attempts++
+		}
+	}
+
+	return nil
+}
+
+func (m *memoryOutbox) DeletePublishedBefore(context.Context, time.Time) (int64, error) {
+	return 0, nil
+}
+
+// recordingSink запоминает доставленные события и может отказывать заданное число раз.
+type recordingSink struct {
+	failures  int
+	delivered []int64
+}
+
+func (s *recordingSink) Name() string { return "recording" }
+
+func (s *recordingSink) Deliver(_ context.Context, e domain.Event) error {
+	if s.failures > 0 {
+		s.failures--
+		return errors.New("sink unavailable")
+	}
+
+	s.delivered = append(s.delivered, e.Sequence)
+
+	return nil
+}
+
+// TestRelay_AtLeastOnce проверяет, что событие публикуется повторно, пока все получатели его не примут.
+func TestRelay_AtLeastOnce(t *testing.T) {
+	repo := newMemoryOutbox(
+		domain.Event{Type: domain.EventReviewersAssigned, PullRequest: domain.PullRequest{ID: "pr-1"}},
+		domain.Event{Type: domain.EventPullRequestMerged, PullRequest: domain.PullRequest{ID: "pr-1"}},
+	)
+
+	healthy := &recordingSink{}
+	flaky := &recordingSink{failures: 1}
+
+	relay := NewRelay(repo, []Sink{healthy, flaky}, Config{BatchSize: 10}, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	ctx := context.Background()
+
+	n, err := relay.RelayOnce(ctx)
+	if err != nil {
+		t.Fatalf("RelayOnce returned error: %v", err)
+	}
+
+	if n != 2 {
+		t.Fatalf("expected 2 claimed events, got %d", n)
+	}
+
+	if repo.published[1] || !repo.published[2] {
+		t.Fatalf("expected only second event published, got %v", repo.published)
+	}
+
+	if repo.failed[1] == "" || repo.records[0].Attempts != 1 {
+		t.Fatalf("expected first event marked failed, got %v", repo.failed)
+	}
+
+	if _, err := relay.RelayOnce(ctx); err != nil {
+		t.Fatalf("RelayOnce returned error: %v", err)
+	}
+
+	if !repo.published[1] {
+		t.Fatalf("expected first event published after retry")
+	}
+
+	// Исправный получатель получил первое событие дважды — доставка как минимум один раз.
+	if len(healthy.delivered) != 3 || healthy.delivered[0] != 1 || healthy.delivered[2] != 1 {
+		t.Fatalf("unexpected deliveries to healthy sink: %v", healthy.delivered)
+	}
+
+	if len(flaky.delivered) != 2 {
+		t.Fatalf("unexpected deliveries to flaky sink: %v", flaky.delivered)
+	}
+}
+
+// TestRelay_Backoff проверяет экспоненциальный рост паузы с ограничением сверху.
+func TestRelay_Backoff(t *testing.T) {
+	relay := NewRelay(newMemoryOutbox(), nil, Config{InitialBackoff: time.Second, MaxBackoff: 10 * time.Second}, nil)
+
+	cases := map[int]time.Duration{0: time.Second, 1: 2 * time.Second, 3: 8 * time.Second, 10: 10 * time.Second}
+	for attempts, want := range cases {
+		if got := relay.backoff(attempts); got != want {
+			t.Fatalf("backoff(%d) = %s, want %s", attempts, got, want)
+		}
+	}
+}
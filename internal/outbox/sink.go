@@ -0,0 +1,60 @@
+// Package outbox содержит фоновый ретранслятор событий из таблицы outbox
+// и получателей (sinks), которым эти события доставляются.
+package outbox
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+
+	"github.com/dixitix/pr-reviewer-service/internal/domain"
+	"github.com/dixitix/pr-reviewer-service/internal/event"
+)
+
+// Sink описывает получателя событий из outbox.
+// Доставка выполняется как минимум один раз: при ошибке событие будет передано повторно,
+// поэтому получатели должны быть готовы к дубликатам (см. domain.Event.Sequence).
+type Sink interface {
+	// Name возвращает имя получателя для логов.
+	Name() string
+
+	// Deliver передаёт событие получателю.
+	Deliver(ctx context.Context, event domain.Event) error
+}
+
+// LogSink пишет события в лог.
+type LogSink struct {
+	logger *slog.Logger
+}
+
+// NewLogSink создаёт получателя, который пишет события в лог.
+func NewLogSink(logger *slog.Logger) *LogSink {
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	return &LogSink{logger: logger}
+}
+
+// Name возвращает имя получателя.
+func (s *LogSink) Name() string {
+	return "log"
+}
+
+// Deliver пишет событие в лог в JSON-представлении.
+func (s *LogSink) Deliver(ctx context.Context, e domain.Event) error {
+	payload, err := json.Marshal(event.NewMessage(e))
+	if err != nil {
+		return err
+	}
+
+	s.logger.InfoContext(
+		ctx,
+		"outbox event",
+		slog.Int64("sequence", e.Sequence),
+		slog.String("event", string(e.Type)),
+		slog.String("payload", string(payload)),
+	)
+
+	return nil
+}
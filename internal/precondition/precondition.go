@@ -0,0 +1,18 @@
+// Package precondition передаёт через context версию ресурса, которую ожидает клиент
+// (заголовок If-Match). Сервисный слой сверяет её с текущей версией перед изменением.
+package precondition
+
+import "context"
+
+type versionKey struct{}
+
+// WithVersion возвращает контекст, в котором изменение допускается только для версии version.
+func WithVersion(ctx context.Context, version int64) context.Context {
+	return context.WithValue(ctx, versionKey{}, version)
+}
+
+// Version возвращает ожидаемую версию из контекста. ok == false, если условия нет.
+func Version(ctx context.Context) (version int64, ok bool) {
+	version, ok = ctx.Value(versionKey{}).(int64)
+	return version, ok
+}
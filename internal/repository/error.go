@@ -7,4 +7,8 @@ import "errors"
 var (
 	ErrNotFound      = errors.New("not found")
 	ErrAlreadyExists = errors.New("already exists")
+	// ErrConflict возвращается, если запись изменилась после чтения.
+	ErrConflict = errors.New("version conflict")
+	// ErrNoOrganization возвращается, если в контексте запроса не задана организация.
+	ErrNoOrganization = errors.New("organization is not set in context")
 )
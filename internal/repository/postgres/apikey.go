@@ -0,0 +1,187 @@
+// Package postgres содержит реализацию репозиториев поверх PostgreSQL.
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/dixitix/pr-reviewer-service/internal/domain"
+	"github.com/dixitix/pr-reviewer-service/internal/repository"
+)
+
+// APIKeyRepository реализует repository.APIKeyRepository поверх *sql.DB.
+type APIKeyRepository struct {
+	db *sql.DB
+}
+
+// NewAPIKeyRepository создаёт новый экземпляр APIKeyRepository.
+func NewAPIKeyRepository(db *sql.DB) repository.APIKeyRepository {
+	return &APIKeyRepository{db: db}
+}
+
+// Create сохраняет ключ организации из контекста.
+func (r *APIKeyRepository) Create(ctx context.Context, key domain.APIKey, hash string) error {
+	org, err := organizationID(ctx)
+	if err != nil {
+		return err
+	}
+
+	const query = `
+		INSERT INTO api_keys (id, org_id, name, key_prefix, key_hash, scopes, created_at)
+		VALUES ($1, $2, $3, $4, $5, string_to_array($6, ' '), $7)
+	`
+
+	_, err = dbtx(ctx, r.db).ExecContext(
+		ctx,
+		query,
+		string(key.ID),
+		org,
+		key.Name,
+		key.Prefix,
+		hash,
+		joinScopes(key.Scopes),
+		key.CreatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("insert api_keys: %w", err)
+	}
+
+	return nil
+}
+
+// List возвращает ключи организации из контекста, включая отозванные.
+func (r *APIKeyRepository) List(ctx context.Context) ([]domain.APIKey, error) {
+	org, err := organizationID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	const query = `
+		SELECT id, org_id, name, key_prefix, array_to_string(scopes, ' '), created_at, last_used_at, revoked_at
+		FROM api_keys
+		WHERE org_id = $1
+		ORDER BY created_at, id
+	`
+
+	rows, err := dbtx(ctx, r.db).QueryContext(ctx, query, org)
+	if err != nil {
+		return nil, fmt.Errorf("select api_keys: %w", err)
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	result := make([]domain.APIKey, 0)
+
+	for rows.Next() {
+		key, err := scanAPIKey(rows)
+		if err != nil {
+			return nil, err
+		}
+
+		result = append(result, key)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate api_keys: %w", err)
+	}
+
+	return result, nil
+}
+
+// Revoke отзывает ключ организации из контекста. Повторный отзыв не меняет время отзыва.
+func (r *APIKeyRepository) Revoke(ctx context.Context, id domain.APIKeyID, at time.Time) error {
+	org, err := organizationID(ctx)
+	if err != nil {
+		return err
+	}
+
+	const query = `
+		UPDATE api_keys
+		SET revoked_at = COALESCE(revoked_at, $3)
+		WHERE org_id = $1 AND id = $2
+	`
+
+	res, err := dbtx(ctx, r.db).ExecContext(ctx, query, org, string(id), at)
+	if err != nil {
+		return fmt.Errorf("revoke api key %s: %w", id, err)
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("rows affected: %w", err)
+	}
+
+	if n == 0 {
+		return repository.ErrNotFound
+	}
+
+	return nil
+}
+
+// Authenticate находит действующий ключ по хэшу и отмечает время его использования.
+func (r *APIKeyRepository) Authenticate(ctx context.Context, hash string, at time.Time) (domain.APIKey, error) {
+	const query = `
+		UPDATE api_keys
+		SET last_used_at = $2
+		WHERE key_hash = $1 AND revoked_at IS NULL
+		RETURNING id, org_id, name, key_prefix, array_to_string(scopes, ' '), created_at, last_used_at, revoked_at
+	`
+
+	key, err := scanAPIKey(dbtx(ctx, r.db).QueryRowContext(ctx, query, hash, at))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return domain.APIKey{}, repository.ErrNotFound
+		}
+
+		return domain.APIKey{}, err
+	}
+
+	return key, nil
+}
+
+// scanAPIKey читает ключ из строки результата.
+func scanAPIKey(row interface{ Scan(dest ...any) error }) (domain.APIKey, error) {
+	var (
+		key    domain.APIKey
+		scopes string
+	)
+
+	err := row.Scan(
+		&key.ID,
+		&key.OrganizationID,
+		&key.Name,
+		&key.Prefix,
+		&scopes,
+		&key.CreatedAt,
+		&key.LastUsedAt,
+		&key.RevokedAt,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return domain.APIKey{}, err
+		}
+
+		return domain.APIKey{}, fmt.Errorf("scan api_keys: %w", err)
+	}
+
+	for _, s := range strings.Fields(scopes) {
+		key.Scopes = append(key.Scopes, domain.APIScope(s))
+	}
+
+	return key, nil
+}
+
+// joinScopes склеивает права ключа через пробел для string_to_array.
+func joinScopes(scopes []domain.APIScope) string {
+	result := make([]string, len(scopes))
+	for i, s := range scopes {
+		result[i] = string(s)
+	}
+
+	return strings.Join(result, " ")
+}
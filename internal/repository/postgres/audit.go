@@ -0,0 +1,61 @@
+// Package postgres содержит реализацию репозиториев поверх PostgreSQL.
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+
+	"github.com/dixitix/pr-reviewer-service/internal/domain"
+	"github.com/dixitix/pr-reviewer-service/internal/repository"
+)
+
+// AuditRepository реализует repository.AuditRepository поверх *sql.DB.
+type AuditRepository struct {
+	db *sql.DB
+}
+
+// NewAuditRepository создаёт новый экземпляр AuditRepository.
+func NewAuditRepository(db *sql.DB) repository.AuditRepository {
+	return &AuditRepository{db: db}
+}
+
+// Record записывает операцию в журнал изменений организации из контекста.
+func (r *AuditRepository) Record(ctx context.Context, entry domain.AuditEntry) error {
+	org, err := organizationID(ctx)
+	if err != nil {
+		return err
+	}
+
+	details := entry.Details
+	if details == nil {
+		details = map[string]any{}
+	}
+
+	payload, err := json.Marshal(details)
+	if err != nil {
+		return fmt.Errorf("marshal audit details of %s: %w", entry.Action, err)
+	}
+
+	const query = `
+		INSERT INTO audit_log (org_id, action, target, actor, details, occurred_at)
+		VALUES ($1, $2, $3, NULLIF($4, ''), $5, $6)
+	`
+
+	_, err = dbtx(ctx, r.db).ExecContext(
+		ctx,
+		query,
+		org,
+		string(entry.Action),
+		entry.Target,
+		string(entry.Actor),
+		string(payload),
+		entry.OccurredAt,
+	)
+	if err != nil {
+		return fmt.Errorf("insert audit_log: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,145 @@
+// Package postgres содержит реализацию репозиториев поверх PostgreSQL.
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/dixitix/pr-reviewer-service/internal/repository"
+)
+
+// IdempotencyRepository реализует repository.IdempotencyRepository поверх *sql.DB.
+type IdempotencyRepository struct {
+	db *sql.DB
+}
+
+// NewIdempotencyRepository создаёт новый экземпляр IdempotencyRepository.
+func NewIdempotencyRepository(db *sql.DB) repository.IdempotencyRepository {
+	return &IdempotencyRepository{db: db}
+}
+
+// Reserve занимает ключ или возвращает сохранённую запись. Истёкший ключ занимается заново.
+func (r *IdempotencyRepository) Reserve(
+	ctx context.Context,
+	key, requestHash string,
+	now, expiresAt time.Time,
+) (repository.IdempotencyRecord, bool, error) {
+	org, err := organizationID(ctx)
+	if err != nil {
+		return repository.IdempotencyRecord{}, false, err
+	}
+
+	const reserve = `
+		INSERT INTO idempotency_keys (org_id, key, request_hash, created_at, expires_at)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (org_id, key) DO UPDATE
+		SET request_hash = EXCLUDED.request_hash,
+		    status_code = NULL,
+		    content_type = NULL,
+		    response_body = NULL,
+		    created_at = EXCLUDED.created_at,
+		    expires_at = EXCLUDED.expires_at
+		WHERE idempotency_keys.expires_at <= EXCLUDED.created_at
+		RETURNING key
+	`
+
+	var reserved string
+
+	err = dbtx(ctx, r.db).QueryRowContext(ctx, reserve, org, key, requestHash, now, expiresAt).Scan(&reserved)
+	if err == nil {
+		return repository.IdempotencyRecord{Key: key, RequestHash: requestHash}, true, nil
+	}
+
+	if !errors.Is(err, sql.ErrNoRows) {
+		return repository.IdempotencyRecord{}, false, fmt.Errorf("reserve idempotency_keys: %w", err)
+	}
+
+	const selectRecord = `
+		SELECT request_hash, status_code, COALESCE(content_type, ''), response_body
+		FROM idempotency_keys
+		WHERE org_id = $1 AND key = $2
+	`
+
+	var (
+		rec    = repository.IdempotencyRecord{Key: key}
+		status sql.NullInt64
+	)
+
+	err = dbtx(ctx, r.db).QueryRowContext(ctx, selectRecord, org, key).Scan(&rec.RequestHash, &status, &rec.ContentType, &rec.Body)
+	if err != nil {
+		return repository.IdempotencyRecord{}, false, fmt.Errorf("select idempotency_keys: %w", err)
+	}
+
+	rec.Completed = status.Valid
+	rec.StatusCode = int(status.Int64)
+
+	return rec, false, nil
+}
+
+// Complete сохраняет ответ на запрос.
+func (r *IdempotencyRepository) Complete(
+	ctx context.Context,
+	key string,
+	statusCode int,
+	contentType string,
+	body []byte,
+) error {
+	org, err := organizationID(ctx)
+	if err != nil {
+		return err
+	}
+
+	const query = `
+		UPDATE idempotency_keys
+		SET status_code = $3, content_type = $4, response_body = $5
+		WHERE org_id = $1 AND key = $2
+	`
+
+	if _, err := dbtx(ctx, r.db).ExecContext(ctx, query, org, key, statusCode, contentType, body); err != nil {
+		return fmt.Errorf("update idempotency_keys: %w", err)
+	}
+
+	return nil
+}
+
+// Release удаляет незавершённый ключ.
+func (r *IdempotencyRepository) Release(ctx context.Context, key string) error {
+	org, err := organizationID(ctx)
+	if err != nil {
+		return err
+	}
+
+	const query = `
+		DELETE FROM idempotency_keys
+		WHERE org_id = $1 AND key = $2 AND status_code IS NULL
+	`
+
+	if _, err := dbtx(ctx, r.db).ExecContext(ctx, query, org, key); err != nil {
+		return fmt.Errorf("delete idempotency_keys: %w", err)
+	}
+
+	return nil
+}
+
+// DeleteExpired удаляет истёкшие ключи всех организаций.
+func (r *IdempotencyRepository) DeleteExpired(ctx context.Context, now time.Time) (int64, error) {
+	const query = `
+		DELETE FROM idempotency_keys
+		WHERE expires_at <= $1
+	`
+
+	res, err := dbtx(ctx, r.db).ExecContext(ctx, query, now)
+	if err != nil {
+		return 0, fmt.Errorf("delete expired idempotency_keys: %w", err)
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("rows affected: %w", err)
+	}
+
+	return n, nil
+}
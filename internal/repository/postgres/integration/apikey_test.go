@@ -0,0 +1,68 @@
+// Package postgres_test содержит интеграционные тесты репозиториев.
+package integration
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/dixitix/pr-reviewer-service/internal/domain"
+	"github.com/dixitix/pr-reviewer-service/internal/repository"
+	"github.com/dixitix/pr-reviewer-service/internal/repository/postgres"
+)
+
+// TestAPIKeyRepository_Lifecycle проверяет сохранение, аутентификацию по хэшу и отзыв ключа.
+func TestAPIKeyRepository_Lifecycle(t *testing.T) {
+	db := openTestDB(t)
+	truncateAllTables(t, db)
+
+	repo := postgres.NewAPIKeyRepository(db)
+	ctx := testContext()
+	now := time.Now().UTC().Truncate(time.Second)
+
+	key := domain.APIKey{
+		ID:        "key-1",
+		Name:      "ci",
+		Prefix:    "prk_01234567",
+		Scopes:    []domain.APIScope{domain.APIScopeRead, domain.APIScopePRWrite},
+		CreatedAt: now,
+	}
+
+	if err := repo.Create(ctx, key, "hash-1"); err != nil {
+		t.Fatalf("Create returned error: %v", err)
+	}
+
+	got, err := repo.Authenticate(ctx, "hash-1", now.Add(time.Minute))
+	if err != nil {
+		t.Fatalf("Authenticate returned error: %v", err)
+	}
+
+	if got.OrganizationID != testOrg || len(got.Scopes) != 2 || got.LastUsedAt == nil {
+		t.Fatalf("unexpected key: %+v", got)
+	}
+
+	if _, err := repo.Authenticate(ctx, "hash-2", now); !errors.Is(err, repository.ErrNotFound) {
+		t.Fatalf("expected ErrNotFound for unknown hash, got %v", err)
+	}
+
+	if err := repo.Revoke(ctx, "key-1", now); err != nil {
+		t.Fatalf("Revoke returned error: %v", err)
+	}
+
+	if _, err := repo.Authenticate(ctx, "hash-1", now); !errors.Is(err, repository.ErrNotFound) {
+		t.Fatalf("expected ErrNotFound for revoked key, got %v", err)
+	}
+
+	keys, err := repo.List(ctx)
+	if err != nil {
+		t.Fatalf("List returned error: %v", err)
+	}
+
+	if len(keys) != 1 || keys[0].RevokedAt == nil {
+		t.Fatalf("expected revoked key in list, got %+v", keys)
+	}
+
+	if err := repo.Revoke(ctx, "missing", now); !errors.Is(err, repository.ErrNotFound) {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+}
@@ -0,0 +1,74 @@
+package integration
+
+import (
+	"testing"
+	"time"
+
+	"github.com/dixitix/pr-reviewer-service/internal/domain"
+	"github.com/dixitix/pr-reviewer-service/internal/repository/postgres"
+)
+
+// TestAuditRepository_Record проверяет запись операции в журнал организации из контекста.
+func TestAuditRepository_Record(t *testing.T) {
+	db := openTestDB(t)
+	truncateAllTables(t, db)
+
+	repo := postgres.NewAuditRepository(db)
+	ctx := testContext()
+	now := time.Now().UTC().Truncate(time.Second)
+
+	entries := []domain.AuditEntry{
+		{
+			Action:     domain.AuditTeamLeadsSet,
+			Target:     "backend",
+			Actor:      "user:u1",
+			Details:    map[string]any{"leads": []domain.UserID{"u1"}},
+			OccurredAt: now,
+		},
+		{Action: domain.AuditAPIKeyRevoked, Target: "k1", OccurredAt: now},
+	}
+
+	for _, e := range entries {
+		if err := repo.Record(ctx, e); err != nil {
+			t.Fatalf("Record returned error: %v", err)
+		}
+	}
+
+	rows, err := db.Query(`SELECT org_id, action, target, actor, details::text FROM audit_log ORDER BY id`)
+	if err != nil {
+		t.Fatalf("select audit_log: %v", err)
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	type row struct {
+		org, action, target string
+		actor               *string
+		details             string
+	}
+
+	var got []row
+
+	for rows.Next() {
+		var r row
+		if err := rows.Scan(&r.org, &r.action, &r.target, &r.actor, &r.details); err != nil {
+			t.Fatalf("scan audit_log: %v", err)
+		}
+
+		got = append(got, r)
+	}
+
+	if len(got) != 2 {
+		t.Fatalf("expected 2 audit entries, got %+v", got)
+	}
+
+	if got[0].org != string(testOrg) || got[0].action != "team.leads_set" || got[0].actor == nil ||
+		*got[0].actor != "user:u1" || got[0].details != `{"leads": ["u1"]}` {
+		t.Fatalf("unexpected first entry: %+v", got[0])
+	}
+
+	if got[1].actor != nil || got[1].details != "{}" {
+		t.Fatalf("expected entry without actor and details, got %+v", got[1])
+	}
+}
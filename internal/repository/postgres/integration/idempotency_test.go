@@ -0,0 +1,53 @@
+package integration
+
+import (
+	"testing"
+	"time"
+
+	"github.com/dixitix/pr-reviewer-service/internal/repository/postgres"
+)
+
+// TestIdempotencyRepository_Lifecycle проверяет резервирование, сохранение ответа, освобождение и истечение ключа.
+func TestIdempotencyRepository_Lifecycle(t *testing.T) {
+	db := openTestDB(t)
+	truncateAllTables(t, db)
+
+	repo := postgres.NewIdempotencyRepository(db)
+	ctx := testContext()
+	now := time.Now().UTC().Truncate(time.Second)
+
+	if _, reserved, err := repo.Reserve(ctx, "key-1", "hash-1", now, now.Add(time.Hour)); err != nil || !reserved {
+		t.Fatalf("Reserve: reserved=%v err=%v", reserved, err)
+	}
+
+	rec, reserved, err := repo.Reserve(ctx, "key-1", "hash-2", now, now.Add(time.Hour))
+	if err != nil || reserved || rec.Completed || rec.RequestHash != "hash-1" {
+		t.Fatalf("expected in-progress record, got %+v reserved=%v err=%v", rec, reserved, err)
+	}
+
+	if err := repo.Complete(ctx, "key-1", 201, "application/json", []byte(`{"ok":true}`)); err != nil {
+		t.Fatalf("Complete returned error: %v", err)
+	}
+
+	rec, reserved, err = repo.Reserve(ctx, "key-1", "hash-1", now, now.Add(time.Hour))
+	if err != nil || reserved || !rec.Completed || rec.StatusCode != 201 || string(rec.Body) != `{"ok":true}` {
+		t.Fatalf("expected completed record, got %+v reserved=%v err=%v", rec, reserved, err)
+	}
+
+	if err := repo.Release(ctx, "key-1"); err != nil {
+		t.Fatalf("Release returned error: %v", err)
+	}
+
+	if _, reserved, _ := repo.Reserve(ctx, "key-1", "hash-1", now, now.Add(time.Hour)); reserved {
+		t.Fatalf("completed key must not be released")
+	}
+
+	if _, reserved, err := repo.Reserve(ctx, "key-1", "hash-3", now.Add(2*time.Hour), now.Add(3*time.Hour)); err != nil || !reserved {
+		t.Fatalf("expected expired key to be reserved again: reserved=%v err=%v", reserved, err)
+	}
+
+	deleted, err := repo.DeleteExpired(ctx, now.Add(4*time.Hour))
+	if err != nil || deleted != 1 {
+		t.Fatalf("DeleteExpired: deleted=%d err=%v", deleted, err)
+	}
+}
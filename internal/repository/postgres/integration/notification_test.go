@@ -0,0 +1,141 @@
+// Package postgres_test содержит интеграционные тесты репозиториев.
+package integration
+
+import (
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/dixitix/pr-reviewer-service/internal/domain"
+	"github.com/dixitix/pr-reviewer-service/internal/repository"
+	"github.com/dixitix/pr-reviewer-service/internal/repository/postgres"
+)
+
+// newTestNotificationRepository создаёт NotificationRepository поверх тестовой БД.
+func newTestNotificationRepository(t *testing.T) (*sql.DB, repository.NotificationRepository) {
+	db := openTestDB(t)
+	truncateAllTables(t, db)
+
+	return db, postgres.NewNotificationRepository(db)
+}
+
+// TestNotificationRepository_Preferences проверяет настройки по умолчанию и их обновление.
+func TestNotificationRepository_Preferences(t *testing.T) {
+	db, repo := newTestNotificationRepository(t)
+	ctx := testContext()
+
+	insertTeam(t, db, "backend")
+	insertUser(t, db, "u1", "alice", "backend", true)
+
+	prefs, err := repo.GetPreferences(ctx, "u1")
+	if err != nil {
+		t.Fatalf("GetPreferences returned error: %v", err)
+	}
+
+	if !prefs.ChatEnabled {
+		t.Fatalf("expected chat enabled by default, got %+v", prefs)
+	}
+
+	if err := repo.UpsertPreferences(ctx, domain.NotificationPreferences{UserID: "u1", ChatEnabled: false}); err != nil {
+		t.Fatalf("UpsertPreferences returned error: %v", err)
+	}
+
+	prefs, err = repo.GetPreferences(ctx, "u1")
+	if err != nil {
+		t.Fatalf("GetPreferences returned error: %v", err)
+	}
+
+	if prefs.ChatEnabled {
+		t.Fatalf("expected chat disabled, got %+v", prefs)
+	}
+}
+
+// TestNotificationRepository_Digests проверяет выборку подписчиков сводки и однократную отметку за день.
+func TestNotificationRepository_Digests(t *testing.T) {
+	db, repo := newTestNotificationRepository(t)
+	ctx := testContext()
+
+	insertTeam(t, db, "backend")
+	insertUser(t, db, "u1", "alice", "backend", true)
+	insertUser(t, db, "u2", "bob", "backend", true)
+
+	for _, prefs := range []domain.NotificationPreferences{
+		{UserID: "u1", ChatEnabled: true, Email: "alice@example.com", EmailMode: domain.EmailModeDigest},
+		{UserID: "u2", ChatEnabled: true, Email: "bob@example.com", EmailMode: domain.EmailModeImmediate},
+	} {
+		if err := repo.UpsertPreferences(ctx, prefs); err != nil {
+			t.Fatalf("UpsertPreferences returned error: %v", err)
+		}
+	}
+
+	subscribers, err := repo.ListPreferencesByEmailMode(ctx, domain.EmailModeDigest)
+	if err != nil {
+		t.Fatalf("ListPreferencesByEmailMode returned error: %v", err)
+	}
+
+	if len(subscribers) != 1 || subscribers[0].UserID != "u1" || subscribers[0].Email != "alice@example.com" {
+		t.Fatalf("unexpected digest subscribers: %+v", subscribers)
+	}
+
+	day := time.Date(2024, 5, 10, 0, 0, 0, 0, time.UTC)
+
+	for i, want := range []bool{true, false} {
+		claimed, err := repo.ClaimDigest(ctx, "u1", day)
+		if err != nil {
+			t.Fatalf("ClaimDigest returned error: %v", err)
+		}
+
+		if claimed != want {
+			t.Fatalf("ClaimDigest #%d = %t, want %t", i+1, claimed, want)
+		}
+	}
+
+	if err := repo.ReleaseDigest(ctx, "u1", day); err != nil {
+		t.Fatalf("ReleaseDigest returned error: %v", err)
+	}
+
+	claimed, err := repo.ClaimDigest(ctx, "u1", day)
+	if err != nil || !claimed {
+		t.Fatalf("expected digest to be claimable after release, got %t, %v", claimed, err)
+	}
+}
+
+// TestNotificationRepository_TeamChatChannel проверяет сохранение, обновление и удаление канала команды.
+func TestNotificationRepository_TeamChatChannel(t *testing.T) {
+	db, repo := newTestNotificationRepository(t)
+	ctx := testContext()
+
+	insertTeam(t, db, "backend")
+
+	if _, err := repo.GetTeamChatChannel(ctx, "backend"); !errors.Is(err, repository.ErrNotFound) {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+
+	channel := domain.TeamChatChannel{TeamName: "backend", WebhookURL: "http://chat/hook", Channel: "#backend"}
+	if err := repo.UpsertTeamChatChannel(ctx, channel); err != nil {
+		t.Fatalf("UpsertTeamChatChannel returned error: %v", err)
+	}
+
+	channel.Channel = "#reviews"
+	if err := repo.UpsertTeamChatChannel(ctx, channel); err != nil {
+		t.Fatalf("UpsertTeamChatChannel returned error: %v", err)
+	}
+
+	got, err := repo.GetTeamChatChannel(ctx, "backend")
+	if err != nil {
+		t.Fatalf("GetTeamChatChannel returned error: %v", err)
+	}
+
+	if got != channel {
+		t.Fatalf("channel mismatch: got %+v, want %+v", got, channel)
+	}
+
+	if err := repo.DeleteTeamChatChannel(ctx, "backend"); err != nil {
+		t.Fatalf("DeleteTeamChatChannel returned error: %v", err)
+	}
+
+	if err := repo.DeleteTeamChatChannel(ctx, "backend"); !errors.Is(err, repository.ErrNotFound) {
+		t.Fatalf("expected ErrNotFound on second delete, got %v", err)
+	}
+}
@@ -0,0 +1,121 @@
+// Package postgres_test содержит интеграционные тесты репозиториев.
+package integration
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/dixitix/pr-reviewer-service/internal/domain"
+	"github.com/dixitix/pr-reviewer-service/internal/repository"
+	"github.com/dixitix/pr-reviewer-service/internal/repository/postgres"
+	"github.com/dixitix/pr-reviewer-service/internal/tenant"
+)
+
+// TestOrganizationRepository_CreateAndExists проверяет создание организации и проверку существования.
+func TestOrganizationRepository_CreateAndExists(t *testing.T) {
+	db := openTestDB(t)
+	truncateAllTables(t, db)
+
+	repo := postgres.NewOrganizationRepository(db)
+	ctx := context.Background()
+
+	org := domain.Organization{ID: "acme", Name: "Acme", CreatedAt: time.Now().UTC()}
+
+	if err := repo.Create(ctx, org); err != nil {
+		t.Fatalf("Create returned error: %v", err)
+	}
+
+	if err := repo.Create(ctx, org); !errors.Is(err, repository.ErrAlreadyExists) {
+		t.Fatalf("expected ErrAlreadyExists, got %v", err)
+	}
+
+	ok, err := repo.Exists(ctx, "acme")
+	if err != nil || !ok {
+		t.Fatalf("Exists(acme) = %v, %v; want true", ok, err)
+	}
+
+	ok, err = repo.Exists(ctx, "missing")
+	if err != nil || ok {
+		t.Fatalf("Exists(missing) = %v, %v; want false", ok, err)
+	}
+
+	orgs, err := repo.List(ctx)
+	if err != nil {
+		t.Fatalf("List returned error: %v", err)
+	}
+
+	if len(orgs) != 2 || orgs[0].ID != "acme" || orgs[1].ID != testOrg {
+		t.Fatalf("unexpected organizations: %+v", orgs)
+	}
+}
+
+// TestRepositories_TenantIsolation проверяет, что одинаковые идентификаторы в разных организациях
+// не пересекаются, а данные другой организации недоступны.
+func TestRepositories_TenantIsolation(t *testing.T) {
+	db := openTestDB(t)
+	truncateAllTables(t, db)
+	insertOrganization(t, db, "acme")
+
+	teamRepo := postgres.NewTeamRepository(db)
+	userRepo := postgres.NewUserRepository(db)
+	prRepo := postgres.NewPullRequestRepository(db)
+
+	defaultCtx := testContext()
+	acmeCtx := tenant.WithOrganization(context.Background(), "acme")
+
+	for _, tc := range []struct {
+		ctx      context.Context
+		username string
+	}{
+		{ctx: defaultCtx, username: "alice"},
+		{ctx: acmeCtx, username: "alice-acme"},
+	} {
+		if err := teamRepo.CreateTeam(tc.ctx, domain.Team{Name: "backend"}); err != nil {
+			t.Fatalf("CreateTeam returned error: %v", err)
+		}
+
+		err := teamRepo.UpsertMembers(tc.ctx, "backend", []domain.User{
+			{ID: "u1", Username: tc.username, TeamName: "backend", IsActive: true},
+		})
+		if err != nil {
+			t.Fatalf("UpsertMembers returned error: %v", err)
+		}
+	}
+
+	user, err := userRepo.GetByID(acmeCtx, "u1")
+	if err != nil {
+		t.Fatalf("GetByID returned error: %v", err)
+	}
+
+	if user.Username != "alice-acme" {
+		t.Fatalf("expected user of acme, got %+v", user)
+	}
+
+	pr := domain.PullRequest{ID: "pr-1", Name: "Add feature", AuthorID: "u1", Status: domain.PullRequestStatusOpen}
+	if err := prRepo.Create(defaultCtx, pr); err != nil {
+		t.Fatalf("Create returned error: %v", err)
+	}
+
+	if _, err := prRepo.GetByID(acmeCtx, "pr-1"); !errors.Is(err, repository.ErrNotFound) {
+		t.Fatalf("expected ErrNotFound for pull request of another organization, got %v", err)
+	}
+
+	if err := userRepo.SetActive(acmeCtx, "u1", false); err != nil {
+		t.Fatalf("SetActive returned error: %v", err)
+	}
+
+	user, err = userRepo.GetByID(defaultCtx, "u1")
+	if err != nil {
+		t.Fatalf("GetByID returned error: %v", err)
+	}
+
+	if !user.IsActive {
+		t.Fatalf("user of default organization changed by acme")
+	}
+
+	if _, err := userRepo.GetByID(context.Background(), "u1"); !errors.Is(err, repository.ErrNoOrganization) {
+		t.Fatalf("expected ErrNoOrganization without organization, got %v", err)
+	}
+}
@@ -0,0 +1,152 @@
+// Package postgres_test содержит интеграционные тесты репозиториев.
+package integration
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/dixitix/pr-reviewer-service/internal/domain"
+	"github.com/dixitix/pr-reviewer-service/internal/repository"
+	"github.com/dixitix/pr-reviewer-service/internal/repository/postgres"
+)
+
+// TestOutboxRepository_Lifecycle проверяет запись событий вместе с PR, захват и отметку публикации.
+func TestOutboxRepository_Lifecycle(t *testing.T) {
+	db := openTestDB(t)
+	truncateAllTables(t, db)
+
+	prRepo := postgres.NewPullRequestRepository(db)
+	outboxRepo := postgres.NewOutboxRepository(db)
+	ctx := testContext()
+
+	insertTeam(t, db, "backend")
+	insertUser(t, db, "author-1", "author", "backend", true)
+	insertUser(t, db, "reviewer-1", "r1", "backend", true)
+
+	now := time.Now().UTC().Truncate(time.Second)
+
+	pr := domain.PullRequest{
+		ID:                "pr-1",
+		Name:              "Add feature",
+		AuthorID:          "author-1",
+		Status:            domain.PullRequestStatusOpen,
+		AssignedReviewers: []domain.UserID{"reviewer-1"},
+		CreatedAt:         &now,
+		Version:           1,
+	}
+
+	assigned := domain.Event{
+		Type:           domain.EventReviewersAssigned,
+		PullRequest:    pr,
+		AddedReviewers: pr.AssignedReviewers,
+		OccurredAt:     now,
+	}
+
+	if err := prRepo.Create(ctx, pr, assigned); err != nil {
+		t.Fatalf("Create returned error: %v", err)
+	}
+
+	pr.Status = domain.PullRequestStatusMerged
+	pr.MergedAt = &now
+
+	merged := domain.Event{Type: domain.EventPullRequestMerged, PullRequest: pr, OccurredAt: now}
+
+	if err := prRepo.Update(ctx, pr, merged); err != nil {
+		t.Fatalf("Update returned error: %v", err)
+	}
+
+	records, err := outboxRepo.ClaimPending(ctx, 10, time.Minute)
+	if err != nil {
+		t.Fatalf("ClaimPending returned error: %v", err)
+	}
+
+	if len(records) != 2 {
+		t.Fatalf("expected 2 outbox records, got %d", len(records))
+	}
+
+	first, second := records[0].Event, records[1].Event
+	if first.Type != domain.EventReviewersAssigned || second.Type != domain.EventPullRequestMerged {
+		t.Fatalf("unexpected event order: %s, %s", first.Type, second.Type)
+	}
+
+	if first.Sequence >= second.Sequence {
+		t.Fatalf("expected increasing sequence, got %d and %d", first.Sequence, second.Sequence)
+	}
+
+	if len(first.AddedReviewers) != 1 || first.AddedReviewers[0] != "reviewer-1" {
+		t.Fatalf("unexpected added reviewers: %v", first.AddedReviewers)
+	}
+
+	// Захваченные события скрыты от повторного захвата на время аренды.
+	again, err := outboxRepo.ClaimPending(ctx, 10, time.Minute)
+	if err != nil {
+		t.Fatalf("ClaimPending returned error: %v", err)
+	}
+
+	if len(again) != 0 {
+		t.Fatalf("expected leased events to be hidden, got %d", len(again))
+	}
+
+	if err := outboxRepo.MarkPublished(ctx, first.Sequence); err != nil {
+		t.Fatalf("MarkPublished returned error: %v", err)
+	}
+
+	if err := outboxRepo.MarkFailed(ctx, second.Sequence, "sink down", time.Now().Add(-time.Second)); err != nil {
+		t.Fatalf("MarkFailed returned error: %v", err)
+	}
+
+	retried, err := outboxRepo.ClaimPending(ctx, 10, time.Minute)
+	if err != nil {
+		t.Fatalf("ClaimPending returned error: %v", err)
+	}
+
+	if len(retried) != 1 || retried[0].Event.Sequence != second.Sequence || retried[0].Attempts != 1 {
+		t.Fatalf("expected failed event to be claimed again, got %+v", retried)
+	}
+
+	deleted, err := outboxRepo.DeletePublishedBefore(ctx, time.Now().Add(time.Minute))
+	if err != nil {
+		t.Fatalf("DeletePublishedBefore returned error: %v", err)
+	}
+
+	if deleted != 1 {
+		t.Fatalf("expected 1 deleted event, got %d", deleted)
+	}
+
+	if err := outboxRepo.MarkPublished(ctx, first.Sequence); !errors.Is(err, repository.ErrNotFound) {
+		t.Fatalf("expected ErrNotFound for deleted event, got %v", err)
+	}
+}
+
+// TestOutboxRepository_RollbackDropsEvents проверяет, что события не записываются, если PR не сохранён.
+func TestOutboxRepository_RollbackDropsEvents(t *testing.T) {
+	db := openTestDB(t)
+	truncateAllTables(t, db)
+
+	prRepo := postgres.NewPullRequestRepository(db)
+	ctx := testContext()
+
+	insertTeam(t, db, "backend")
+	insertUser(t, db, "author-1", "author", "backend", true)
+
+	pr := domain.PullRequest{ID: "pr-1", Name: "Add feature", AuthorID: "author-1", Status: domain.PullRequestStatusOpen}
+
+	if err := prRepo.Create(ctx, pr); err != nil {
+		t.Fatalf("Create returned error: %v", err)
+	}
+
+	err := prRepo.Create(ctx, pr, domain.Event{Type: domain.EventReviewersAssigned, PullRequest: pr, OccurredAt: time.Now()})
+	if err == nil {
+		t.Fatalf("expected error for duplicate pull request")
+	}
+
+	var count int
+	if err := db.QueryRowContext(ctx, `SELECT count(*) FROM outbox_events`).Scan(&count); err != nil {
+		t.Fatalf("count outbox_events: %v", err)
+	}
+
+	if count != 0 {
+		t.Fatalf("expected no outbox events after failed create, got %d", count)
+	}
+}
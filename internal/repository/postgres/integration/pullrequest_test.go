@@ -2,7 +2,6 @@
 package integration
 
 import (
-	"context"
 	"database/sql"
 	"errors"
 	"testing"
@@ -28,7 +27,7 @@ func TestPullRequestRepository_CreateAndGet(t *testing.T) {
 		_ = db.Close()
 	}()
 
-	ctx := context.Background()
+	ctx := testContext()
 
 	const (
 		teamName    = "backend"
@@ -100,7 +99,7 @@ func TestPullRequestRepository_Update(t *testing.T) {
 		_ = db.Close()
 	}()
 
-	ctx := context.Background()
+	ctx := testContext()
 
 	const (
 		teamName    = "backend"
@@ -125,6 +124,8 @@ func TestPullRequestRepository_Update(t *testing.T) {
 		Status:            domain.PullRequestStatusOpen,
 		AssignedReviewers: []domain.UserID{domain.UserID(reviewer1), domain.UserID(reviewer2)},
 		CreatedAt:         &createdAt,
+		CreatedBy:         "api_key:ci",
+		Version:           1,
 	}
 
 	if err := repo.Create(ctx, pr); err != nil {
@@ -134,6 +135,7 @@ func TestPullRequestRepository_Update(t *testing.T) {
 	mergedAt := time.Now().UTC().Truncate(time.Second)
 	pr.Status = domain.PullRequestStatusMerged
 	pr.MergedAt = &mergedAt
+	pr.MergedBy = "user:" + authorID
 	pr.AssignedReviewers = []domain.UserID{domain.UserID(reviewer2)}
 
 	if err := repo.Update(ctx, pr); err != nil {
@@ -157,6 +159,18 @@ func TestPullRequestRepository_Update(t *testing.T) {
 	if got.AssignedReviewers[0] != domain.UserID(reviewer2) {
 		t.Fatalf("AssignedReviewers[0] mismatch: got %q, want %q", got.AssignedReviewers[0], reviewer2)
 	}
+	if got.CreatedBy != pr.CreatedBy || got.MergedBy != pr.MergedBy || got.ReassignedBy != "" {
+		t.Fatalf("actors mismatch after update: got %q/%q/%q", got.CreatedBy, got.MergedBy, got.ReassignedBy)
+	}
+	if got.Version != 2 {
+		t.Fatalf("Version after update: got %d, want 2", got.Version)
+	}
+
+	// Повторное обновление по устаревшей версии не применяется.
+	pr.AssignedReviewers = nil
+	if err := repo.Update(ctx, pr); !errors.Is(err, repository.ErrConflict) {
+		t.Fatalf("expected ErrConflict for stale version, got: %v", err)
+	}
 }
 
 // TestPullRequestRepository_Update_NotFound проверяет, что обновление несуществующего pull request возвращает ErrNotFound.
@@ -166,7 +180,7 @@ func TestPullRequestRepository_Update_NotFound(t *testing.T) {
 		_ = db.Close()
 	}()
 
-	ctx := context.Background()
+	ctx := testContext()
 
 	pr := domain.PullRequest{
 		ID:       domain.PullRequestID("non-existent"),
@@ -188,7 +202,7 @@ func TestPullRequestRepository_ListByReviewer(t *testing.T) {
 		_ = db.Close()
 	}()
 
-	ctx := context.Background()
+	ctx := testContext()
 
 	const (
 		teamName   = "backend"
@@ -259,3 +273,38 @@ func TestPullRequestRepository_ListByReviewer(t *testing.T) {
 		t.Fatalf("unexpected reviewers in pr2: %+v", got2.AssignedReviewers)
 	}
 }
+
+func TestPullRequestRepository_CountOpenReviewsByTeam(t *testing.T) {
+	db, repo := newTestPullRequestRepository(t)
+	defer func() {
+		_ = db.Close()
+	}()
+
+	ctx := testContext()
+
+	insertTeam(t, db, "backend")
+	insertTeam(t, db, "frontend")
+	insertUser(t, db, "a1", "a1", "backend", true)
+	insertUser(t, db, "r1", "r1", "backend", true)
+	insertUser(t, db, "r2", "r2", "frontend", true)
+
+	now := time.Now().UTC().Truncate(time.Second)
+
+	for _, pr := range []domain.PullRequest{
+		{ID: "pr-open", Name: "open", AuthorID: "a1", Status: domain.PullRequestStatusOpen, AssignedReviewers: []domain.UserID{"r1", "r2"}, CreatedAt: &now},
+		{ID: "pr-merged", Name: "merged", AuthorID: "a1", Status: domain.PullRequestStatusMerged, AssignedReviewers: []domain.UserID{"r1"}, CreatedAt: &now, MergedAt: &now},
+	} {
+		if err := repo.Create(ctx, pr); err != nil {
+			t.Fatalf("Create %s returned error: %v", pr.ID, err)
+		}
+	}
+
+	counts, err := repo.CountOpenReviewsByTeam(ctx)
+	if err != nil {
+		t.Fatalf("CountOpenReviewsByTeam returned error: %v", err)
+	}
+
+	if len(counts) != 2 || counts["backend"] != 1 || counts["frontend"] != 1 {
+		t.Fatalf("unexpected open reviews: %+v", counts)
+	}
+}
@@ -0,0 +1,251 @@
+// Package postgres_test содержит интеграционные тесты репозиториев.
+package integration
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/dixitix/pr-reviewer-service/internal/domain"
+	"github.com/dixitix/pr-reviewer-service/internal/repository"
+)
+
+// TestTeamRepository_ReviewPolicy проверяет создание, обновление и выборку SLA ревью команд.
+func TestTeamRepository_ReviewPolicy(t *testing.T) {
+	db, repo := newTestTeamRepository(t)
+	defer func() {
+		_ = db.Close()
+	}()
+
+	ctx := testContext()
+
+	insertTeam(t, db, "backend")
+
+	if _, err := repo.GetReviewPolicy(ctx, "backend"); !errors.Is(err, repository.ErrNotFound) {
+		t.Fatalf("expected ErrNotFound before upsert, got %v", err)
+	}
+
+	policy := domain.ReviewPolicy{
+		TeamName:      "backend",
+		ReminderAfter: 4 * time.Hour,
+		EscalateAfter: 24 * time.Hour,
+		Action:        domain.EscalationActionReassign,
+	}
+
+	if err := repo.UpsertReviewPolicy(ctx, policy); err != nil {
+		t.Fatalf("UpsertReviewPolicy returned error: %v", err)
+	}
+
+	policy.Action = domain.EscalationActionEscalate
+	if err := repo.UpsertReviewPolicy(ctx, policy); err != nil {
+		t.Fatalf("UpsertReviewPolicy (update) returned error: %v", err)
+	}
+
+	got, err := repo.GetReviewPolicy(ctx, "backend")
+	if err != nil {
+		t.Fatalf("GetReviewPolicy returned error: %v", err)
+	}
+
+	if got != policy {
+		t.Fatalf("unexpected policy: got %+v, want %+v", got, policy)
+	}
+
+	policies, err := repo.ListReviewPolicies(ctx)
+	if err != nil {
+		t.Fatalf("ListReviewPolicies returned error: %v", err)
+	}
+
+	if len(policies) != 1 || policies[0] != policy {
+		t.Fatalf("unexpected policies: %+v", policies)
+	}
+}
+
+// TestPullRequestRepository_ReviewAssignments проверяет выборку открытых назначений,
+// отметки напоминания и эскалации и историю PR.
+func TestPullRequestRepository_ReviewAssignments(t *testing.T) {
+	db, repo := newTestPullRequestRepository(t)
+	defer func() {
+		_ = db.Close()
+	}()
+
+	ctx := testContext()
+
+	insertTeam(t, db, "backend")
+	insertUser(t, db, "u1", "alice", "backend", true)
+	insertUser(t, db, "u2", "bob", "backend", true)
+
+	now := time.Now().UTC().Truncate(time.Second)
+
+	pr := domain.PullRequest{
+		ID:                "pr-1",
+		Name:              "Add search",
+		AuthorID:          "u1",
+		Status:            domain.PullRequestStatusOpen,
+		AssignedReviewers: []domain.UserID{"u2"},
+		CreatedAt:         &now,
+		Version:           1,
+	}
+
+	created := domain.Event{Type: domain.EventReviewersAssigned, PullRequest: pr, AddedReviewers: pr.AssignedReviewers, OccurredAt: now}
+	if err := repo.Create(ctx, pr, created); err != nil {
+		t.Fatalf("Create returned error: %v", err)
+	}
+
+	assignments, err := repo.ListOpenAssignments(ctx, now.Add(-time.Hour))
+	if err != nil {
+		t.Fatalf("ListOpenAssignments returned error: %v", err)
+	}
+
+	if len(assignments) != 0 {
+		t.Fatalf("expected no assignments older than an hour, got %+v", assignments)
+	}
+
+	assignments, err = repo.ListOpenAssignments(ctx, now.Add(time.Hour))
+	if err != nil {
+		t.Fatalf("ListOpenAssignments returned error: %v", err)
+	}
+
+	if len(assignments) != 1 || assignments[0].ReviewerID != "u2" || assignments[0].TeamName != "backend" {
+		t.Fatalf("unexpected assignments: %+v", assignments)
+	}
+
+	remindedAt := now.Add(time.Minute)
+	reminder := domain.Event{
+		Type:        domain.EventReviewReminder,
+		PullRequest: pr,
+		Reviewer:    "u2",
+		Reason:      "review pending",
+		OccurredAt:  remindedAt,
+	}
+
+	if err := repo.MarkReminded(ctx, pr.ID, "u2", remindedAt, reminder); err != nil {
+		t.Fatalf("MarkReminded returned error: %v", err)
+	}
+
+	if err := repo.MarkEscalated(ctx, pr.ID, "u2", remindedAt); err != nil {
+		t.Fatalf("MarkEscalated returned error: %v", err)
+	}
+
+	if err := repo.MarkReminded(ctx, pr.ID, "u1", remindedAt); !errors.Is(err, repository.ErrNotFound) {
+		t.Fatalf("expected ErrNotFound for unassigned reviewer, got %v", err)
+	}
+
+	assignments, err = repo.ListOpenAssignments(ctx, now.Add(time.Hour))
+	if err != nil {
+		t.Fatalf("ListOpenAssignments returned error: %v", err)
+	}
+
+	a := assignments[0]
+	if a.RemindedAt == nil || !a.RemindedAt.Equal(remindedAt) || a.EscalatedAt == nil {
+		t.Fatalf("expected reminded and escalated assignment, got %+v", a)
+	}
+
+	history, err := repo.ListHistory(ctx, pr.ID)
+	if err != nil {
+		t.Fatalf("ListHistory returned error: %v", err)
+	}
+
+	if len(history) != 2 || history[0].Type != domain.EventReviewersAssigned || history[1].Reviewer != "u2" {
+		t.Fatalf("unexpected history: %+v", history)
+	}
+
+	// Слияние PR убирает его назначения из выборки.
+	pr.Status = domain.PullRequestStatusMerged
+	if err := repo.Update(ctx, pr); err != nil {
+		t.Fatalf("Update returned error: %v", err)
+	}
+
+	assignments, err = repo.ListOpenAssignments(ctx, now.Add(time.Hour))
+	if err != nil {
+		t.Fatalf("ListOpenAssignments returned error: %v", err)
+	}
+
+	if len(assignments) != 0 {
+		t.Fatalf("expected no assignments for merged PR, got %+v", assignments)
+	}
+}
+
+// TestPullRequestRepository_ListEvents проверяет выборку ленты событий с фильтрами и продолжением по номеру.
+func TestPullRequestRepository_ListEvents(t *testing.T) {
+	db, repo := newTestPullRequestRepository(t)
+	defer func() {
+		_ = db.Close()
+	}()
+
+	ctx := testContext()
+
+	insertTeam(t, db, "backend")
+	insertTeam(t, db, "frontend")
+	insertUser(t, db, "a1", "a1", "backend", true)
+	insertUser(t, db, "r1", "r1", "backend", true)
+	insertUser(t, db, "a2", "a2", "frontend", true)
+	insertUser(t, db, "r2", "r2", "frontend", true)
+
+	if seq, err := repo.LastEventSequence(ctx); err != nil || seq != 0 {
+		t.Fatalf("expected no events, got %d, %v", seq, err)
+	}
+
+	now := time.Now().UTC().Truncate(time.Second)
+
+	for _, pr := range []domain.PullRequest{
+		{ID: "pr-1", Name: "backend", AuthorID: "a1", Status: domain.PullRequestStatusOpen, AssignedReviewers: []domain.UserID{"r1"}, CreatedAt: &now},
+		{ID: "pr-2", Name: "frontend", AuthorID: "a2", Status: domain.PullRequestStatusOpen, AssignedReviewers: []domain.UserID{"r2"}, CreatedAt: &now},
+	} {
+		created := domain.Event{
+			Type:           domain.EventReviewersAssigned,
+			PullRequest:    pr,
+			AddedReviewers: pr.AssignedReviewers,
+			OccurredAt:     now,
+		}
+
+		if err := repo.Create(ctx, pr, created); err != nil {
+			t.Fatalf("Create %s returned error: %v", pr.ID, err)
+		}
+	}
+
+	all, err := repo.ListEvents(ctx, repository.EventFilter{Limit: 10})
+	if err != nil {
+		t.Fatalf("ListEvents returned error: %v", err)
+	}
+
+	if len(all) != 2 || all[0].PullRequest.ID != "pr-1" || all[1].Sequence <= all[0].Sequence {
+		t.Fatalf("unexpected events: %+v", all)
+	}
+
+	last, err := repo.LastEventSequence(ctx)
+	if err != nil || last != all[1].Sequence {
+		t.Fatalf("expected last sequence %d, got %d, %v", all[1].Sequence, last, err)
+	}
+
+	cases := []struct {
+		name   string
+		filter repository.EventFilter
+		want   []domain.PullRequestID
+	}{
+		{"after sequence", repository.EventFilter{AfterSequence: all[0].Sequence}, []domain.PullRequestID{"pr-2"}},
+		{"team", repository.EventFilter{TeamName: "backend"}, []domain.PullRequestID{"pr-1"}},
+		{"reviewer", repository.EventFilter{UserID: "r2"}, []domain.PullRequestID{"pr-2"}},
+		{"other type", repository.EventFilter{Types: []domain.EventType{domain.EventPullRequestMerged}}, nil},
+		{"limit", repository.EventFilter{Types: []domain.EventType{domain.EventReviewersAssigned}, Limit: 1}, []domain.PullRequestID{"pr-1"}},
+	}
+
+	for _, tc := range cases {
+		if tc.filter.Limit == 0 {
+			tc.filter.Limit = 10
+		}
+
+		events, err := repo.ListEvents(ctx, tc.filter)
+		if err != nil {
+			t.Fatalf("%s: ListEvents returned error: %v", tc.name, err)
+		}
+
+		got := make([]domain.PullRequestID, 0, len(events))
+		for _, e := range events {
+			got = append(got, e.PullRequest.ID)
+		}
+
+		if len(got) != len(tc.want) || (len(got) > 0 && got[0] != tc.want[0]) {
+			t.Fatalf("%s: expected %v, got %v", tc.name, tc.want, got)
+		}
+	}
+}
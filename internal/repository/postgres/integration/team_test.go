@@ -2,7 +2,6 @@
 package integration
 
 import (
-	"context"
 	"database/sql"
 	"errors"
 	"sort"
@@ -25,7 +24,7 @@ func newTestTeamRepository(t *testing.T) (*sql.DB, repository.TeamRepository) {
 func TestTeamRepository_CreateAndGet(t *testing.T) {
 	_, repo := newTestTeamRepository(t)
 
-	ctx := context.Background()
+	ctx := testContext()
 
 	const teamName = domain.TeamName("backend")
 
@@ -95,7 +94,7 @@ func TestTeamRepository_CreateAndGet(t *testing.T) {
 // TestTeamRepository_TeamExists проверяет, что TeamExists корректно работает для существующей и несуществующей команды.
 func TestTeamRepository_TeamExists(t *testing.T) {
 	_, repo := newTestTeamRepository(t)
-	ctx := context.Background()
+	ctx := testContext()
 
 	const teamName = domain.TeamName("backend")
 
@@ -123,7 +122,7 @@ func TestTeamRepository_TeamExists(t *testing.T) {
 // TestTeamRepository_GetTeamWithMembers_NotFound проверяет, что для несуществующей команды возвращается repository.ErrNotFound.
 func TestTeamRepository_GetTeamWithMembers_NotFound(t *testing.T) {
 	_, repo := newTestTeamRepository(t)
-	ctx := context.Background()
+	ctx := testContext()
 
 	_, _, err := repo.GetTeamWithMembers(ctx, domain.TeamName("unknown"))
 	if err == nil {
@@ -137,7 +136,7 @@ func TestTeamRepository_GetTeamWithMembers_NotFound(t *testing.T) {
 // TestTeamRepository_UpsertMembers_UpdateAndInsert проверяет, что UpsertMembers обновляет существующих участников и добавляет новых.
 func TestTeamRepository_UpsertMembers_UpdateAndInsert(t *testing.T) {
 	_, repo := newTestTeamRepository(t)
-	ctx := context.Background()
+	ctx := testContext()
 
 	const teamName = domain.TeamName("backend")
 
@@ -225,3 +224,283 @@ func TestTeamRepository_UpsertMembers_UpdateAndInsert(t *testing.T) {
 		}
 	}
 }
+
+// TestTeamRepository_Leads проверяет замену лидов команды и их выдачу вместе с командой.
+func TestTeamRepository_Leads(t *testing.T) {
+	db, repo := newTestTeamRepository(t)
+	defer func() {
+		_ = db.Close()
+	}()
+
+	ctx := testContext()
+
+	insertTeam(t, db, "backend")
+	insertTeam(t, db, "platform")
+	insertUser(t, db, "u1", "alice", "backend", true)
+	insertUser(t, db, "u2", "bob", "backend", true)
+	insertUser(t, db, "u3", "carol", "platform", false)
+
+	if err := repo.SetLeads(ctx, "backend", []domain.UserID{"u1", "u3"}); err != nil {
+		t.Fatalf("SetLeads returned error: %v", err)
+	}
+
+	if err := repo.SetLeads(ctx, "backend", []domain.UserID{"u3", "u2"}); err != nil {
+		t.Fatalf("SetLeads (replace) returned error: %v", err)
+	}
+
+	team, _, err := repo.GetTeamWithMembers(ctx, "backend")
+	if err != nil {
+		t.Fatalf("GetTeamWithMembers returned error: %v", err)
+	}
+
+	if len(team.Leads) != 2 || team.Leads[0] != "u2" || team.Leads[1] != "u3" {
+		t.Fatalf("unexpected leads: %v", team.Leads)
+	}
+
+	leads, err := repo.ListLeads(ctx, "backend")
+	if err != nil {
+		t.Fatalf("ListLeads returned error: %v", err)
+	}
+
+	// Лид из другой команды сохраняет свою команду и флаг активности.
+	if len(leads) != 2 || leads[1].TeamName != "platform" || leads[1].IsActive {
+		t.Fatalf("unexpected lead users: %+v", leads)
+	}
+
+	if err := repo.SetLeads(ctx, "backend", nil); err != nil {
+		t.Fatalf("SetLeads (clear) returned error: %v", err)
+	}
+
+	team, _, err = repo.GetTeamWithMembers(ctx, "backend")
+	if err != nil {
+		t.Fatalf("GetTeamWithMembers returned error: %v", err)
+	}
+
+	if len(team.Leads) != 0 {
+		t.Fatalf("expected no leads, got %v", team.Leads)
+	}
+}
+
+// TestTeamRepository_Hierarchy проверяет цепочку предков, участников поддерева и смену родителя.
+func TestTeamRepository_Hierarchy(t *testing.T) {
+	db, repo := newTestTeamRepository(t)
+	defer func() {
+		_ = db.Close()
+	}()
+
+	ctx := testContext()
+
+	for _, team := range []domain.Team{
+		{Name: "platform"},
+		{Name: "backend", Parent: "platform"},
+		{Name: "payments", Parent: "backend", FallbackToParent: true},
+		{Name: "frontend"},
+	} {
+		if err := repo.CreateTeam(ctx, team); err != nil {
+			t.Fatalf("CreateTeam %s returned error: %v", team.Name, err)
+		}
+	}
+
+	insertUser(t, db, "u1", "alice", "platform", true)
+	insertUser(t, db, "u2", "bob", "backend", true)
+	insertUser(t, db, "u3", "carol", "payments", true)
+	insertUser(t, db, "u4", "dave", "frontend", true)
+
+	chain, err := repo.ListTeamChain(ctx, "payments")
+	if err != nil {
+		t.Fatalf("ListTeamChain returned error: %v", err)
+	}
+
+	if len(chain) != 3 || chain[0].Name != "payments" || !chain[0].FallbackToParent ||
+		chain[1].Name != "backend" || chain[2].Name != "platform" || chain[2].Parent != "" {
+		t.Fatalf("unexpected chain: %+v", chain)
+	}
+
+	if _, err := repo.ListTeamChain(ctx, "unknown"); !errors.Is(err, repository.ErrNotFound) {
+		t.Fatalf("expected ErrNotFound for unknown team, got %v", err)
+	}
+
+	members, err := repo.ListSubtreeMembers(ctx, "platform")
+	if err != nil {
+		t.Fatalf("ListSubtreeMembers returned error: %v", err)
+	}
+
+	if len(members) != 3 {
+		t.Fatalf("expected 3 members in platform subtree, got %+v", members)
+	}
+
+	if err := repo.SetParent(ctx, "frontend", "platform", false); err != nil {
+		t.Fatalf("SetParent returned error: %v", err)
+	}
+
+	members, err = repo.ListSubtreeMembers(ctx, "platform")
+	if err != nil {
+		t.Fatalf("ListSubtreeMembers returned error: %v", err)
+	}
+
+	if len(members) != 4 {
+		t.Fatalf("expected 4 members after moving frontend, got %+v", members)
+	}
+
+	team, _, err := repo.GetTeamWithMembers(ctx, "frontend")
+	if err != nil {
+		t.Fatalf("GetTeamWithMembers returned error: %v", err)
+	}
+
+	if team.Parent != "platform" {
+		t.Fatalf("expected frontend under platform, got %+v", team)
+	}
+
+	if err := repo.SetParent(ctx, "unknown", "", false); !errors.Is(err, repository.ErrNotFound) {
+		t.Fatalf("expected ErrNotFound for unknown team, got %v", err)
+	}
+}
+
+// TestTeamRepository_Memberships проверяет дополнительные команды пользователя и смену основной.
+func TestTeamRepository_Memberships(t *testing.T) {
+	db, repo := newTestTeamRepository(t)
+	defer func() {
+		_ = db.Close()
+	}()
+
+	users := postgres.NewUserRepository(db)
+	ctx := testContext()
+
+	for _, name := range []domain.TeamName{"backend", "frontend", "go-guild"} {
+		if err := repo.CreateTeam(ctx, domain.Team{Name: name}); err != nil {
+			t.Fatalf("CreateTeam %s returned error: %v", name, err)
+		}
+	}
+
+	if err := repo.UpsertMembers(ctx, "backend", []domain.User{
+		{ID: "u1", Username: "alice", IsActive: true},
+		{ID: "u2", Username: "bob", IsActive: true},
+	}); err != nil {
+		t.Fatalf("UpsertMembers returned error: %v", err)
+	}
+
+	if err := repo.AddMember(ctx, "go-guild", "u1", false); err != nil {
+		t.Fatalf("AddMember returned error: %v", err)
+	}
+
+	guild, err := users.ListActiveByTeam(ctx, "go-guild", nil)
+	if err != nil {
+		t.Fatalf("ListActiveByTeam returned error: %v", err)
+	}
+
+	if len(guild) != 1 || guild[0].ID != "u1" || guild[0].TeamName != "backend" {
+		t.Fatalf("unexpected go-guild members: %+v", guild)
+	}
+
+	if err := repo.AddMember(ctx, "frontend", "u1", true); err != nil {
+		t.Fatalf("AddMember primary returned error: %v", err)
+	}
+
+	memberships, err := users.ListMemberships(ctx, "u1")
+	if err != nil {
+		t.Fatalf("ListMemberships returned error: %v", err)
+	}
+
+	want := []domain.TeamMembership{
+		{TeamName: "frontend", UserID: "u1", IsPrimary: true},
+		{TeamName: "backend", UserID: "u1"},
+		{TeamName: "go-guild", UserID: "u1"},
+	}
+
+	if len(memberships) != len(want) {
+		t.Fatalf("unexpected memberships: %+v", memberships)
+	}
+
+	for i := range want {
+		if memberships[i] != want[i] {
+			t.Fatalf("unexpected memberships: %+v", memberships)
+		}
+	}
+
+	if err := repo.RemoveMember(ctx, "frontend", "u1"); !errors.Is(err, repository.ErrNotFound) {
+		t.Fatalf("expected ErrNotFound when removing primary membership, got %v", err)
+	}
+
+	if err := repo.RemoveMember(ctx, "go-guild", "u1"); err != nil {
+		t.Fatalf("RemoveMember returned error: %v", err)
+	}
+
+	// /team/add переносит пользователя: прежняя основная команда не остаётся дополнительной.
+	if err := repo.UpsertMembers(ctx, "go-guild", []domain.User{{ID: "u2", Username: "bob", IsActive: true}}); err != nil {
+		t.Fatalf("UpsertMembers returned error: %v", err)
+	}
+
+	backend, err := users.ListActiveByTeam(ctx, "backend", nil)
+	if err != nil {
+		t.Fatalf("ListActiveByTeam returned error: %v", err)
+	}
+
+	if len(backend) != 1 || backend[0].ID != "u1" {
+		t.Fatalf("unexpected backend members: %+v", backend)
+	}
+}
+
+// TestTeamRepository_Version проверяет, что версия команды растёт при изменении лидов, состава
+// и активности участников.
+func TestTeamRepository_Version(t *testing.T) {
+	db, repo := newTestTeamRepository(t)
+	users := postgres.NewUserRepository(db)
+	ctx := testContext()
+
+	const teamName = domain.TeamName("backend")
+
+	if err := repo.CreateTeam(ctx, domain.Team{Name: teamName}); err != nil {
+		t.Fatalf("CreateTeam returned error: %v", err)
+	}
+
+	version := func() int64 {
+		t.Helper()
+
+		team, _, err := repo.GetTeamWithMembers(ctx, teamName)
+		if err != nil {
+			t.Fatalf("GetTeamWithMembers returned error: %v", err)
+		}
+
+		return team.Version
+	}
+
+	if got := version(); got != 1 {
+		t.Fatalf("initial version: got %d, want 1", got)
+	}
+
+	members := []domain.User{{ID: "u1", Username: "alice", TeamName: teamName, IsActive: true}}
+	if err := repo.UpsertMembers(ctx, teamName, members); err != nil {
+		t.Fatalf("UpsertMembers returned error: %v", err)
+	}
+
+	afterMembers := version()
+	if afterMembers <= 1 {
+		t.Fatalf("version must grow after UpsertMembers, got %d", afterMembers)
+	}
+
+	if err := users.SetActive(ctx, "u1", false); err != nil {
+		t.Fatalf("SetActive returned error: %v", err)
+	}
+
+	afterActive := version()
+	if afterActive <= afterMembers {
+		t.Fatalf("version must grow after SetActive, got %d", afterActive)
+	}
+
+	if err := repo.SetLeads(ctx, teamName, []domain.UserID{"u1"}); err != nil {
+		t.Fatalf("SetLeads returned error: %v", err)
+	}
+
+	locked, err := repo.LockTeam(ctx, teamName)
+	if err != nil {
+		t.Fatalf("LockTeam returned error: %v", err)
+	}
+
+	if locked <= afterActive || locked != version() {
+		t.Fatalf("unexpected version after SetLeads: %d", locked)
+	}
+
+	if _, err := repo.LockTeam(ctx, "missing"); !errors.Is(err, repository.ErrNotFound) {
+		t.Fatalf("expected ErrNotFound for missing team, got %v", err)
+	}
+}
@@ -0,0 +1,71 @@
+package integration
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/dixitix/pr-reviewer-service/internal/domain"
+	"github.com/dixitix/pr-reviewer-service/internal/repository"
+	"github.com/dixitix/pr-reviewer-service/internal/repository/postgres"
+)
+
+// TestTransactor_RollbackSpansRepositories проверяет, что ошибка откатывает изменения всех репозиториев,
+// включая методы с собственной транзакцией, а успешный вызов фиксирует их вместе.
+func TestTransactor_RollbackSpansRepositories(t *testing.T) {
+	db := openTestDB(t)
+	truncateAllTables(t, db)
+
+	tx := postgres.NewTransactor(db)
+	teams := postgres.NewTeamRepository(db)
+	users := postgres.NewUserRepository(db)
+	ctx := testContext()
+
+	members := []domain.User{{ID: "u1", Username: "alice", TeamName: "backend", IsActive: true}}
+	errBoom := errors.New("boom")
+
+	err := tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := teams.CreateTeam(ctx, domain.Team{Name: "backend"}); err != nil {
+			return err
+		}
+
+		if err := teams.UpsertMembers(ctx, "backend", members); err != nil {
+			return err
+		}
+
+		return errBoom
+	})
+	if !errors.Is(err, errBoom) {
+		t.Fatalf("expected errBoom, got %v", err)
+	}
+
+	if exists, err := teams.TeamExists(ctx, "backend"); err != nil || exists {
+		t.Fatalf("team must be rolled back: exists=%v err=%v", exists, err)
+	}
+
+	if _, err := users.GetByID(ctx, "u1"); !errors.Is(err, repository.ErrNotFound) {
+		t.Fatalf("members must be rolled back, got %v", err)
+	}
+
+	err = tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := teams.CreateTeam(ctx, domain.Team{Name: "backend"}); err != nil {
+			return err
+		}
+
+		// Вложенный вызов выполняется в той же транзакции.
+		return tx.WithinTransaction(ctx, func(ctx context.Context) error {
+			return teams.UpsertMembers(ctx, "backend", members)
+		})
+	})
+	if err != nil {
+		t.Fatalf("WithinTransaction returned error: %v", err)
+	}
+
+	if _, err := users.GetByID(ctx, "u1"); err != nil {
+		t.Fatalf("members must be committed, got %v", err)
+	}
+
+	if err := teams.CreateTeam(ctx, domain.Team{Name: "backend"}); !errors.Is(err, repository.ErrAlreadyExists) {
+		t.Fatalf("expected ErrAlreadyExists for duplicate team, got %v", err)
+	}
+}
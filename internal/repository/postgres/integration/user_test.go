@@ -2,7 +2,6 @@
 package integration
 
 import (
-	"context"
 	"database/sql"
 	"errors"
 	"testing"
@@ -33,7 +32,7 @@ func TestUserRepository_GetByID(t *testing.T) {
 	insertUser(t, db, userID, "alice", teamName, true)
 
 	t.Run("ok", func(t *testing.T) {
-		ctx := context.Background()
+		ctx := testContext()
 
 		user, err := repo.GetByID(ctx, domain.UserID(userID))
 		if err != nil {
@@ -58,7 +57,7 @@ func TestUserRepository_GetByID(t *testing.T) {
 	})
 
 	t.Run("not found", func(t *testing.T) {
-		ctx := context.Background()
+		ctx := testContext()
 
 		_, err := repo.GetByID(ctx, domain.UserID("unknown"))
 
@@ -81,7 +80,7 @@ func TestUserRepository_SetActive(t *testing.T) {
 	insertUser(t, db, userID, "bob", teamName, false)
 
 	t.Run("ok", func(t *testing.T) {
-		ctx := context.Background()
+		ctx := testContext()
 
 		if err := repo.SetActive(ctx, domain.UserID(userID), true); err != nil {
 			t.Fatalf("SetActive returned error: %v", err)
@@ -98,7 +97,7 @@ func TestUserRepository_SetActive(t *testing.T) {
 	})
 
 	t.Run("not found", func(t *testing.T) {
-		ctx := context.Background()
+		ctx := testContext()
 
 		err := repo.SetActive(ctx, domain.UserID("unknown"), true)
 		if !errors.Is(err, repository.ErrNotFound) {
@@ -124,7 +123,7 @@ func TestUserRepository_ListActiveByTeam(t *testing.T) {
 	insertUser(t, db, "user-3", "charlie", teamA, false)
 	insertUser(t, db, "user-4", "dave", teamB, true)
 
-	ctx := context.Background()
+	ctx := testContext()
 
 	t.Run("only active users of team", func(t *testing.T) {
 		users, err := repo.ListActiveByTeam(ctx, domain.TeamName(teamA), nil)
@@ -0,0 +1,94 @@
+package integration
+
+import (
+	"testing"
+	"time"
+
+	"github.com/dixitix/pr-reviewer-service/internal/domain"
+	"github.com/dixitix/pr-reviewer-service/internal/repository"
+	"github.com/dixitix/pr-reviewer-service/internal/repository/postgres"
+)
+
+// TestVCSSyncRepository_Lifecycle проверяет сохранение, захват и удаление неудачных синхронизаций.
+func TestVCSSyncRepository_Lifecycle(t *testing.T) {
+	db := openTestDB(t)
+	truncateAllTables(t, db)
+
+	repo := postgres.NewVCSSyncRepository(db)
+	ctx := testContext()
+	now := time.Now().UTC().Truncate(time.Second)
+
+	temporary := repository.VCSSyncFailure{
+		OrganizationID: testOrg,
+		PullRequestID:  "pr-1",
+		Add:            []domain.UserID{"u2"},
+		Remove:         []domain.UserID{"u1"},
+		Attempts:       3,
+		LastError:      "timeout",
+		FailedAt:       now,
+	}
+
+	id, err := repo.SaveFailure(ctx, temporary, now.Add(-time.Second))
+	if err != nil {
+		t.Fatalf("SaveFailure returned error: %v", err)
+	}
+
+	permanent := temporary
+	permanent.PullRequestID = "pr-2"
+	permanent.Permanent = true
+
+	if _, err := repo.SaveFailure(ctx, permanent, now.Add(-time.Second)); err != nil {
+		t.Fatalf("SaveFailure returned error: %v", err)
+	}
+
+	claimed, err := repo.ClaimFailures(ctx, 10, time.Minute)
+	if err != nil {
+		t.Fatalf("ClaimFailures returned error: %v", err)
+	}
+
+	if len(claimed) != 1 || claimed[0].ID != id || claimed[0].OrganizationID != testOrg {
+		t.Fatalf("expected only temporary failure to be claimed, got %+v", claimed)
+	}
+
+	if len(claimed[0].Add) != 1 || claimed[0].Add[0] != "u2" || len(claimed[0].Remove) != 1 || claimed[0].Remove[0] != "u1" {
+		t.Fatalf("unexpected reviewers: %+v", claimed[0])
+	}
+
+	again, err := repo.ClaimFailures(ctx, 10, time.Minute)
+	if err != nil {
+		t.Fatalf("ClaimFailures returned error: %v", err)
+	}
+
+	if len(again) != 0 {
+		t.Fatalf("expected leased failure to be hidden, got %+v", again)
+	}
+
+	temporary.ID = id
+	temporary.Attempts = 5
+
+	if _, err := repo.SaveFailure(ctx, temporary, now.Add(-time.Second)); err != nil {
+		t.Fatalf("SaveFailure (update) returned error: %v", err)
+	}
+
+	claimed, err = repo.ClaimFailures(ctx, 10, time.Minute)
+	if err != nil {
+		t.Fatalf("ClaimFailures returned error: %v", err)
+	}
+
+	if len(claimed) != 1 || claimed[0].Attempts != 5 {
+		t.Fatalf("expected updated failure, got %+v", claimed)
+	}
+
+	if err := repo.DeleteFailure(ctx, id); err != nil {
+		t.Fatalf("DeleteFailure returned error: %v", err)
+	}
+
+	var count int
+	if err := db.QueryRow(`SELECT count(*) FROM vcs_sync_failures`).Scan(&count); err != nil {
+		t.Fatalf("count vcs_sync_failures: %v", err)
+	}
+
+	if count != 1 {
+		t.Fatalf("expected only permanent failure to remain, got %d", count)
+	}
+}
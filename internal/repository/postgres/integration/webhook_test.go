@@ -0,0 +1,140 @@
+// Package postgres_test содержит интеграционные тесты репозиториев.
+package integration
+
+import (
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/dixitix/pr-reviewer-service/internal/domain"
+	"github.com/dixitix/pr-reviewer-service/internal/repository"
+	"github.com/dixitix/pr-reviewer-service/internal/repository/postgres"
+)
+
+// newTestWebhookRepository создаёт WebhookRepository поверх тестовой БД.
+func newTestWebhookRepository(t *testing.T) (*sql.DB, repository.WebhookRepository) {
+	db := openTestDB(t)
+	truncateAllTables(t, db)
+
+	return db, postgres.NewWebhookRepository(db)
+}
+
+// TestWebhookRepository_Subscriptions проверяет создание подписок и выборку по фильтру событий.
+func TestWebhookRepository_Subscriptions(t *testing.T) {
+	_, repo := newTestWebhookRepository(t)
+	ctx := testContext()
+
+	now := time.Now().UTC().Truncate(time.Second)
+
+	all := domain.WebhookSubscription{ID: "sub-all", URL: "http://a", Secret: "s1", CreatedAt: now}
+	merged := domain.WebhookSubscription{
+		ID:        "sub-merged",
+		URL:       "http://b",
+		Events:    []domain.EventType{domain.EventPullRequestMerged},
+		Secret:    "s2",
+		CreatedAt: now.Add(time.Second),
+	}
+
+	for _, sub := range []domain.WebhookSubscription{all, merged} {
+		if err := repo.CreateSubscription(ctx, sub); err != nil {
+			t.Fatalf("CreateSubscription(%s) returned error: %v", sub.ID, err)
+		}
+	}
+
+	if err := repo.CreateSubscription(ctx, all); !errors.Is(err, repository.ErrAlreadyExists) {
+		t.Fatalf("expected ErrAlreadyExists for duplicate, got %v", err)
+	}
+
+	subs, err := repo.ListSubscriptions(ctx)
+	if err != nil {
+		t.Fatalf("ListSubscriptions returned error: %v", err)
+	}
+
+	if len(subs) != 2 || subs[0].ID != all.ID || subs[1].ID != merged.ID {
+		t.Fatalf("unexpected subscriptions: %+v", subs)
+	}
+
+	if len(subs[1].Events) != 1 || subs[1].Events[0] != domain.EventPullRequestMerged || subs[1].Secret != "s2" {
+		t.Fatalf("unexpected filtered subscription: %+v", subs[1])
+	}
+
+	t.Run("by event", func(t *testing.T) {
+		got, err := repo.ListSubscriptionsByEvent(ctx, domain.EventReviewerReassigned)
+		if err != nil {
+			t.Fatalf("ListSubscriptionsByEvent returned error: %v", err)
+		}
+
+		if len(got) != 1 || got[0].ID != all.ID {
+			t.Fatalf("expected only unfiltered subscription, got %+v", got)
+		}
+
+		got, err = repo.ListSubscriptionsByEvent(ctx, domain.EventPullRequestMerged)
+		if err != nil {
+			t.Fatalf("ListSubscriptionsByEvent returned error: %v", err)
+		}
+
+		if len(got) != 2 {
+			t.Fatalf("expected both subscriptions, got %+v", got)
+		}
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		if err := repo.DeleteSubscription(ctx, merged.ID); err != nil {
+			t.Fatalf("DeleteSubscription returned error: %v", err)
+		}
+
+		if err := repo.DeleteSubscription(ctx, merged.ID); !errors.Is(err, repository.ErrNotFound) {
+			t.Fatalf("expected ErrNotFound, got %v", err)
+		}
+	})
+}
+
+// TestWebhookRepository_DeadLetters проверяет сохранение и выборку недоставленных вебхуков.
+func TestWebhookRepository_DeadLetters(t *testing.T) {
+	_, repo := newTestWebhookRepository(t)
+	ctx := testContext()
+
+	now := time.Now().UTC().Truncate(time.Second)
+
+	for _, id := range []domain.WebhookSubscriptionID{"sub-1", "sub-2"} {
+		if err := repo.CreateSubscription(ctx, domain.WebhookSubscription{ID: id, URL: "http://x", Secret: "s", CreatedAt: now}); err != nil {
+			t.Fatalf("CreateSubscription returned error: %v", err)
+		}
+	}
+
+	deliveries := []domain.WebhookDelivery{
+		{ID: "d-1", SubscriptionID: "sub-1", EventType: domain.EventPullRequestMerged, Payload: []byte(`{"a":1}`), Attempts: 3, LastError: "timeout", FailedAt: now},
+		{ID: "d-2", SubscriptionID: "sub-2", EventType: domain.EventReviewerReassigned, Payload: []byte(`{"b":2}`), Attempts: 6, LastError: "unexpected status 500", LastStatusCode: 500, FailedAt: now.Add(time.Minute)},
+	}
+
+	for _, d := range deliveries {
+		if err := repo.CreateDeadLetter(ctx, d); err != nil {
+			t.Fatalf("CreateDeadLetter returned error: %v", err)
+		}
+	}
+
+	got, err := repo.ListDeadLetters(ctx, nil, 10)
+	if err != nil {
+		t.Fatalf("ListDeadLetters returned error: %v", err)
+	}
+
+	if len(got) != 2 || got[0].ID != "d-2" || got[1].ID != "d-1" {
+		t.Fatalf("expected newest first, got %+v", got)
+	}
+
+	if got[0].LastStatusCode != 500 || got[1].LastStatusCode != 0 {
+		t.Fatalf("unexpected status codes: %d, %d", got[0].LastStatusCode, got[1].LastStatusCode)
+	}
+
+	subID := domain.WebhookSubscriptionID("sub-1")
+
+	got, err = repo.ListDeadLetters(ctx, &subID, 10)
+	if err != nil {
+		t.Fatalf("ListDeadLetters returned error: %v", err)
+	}
+
+	if len(got) != 1 || got[0].ID != "d-1" || got[0].Attempts != 3 {
+		t.Fatalf("unexpected filtered dead letters: %+v", got)
+	}
+}
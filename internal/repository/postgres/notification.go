@@ -0,0 +1,244 @@
+// Package postgres содержит реализацию репозиториев поверх PostgreSQL.
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/dixitix/pr-reviewer-service/internal/domain"
+	"github.com/dixitix/pr-reviewer-service/internal/repository"
+)
+
+// NotificationRepository реализует repository.NotificationRepository поверх *sql.DB.
+type NotificationRepository struct {
+	db *sql.DB
+}
+
+// NewNotificationRepository создаёт новый экземпляр NotificationRepository.
+func NewNotificationRepository(db *sql.DB) repository.NotificationRepository {
+	return &NotificationRepository{db: db}
+}
+
+// GetPreferences возвращает настройки уведомлений пользователя или настройки по умолчанию.
+func (r *NotificationRepository) GetPreferences(
+	ctx context.Context,
+	userID domain.UserID,
+) (domain.NotificationPreferences, error) {
+	org, err := organizationID(ctx)
+	if err != nil {
+		return domain.NotificationPreferences{}, err
+	}
+
+	const query = `
+		SELECT chat_enabled, email, email_mode
+		FROM user_notification_preferences
+		WHERE org_id = $1 AND user_id = $2
+	`
+
+	prefs := domain.DefaultNotificationPreferences(userID)
+
+	err = dbtx(ctx, r.db).QueryRowContext(ctx, query, org, string(userID)).Scan(&prefs.ChatEnabled, &prefs.Email, &prefs.EmailMode)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return domain.DefaultNotificationPreferences(userID), nil
+		}
+
+		return domain.NotificationPreferences{}, fmt.Errorf("select user_notification_preferences: %w", err)
+	}
+
+	return prefs, nil
+}
+
+// UpsertPreferences создаёт или обновляет настройки уведомлений пользователя.
+func (r *NotificationRepository) UpsertPreferences(ctx context.Context, prefs domain.NotificationPreferences) error {
+	org, err := organizationID(ctx)
+	if err != nil {
+		return err
+	}
+
+	const query = `
+		INSERT INTO user_notification_preferences (org_id, user_id, chat_enabled, email, email_mode)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (org_id, user_id) DO UPDATE
+		SET chat_enabled = EXCLUDED.chat_enabled,
+		    email = EXCLUDED.email,
+		    email_mode = EXCLUDED.email_mode,
+		    updated_at = now()
+	`
+
+	_, err = dbtx(ctx, r.db).ExecContext(ctx, query, org, string(prefs.UserID), prefs.ChatEnabled, prefs.Email, string(prefs.EmailMode))
+	if err != nil {
+		return fmt.Errorf("upsert user_notification_preferences: %w", err)
+	}
+
+	return nil
+}
+
+// ListPreferencesByEmailMode возвращает настройки пользователей с указанным режимом почтовых уведомлений.
+func (r *NotificationRepository) ListPreferencesByEmailMode(
+	ctx context.Context,
+	mode domain.EmailMode,
+) ([]domain.NotificationPreferences, error) {
+	org, err := organizationID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	const query = `
+		SELECT user_id, chat_enabled, email, email_mode
+		FROM user_notification_preferences
+		WHERE org_id = $1 AND email_mode = $2
+		ORDER BY user_id
+	`
+
+	rows, err := dbtx(ctx, r.db).QueryContext(ctx, query, org, string(mode))
+	if err != nil {
+		return nil, fmt.Errorf("list user_notification_preferences by email mode: %w", err)
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	result := make([]domain.NotificationPreferences, 0)
+
+	for rows.Next() {
+		var prefs domain.NotificationPreferences
+
+		if err := rows.Scan(&prefs.UserID, &prefs.ChatEnabled, &prefs.Email, &prefs.EmailMode); err != nil {
+			return nil, fmt.Errorf("scan user_notification_preferences: %w", err)
+		}
+
+		result = append(result, prefs)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate user_notification_preferences: %w", err)
+	}
+
+	return result, nil
+}
+
+// ClaimDigest отмечает отправку сводки за день. Повторная отметка за тот же день не проходит,
+// поэтому несколько экземпляров сервиса не отправят одну сводку дважды.
+func (r *NotificationRepository) ClaimDigest(ctx context.Context, userID domain.UserID, day time.Time) (bool, error) {
+	org, err := organizationID(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	const query = `
+		INSERT INTO email_digests (org_id, user_id, digest_date)
+		VALUES ($1, $2, $3::date)
+		ON CONFLICT DO NOTHING
+	`
+
+	res, err := dbtx(ctx, r.db).ExecContext(ctx, query, org, string(userID), day.Format(time.DateOnly))
+	if err != nil {
+		return false, fmt.Errorf("insert email_digests: %w", err)
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("rows affected: %w", err)
+	}
+
+	return n > 0, nil
+}
+
+// ReleaseDigest снимает отметку об отправке сводки за день.
+func (r *NotificationRepository) ReleaseDigest(ctx context.Context, userID domain.UserID, day time.Time) error {
+	org, err := organizationID(ctx)
+	if err != nil {
+		return err
+	}
+
+	const query = `DELETE FROM email_digests WHERE org_id = $1 AND user_id = $2 AND digest_date = $3::date`
+
+	if _, err := dbtx(ctx, r.db).ExecContext(ctx, query, org, string(userID), day.Format(time.DateOnly)); err != nil {
+		return fmt.Errorf("delete email_digests: %w", err)
+	}
+
+	return nil
+}
+
+// GetTeamChatChannel возвращает канал чата команды.
+func (r *NotificationRepository) GetTeamChatChannel(
+	ctx context.Context,
+	teamName domain.TeamName,
+) (domain.TeamChatChannel, error) {
+	org, err := organizationID(ctx)
+	if err != nil {
+		return domain.TeamChatChannel{}, err
+	}
+
+	const query = `
+		SELECT webhook_url, channel
+		FROM team_chat_channels
+		WHERE org_id = $1 AND team_name = $2
+	`
+
+	channel := domain.TeamChatChannel{TeamName: teamName}
+
+	err = dbtx(ctx, r.db).QueryRowContext(ctx, query, org, string(teamName)).Scan(&channel.WebhookURL, &channel.Channel)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return domain.TeamChatChannel{}, repository.ErrNotFound
+		}
+
+		return domain.TeamChatChannel{}, fmt.Errorf("select team_chat_channels: %w", err)
+	}
+
+	return channel, nil
+}
+
+// UpsertTeamChatChannel создаёт или обновляет канал чата команды.
+func (r *NotificationRepository) UpsertTeamChatChannel(ctx context.Context, channel domain.TeamChatChannel) error {
+	org, err := organizationID(ctx)
+	if err != nil {
+		return err
+	}
+
+	const query = `
+		INSERT INTO team_chat_channels (org_id, team_name, webhook_url, channel)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (org_id, team_name) DO UPDATE
+		SET webhook_url = EXCLUDED.webhook_url,
+		    channel = EXCLUDED.channel,
+		    updated_at = now()
+	`
+
+	if _, err := dbtx(ctx, r.db).ExecContext(ctx, query, org, string(channel.TeamName), channel.WebhookURL, channel.Channel); err != nil {
+		return fmt.Errorf("upsert team_chat_channels: %w", err)
+	}
+
+	return nil
+}
+
+// DeleteTeamChatChannel удаляет канал чата команды.
+func (r *NotificationRepository) DeleteTeamChatChannel(ctx context.Context, teamName domain.TeamName) error {
+	org, err := organizationID(ctx)
+	if err != nil {
+		return err
+	}
+
+	const query = `DELETE FROM team_chat_channels WHERE org_id = $1 AND team_name = $2`
+
+	res, err := dbtx(ctx, r.db).ExecContext(ctx, query, org, string(teamName))
+	if err != nil {
+		return fmt.Errorf("delete team_chat_channels: %w", err)
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("rows affected: %w", err)
+	}
+
+	if n == 0 {
+		return repository.ErrNotFound
+	}
+
+	return nil
+}
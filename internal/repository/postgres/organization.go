@@ -0,0 +1,114 @@
+// Package postgres содержит реализацию репозиториев поверх PostgreSQL.
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/dixitix/pr-reviewer-service/internal/domain"
+	"github.com/dixitix/pr-reviewer-service/internal/repository"
+	"github.com/dixitix/pr-reviewer-service/internal/tenant"
+)
+
+// OrganizationRepository реализует repository.OrganizationRepository поверх *sql.DB.
+type OrganizationRepository struct {
+	db *sql.DB
+}
+
+// NewOrganizationRepository создаёт новый экземпляр OrganizationRepository.
+func NewOrganizationRepository(db *sql.DB) repository.OrganizationRepository {
+	return &OrganizationRepository{db: db}
+}
+
+// Create создаёт организацию.
+func (r *OrganizationRepository) Create(ctx context.Context, org domain.Organization) error {
+	const query = `
+		INSERT INTO organizations (id, name, created_at)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (id) DO NOTHING
+	`
+
+	res, err := dbtx(ctx, r.db).ExecContext(ctx, query, string(org.ID), org.Name, org.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("insert organization %s: %w", org.ID, err)
+	}
+
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return repository.ErrAlreadyExists
+	}
+
+	return nil
+}
+
+// Exists возвращает true, если организация существует.
+func (r *OrganizationRepository) Exists(ctx context.Context, id domain.OrganizationID) (bool, error) {
+	const query = `
+		SELECT 1
+		FROM organizations
+		WHERE id = $1
+	`
+
+	var dummy int
+	if err := dbtx(ctx, r.db).QueryRowContext(ctx, query, string(id)).Scan(&dummy); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return false, nil
+		}
+
+		return false, fmt.Errorf("query organization %s exists: %w", id, err)
+	}
+
+	return true, nil
+}
+
+// List возвращает все организации.
+func (r *OrganizationRepository) List(ctx context.Context) ([]domain.Organization, error) {
+	const query = `
+		SELECT id, name, created_at
+		FROM organizations
+		ORDER BY id
+	`
+
+	rows, err := dbtx(ctx, r.db).QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("list organizations: %w", err)
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	result := make([]domain.Organization, 0)
+
+	for rows.Next() {
+		var org domain.Organization
+
+		if err := rows.Scan(&org.ID, &org.Name, &org.CreatedAt); err != nil {
+			return nil, fmt.Errorf("scan organization: %w", err)
+		}
+
+		result = append(result, org)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate organizations: %w", err)
+	}
+
+	return result, nil
+}
+
+// organizationID возвращает организацию из контекста запроса или repository.ErrNoOrganization.
+// Все запросы к данным организации ограничиваются этим значением.
+func organizationID(ctx context.Context) (string, error) {
+	id, ok := tenant.Organization(ctx)
+	if !ok {
+		return "", repository.ErrNoOrganization
+	}
+
+	return string(id), nil
+}
@@ -0,0 +1,219 @@
+// Package postgres содержит реализацию репозиториев поверх PostgreSQL.
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/dixitix/pr-reviewer-service/internal/domain"
+	"github.com/dixitix/pr-reviewer-service/internal/event"
+	"github.com/dixitix/pr-reviewer-service/internal/repository"
+)
+
+// insertOutboxEvents записывает события в outbox и в историю PR в рамках переданной транзакции.
+// События относятся к организации из контекста.
+func insertOutboxEvents(ctx context.Context, tx querier, events []domain.Event) error {
+	if len(events) == 0 {
+		return nil
+	}
+
+	org, err := organizationID(ctx)
+	if err != nil {
+		return err
+	}
+
+	const query = `
+		INSERT INTO outbox_events (org_id, event_type, pull_request_id, payload, created_at)
+		VALUES ($1, $2, $3, $4, $5)
+	`
+
+	const historyQuery = `
+		INSERT INTO pull_request_history (org_id, pull_request_id, event_type, payload, occurred_at)
+		VALUES ($1, $2, $3, $4, $5)
+	`
+
+	// Лента событий (/events/stream) возобновляется с последнего полученного id, поэтому id истории
+	// организации должны становиться видимыми по возрастанию. Блокировка до конца транзакции
+	// не даёт записи с меньшим id зафиксироваться позже записи с большим.
+	const lockQuery = `SELECT pg_advisory_xact_lock(hashtextextended('pull_request_history:' || $1::text, 0))`
+
+	if _, err := tx.ExecContext(ctx, lockQuery, org); err != nil {
+		return fmt.Errorf("lock pull_request_history: %w", err)
+	}
+
+	for _, e := range events {
+		e.OrganizationID = domain.OrganizationID(org)
+
+		payload, err := json.Marshal(event.NewMessage(e))
+		if err != nil {
+			return fmt.Errorf("marshal outbox event %s: %w", e.Type, err)
+		}
+
+		if _, err := tx.ExecContext(ctx, query, org, string(e.Type), string(e.PullRequest.ID), string(payload), e.OccurredAt); err != nil {
+			return fmt.Errorf("insert outbox_events: %w", err)
+		}
+
+		if _, err := tx.ExecContext(ctx, historyQuery, org, string(e.PullRequest.ID), string(e.Type), string(payload), e.OccurredAt); err != nil {
+			return fmt.Errorf("insert pull_request_history: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// OutboxRepository реализует repository.OutboxRepository поверх *sql.DB.
+type OutboxRepository struct {
+	db *sql.DB
+}
+
+// NewOutboxRepository создаёт новый экземпляр OutboxRepository.
+func NewOutboxRepository(db *sql.DB) repository.OutboxRepository {
+	return &OutboxRepository{db: db}
+}
+
+// ClaimPending захватывает неопубликованные события, сдвигая available_at на время аренды.
+// FOR UPDATE SKIP LOCKED позволяет нескольким экземплярам сервиса забирать разные события.
+// Захватываются события всех организаций, организация события возвращается в Event.OrganizationID.
+func (r *OutboxRepository) ClaimPending(
+	ctx context.Context,
+	limit int,
+	lease time.Duration,
+) ([]repository.OutboxRecord, error) {
+	const query = `
+		UPDATE outbox_events
+		SET available_at = now() + $2::double precision * interval '1 millisecond'
+		WHERE id IN (
+			SELECT id
+			FROM outbox_events
+			WHERE published_at IS NULL
+			  AND available_at <= now()
+			ORDER BY id
+			LIMIT $1
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING id, org_id, payload, attempts
+	`
+
+	rows, err := dbtx(ctx, r.db).QueryContext(ctx, query, limit, float64(lease.Milliseconds()))
+	if err != nil {
+		return nil, fmt.Errorf("claim outbox_events: %w", err)
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	result := make([]repository.OutboxRecord, 0)
+
+	for rows.Next() {
+		var (
+			id       int64
+			org      string
+			payload  string
+			attempts int
+		)
+
+		if err := rows.Scan(&id, &org, &payload, &attempts); err != nil {
+			return nil, fmt.Errorf("scan outbox_events: %w", err)
+		}
+
+		var msg event.Message
+		if err := json.Unmarshal([]byte(payload), &msg); err != nil {
+			return nil, fmt.Errorf("unmarshal outbox event %d: %w", id, err)
+		}
+
+		e := msg.Domain()
+		e.Sequence = id
+		e.OrganizationID = domain.OrganizationID(org)
+
+		result = append(result, repository.OutboxRecord{
+			Event:    e,
+			Attempts: attempts,
+		})
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate outbox_events: %w", err)
+	}
+
+	// UPDATE ... RETURNING не гарантирует порядок строк.
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Event.Sequence < result[j].Event.Sequence
+	})
+
+	return result, nil
+}
+
+// MarkPublished отмечает событие как опубликованное.
+func (r *OutboxRepository) MarkPublished(ctx context.Context, sequence int64) error {
+	const query = `
+		UPDATE outbox_events
+		SET published_at = now(),
+		    attempts = attempts + 1,
+		    last_error = NULL
+		WHERE id = $1
+	`
+
+	res, err := dbtx(ctx, r.db).ExecContext(ctx, query, sequence)
+	if err != nil {
+		return fmt.Errorf("mark outbox event %d published: %w", sequence, err)
+	}
+
+	return requireAffected(res, sequence)
+}
+
+// MarkFailed увеличивает счётчик попыток и откладывает событие до retryAt.
+func (r *OutboxRepository) MarkFailed(ctx context.Context, sequence int64, lastErr string, retryAt time.Time) error {
+	const query = `
+		UPDATE outbox_events
+		SET attempts = attempts + 1,
+		    last_error = $2,
+		    available_at = $3
+		WHERE id = $1
+	`
+
+	res, err := dbtx(ctx, r.db).ExecContext(ctx, query, sequence, lastErr, retryAt)
+	if err != nil {
+		return fmt.Errorf("mark outbox event %d failed: %w", sequence, err)
+	}
+
+	return requireAffected(res, sequence)
+}
+
+// DeletePublishedBefore удаляет события, опубликованные раньше before.
+func (r *OutboxRepository) DeletePublishedBefore(ctx context.Context, before time.Time) (int64, error) {
+	const query = `
+		DELETE FROM outbox_events
+		WHERE published_at IS NOT NULL
+		  AND published_at < $1
+	`
+
+	res, err := dbtx(ctx, r.db).ExecContext(ctx, query, before)
+	if err != nil {
+		return 0, fmt.Errorf("delete published outbox_events: %w", err)
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("rows affected: %w", err)
+	}
+
+	return n, nil
+}
+
+// requireAffected возвращает repository.ErrNotFound, если запрос не изменил ни одной строки.
+func requireAffected(res sql.Result, sequence int64) error {
+	n, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("rows affected for outbox event %d: %w", sequence, err)
+	}
+
+	if n == 0 {
+		return repository.ErrNotFound
+	}
+
+	return nil
+}
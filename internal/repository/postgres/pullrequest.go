@@ -22,9 +22,18 @@ func NewPullRequestRepository(db *sql.DB) repository.PullRequestRepository {
 	return &PullRequestRepository{db: db}
 }
 
-// Create создаёт новый PR и всех его ревьюверов.
-func (r *PullRequestRepository) Create(ctx context.Context, pr domain.PullRequest) (err error) {
-	tx, err := r.db.BeginTx(ctx, nil)
+// Create создаёт новый PR, всех его ревьюверов и записи outbox. Если PR уже есть, возвращает ErrAlreadyExists.
+func (r *PullRequestRepository) Create(
+	ctx context.Context,
+	pr domain.PullRequest,
+	events ...domain.Event,
+) (err error) {
+	org, err := organizationID(ctx)
+	if err != nil {
+		return err
+	}
+
+	tx, err := beginTx(ctx, r.db)
 	if err != nil {
 		return fmt.Errorf("begin tx: %w", err)
 	}
@@ -36,37 +45,55 @@ func (r *PullRequestRepository) Create(ctx context.Context, pr domain.PullReques
 	}()
 
 	const insertPR = `
-		INSERT INTO pull_requests (id, name, author_id, status, created_at, merged_at)
-		VALUES ($1, $2, $3, $4, $5, $6)
+		INSERT INTO pull_requests (org_id, id, name, author_id, status, created_at, merged_at, created_by)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, NULLIF($8, ''))
+		ON CONFLICT (org_id, id) DO NOTHING
 	`
 
-	_, err = tx.ExecContext(
+	res, err := tx.ExecContext(
 		ctx,
 		insertPR,
+		org,
 		pr.ID,
 		pr.Name,
 		pr.AuthorID,
 		string(pr.Status),
 		pr.CreatedAt,
 		pr.MergedAt,
+		string(pr.CreatedBy),
 	)
 	if err != nil {
 		err = fmt.Errorf("insert pull_requests: %w", err)
 		return err
 	}
 
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		err = fmt.Errorf("rows affected: %w", err)
+		return err
+	}
+
+	if rowsAffected == 0 {
+		err = repository.ErrAlreadyExists
+		return err
+	}
+
 	const insertReviewer = `
-		INSERT INTO pull_request_reviewers (pull_request_id, reviewer_id)
-		VALUES ($1, $2)
+		INSERT INTO pull_request_reviewers (org_id, pull_request_id, reviewer_id)
+		VALUES ($1, $2, $3)
 	`
 
 	for _, reviewerID := range pr.AssignedReviewers {
-		if _, err = tx.ExecContext(ctx, insertReviewer, pr.ID, reviewerID); err != nil {
+		if _, err = tx.ExecContext(ctx, insertReviewer, org, pr.ID, reviewerID); err != nil {
 			err = fmt.Errorf("insert pull_request_reviewers: %w", err)
 			return err
 		}
 	}
 
+	if err = insertOutboxEvents(ctx, tx, events); err != nil {
+		return err
+	}
+
 	if err = tx.Commit(); err != nil {
 		return fmt.Errorf("commit tx: %w", err)
 	}
@@ -79,10 +106,16 @@ func (r *PullRequestRepository) GetByID(
 	ctx context.Context,
 	id domain.PullRequestID,
 ) (domain.PullRequest, error) {
+	org, err := organizationID(ctx)
+	if err != nil {
+		return domain.PullRequest{}, err
+	}
+
 	const selectPR = `
-		SELECT id, name, author_id, status, created_at, merged_at
+		SELECT id, name, author_id, status, created_at, merged_at,
+		       COALESCE(created_by, ''), COALESCE(merged_by, ''), COALESCE(reassigned_by, ''), version
 		FROM pull_requests
-		WHERE id = $1
+		WHERE org_id = $1 AND id = $2
 	`
 
 	var (
@@ -90,14 +123,18 @@ func (r *PullRequestRepository) GetByID(
 		statusValue string
 	)
 
-	row := r.db.QueryRowContext(ctx, selectPR, id)
-	err := row.Scan(
+	row := dbtx(ctx, r.db).QueryRowContext(ctx, selectPR, org, id)
+	err = row.Scan(
 		&pr.ID,
 		&pr.Name,
 		&pr.AuthorID,
 		&statusValue,
 		&pr.CreatedAt,
 		&pr.MergedAt,
+		&pr.CreatedBy,
+		&pr.MergedBy,
+		&pr.ReassignedBy,
+		&pr.Version,
 	)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
@@ -112,11 +149,11 @@ func (r *PullRequestRepository) GetByID(
 	const selectReviewers = `
 		SELECT reviewer_id
 		FROM pull_request_reviewers
-		WHERE pull_request_id = $1
+		WHERE org_id = $1 AND pull_request_id = $2
 		ORDER BY reviewer_id
 	`
 
-	rows, err := r.db.QueryContext(ctx, selectReviewers, id)
+	rows, err := dbtx(ctx, r.db).QueryContext(ctx, selectReviewers, org, id)
 	if err != nil {
 		return domain.PullRequest{}, fmt.Errorf("select pull_request_reviewers: %w", err)
 	}
@@ -141,9 +178,19 @@ func (r *PullRequestRepository) GetByID(
 	return pr, nil
 }
 
-// Update обновляет запись pull_requests и список ревьюверов.
-func (r *PullRequestRepository) Update(ctx context.Context, pr domain.PullRequest) (err error) {
-	tx, err := r.db.BeginTx(ctx, nil)
+// Update обновляет запись pull_requests, если её версия не изменилась, список ревьюверов
+// и записывает события в outbox.
+func (r *PullRequestRepository) Update(
+	ctx context.Context,
+	pr domain.PullRequest,
+	events ...domain.Event,
+) (err error) {
+	org, err := organizationID(ctx)
+	if err != nil {
+		return err
+	}
+
+	tx, err := beginTx(ctx, r.db)
 	if err != nil {
 		return fmt.Errorf("begin tx: %w", err)
 	}
@@ -156,21 +203,28 @@ func (r *PullRequestRepository) Update(ctx context.Context, pr domain.PullReques
 
 	const updatePR = `
 		UPDATE pull_requests
-		SET name = $2,
-		    author_id = $3,
-		    status = $4,
-		    merged_at = $5
-		WHERE id = $1
+		SET name = $3,
+		    author_id = $4,
+		    status = $5,
+		    merged_at = $6,
+		    merged_by = NULLIF($7, ''),
+		    reassigned_by = NULLIF($8, ''),
+		    version = version + 1
+		WHERE org_id = $1 AND id = $2 AND version = $9
 	`
 
 	res, err := tx.ExecContext(
 		ctx,
 		updatePR,
+		org,
 		pr.ID,
 		pr.Name,
 		pr.AuthorID,
 		string(pr.Status),
 		pr.MergedAt,
+		string(pr.MergedBy),
+		string(pr.ReassignedBy),
+		pr.Version,
 	)
 	if err != nil {
 		err = fmt.Errorf("update pull_requests: %w", err)
@@ -184,34 +238,102 @@ func (r *PullRequestRepository) Update(ctx context.Context, pr domain.PullReques
 	}
 
 	if rowsAffected == 0 {
-		err = repository.ErrNotFound
+		err = pullRequestMissingOrChanged(ctx, tx, org, pr.ID)
 		return err
 	}
 
-	const deleteReviewers = `
-		DELETE FROM pull_request_reviewers
-		WHERE pull_request_id = $1
-	`
+	if err = syncReviewers(ctx, tx, org, pr); err != nil {
+		return err
+	}
 
-	if _, err = tx.ExecContext(ctx, deleteReviewers, pr.ID); err != nil {
-		err = fmt.Errorf("delete pull_request_reviewers: %w", err)
+	if err = insertOutboxEvents(ctx, tx, events); err != nil {
 		return err
 	}
 
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("commit tx: %w", err)
+	}
+
+	return nil
+}
+
+// pullRequestMissingOrChanged объясняет, почему PR не обновился: ErrNotFound, если его нет,
+// и ErrConflict, если изменилась версия.
+func pullRequestMissingOrChanged(ctx context.Context, tx querier, org string, id domain.PullRequestID) error {
+	const query = `SELECT EXISTS (SELECT 1 FROM pull_requests WHERE org_id = $1 AND id = $2)`
+
+	var exists bool
+	if err := tx.QueryRowContext(ctx, query, org, id).Scan(&exists); err != nil {
+		return fmt.Errorf("check pull_requests: %w", err)
+	}
+
+	if !exists {
+		return repository.ErrNotFound
+	}
+
+	return repository.ErrConflict
+}
+
+// syncReviewers приводит список ревьюверов PR к pr.AssignedReviewers.
+// Оставшиеся ревьюверы не пересоздаются, чтобы сохранить время их назначения.
+func syncReviewers(ctx context.Context, tx querier, org string, pr domain.PullRequest) error {
+	const selectReviewers = `
+		SELECT reviewer_id
+		FROM pull_request_reviewers
+		WHERE org_id = $1 AND pull_request_id = $2
+		FOR UPDATE
+	`
+
+	rows, err := tx.QueryContext(ctx, selectReviewers, org, pr.ID)
+	if err != nil {
+		return fmt.Errorf("select pull_request_reviewers: %w", err)
+	}
+
+	current := make(map[domain.UserID]struct{})
+
+	for rows.Next() {
+		var id domain.UserID
+		if err := rows.Scan(&id); err != nil {
+			_ = rows.Close()
+			return fmt.Errorf("scan pull_request_reviewers: %w", err)
+		}
+
+		current[id] = struct{}{}
+	}
+
+	if err := rows.Close(); err != nil {
+		return fmt.Errorf("close pull_request_reviewers rows: %w", err)
+	}
+
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("iterate pull_request_reviewers: %w", err)
+	}
+
 	const insertReviewer = `
-		INSERT INTO pull_request_reviewers (pull_request_id, reviewer_id)
-		VALUES ($1, $2)
+		INSERT INTO pull_request_reviewers (org_id, pull_request_id, reviewer_id)
+		VALUES ($1, $2, $3)
 	`
 
-	for _, reviewerID := range pr.AssignedReviewers {
-		if _, err = tx.ExecContext(ctx, insertReviewer, pr.ID, reviewerID); err != nil {
-			err = fmt.Errorf("insert pull_request_reviewers: %w", err)
-			return err
+	for _, id := range pr.AssignedReviewers {
+		if _, ok := current[id]; ok {
+			delete(current, id)
+			continue
+		}
+
+		if _, err := tx.ExecContext(ctx, insertReviewer, org, pr.ID, id); err != nil {
+			return fmt.Errorf("insert pull_request_reviewers: %w", err)
 		}
 	}
 
-	if err = tx.Commit(); err != nil {
-		return fmt.Errorf("commit tx: %w", err)
+	const deleteReviewer = `
+		DELETE FROM pull_request_reviewers
+		WHERE org_id = $1 AND pull_request_id = $2 AND reviewer_id = $3
+	`
+
+	for id := range current {
+		if _, err := tx.ExecContext(ctx, deleteReviewer, org, pr.ID, id); err != nil {
+			return fmt.Errorf("delete pull_request_reviewers: %w", err)
+		}
 	}
 
 	return nil
@@ -222,6 +344,11 @@ func (r *PullRequestRepository) ListByReviewer(
 	ctx context.Context,
 	reviewerID domain.UserID,
 ) ([]domain.PullRequest, error) {
+	org, err := organizationID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
 	const query = `
 		SELECT
 			pr.id,
@@ -230,15 +357,19 @@ func (r *PullRequestRepository) ListByReviewer(
 			pr.status,
 			pr.created_at,
 			pr.merged_at,
+			COALESCE(pr.created_by, ''),
+			COALESCE(pr.merged_by, ''),
+			COALESCE(pr.reassigned_by, ''),
+			pr.version,
 			r.reviewer_id
 		FROM pull_requests pr
 		JOIN pull_request_reviewers r
-			ON r.pull_request_id = pr.id
-		WHERE r.reviewer_id = $1
+			ON r.org_id = pr.org_id AND r.pull_request_id = pr.id
+		WHERE pr.org_id = $1 AND r.reviewer_id = $2
 		ORDER BY pr.created_at DESC, pr.id, r.reviewer_id
 	`
 
-	rows, err := r.db.QueryContext(ctx, query, reviewerID)
+	rows, err := dbtx(ctx, r.db).QueryContext(ctx, query, org, reviewerID)
 	if err != nil {
 		return nil, fmt.Errorf("list pull_requests by reviewer: %w", err)
 	}
@@ -259,6 +390,8 @@ func (r *PullRequestRepository) ListByReviewer(
 			statusValue string
 			createdAt   *time.Time
 			mergedAt    *time.Time
+			actors      [3]domain.Actor
+			version     int64
 			reviewer    domain.UserID
 		)
 
@@ -269,6 +402,10 @@ func (r *PullRequestRepository) ListByReviewer(
 			&statusValue,
 			&createdAt,
 			&mergedAt,
+			&actors[0],
+			&actors[1],
+			&actors[2],
+			&version,
 			&reviewer,
 		); err != nil {
 			return nil, fmt.Errorf("scan list row: %w", err)
@@ -277,12 +414,16 @@ func (r *PullRequestRepository) ListByReviewer(
 		pr, exists := prByID[id]
 		if !exists {
 			newPR := &domain.PullRequest{
-				ID:        id,
-				Name:      name,
-				AuthorID:  authorID,
-				Status:    domain.PullRequestStatus(statusValue),
-				CreatedAt: createdAt,
-				MergedAt:  mergedAt,
+				ID:           id,
+				Name:         name,
+				AuthorID:     authorID,
+				Status:       domain.PullRequestStatus(statusValue),
+				CreatedAt:    createdAt,
+				MergedAt:     mergedAt,
+				CreatedBy:    actors[0],
+				MergedBy:     actors[1],
+				ReassignedBy: actors[2],
+				Version:      version,
 			}
 
 			prByID[id] = newPR
@@ -310,14 +451,20 @@ func (r *PullRequestRepository) ListByReviewer(
 func (r *PullRequestRepository) CountAssignmentsByReviewer(
 	ctx context.Context,
 ) (map[domain.UserID]int, error) {
+	org, err := organizationID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
 	const query = `
 		SELECT reviewer_id, COUNT(*) AS assignments
 		FROM pull_request_reviewers
+		WHERE org_id = $1
 		GROUP BY reviewer_id
 		ORDER BY reviewer_id
 	`
 
-	rows, err := r.db.QueryContext(ctx, query)
+	rows, err := dbtx(ctx, r.db).QueryContext(ctx, query, org)
 	if err != nil {
 		return nil, fmt.Errorf("count assignments by reviewer: %w", err)
 	}
@@ -351,14 +498,20 @@ func (r *PullRequestRepository) CountAssignmentsByReviewer(
 func (r *PullRequestRepository) CountAssignmentsByPullRequest(
 	ctx context.Context,
 ) (map[domain.PullRequestID]int, error) {
+	org, err := organizationID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
 	const query = `
 		SELECT pull_request_id, COUNT(*) AS reviewers
 		FROM pull_request_reviewers
+		WHERE org_id = $1
 		GROUP BY pull_request_id
 		ORDER BY pull_request_id
 	`
 
-	rows, err := r.db.QueryContext(ctx, query)
+	rows, err := dbtx(ctx, r.db).QueryContext(ctx, query, org)
 	if err != nil {
 		return nil, fmt.Errorf("count assignments by pull request: %w", err)
 	}
@@ -387,3 +540,100 @@ func (r *PullRequestRepository) CountAssignmentsByPullRequest(
 
 	return result, nil
 }
+
+// CountAssignmentsByTeam возвращает количество назначений по команде ревьювера.
+func (r *PullRequestRepository) CountAssignmentsByTeam(
+	ctx context.Context,
+) (map[domain.TeamName]int, error) {
+	org, err := organizationID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	const query = `
+		SELECT u.team_name, COUNT(*) AS assignments
+		FROM pull_request_reviewers r
+		JOIN users u ON u.org_id = r.org_id AND u.id = r.reviewer_id
+		WHERE r.org_id = $1
+		GROUP BY u.team_name
+		ORDER BY u.team_name
+	`
+
+	rows, err := dbtx(ctx, r.db).QueryContext(ctx, query, org)
+	if err != nil {
+		return nil, fmt.Errorf("count assignments by team: %w", err)
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	result := make(map[domain.TeamName]int)
+
+	for rows.Next() {
+		var (
+			teamName string
+			count    int
+		)
+
+		if err := rows.Scan(&teamName, &count); err != nil {
+			return nil, fmt.Errorf("scan assignments by team: %w", err)
+		}
+
+		result[domain.TeamName(teamName)] = count
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate assignments by team: %w", err)
+	}
+
+	return result, nil
+}
+
+// CountOpenReviewsByTeam возвращает количество назначений на открытые PR по основной команде ревьювера.
+func (r *PullRequestRepository) CountOpenReviewsByTeam(
+	ctx context.Context,
+) (map[domain.TeamName]int, error) {
+	org, err := organizationID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	const query = `
+		SELECT u.team_name, COUNT(*) AS reviews
+		FROM pull_request_reviewers r
+		JOIN pull_requests p ON p.org_id = r.org_id AND p.id = r.pull_request_id
+		JOIN users u ON u.org_id = r.org_id AND u.id = r.reviewer_id
+		WHERE r.org_id = $1 AND p.status = 'OPEN'
+		GROUP BY u.team_name
+		ORDER BY u.team_name
+	`
+
+	rows, err := dbtx(ctx, r.db).QueryContext(ctx, query, org)
+	if err != nil {
+		return nil, fmt.Errorf("count open reviews by team: %w", err)
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	result := make(map[domain.TeamName]int)
+
+	for rows.Next() {
+		var (
+			teamName string
+			count    int
+		)
+
+		if err := rows.Scan(&teamName, &count); err != nil {
+			return nil, fmt.Errorf("scan open reviews by team: %w", err)
+		}
+
+		result[domain.TeamName(teamName)] = count
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate open reviews by team: %w", err)
+	}
+
+	return result, nil
+}
@@ -0,0 +1,321 @@
+// Package postgres содержит реализацию репозиториев поверх PostgreSQL.
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/dixitix/pr-reviewer-service/internal/domain"
+	"github.com/dixitix/pr-reviewer-service/internal/event"
+	"github.com/dixitix/pr-reviewer-service/internal/repository"
+)
+
+// ListOpenAssignments возвращает назначения ревьюверов на открытые PR, сделанные раньше assignedBefore.
+func (r *PullRequestRepository) ListOpenAssignments(
+	ctx context.Context,
+	assignedBefore time.Time,
+) ([]domain.ReviewAssignment, error) {
+	org, err := organizationID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	const query = `
+		SELECT
+			pr.id,
+			pr.name,
+			pr.author_id,
+			pr.status,
+			pr.created_at,
+			a.team_name,
+			r.reviewer_id,
+			r.assigned_at,
+			r.reminded_at,
+			r.escalated_at
+		FROM pull_request_reviewers r
+		JOIN pull_requests pr
+			ON pr.org_id = r.org_id AND pr.id = r.pull_request_id
+		JOIN users a
+			ON a.org_id = pr.org_id AND a.id = pr.author_id
+		WHERE r.org_id = $1
+		  AND pr.status = 'OPEN'
+		  AND r.assigned_at < $2
+		ORDER BY r.assigned_at, pr.id, r.reviewer_id
+	`
+
+	rows, err := dbtx(ctx, r.db).QueryContext(ctx, query, org, assignedBefore)
+	if err != nil {
+		return nil, fmt.Errorf("list open assignments: %w", err)
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	result := make([]domain.ReviewAssignment, 0)
+
+	for rows.Next() {
+		var (
+			a         domain.ReviewAssignment
+			status    string
+			createdAt *time.Time
+		)
+
+		if err := rows.Scan(
+			&a.PullRequest.ID,
+			&a.PullRequest.Name,
+			&a.PullRequest.AuthorID,
+			&status,
+			&createdAt,
+			&a.TeamName,
+			&a.ReviewerID,
+			&a.AssignedAt,
+			&a.RemindedAt,
+			&a.EscalatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("scan open assignment: %w", err)
+		}
+
+		a.PullRequest.Status = domain.PullRequestStatus(status)
+		a.PullRequest.CreatedAt = createdAt
+
+		result = append(result, a)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate open assignments: %w", err)
+	}
+
+	return result, nil
+}
+
+// MarkReminded запоминает время напоминания ревьюверу и записывает события в outbox.
+func (r *PullRequestRepository) MarkReminded(
+	ctx context.Context,
+	prID domain.PullRequestID,
+	reviewerID domain.UserID,
+	at time.Time,
+	events ...domain.Event,
+) error {
+	const query = `
+		UPDATE pull_request_reviewers
+		SET reminded_at = $4
+		WHERE org_id = $1 AND pull_request_id = $2 AND reviewer_id = $3
+	`
+
+	return r.touchAssignment(ctx, query, prID, reviewerID, at, events)
+}
+
+// MarkEscalated запоминает время эскалации ревью и записывает события в outbox.
+func (r *PullRequestRepository) MarkEscalated(
+	ctx context.Context,
+	prID domain.PullRequestID,
+	reviewerID domain.UserID,
+	at time.Time,
+	events ...domain.Event,
+) error {
+	const query = `
+		UPDATE pull_request_reviewers
+		SET escalated_at = $4
+		WHERE org_id = $1 AND pull_request_id = $2 AND reviewer_id = $3
+	`
+
+	return r.touchAssignment(ctx, query, prID, reviewerID, at, events)
+}
+
+// touchAssignment обновляет отметку назначения и записывает события в одной транзакции.
+func (r *PullRequestRepository) touchAssignment(
+	ctx context.Context,
+	query string,
+	prID domain.PullRequestID,
+	reviewerID domain.UserID,
+	at time.Time,
+	events []domain.Event,
+) (err error) {
+	org, err := organizationID(ctx)
+	if err != nil {
+		return err
+	}
+
+	tx, err := beginTx(ctx, r.db)
+	if err != nil {
+		return fmt.Errorf("begin tx: %w", err)
+	}
+
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback()
+		}
+	}()
+
+	res, err := tx.ExecContext(ctx, query, org, string(prID), string(reviewerID), at)
+	if err != nil {
+		err = fmt.Errorf("update pull_request_reviewers: %w", err)
+		return err
+	}
+
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		err = fmt.Errorf("rows affected: %w", err)
+		return err
+	}
+
+	if rowsAffected == 0 {
+		err = repository.ErrNotFound
+		return err
+	}
+
+	if err = insertOutboxEvents(ctx, tx, events); err != nil {
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("commit tx: %w", err)
+	}
+
+	return nil
+}
+
+// ListHistory возвращает события PR в порядке их записи.
+func (r *PullRequestRepository) ListHistory(ctx context.Context, prID domain.PullRequestID) ([]domain.Event, error) {
+	org, err := organizationID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	const query = `
+		SELECT id, payload
+		FROM pull_request_history
+		WHERE org_id = $1 AND pull_request_id = $2
+		ORDER BY id
+	`
+
+	rows, err := dbtx(ctx, r.db).QueryContext(ctx, query, org, string(prID))
+	if err != nil {
+		return nil, fmt.Errorf("select pull_request_history: %w", err)
+	}
+
+	return scanHistory(rows)
+}
+
+// historyParticipants — JSON-массив автора и ревьюверов события из pull_request_history h:
+// назначенных, снятых и ревьювера напоминания или эскалации.
+const historyParticipants = `(
+	COALESCE(h.payload->'pull_request'->'assigned_reviewers', '[]'::jsonb)
+	|| COALESCE(h.payload->'removed_reviewers', '[]'::jsonb)
+	|| jsonb_build_array(h.payload->'pull_request'->>'author_id', COALESCE(h.payload->>'reviewer_id', ''))
+)`
+
+// ListEvents возвращает события всех PR организации, подходящие под фильтр, в порядке их записи.
+func (r *PullRequestRepository) ListEvents(ctx context.Context, filter repository.EventFilter) ([]domain.Event, error) {
+	org, err := organizationID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		query strings.Builder
+		args  = []any{org, filter.AfterSequence}
+	)
+
+	query.WriteString(`
+		SELECT h.id, h.payload
+		FROM pull_request_history h
+		WHERE h.org_id = $1 AND h.id > $2`)
+
+	if len(filter.Types) > 0 {
+		placeholders := make([]string, len(filter.Types))
+		for i, t := range filter.Types {
+			args = append(args, string(t))
+			placeholders[i] = fmt.Sprintf("$%d", len(args))
+		}
+
+		fmt.Fprintf(&query, "\n\t\tAND h.event_type IN (%s)", strings.Join(placeholders, ", "))
+	}
+
+	if filter.TeamName != "" {
+		args = append(args, string(filter.TeamName))
+		fmt.Fprintf(&query, `
+		AND EXISTS (
+			SELECT 1
+			FROM team_memberships m
+			WHERE m.org_id = h.org_id AND m.team_name = $%d AND %s ? m.user_id
+		)`, len(args), historyParticipants)
+	}
+
+	if filter.UserID != "" {
+		args = append(args, string(filter.UserID))
+		fmt.Fprintf(&query, "\n\t\tAND %s ? $%d", historyParticipants, len(args))
+	}
+
+	args = append(args, filter.Limit)
+	fmt.Fprintf(&query, "\n\t\tORDER BY h.id\n\t\tLIMIT $%d", len(args))
+
+	rows, err := dbtx(ctx, r.db).QueryContext(ctx, query.String(), args...)
+	if err != nil {
+		return nil, fmt.Errorf("select pull_request_history: %w", err)
+	}
+
+	return scanHistory(rows)
+}
+
+// LastEventSequence возвращает номер последнего события организации или 0, если событий нет.
+func (r *PullRequestRepository) LastEventSequence(ctx context.Context) (int64, error) {
+	org, err := organizationID(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	const query = `
+		SELECT COALESCE(MAX(id), 0)
+		FROM pull_request_history
+		WHERE org_id = $1
+	`
+
+	var seq int64
+	if err := dbtx(ctx, r.db).QueryRowContext(ctx, query, org).Scan(&seq); err != nil {
+		return 0, fmt.Errorf("select last pull_request_history id: %w", err)
+	}
+
+	return seq, nil
+}
+
+// scanHistory читает события из выборки pull_request_history (id, payload) и закрывает её.
+// Event.Sequence — id записи в истории.
+func scanHistory(rows *sql.Rows) ([]domain.Event, error) {
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	result := make([]domain.Event, 0)
+
+	for rows.Next() {
+		var (
+			id      int64
+			payload string
+		)
+
+		if err := rows.Scan(&id, &payload); err != nil {
+			return nil, fmt.Errorf("scan pull_request_history: %w", err)
+		}
+
+		var msg event.Message
+		if err := json.Unmarshal([]byte(payload), &msg); err != nil {
+			return nil, fmt.Errorf("unmarshal pull_request_history %d: %w", id, err)
+		}
+
+		e := msg.Domain()
+		e.Sequence = id
+
+		result = append(result, e)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate pull_request_history: %w", err)
+	}
+
+	return result, nil
+}
@@ -6,11 +6,15 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
+	"time"
 
 	"github.com/dixitix/pr-reviewer-service/internal/domain"
 	"github.com/dixitix/pr-reviewer-service/internal/repository"
 )
 
+// maxTeamDepth ограничивает обход иерархии команд на случай цикла в данных.
+const maxTeamDepth = 32
+
 // TeamRepository реализует repository.TeamRepository поверх *sql.DB.
 type TeamRepository struct {
 	db *sql.DB
@@ -23,37 +27,49 @@ func NewTeamRepository(db *sql.DB) repository.TeamRepository {
 
 // CreateTeam создаёт запись о команде.
 func (r *TeamRepository) CreateTeam(ctx context.Context, team domain.Team) error {
-	exists, err := r.TeamExists(ctx, team.Name)
+	org, err := organizationID(ctx)
 	if err != nil {
-		return fmt.Errorf("check team %s exists: %w", team.Name, err)
-	}
-
-	if exists {
-		return repository.ErrAlreadyExists
+		return err
 	}
 
 	const query = `
-		INSERT INTO teams (name)
-		VALUES ($1)
+		INSERT INTO teams (org_id, name, parent_name, fallback_to_parent)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (org_id, name) DO NOTHING
 	`
 
-	if _, err := r.db.ExecContext(ctx, query, string(team.Name)); err != nil {
+	res, err := dbtx(ctx, r.db).ExecContext(ctx, query, org, string(team.Name), nullTeamName(team.Parent), team.FallbackToParent)
+	if err != nil {
 		return fmt.Errorf("insert team %s: %w", team.Name, err)
 	}
 
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return repository.ErrAlreadyExists
+	}
+
 	return nil
 }
 
 // TeamExists возвращает true, если команда с таким именем существует.
 func (r *TeamRepository) TeamExists(ctx context.Context, name domain.TeamName) (bool, error) {
+	org, err := organizationID(ctx)
+	if err != nil {
+		return false, err
+	}
+
 	const query = `
 		SELECT 1
 		FROM teams
-		WHERE name = $1
+		WHERE org_id = $1 AND name = $2
 	`
 
 	var dummy int
-	err := r.db.QueryRowContext(ctx, query, string(name)).Scan(&dummy)
+	err = dbtx(ctx, r.db).QueryRowContext(ctx, query, org, string(name)).Scan(&dummy)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return false, nil
@@ -70,14 +86,26 @@ func (r *TeamRepository) GetTeamWithMembers(
 	ctx context.Context,
 	name domain.TeamName,
 ) (domain.Team, []domain.User, error) {
+	org, err := organizationID(ctx)
+	if err != nil {
+		return domain.Team{}, nil, err
+	}
+
 	const teamQuery = `
-		SELECT name
+		SELECT name, parent_name, fallback_to_parent, version
 		FROM teams
-		WHERE name = $1
+		WHERE org_id = $1 AND name = $2
 	`
 
-	var teamName string
-	if err := r.db.QueryRowContext(ctx, teamQuery, string(name)).Scan(&teamName); err != nil {
+	var (
+		teamName         string
+		parent           sql.NullString
+		fallbackToParent bool
+		version          int64
+	)
+
+	err = dbtx(ctx, r.db).QueryRowContext(ctx, teamQuery, org, string(name)).Scan(&teamName, &parent, &fallbackToParent, &version)
+	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return domain.Team{}, nil, repository.ErrNotFound
 		}
@@ -86,13 +114,14 @@ func (r *TeamRepository) GetTeamWithMembers(
 	}
 
 	const membersQuery = `
-		SELECT id, username, team_name, is_active
-		FROM users
-		WHERE team_name = $1
-		ORDER BY id
+		SELECT u.id, u.username, u.team_name, u.is_active
+		FROM team_memberships m
+		JOIN users u ON u.org_id = m.org_id AND u.id = m.user_id
+		WHERE m.org_id = $1 AND m.team_name = $2
+		ORDER BY u.id
 	`
 
-	rows, err := r.db.QueryContext(ctx, membersQuery, teamName)
+	rows, err := dbtx(ctx, r.db).QueryContext(ctx, membersQuery, org, teamName)
 	if err != nil {
 		return domain.Team{}, nil, fmt.Errorf("query members for team %s: %w", name, err)
 	}
@@ -126,24 +155,133 @@ func (r *TeamRepository) GetTeamWithMembers(
 		return domain.Team{}, nil, fmt.Errorf("iterate members for team %s: %w", name, err)
 	}
 
+	leads, err := r.ListLeads(ctx, name)
+	if err != nil {
+		return domain.Team{}, nil, err
+	}
+
 	team := domain.Team{
-		Name: name,
+		Name:             name,
+		Parent:           domain.TeamName(parent.String),
+		FallbackToParent: fallbackToParent,
+		Leads:            make([]domain.UserID, len(leads)),
+		Version:          version,
+	}
+
+	for i, lead := range leads {
+		team.Leads[i] = lead.ID
 	}
 
 	return team, members, nil
 }
 
+// ListLeads возвращает лидов команды.
+func (r *TeamRepository) ListLeads(ctx context.Context, teamName domain.TeamName) ([]domain.User, error) {
+	org, err := organizationID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	const query = `
+		SELECT u.id, u.username, u.team_name, u.is_active
+		FROM team_leads l
+		JOIN users u ON u.org_id = l.org_id AND u.id = l.user_id
+		WHERE l.org_id = $1 AND l.team_name = $2
+		ORDER BY u.id
+	`
+
+	rows, err := dbtx(ctx, r.db).QueryContext(ctx, query, org, string(teamName))
+	if err != nil {
+		return nil, fmt.Errorf("query leads for team %s: %w", teamName, err)
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	leads := make([]domain.User, 0)
+
+	for rows.Next() {
+		var u domain.User
+
+		if err := rows.Scan(&u.ID, &u.Username, &u.TeamName, &u.IsActive); err != nil {
+			return nil, fmt.Errorf("scan lead row for team %s: %w", teamName, err)
+		}
+
+		leads = append(leads, u)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate leads for team %s: %w", teamName, err)
+	}
+
+	return leads, nil
+}
+
+// SetLeads заменяет список лидов команды.
+func (r *TeamRepository) SetLeads(ctx context.Context, teamName domain.TeamName, leads []domain.UserID) (err error) {
+	org, err := organizationID(ctx)
+	if err != nil {
+		return err
+	}
+
+	tx, err := beginTx(ctx, r.db)
+	if err != nil {
+		return fmt.Errorf("begin tx for set leads of team %s: %w", teamName, err)
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback()
+		}
+	}()
+
+	const deleteQuery = `
+		DELETE FROM team_leads
+		WHERE org_id = $1 AND team_name = $2
+	`
+
+	if _, err = tx.ExecContext(ctx, deleteQuery, org, string(teamName)); err != nil {
+		return fmt.Errorf("delete leads of team %s: %w", teamName, err)
+	}
+
+	const insertQuery = `
+		INSERT INTO team_leads (org_id, team_name, user_id)
+		VALUES ($1, $2, $3)
+		ON CONFLICT DO NOTHING
+	`
+
+	for _, id := range leads {
+		if _, err = tx.ExecContext(ctx, insertQuery, org, string(teamName), string(id)); err != nil {
+			return fmt.Errorf("insert lead %s of team %s: %w", id, teamName, err)
+		}
+	}
+
+	if err = bumpTeamVersion(ctx, tx, org, teamName); err != nil {
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("commit set leads of team %s: %w", teamName, err)
+	}
+
+	return nil
+}
+
 // UpsertMembers создаёт или обновляет пользователей команды по их ID.
 func (r *TeamRepository) UpsertMembers(
 	ctx context.Context,
 	teamName domain.TeamName,
 	members []domain.User,
-) error {
+) (err error) {
 	if len(members) == 0 {
 		return nil
 	}
 
-	tx, err := r.db.BeginTx(ctx, nil)
+	org, err := organizationID(ctx)
+	if err != nil {
+		return err
+	}
+
+	tx, err := beginTx(ctx, r.db)
 	if err != nil {
 		return fmt.Errorf("begin tx for upsert members of team %s: %w", teamName, err)
 	}
@@ -154,19 +292,41 @@ func (r *TeamRepository) UpsertMembers(
 	}()
 
 	const query = `
-		INSERT INTO users (id, username, team_name, is_active)
-		VALUES ($1, $2, $3, $4)
-		ON CONFLICT (id) DO UPDATE
+		INSERT INTO users (org_id, id, username, team_name, is_active)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (org_id, id) DO UPDATE
 		SET
 			username  = EXCLUDED.username,
 			team_name = EXCLUDED.team_name,
 			is_active = EXCLUDED.is_active
 	`
 
+	// Прежняя основная команда пользователя перестаёт быть его командой.
+	const dropPrimaryQuery = `
+		DELETE FROM team_memberships
+		WHERE org_id = $1
+		  AND user_id = $2
+		  AND is_primary
+		  AND team_name <> $3
+	`
+
+	const membershipQuery = `
+		INSERT INTO team_memberships (org_id, team_name, user_id, is_primary)
+		VALUES ($1, $2, $3, TRUE)
+		ON CONFLICT (org_id, team_name, user_id) DO UPDATE
+		SET is_primary = TRUE
+	`
+
 	for _, m := range members {
-		_, err := tx.ExecContext(
+		// Пользователь может уйти из прежней основной команды — её состав тоже меняется.
+		if err = bumpMemberTeamsVersion(ctx, tx, org, m.ID); err != nil {
+			return err
+		}
+
+		_, err = tx.ExecContext(
 			ctx,
 			query,
+			org,
 			string(m.ID),
 			m.Username,
 			string(teamName),
@@ -175,11 +335,501 @@ func (r *TeamRepository) UpsertMembers(
 		if err != nil {
 			return fmt.Errorf("upsert member %s for team %s: %w", m.ID, teamName, err)
 		}
+
+		if _, err = tx.ExecContext(ctx, dropPrimaryQuery, org, string(m.ID), string(teamName)); err != nil {
+			return fmt.Errorf("drop previous primary team of member %s: %w", m.ID, err)
+		}
+
+		if _, err = tx.ExecContext(ctx, membershipQuery, org, string(teamName), string(m.ID)); err != nil {
+			return fmt.Errorf("upsert membership of %s in team %s: %w", m.ID, teamName, err)
+		}
+	}
+
+	if err = bumpTeamVersion(ctx, tx, org, teamName); err != nil {
+		return err
 	}
 
-	if err := tx.Commit(); err != nil {
+	if err = tx.Commit(); err != nil {
 		return fmt.Errorf("commit upsert members for team %s: %w", teamName, err)
 	}
 
 	return nil
 }
+
+// AddMember добавляет пользователя в команду; если primary, команда становится основной.
+func (r *TeamRepository) AddMember(
+	ctx context.Context,
+	teamName domain.TeamName,
+	userID domain.UserID,
+	primary bool,
+) (err error) {
+	org, err := organizationID(ctx)
+	if err != nil {
+		return err
+	}
+
+	tx, err := beginTx(ctx, r.db)
+	if err != nil {
+		return fmt.Errorf("begin tx for add member %s to team %s: %w", userID, teamName, err)
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback()
+		}
+	}()
+
+	if primary {
+		// Прежняя основная команда остаётся дополнительной.
+		const demoteQuery = `
+			UPDATE team_memberships
+			SET is_primary = FALSE
+			WHERE org_id = $1
+			  AND user_id = $2
+			  AND is_primary
+			  AND team_name <> $3
+		`
+
+		if _, err = tx.ExecContext(ctx, demoteQuery, org, string(userID), string(teamName)); err != nil {
+			return fmt.Errorf("demote primary team of %s: %w", userID, err)
+		}
+
+		const userQuery = `
+			UPDATE users
+			SET team_name = $3
+			WHERE org_id = $1 AND id = $2
+		`
+
+		if _, err = tx.ExecContext(ctx, userQuery, org, string(userID), string(teamName)); err != nil {
+			return fmt.Errorf("set primary team of %s: %w", userID, err)
+		}
+	}
+
+	const insertQuery = `
+		INSERT INTO team_memberships (org_id, team_name, user_id, is_primary)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (org_id, team_name, user_id) DO UPDATE
+		SET is_primary = team_memberships.is_primary OR EXCLUDED.is_primary
+	`
+
+	if _, err = tx.ExecContext(ctx, insertQuery, org, string(teamName), string(userID), primary); err != nil {
+		return fmt.Errorf("insert membership of %s in team %s: %w", userID, teamName, err)
+	}
+
+	// Основная команда пользователя видна в составе всех его команд.
+	if err = bumpMemberTeamsVersion(ctx, tx, org, userID); err != nil {
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("commit add member %s to team %s: %w", userID, teamName, err)
+	}
+
+	return nil
+}
+
+// RemoveMember удаляет дополнительное членство пользователя в команде.
+func (r *TeamRepository) RemoveMember(ctx context.Context, teamName domain.TeamName, userID domain.UserID) error {
+	org, err := organizationID(ctx)
+	if err != nil {
+		return err
+	}
+
+	const query = `
+		DELETE FROM team_memberships
+		WHERE org_id = $1
+		  AND team_name = $2
+		  AND user_id = $3
+		  AND NOT is_primary
+	`
+
+	res, err := dbtx(ctx, r.db).ExecContext(ctx, query, org, string(teamName), string(userID))
+	if err != nil {
+		return fmt.Errorf("delete membership of %s in team %s: %w", userID, teamName, err)
+	}
+
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return repository.ErrNotFound
+	}
+
+	return bumpTeamVersion(ctx, dbtx(ctx, r.db), org, teamName)
+}
+
+// LockTeam блокирует команду до конца транзакции и возвращает её версию.
+func (r *TeamRepository) LockTeam(ctx context.Context, name domain.TeamName) (int64, error) {
+	org, err := organizationID(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	const query = `
+		SELECT version
+		FROM teams
+		WHERE org_id = $1 AND name = $2
+		FOR UPDATE
+	`
+
+	var version int64
+	if err := dbtx(ctx, r.db).QueryRowContext(ctx, query, org, string(name)).Scan(&version); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return 0, repository.ErrNotFound
+		}
+
+		return 0, fmt.Errorf("lock team %s: %w", name, err)
+	}
+
+	return version, nil
+}
+
+// bumpTeamVersion увеличивает версию команды после изменения её лидов или состава.
+func bumpTeamVersion(ctx context.Context, q querier, org string, name domain.TeamName) error {
+	const query = `
+		UPDATE teams
+		SET version = version + 1
+		WHERE org_id = $1 AND name = $2
+	`
+
+	if _, err := q.ExecContext(ctx, query, org, string(name)); err != nil {
+		return fmt.Errorf("bump version of team %s: %w", name, err)
+	}
+
+	return nil
+}
+
+// bumpMemberTeamsVersion увеличивает версии всех команд пользователя: их состав показывает
+// его активность и основную команду.
+func bumpMemberTeamsVersion(ctx context.Context, q querier, org string, userID domain.UserID) error {
+	const query = `
+		UPDATE teams t
+		SET version = t.version + 1
+		FROM team_memberships m
+		WHERE m.org_id = $1 AND m.user_id = $2
+		  AND t.org_id = m.org_id AND t.name = m.team_name
+	`
+
+	if _, err := q.ExecContext(ctx, query, org, string(userID)); err != nil {
+		return fmt.Errorf("bump versions of teams of user %s: %w", userID, err)
+	}
+
+	return nil
+}
+
+// GetReviewPolicy возвращает SLA ревью команды.
+func (r *TeamRepository) GetReviewPolicy(ctx context.Context, teamName domain.TeamName) (domain.ReviewPolicy, error) {
+	org, err := organizationID(ctx)
+	if err != nil {
+		return domain.ReviewPolicy{}, err
+	}
+
+	const query = `
+		SELECT reminder_after_seconds, escalate_after_seconds, escalation_action
+		FROM team_review_policies
+		WHERE org_id = $1 AND team_name = $2
+	`
+
+	policy := domain.ReviewPolicy{TeamName: teamName}
+
+	var reminderAfter, escalateAfter int64
+
+	err = dbtx(ctx, r.db).QueryRowContext(ctx, query, org, string(teamName)).Scan(&reminderAfter, &escalateAfter, &policy.Action)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return domain.ReviewPolicy{}, repository.ErrNotFound
+		}
+
+		return domain.ReviewPolicy{}, fmt.Errorf("select team_review_policies: %w", err)
+	}
+
+	policy.ReminderAfter = time.Duration(reminderAfter) * time.Second
+	policy.EscalateAfter = time.Duration(escalateAfter) * time.Second
+
+	return policy, nil
+}
+
+// UpsertReviewPolicy создаёт или обновляет SLA ревью команды.
+func (r *TeamRepository) UpsertReviewPolicy(ctx context.Context, policy domain.ReviewPolicy) error {
+	org, err := organizationID(ctx)
+	if err != nil {
+		return err
+	}
+
+	const query = `
+		INSERT INTO team_review_policies (org_id, team_name, reminder_after_seconds, escalate_after_seconds, escalation_action)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (org_id, team_name) DO UPDATE
+		SET reminder_after_seconds = EXCLUDED.reminder_after_seconds,
+		    escalate_after_seconds = EXCLUDED.escalate_after_seconds,
+		    escalation_action = EXCLUDED.escalation_action,
+		    updated_at = now()
+	`
+
+	_, err = dbtx(ctx, r.db).ExecContext(
+		ctx,
+		query,
+		org,
+		string(policy.TeamName),
+		int64(policy.ReminderAfter/time.Second),
+		int64(policy.EscalateAfter/time.Second),
+		string(policy.Action),
+	)
+	if err != nil {
+		return fmt.Errorf("upsert team_review_policies: %w", err)
+	}
+
+	return nil
+}
+
+// ListReviewPolicies возвращает SLA ревью всех команд, для которых оно задано.
+func (r *TeamRepository) ListReviewPolicies(ctx context.Context) ([]domain.ReviewPolicy, error) {
+	org, err := organizationID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	const query = `
+		SELECT team_name, reminder_after_seconds, escalate_after_seconds, escalation_action
+		FROM team_review_policies
+		WHERE org_id = $1
+		ORDER BY team_name
+	`
+
+	rows, err := dbtx(ctx, r.db).QueryContext(ctx, query, org)
+	if err != nil {
+		return nil, fmt.Errorf("list team_review_policies: %w", err)
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	result := make([]domain.ReviewPolicy, 0)
+
+	for rows.Next() {
+		var (
+			policy                       domain.ReviewPolicy
+			reminderAfter, escalateAfter int64
+		)
+
+		if err := rows.Scan(&policy.TeamName, &reminderAfter, &escalateAfter, &policy.Action); err != nil {
+			return nil, fmt.Errorf("scan team_review_policies: %w", err)
+		}
+
+		policy.ReminderAfter = time.Duration(reminderAfter) * time.Second
+		policy.EscalateAfter = time.Duration(escalateAfter) * time.Second
+
+		result = append(result, policy)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate team_review_policies: %w", err)
+	}
+
+	return result, nil
+}
+
+// SetParent меняет родительскую команду и разрешение искать ревьюверов вверх по иерархии.
+func (r *TeamRepository) SetParent(
+	ctx context.Context,
+	name domain.TeamName,
+	parent domain.TeamName,
+	fallbackToParent bool,
+) error {
+	org, err := organizationID(ctx)
+	if err != nil {
+		return err
+	}
+
+	const query = `
+		UPDATE teams
+		SET parent_name = $3,
+		    fallback_to_parent = $4,
+		    version = version + 1
+		WHERE org_id = $1 AND name = $2
+	`
+
+	res, err := dbtx(ctx, r.db).ExecContext(ctx, query, org, string(name), nullTeamName(parent), fallbackToParent)
+	if err != nil {
+		return fmt.Errorf("update parent of team %s: %w", name, err)
+	}
+
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return repository.ErrNotFound
+	}
+
+	return nil
+}
+
+// ListTeamChain возвращает команду и всех её предков от ближайшего к корню.
+func (r *TeamRepository) ListTeamChain(ctx context.Context, name domain.TeamName) ([]domain.Team, error) {
+	org, err := organizationID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	const query = `
+		WITH RECURSIVE chain AS (
+			SELECT name, parent_name, fallback_to_parent, 0 AS depth
+			FROM teams
+			WHERE org_id = $1 AND name = $2
+			UNION ALL
+			SELECT t.name, t.parent_name, t.fallback_to_parent, c.depth + 1
+			FROM teams t
+			JOIN chain c ON t.name = c.parent_name
+			WHERE t.org_id = $1 AND c.depth < $3
+		)
+		SELECT name, parent_name, fallback_to_parent
+		FROM chain
+		ORDER BY depth
+	`
+
+	rows, err := dbtx(ctx, r.db).QueryContext(ctx, query, org, string(name), maxTeamDepth)
+	if err != nil {
+		return nil, fmt.Errorf("query chain of team %s: %w", name, err)
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	chain := make([]domain.Team, 0)
+
+	for rows.Next() {
+		team, err := scanTeam(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan chain of team %s: %w", name, err)
+		}
+
+		chain = append(chain, team)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate chain of team %s: %w", name, err)
+	}
+
+	if len(chain) == 0 {
+		return nil, repository.ErrNotFound
+	}
+
+	return chain, nil
+}
+
+// ListTeams возвращает все команды с их родителями, без лидов.
+func (r *TeamRepository) ListTeams(ctx context.Context) ([]domain.Team, error) {
+	org, err := organizationID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	const query = `
+		SELECT name, parent_name, fallback_to_parent
+		FROM teams
+		WHERE org_id = $1
+		ORDER BY name
+	`
+
+	rows, err := dbtx(ctx, r.db).QueryContext(ctx, query, org)
+	if err != nil {
+		return nil, fmt.Errorf("query teams: %w", err)
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	teams := make([]domain.Team, 0)
+
+	for rows.Next() {
+		team, err := scanTeam(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan team: %w", err)
+		}
+
+		teams = append(teams, team)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate teams: %w", err)
+	}
+
+	return teams, nil
+}
+
+// ListSubtreeMembers возвращает участников команды и всех её дочерних команд.
+func (r *TeamRepository) ListSubtreeMembers(ctx context.Context, name domain.TeamName) ([]domain.User, error) {
+	org, err := organizationID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	const query = `
+		WITH RECURSIVE subtree AS (
+			SELECT name
+			FROM teams
+			WHERE org_id = $1 AND name = $2
+			UNION
+			SELECT t.name
+			FROM teams t
+			JOIN subtree s ON t.parent_name = s.name
+			WHERE t.org_id = $1
+		)
+		SELECT DISTINCT u.id, u.username, u.team_name, u.is_active
+		FROM team_memberships m
+		JOIN subtree s ON s.name = m.team_name
+		JOIN users u ON u.org_id = m.org_id AND u.id = m.user_id
+		WHERE m.org_id = $1
+		ORDER BY u.team_name, u.id
+	`
+
+	rows, err := dbtx(ctx, r.db).QueryContext(ctx, query, org, string(name))
+	if err != nil {
+		return nil, fmt.Errorf("query subtree members of team %s: %w", name, err)
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	members := make([]domain.User, 0)
+
+	for rows.Next() {
+		var u domain.User
+
+		if err := rows.Scan(&u.ID, &u.Username, &u.TeamName, &u.IsActive); err != nil {
+			return nil, fmt.Errorf("scan subtree member of team %s: %w", name, err)
+		}
+
+		members = append(members, u)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate subtree members of team %s: %w", name, err)
+	}
+
+	return members, nil
+}
+
+// scanTeam читает строку (name, parent_name, fallback_to_parent).
+func scanTeam(rows *sql.Rows) (domain.Team, error) {
+	var (
+		team   domain.Team
+		parent sql.NullString
+	)
+
+	if err := rows.Scan(&team.Name, &parent, &team.FallbackToParent); err != nil {
+		return domain.Team{}, err
+	}
+
+	team.Parent = domain.TeamName(parent.String)
+
+	return team, nil
+}
+
+// nullTeamName превращает пустое имя команды в NULL.
+func nullTeamName(name domain.TeamName) sql.NullString {
+	return sql.NullString{String: string(name), Valid: name != ""}
+}
@@ -0,0 +1,112 @@
+// Package postgres содержит реализацию репозиториев поверх PostgreSQL.
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/dixitix/pr-reviewer-service/internal/repository"
+)
+
+// txKey — ключ транзакции Transactor в контексте.
+type txKey struct{}
+
+// querier — общие методы *sql.DB и *sql.Tx, через которые репозитории выполняют запросы.
+type querier interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+// Transactor реализует repository.Transactor поверх *sql.DB.
+type Transactor struct {
+	db *sql.DB
+}
+
+// NewTransactor создаёт новый экземпляр Transactor.
+func NewTransactor(db *sql.DB) repository.Transactor {
+	return &Transactor{db: db}
+}
+
+// WithinTransaction выполняет fn в транзакции, которую через контекст разделяют все репозитории.
+// Вложенный вызов выполняется в уже открытой транзакции.
+func (t *Transactor) WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) (err error) {
+	if _, ok := ctx.Value(txKey{}).(*sql.Tx); ok {
+		return fn(ctx)
+	}
+
+	tx, err := t.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin tx: %w", err)
+	}
+
+	defer func() {
+		if p := recover(); p != nil {
+			_ = tx.Rollback()
+			panic(p)
+		}
+
+		if err != nil {
+			_ = tx.Rollback()
+		}
+	}()
+
+	if err = fn(context.WithValue(ctx, txKey{}, tx)); err != nil {
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("commit tx: %w", err)
+	}
+
+	return nil
+}
+
+// dbtx возвращает транзакцию Transactor из контекста, а без неё — db.
+func dbtx(ctx context.Context, db *sql.DB) querier {
+	if tx, ok := ctx.Value(txKey{}).(*sql.Tx); ok {
+		return tx
+	}
+
+	return db
+}
+
+// localTx — транзакция метода репозитория. Внутри Transactor это его транзакция, и фиксирует
+// или откатывает её только WithinTransaction.
+type localTx struct {
+	*sql.Tx
+	owned bool
+}
+
+// beginTx начинает транзакцию метода репозитория или присоединяется к транзакции Transactor.
+func beginTx(ctx context.Context, db *sql.DB) (*localTx, error) {
+	if tx, ok := ctx.Value(txKey{}).(*sql.Tx); ok {
+		return &localTx{Tx: tx}, nil
+	}
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return &localTx{Tx: tx, owned: true}, nil
+}
+
+// Commit фиксирует собственную транзакцию.
+func (t *localTx) Commit() error {
+	if !t.owned {
+		return nil
+	}
+
+	return t.Tx.Commit()
+}
+
+// Rollback откатывает собственную транзакцию. Общую транзакцию откатит WithinTransaction по ошибке.
+func (t *localTx) Rollback() error {
+	if !t.owned {
+		return nil
+	}
+
+	return t.Tx.Rollback()
+}
@@ -26,10 +26,15 @@ func (r *UserRepository) GetByID(
 	ctx context.Context,
 	id domain.UserID,
 ) (domain.User, error) {
+	org, err := organizationID(ctx)
+	if err != nil {
+		return domain.User{}, err
+	}
+
 	const query = `
 		SELECT id, username, team_name, is_active
 		FROM users
-		WHERE id = $1
+		WHERE org_id = $1 AND id = $2
 	`
 
 	var (
@@ -39,7 +44,7 @@ func (r *UserRepository) GetByID(
 		isActive bool
 	)
 
-	err := r.db.QueryRowContext(ctx, query, string(id)).Scan(&userID, &username, &teamName, &isActive)
+	err = dbtx(ctx, r.db).QueryRowContext(ctx, query, org, string(id)).Scan(&userID, &username, &teamName, &isActive)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return domain.User{}, repository.ErrNotFound
@@ -62,13 +67,18 @@ func (r *UserRepository) SetActive(
 	id domain.UserID,
 	isActive bool,
 ) error {
+	org, err := organizationID(ctx)
+	if err != nil {
+		return err
+	}
+
 	const query = `
 		UPDATE users
-		SET is_active = $2
-		WHERE id = $1
+		SET is_active = $3
+		WHERE org_id = $1 AND id = $2
 	`
 
-	res, err := r.db.ExecContext(ctx, query, string(id), isActive)
+	res, err := dbtx(ctx, r.db).ExecContext(ctx, query, org, string(id), isActive)
 	if err != nil {
 		return fmt.Errorf("update user %s active=%t: %w", id, isActive, err)
 	}
@@ -82,34 +92,41 @@ func (r *UserRepository) SetActive(
 		return repository.ErrNotFound
 	}
 
-	return nil
+	return bumpMemberTeamsVersion(ctx, dbtx(ctx, r.db), org, id)
 }
 
-// ListActiveByTeam возвращает активных пользователей команды.
+// ListActiveByTeam возвращает активных участников команды, включая тех, для кого она не основная.
 // Если excludeID != nil, этот пользователь исключается из результата.
 func (r *UserRepository) ListActiveByTeam(
 	ctx context.Context,
 	teamName domain.TeamName,
 	excludeID *domain.UserID,
 ) ([]domain.User, error) {
+	org, err := organizationID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
 	baseQuery := `
-		SELECT id, username, team_name, is_active
-		FROM users
-		WHERE team_name = $1
-		  AND is_active = TRUE
+		SELECT u.id, u.username, u.team_name, u.is_active
+		FROM team_memberships m
+		JOIN users u ON u.org_id = m.org_id AND u.id = m.user_id
+		WHERE m.org_id = $1
+		  AND m.team_name = $2
+		  AND u.is_active = TRUE
 	`
 
-	args := []any{string(teamName)}
+	args := []any{org, string(teamName)}
 
 	// Динамически добавляем фильтр исключения, если нужно.
 	if excludeID != nil {
-		baseQuery += " AND id <> $2"
+		baseQuery += " AND u.id <> $3"
 		args = append(args, string(*excludeID))
 	}
 
-	baseQuery += " ORDER BY id"
+	baseQuery += " ORDER BY u.id"
 
-	rows, err := r.db.QueryContext(ctx, baseQuery, args...)
+	rows, err := dbtx(ctx, r.db).QueryContext(ctx, baseQuery, args...)
 	if err != nil {
 		return nil, fmt.Errorf("list active users for team %s: %w", teamName, err)
 	}
@@ -149,3 +166,44 @@ func (r *UserRepository) ListActiveByTeam(
 
 	return users, nil
 }
+
+// ListMemberships возвращает команды пользователя, основную первой.
+func (r *UserRepository) ListMemberships(ctx context.Context, id domain.UserID) ([]domain.TeamMembership, error) {
+	org, err := organizationID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	const query = `
+		SELECT team_name, user_id, is_primary
+		FROM team_memberships
+		WHERE org_id = $1 AND user_id = $2
+		ORDER BY is_primary DESC, team_name
+	`
+
+	rows, err := dbtx(ctx, r.db).QueryContext(ctx, query, org, string(id))
+	if err != nil {
+		return nil, fmt.Errorf("list memberships of user %s: %w", id, err)
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	memberships := make([]domain.TeamMembership, 0)
+
+	for rows.Next() {
+		var m domain.TeamMembership
+
+		if err := rows.Scan(&m.TeamName, &m.UserID, &m.IsPrimary); err != nil {
+			return nil, fmt.Errorf("scan membership of user %s: %w", id, err)
+		}
+
+		memberships = append(memberships, m)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate memberships of user %s: %w", id, err)
+	}
+
+	return memberships, nil
+}
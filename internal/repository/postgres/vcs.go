@@ -0,0 +1,198 @@
+// Package postgres содержит реализацию репозиториев поверх PostgreSQL.
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/dixitix/pr-reviewer-service/internal/domain"
+	"github.com/dixitix/pr-reviewer-service/internal/repository"
+)
+
+// VCSSyncRepository реализует repository.VCSSyncRepository поверх *sql.DB.
+type VCSSyncRepository struct {
+	db *sql.DB
+}
+
+// NewVCSSyncRepository создаёт новый экземпляр VCSSyncRepository.
+func NewVCSSyncRepository(db *sql.DB) repository.VCSSyncRepository {
+	return &VCSSyncRepository{db: db}
+}
+
+// SaveFailure сохраняет неудачное изменение ревьюверов. Организация берётся из failure.OrganizationID,
+// а не из контекста: очередь синхронизации общая для всех организаций.
+func (r *VCSSyncRepository) SaveFailure(
+	ctx context.Context,
+	failure repository.VCSSyncFailure,
+	retryAt time.Time,
+) (int64, error) {
+	add, err := json.Marshal(userIDsOrEmpty(failure.Add))
+	if err != nil {
+		return 0, fmt.Errorf("marshal add_reviewers: %w", err)
+	}
+
+	remove, err := json.Marshal(userIDsOrEmpty(failure.Remove))
+	if err != nil {
+		return 0, fmt.Errorf("marshal remove_reviewers: %w", err)
+	}
+
+	if failure.ID == 0 {
+		const query = `
+			INSERT INTO vcs_sync_failures
+				(org_id, pull_request_id, add_reviewers, remove_reviewers, attempts, last_error, permanent, failed_at, retry_at)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+			RETURNING id
+		`
+
+		var id int64
+
+		err := dbtx(ctx, r.db).QueryRowContext(
+			ctx,
+			query,
+			string(failure.OrganizationID),
+			string(failure.PullRequestID),
+			string(add),
+			string(remove),
+			failure.Attempts,
+			failure.LastError,
+			failure.Permanent,
+			failure.FailedAt,
+			retryAt,
+		).Scan(&id)
+		if err != nil {
+			return 0, fmt.Errorf("insert vcs_sync_failures: %w", err)
+		}
+
+		return id, nil
+	}
+
+	const query = `
+		UPDATE vcs_sync_failures
+		SET attempts   = $2,
+		    last_error = $3,
+		    permanent  = $4,
+		    failed_at  = $5,
+		    retry_at   = $6
+		WHERE id = $1
+	`
+
+	res, err := dbtx(ctx, r.db).ExecContext(
+		ctx,
+		query,
+		failure.ID,
+		failure.Attempts,
+		failure.LastError,
+		failure.Permanent,
+		failure.FailedAt,
+		retryAt,
+	)
+	if err != nil {
+		return 0, fmt.Errorf("update vcs_sync_failures %d: %w", failure.ID, err)
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("rows affected for vcs_sync_failures %d: %w", failure.ID, err)
+	}
+
+	if n == 0 {
+		return 0, repository.ErrNotFound
+	}
+
+	return failure.ID, nil
+}
+
+// ClaimFailures захватывает изменения с временной ошибкой, сдвигая retry_at на время аренды.
+// FOR UPDATE SKIP LOCKED позволяет нескольким экземплярам сервиса забирать разные изменения.
+func (r *VCSSyncRepository) ClaimFailures(
+	ctx context.Context,
+	limit int,
+	lease time.Duration,
+) ([]repository.VCSSyncFailure, error) {
+	const query = `
+		UPDATE vcs_sync_failures
+		SET retry_at = now() + $2::double precision * interval '1 millisecond'
+		WHERE id IN (
+			SELECT id
+			FROM vcs_sync_failures
+			WHERE NOT permanent
+			  AND retry_at <= now()
+			ORDER BY id
+			LIMIT $1
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING id, org_id, pull_request_id, add_reviewers, remove_reviewers, attempts, last_error, failed_at
+	`
+
+	rows, err := dbtx(ctx, r.db).QueryContext(ctx, query, limit, float64(lease.Milliseconds()))
+	if err != nil {
+		return nil, fmt.Errorf("claim vcs_sync_failures: %w", err)
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	result := make([]repository.VCSSyncFailure, 0)
+
+	for rows.Next() {
+		var (
+			f      repository.VCSSyncFailure
+			org    string
+			prID   string
+			add    string
+			remove string
+		)
+
+		if err := rows.Scan(&f.ID, &org, &prID, &add, &remove, &f.Attempts, &f.LastError, &f.FailedAt); err != nil {
+			return nil, fmt.Errorf("scan vcs_sync_failures: %w", err)
+		}
+
+		if err := json.Unmarshal([]byte(add), &f.Add); err != nil {
+			return nil, fmt.Errorf("unmarshal add_reviewers of vcs_sync_failures %d: %w", f.ID, err)
+		}
+
+		if err := json.Unmarshal([]byte(remove), &f.Remove); err != nil {
+			return nil, fmt.Errorf("unmarshal remove_reviewers of vcs_sync_failures %d: %w", f.ID, err)
+		}
+
+		f.OrganizationID = domain.OrganizationID(org)
+		f.PullRequestID = domain.PullRequestID(prID)
+
+		result = append(result, f)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate vcs_sync_failures: %w", err)
+	}
+
+	// UPDATE ... RETURNING не гарантирует порядок строк.
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].ID < result[j].ID
+	})
+
+	return result, nil
+}
+
+// DeleteFailure удаляет изменение после успешной синхронизации.
+func (r *VCSSyncRepository) DeleteFailure(ctx context.Context, id int64) error {
+	const query = `DELETE FROM vcs_sync_failures WHERE id = $1`
+
+	if _, err := dbtx(ctx, r.db).ExecContext(ctx, query, id); err != nil {
+		return fmt.Errorf("delete vcs_sync_failures %d: %w", id, err)
+	}
+
+	return nil
+}
+
+// userIDsOrEmpty заменяет nil пустым списком, чтобы в jsonb не попадал null.
+func userIDsOrEmpty(ids []domain.UserID) []domain.UserID {
+	if ids == nil {
+		return []domain.UserID{}
+	}
+
+	return ids
+}
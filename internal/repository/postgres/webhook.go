@@ -0,0 +1,321 @@
+// Package postgres содержит реализацию репозиториев поверх PostgreSQL.
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/dixitix/pr-reviewer-service/internal/domain"
+	"github.com/dixitix/pr-reviewer-service/internal/repository"
+)
+
+// WebhookRepository реализует repository.WebhookRepository поверх *sql.DB.
+type WebhookRepository struct {
+	db *sql.DB
+}
+
+// NewWebhookRepository создаёт новый экземпляр WebhookRepository.
+func NewWebhookRepository(db *sql.DB) repository.WebhookRepository {
+	return &WebhookRepository{db: db}
+}
+
+// CreateSubscription создаёт подписку вместе с фильтром событий.
+func (r *WebhookRepository) CreateSubscription(ctx context.Context, sub domain.WebhookSubscription) (err error) {
+	org, err := organizationID(ctx)
+	if err != nil {
+		return err
+	}
+
+	tx, err := beginTx(ctx, r.db)
+	if err != nil {
+		return fmt.Errorf("begin tx: %w", err)
+	}
+
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback()
+		}
+	}()
+
+	const insertSub = `
+		INSERT INTO webhook_subscriptions (org_id, id, url, secret, created_at)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (id) DO NOTHING
+	`
+
+	res, err := tx.ExecContext(ctx, insertSub, org, string(sub.ID), sub.URL, sub.Secret, sub.CreatedAt)
+	if err != nil {
+		err = fmt.Errorf("insert webhook_subscriptions: %w", err)
+		return err
+	}
+
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		err = fmt.Errorf("rows affected: %w", err)
+		return err
+	}
+
+	if rowsAffected == 0 {
+		err = repository.ErrAlreadyExists
+		return err
+	}
+
+	const insertEvent = `
+		INSERT INTO webhook_subscription_events (subscription_id, event_type)
+		VALUES ($1, $2)
+		ON CONFLICT DO NOTHING
+	`
+
+	for _, eventType := range sub.Events {
+		if _, err = tx.ExecContext(ctx, insertEvent, string(sub.ID), string(eventType)); err != nil {
+			err = fmt.Errorf("insert webhook_subscription_events: %w", err)
+			return err
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("commit tx: %w", err)
+	}
+
+	return nil
+}
+
+// ListSubscriptions возвращает все подписки.
+func (r *WebhookRepository) ListSubscriptions(ctx context.Context) ([]domain.WebhookSubscription, error) {
+	org, err := organizationID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	const query = `
+		SELECT s.id, s.url, s.secret, s.created_at, e.event_type
+		FROM webhook_subscriptions s
+		LEFT JOIN webhook_subscription_events e
+			ON e.subscription_id = s.id
+		WHERE s.org_id = $1
+		ORDER BY s.created_at, s.id, e.event_type
+	`
+
+	return r.querySubscriptions(ctx, query, org)
+}
+
+// ListSubscriptionsByEvent возвращает подписки без фильтра и подписки, фильтр которых включает событие.
+func (r *WebhookRepository) ListSubscriptionsByEvent(
+	ctx context.Context,
+	eventType domain.EventType,
+) ([]domain.WebhookSubscription, error) {
+	org, err := organizationID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	const query = `
+		SELECT s.id, s.url, s.secret, s.created_at, e.event_type
+		FROM webhook_subscriptions s
+		LEFT JOIN webhook_subscription_events e
+			ON e.subscription_id = s.id
+		WHERE s.org_id = $1
+		  AND (
+				NOT EXISTS (
+					SELECT 1 FROM webhook_subscription_events f WHERE f.subscription_id = s.id
+				)
+				OR EXISTS (
+					SELECT 1 FROM webhook_subscription_events f WHERE f.subscription_id = s.id AND f.event_type = $2
+				)
+			)
+		ORDER BY s.created_at, s.id, e.event_type
+	`
+
+	return r.querySubscriptions(ctx, query, org, string(eventType))
+}
+
+// querySubscriptions выполняет запрос подписок и собирает фильтры событий.
+func (r *WebhookRepository) querySubscriptions(
+	ctx context.Context,
+	query string,
+	args ...any,
+) ([]domain.WebhookSubscription, error) {
+	rows, err := dbtx(ctx, r.db).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("select webhook_subscriptions: %w", err)
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	result := make([]domain.WebhookSubscription, 0)
+	index := make(map[domain.WebhookSubscriptionID]int)
+
+	for rows.Next() {
+		var (
+			sub       domain.WebhookSubscription
+			eventType sql.NullString
+		)
+
+		if err := rows.Scan(&sub.ID, &sub.URL, &sub.Secret, &sub.CreatedAt, &eventType); err != nil {
+			return nil, fmt.Errorf("scan webhook_subscriptions: %w", err)
+		}
+
+		i, exists := index[sub.ID]
+		if !exists {
+			i = len(result)
+			index[sub.ID] = i
+			result = append(result, sub)
+		}
+
+		if eventType.Valid {
+			result[i].Events = append(result[i].Events, domain.EventType(eventType.String))
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate webhook_subscriptions: %w", err)
+	}
+
+	return result, nil
+}
+
+// DeleteSubscription удаляет подписку; фильтры и недоставленные вебхуки удаляются каскадно.
+func (r *WebhookRepository) DeleteSubscription(ctx context.Context, id domain.WebhookSubscriptionID) error {
+	org, err := organizationID(ctx)
+	if err != nil {
+		return err
+	}
+
+	const query = `
+		DELETE FROM webhook_subscriptions
+		WHERE org_id = $1 AND id = $2
+	`
+
+	res, err := dbtx(ctx, r.db).ExecContext(ctx, query, org, string(id))
+	if err != nil {
+		return fmt.Errorf("delete webhook subscription %s: %w", id, err)
+	}
+
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("rows affected for webhook subscription %s: %w", id, err)
+	}
+
+	if rows == 0 {
+		return repository.ErrNotFound
+	}
+
+	return nil
+}
+
+// CreateDeadLetter сохраняет вебхук, который не удалось доставить.
+func (r *WebhookRepository) CreateDeadLetter(ctx context.Context, d domain.WebhookDelivery) error {
+	org, err := organizationID(ctx)
+	if err != nil {
+		return err
+	}
+
+	const query = `
+		INSERT INTO webhook_dead_letters
+			(org_id, id, subscription_id, event_type, payload, attempts, last_error, last_status_code, failed_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		ON CONFLICT (id) DO UPDATE
+		SET
+			attempts         = EXCLUDED.attempts,
+			last_error       = EXCLUDED.last_error,
+			last_status_code = EXCLUDED.last_status_code,
+			failed_at        = EXCLUDED.failed_at
+	`
+
+	var statusCode sql.NullInt32
+	if d.LastStatusCode != 0 {
+		statusCode = sql.NullInt32{Int32: int32(d.LastStatusCode), Valid: true}
+	}
+
+	_, err = dbtx(ctx, r.db).ExecContext(
+		ctx,
+		query,
+		org,
+		d.ID,
+		string(d.SubscriptionID),
+		string(d.EventType),
+		string(d.Payload),
+		d.Attempts,
+		d.LastError,
+		statusCode,
+		d.FailedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("insert webhook dead letter %s: %w", d.ID, err)
+	}
+
+	return nil
+}
+
+// ListDeadLetters возвращает недоставленные вебхуки, начиная с самых новых.
+func (r *WebhookRepository) ListDeadLetters(
+	ctx context.Context,
+	subscriptionID *domain.WebhookSubscriptionID,
+	limit int,
+) ([]domain.WebhookDelivery, error) {
+	org, err := organizationID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	query := `
+		SELECT id, subscription_id, event_type, payload, attempts, last_error, last_status_code, failed_at
+		FROM webhook_dead_letters
+		WHERE org_id = $2
+	`
+
+	args := []any{limit, org}
+
+	if subscriptionID != nil {
+		query += " AND subscription_id = $3"
+		args = append(args, string(*subscriptionID))
+	}
+
+	query += " ORDER BY failed_at DESC, id LIMIT $1"
+
+	rows, err := dbtx(ctx, r.db).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("select webhook_dead_letters: %w", err)
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	result := make([]domain.WebhookDelivery, 0)
+
+	for rows.Next() {
+		var (
+			d          domain.WebhookDelivery
+			eventType  string
+			payload    string
+			statusCode sql.NullInt32
+		)
+
+		if err := rows.Scan(
+			&d.ID,
+			&d.SubscriptionID,
+			&eventType,
+			&payload,
+			&d.Attempts,
+			&d.LastError,
+			&statusCode,
+			&d.FailedAt,
+		); err != nil {
+			return nil, fmt.Errorf("scan webhook_dead_letters: %w", err)
+		}
+
+		d.EventType = domain.EventType(eventType)
+		d.Payload = []byte(payload)
+		d.LastStatusCode = int(statusCode.Int32)
+
+		result = append(result, d)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate webhook_dead_letters: %w", err)
+	}
+
+	return result, nil
+}
@@ -3,10 +3,50 @@ package repository
 
 import (
 	"context"
+	"time"
 
 	"github.com/dixitix/pr-reviewer-service/internal/domain"
 )
 
+// OrganizationRepository описывает операции с организациями. В отличие от остальных репозиториев,
+// не ограничивается организацией из контекста.
+type OrganizationRepository interface {
+	// Create создаёт организацию или возвращает ErrAlreadyExists.
+	Create(ctx context.Context, org domain.Organization) error
+
+	// Exists возвращает true, если организация существует.
+	Exists(ctx context.Context, id domain.OrganizationID) (bool, error)
+
+	// List возвращает все организации.
+	List(ctx context.Context) ([]domain.Organization, error)
+}
+
+// APIKeyRepository описывает хранение API-ключей. Ключи хранятся только в виде хэша.
+type APIKeyRepository interface {
+	// Create сохраняет ключ организации из контекста.
+	Create(ctx context.Context, key domain.APIKey, hash string) error
+
+	// List возвращает ключи организации из контекста, включая отозванные.
+	List(ctx context.Context) ([]domain.APIKey, error)
+
+	// Revoke отзывает ключ организации из контекста. Если ключ не найден, возвращается ErrNotFound.
+	Revoke(ctx context.Context, id domain.APIKeyID, at time.Time) error
+
+	// Authenticate находит действующий ключ по хэшу среди ключей всех организаций и отмечает его использование.
+	// Если ключ не найден или отозван, возвращается ErrNotFound.
+	Authenticate(ctx context.Context, hash string, at time.Time) (domain.APIKey, error)
+}
+
+// Transactor выполняет функцию в одной транзакции: все вызовы репозиториев с переданным ей контекстом
+// видят изменения друг друга и фиксируются вместе. Ошибка fn откатывает транзакцию, вложенный вызов
+// выполняется в уже открытой транзакции.
+type Transactor interface {
+	WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
+// Репозитории ниже работают с данными организации из контекста (tenant.WithOrganization)
+// и возвращают ErrNoOrganization, если она не задана. Данные других организаций для них не существуют.
+
 // TeamRepository описывает операции с командами и их участниками.
 type TeamRepository interface {
 	// CreateTeam создаёт запись о команде.
@@ -15,11 +55,53 @@ type TeamRepository interface {
 	// TeamExists возвращает true, если команда с таким именем существует.
 	TeamExists(ctx context.Context, name domain.TeamName) (bool, error)
 
-	// GetTeamWithMembers возвращает команду и всех её участников.
+	// GetTeamWithMembers возвращает команду с её лидами и всех её участников, включая дополнительных.
 	GetTeamWithMembers(ctx context.Context, name domain.TeamName) (domain.Team, []domain.User, error)
 
-	// UpsertMembers создаёт или обновляет пользователей команды по их ID.
+	// ListLeads возвращает лидов команды. Лид может состоять в другой команде.
+	ListLeads(ctx context.Context, teamName domain.TeamName) ([]domain.User, error)
+
+	// SetLeads заменяет список лидов команды.
+	SetLeads(ctx context.Context, teamName domain.TeamName, leads []domain.UserID) error
+
+	// SetParent меняет родительскую команду (пустое значение — корень) и разрешение
+	// искать ревьюверов вверх по иерархии. Если команда не найдена, возвращается ErrNotFound.
+	SetParent(ctx context.Context, name domain.TeamName, parent domain.TeamName, fallbackToParent bool) error
+
+	// LockTeam блокирует команду до конца транзакции и возвращает её версию.
+	// Если команды нет, возвращается ErrNotFound.
+	LockTeam(ctx context.Context, name domain.TeamName) (int64, error)
+
+	// ListTeamChain возвращает команду и всех её предков от ближайшего к корню.
+	// Если команда не найдена, возвращается ErrNotFound.
+	ListTeamChain(ctx context.Context, name domain.TeamName) ([]domain.Team, error)
+
+	// ListTeams возвращает все команды с их родителями, без лидов.
+	ListTeams(ctx context.Context) ([]domain.Team, error)
+
+	// ListSubtreeMembers возвращает участников команды и всех её дочерних команд.
+	ListSubtreeMembers(ctx context.Context, name domain.TeamName) ([]domain.User, error)
+
+	// UpsertMembers создаёт или обновляет пользователей команды по их ID и делает команду их основной.
+	// Прежняя основная команда пользователя перестаёт быть его командой.
 	UpsertMembers(ctx context.Context, teamName domain.TeamName, members []domain.User) error
+
+	// AddMember добавляет пользователя в команду. Если primary, команда становится основной
+	// командой пользователя, а прежняя основная остаётся дополнительной.
+	AddMember(ctx context.Context, teamName domain.TeamName, userID domain.UserID, primary bool) error
+
+	// RemoveMember удаляет дополнительное членство пользователя в команде.
+	// Если пользователь не состоит в команде, возвращается ErrNotFound.
+	RemoveMember(ctx context.Context, teamName domain.TeamName, userID domain.UserID) error
+
+	// GetReviewPolicy возвращает SLA ревью команды или ErrNotFound, если оно не задано.
+	GetReviewPolicy(ctx context.Context, teamName domain.TeamName) (domain.ReviewPolicy, error)
+
+	// UpsertReviewPolicy создаёт или обновляет SLA ревью команды.
+	UpsertReviewPolicy(ctx context.Context, policy domain.ReviewPolicy) error
+
+	// ListReviewPolicies возвращает SLA ревью всех команд, для которых оно задано.
+	ListReviewPolicies(ctx context.Context) ([]domain.ReviewPolicy, error)
 }
 
 // UserRepository описывает операции с пользователями.
@@ -30,21 +112,27 @@ type UserRepository interface {
 	// SetActive меняет флаг активности пользователя.
 	SetActive(ctx context.Context, id domain.UserID, isActive bool) error
 
-	// ListActiveByTeam возвращает активных пользователей команды.
+	// ListActiveByTeam возвращает активных участников команды, включая тех, для кого она не основная.
 	// Если excludeID != nil, пользователь с таким ID исключается из результата.
 	ListActiveByTeam(ctx context.Context, teamName domain.TeamName, excludeID *domain.UserID) ([]domain.User, error)
+
+	// ListMemberships возвращает команды пользователя, основную первой.
+	ListMemberships(ctx context.Context, id domain.UserID) ([]domain.TeamMembership, error)
 }
 
 // PullRequestRepository описывает операции с Pull Request'ами и их ревьюверами.
 type PullRequestRepository interface {
 	// Create создаёт новый PR вместе с назначенными ревьюверами.
-	Create(ctx context.Context, pr domain.PullRequest) error
+	// События записываются в outbox в той же транзакции.
+	Create(ctx context.Context, pr domain.PullRequest, events ...domain.Event) error
 
 	// GetByID возвращает PR по его идентификатору.
 	GetByID(ctx context.Context, id domain.PullRequestID) (domain.PullRequest, error)
 
-	// Update обновляет состояние PR, включая список ревьюверов и статусы.
-	Update(ctx context.Context, pr domain.PullRequest) error
+	// Update обновляет состояние PR, включая список ревьюверов и статусы, и увеличивает версию.
+	// Обновление выполняется, только если версия в хранилище равна pr.Version, иначе возвращается ErrConflict.
+	// События записываются в outbox в той же транзакции.
+	Update(ctx context.Context, pr domain.PullRequest, events ...domain.Event) error
 
 	// ListByReviewer возвращает PR'ы, где пользователь является ревьювером.
 	ListByReviewer(ctx context.Context, reviewerID domain.UserID) ([]domain.PullRequest, error)
@@ -54,4 +142,182 @@ type PullRequestRepository interface {
 
 	// CountAssignmentsByPullRequest возвращает количество ревьюверов по каждому PR.
 	CountAssignmentsByPullRequest(ctx context.Context) (map[domain.PullRequestID]int, error)
+
+	// CountAssignmentsByTeam возвращает количество назначений по команде ревьювера.
+	CountAssignmentsByTeam(ctx context.Context) (map[domain.TeamName]int, error)
+
+	// CountOpenReviewsByTeam возвращает количество назначений на открытые PR по основной команде ревьювера.
+	CountOpenReviewsByTeam(ctx context.Context) (map[domain.TeamName]int, error)
+
+	// ListOpenAssignments возвращает назначения ревьюверов на открытые PR, сделанные раньше assignedBefore,
+	// начиная с самых старых.
+	ListOpenAssignments(ctx context.Context, assignedBefore time.Time) ([]domain.ReviewAssignment, error)
+
+	// MarkReminded запоминает время напоминания ревьюверу и записывает события в outbox.
+	MarkReminded(ctx context.Context, prID domain.PullRequestID, reviewerID domain.UserID, at time.Time, events ...domain.Event) error
+
+	// MarkEscalated запоминает время эскалации ревью и записывает события в outbox.
+	MarkEscalated(ctx context.Context, prID domain.PullRequestID, reviewerID domain.UserID, at time.Time, events ...domain.Event) error
+
+	// ListHistory возвращает события PR в порядке их записи.
+	ListHistory(ctx context.Context, prID domain.PullRequestID) ([]domain.Event, error)
+
+	// ListEvents возвращает события всех PR организации, подходящие под фильтр, в порядке их записи.
+	// Event.Sequence — номер записи в истории, он возрастает и не переиспользуется.
+	ListEvents(ctx context.Context, filter EventFilter) ([]domain.Event, error)
+
+	// LastEventSequence возвращает номер последнего события организации или 0, если событий нет.
+	LastEventSequence(ctx context.Context) (int64, error)
+}
+
+// EventFilter описывает выборку событий из истории PR.
+type EventFilter struct {
+	// AfterSequence — события с номером не больше него пропускаются.
+	AfterSequence int64
+	// Types — типы событий; пустой список означает все типы.
+	Types []domain.EventType
+	// TeamName — только события, где автор или ревьювер состоит в команде; пустое значение — без фильтра.
+	TeamName domain.TeamName
+	// UserID — только события, где пользователь автор или ревьювер; пустое значение — без фильтра.
+	UserID domain.UserID
+	// Limit — максимальное число событий.
+	Limit int
+}
+
+// WebhookRepository описывает операции с подписками на вебхуки и недоставленными вебхуками.
+type WebhookRepository interface {
+	// CreateSubscription создаёт подписку вместе с фильтром событий.
+	CreateSubscription(ctx context.Context, sub domain.WebhookSubscription) error
+
+	// ListSubscriptions возвращает все подписки.
+	ListSubscriptions(ctx context.Context) ([]domain.WebhookSubscription, error)
+
+	// ListSubscriptionsByEvent возвращает подписки, которые должны получить событие данного типа.
+	ListSubscriptionsByEvent(ctx context.Context, eventType domain.EventType) ([]domain.WebhookSubscription, error)
+
+	// DeleteSubscription удаляет подписку и все её недоставленные вебхуки.
+	DeleteSubscription(ctx context.Context, id domain.WebhookSubscriptionID) error
+
+	// CreateDeadLetter сохраняет вебхук, который не удалось доставить.
+	CreateDeadLetter(ctx context.Context, delivery domain.WebhookDelivery) error
+
+	// ListDeadLetters возвращает недоставленные вебхуки, начиная с самых новых.
+	// Если subscriptionID != nil, возвращаются только вебхуки этой подписки.
+	ListDeadLetters(ctx context.Context, subscriptionID *domain.WebhookSubscriptionID, limit int) ([]domain.WebhookDelivery, error)
+}
+
+// OutboxRecord описывает событие outbox, ожидающее публикации.
+type OutboxRecord struct {
+	Event    domain.Event
+	Attempts int
+}
+
+// OutboxRepository описывает чтение outbox фоновым ретранслятором событий.
+// Ретранслятор обрабатывает события всех организаций, организация события — Event.OrganizationID.
+type OutboxRepository interface {
+	// ClaimPending захватывает до limit неопубликованных событий, доступных для обработки,
+	// в порядке их записи. Захваченные события недоступны другим ретрансляторам в течение lease.
+	ClaimPending(ctx context.Context, limit int, lease time.Duration) ([]OutboxRecord, error)
+
+	// MarkPublished отмечает событие как опубликованное.
+	MarkPublished(ctx context.Context, sequence int64) error
+
+	// MarkFailed увеличивает счётчик попыток и откладывает событие до retryAt.
+	MarkFailed(ctx context.Context, sequence int64, lastErr string, retryAt time.Time) error
+
+	// DeletePublishedBefore удаляет события, опубликованные раньше before, и возвращает их количество.
+	DeletePublishedBefore(ctx context.Context, before time.Time) (int64, error)
+}
+
+// VCSSyncFailure описывает изменение ревьюверов, которое не удалось отправить VCS-провайдеру.
+type VCSSyncFailure struct {
+	// ID — номер записи; 0 — изменение ещё не сохранялось.
+	ID             int64
+	OrganizationID domain.OrganizationID
+	PullRequestID  domain.PullRequestID
+	Add            []domain.UserID
+	Remove         []domain.UserID
+	Attempts       int
+	LastError      string
+	// Permanent — постоянная ошибка: изменение не повторяется.
+	Permanent bool
+	FailedAt  time.Time
+}
+
+// VCSSyncRepository хранит неудачные синхронизации ревьюверов с VCS-провайдером, чтобы они
+// переживали перезапуск. Очередь синхронизации обрабатывает изменения всех организаций,
+// организация изменения — VCSSyncFailure.OrganizationID.
+type VCSSyncRepository interface {
+	// SaveFailure сохраняет неудачное изменение и возвращает номер записи: при ID == 0 создаёт запись,
+	// иначе обновляет её. Временная ошибка будет повторена не раньше retryAt.
+	SaveFailure(ctx context.Context, failure VCSSyncFailure, retryAt time.Time) (int64, error)
+
+	// ClaimFailures захватывает до limit изменений с временной ошибкой, которые пора повторить,
+	// начиная с самых старых. Захваченные изменения недоступны другим экземплярам в течение lease.
+	ClaimFailures(ctx context.Context, limit int, lease time.Duration) ([]VCSSyncFailure, error)
+
+	// DeleteFailure удаляет изменение после успешной синхронизации.
+	DeleteFailure(ctx context.Context, id int64) error
+}
+
+// NotificationRepository описывает хранение настроек уведомлений пользователей и каналов чата команд.
+type NotificationRepository interface {
+	// GetPreferences возвращает настройки уведомлений пользователя.
+	// Если настройки не сохранялись, возвращаются настройки по умолчанию.
+	GetPreferences(ctx context.Context, userID domain.UserID) (domain.NotificationPreferences, error)
+
+	// UpsertPreferences создаёт или обновляет настройки уведомлений пользователя.
+	UpsertPreferences(ctx context.Context, prefs domain.NotificationPreferences) error
+
+	// ListPreferencesByEmailMode возвращает настройки пользователей с указанным режимом почтовых уведомлений.
+	ListPreferencesByEmailMode(ctx context.Context, mode domain.EmailMode) ([]domain.NotificationPreferences, error)
+
+	// ClaimDigest отмечает, что сводка за день day отправляется пользователю.
+	// Возвращает false, если сводка за этот день уже была отправлена.
+	ClaimDigest(ctx context.Context, userID domain.UserID, day time.Time) (bool, error)
+
+	// ReleaseDigest снимает отметку об отправке сводки, если отправить её не удалось.
+	ReleaseDigest(ctx context.Context, userID domain.UserID, day time.Time) error
+
+	// GetTeamChatChannel возвращает канал чата команды или ErrNotFound, если он не настроен.
+	GetTeamChatChannel(ctx context.Context, teamName domain.TeamName) (domain.TeamChatChannel, error)
+
+	// UpsertTeamChatChannel создаёт или обновляет канал чата команды.
+	UpsertTeamChatChannel(ctx context.Context, channel domain.TeamChatChannel) error
+
+	// DeleteTeamChatChannel удаляет канал чата команды или возвращает ErrNotFound.
+	DeleteTeamChatChannel(ctx context.Context, teamName domain.TeamName) error
+}
+
+// IdempotencyRecord описывает запрос с ключом идемпотентности и, если он выполнен, сохранённый ответ.
+type IdempotencyRecord struct {
+	Key         string
+	RequestHash string
+	// Completed == false — запрос с этим ключом ещё выполняется.
+	Completed   bool
+	StatusCode  int
+	ContentType string
+	Body        []byte
+}
+
+// IdempotencyRepository хранит ответы на запросы с ключом идемпотентности в организации из контекста.
+type IdempotencyRepository interface {
+	// Reserve занимает ключ для запроса с хэшем requestHash до expiresAt и возвращает reserved == true.
+	// Если ключ уже занят и не истёк, возвращается сохранённая запись и reserved == false.
+	Reserve(ctx context.Context, key, requestHash string, now, expiresAt time.Time) (rec IdempotencyRecord, reserved bool, err error)
+
+	// Complete сохраняет ответ на запрос с ключом key.
+	Complete(ctx context.Context, key string, statusCode int, contentType string, body []byte) error
+
+	// Release освобождает ключ, если запрос не удалось выполнить и его можно повторить.
+	Release(ctx context.Context, key string) error
+
+	// DeleteExpired удаляет истёкшие ключи всех организаций и возвращает их количество.
+	DeleteExpired(ctx context.Context, now time.Time) (int64, error)
+}
+
+// AuditRepository описывает журнал изменений организации.
+type AuditRepository interface {
+	// Record записывает операцию в журнал организации из контекста. Вызывается в транзакции операции.
+	Record(ctx context.Context, entry domain.AuditEntry) error
 }
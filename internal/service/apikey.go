@@ -0,0 +1,113 @@
+// Package service содержит реализацию бизнес-логики сервиса назначения ревьюеров.
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/dixitix/pr-reviewer-service/internal/domain"
+	"github.com/dixitix/pr-reviewer-service/internal/idgen"
+	"github.com/dixitix/pr-reviewer-service/internal/repository"
+)
+
+const (
+	// apiKeyPrefix отличает API-ключи сервиса от других секретов.
+	apiKeyPrefix = "prk_"
+	// apiKeyVisiblePrefix — длина начала ключа, которое хранится для опознания.
+	apiKeyVisiblePrefix = len(apiKeyPrefix) + 8
+)
+
+// CreateAPIKey выпускает API-ключ организации из контекста. Ключ выпускает только администратор
+// и только с правами, которые есть у него самого.
+func (s *service) CreateAPIKey(
+	ctx context.Context,
+	name string,
+	scopes []domain.APIScope,
+) (domain.APIKey, string, error) {
+	if err := authorizeAdmin(ctx); err != nil {
+		return domain.APIKey{}, "", err
+	}
+
+	if err := authorizeScopes(ctx, scopes); err != nil {
+		return domain.APIKey{}, "", err
+	}
+
+	secret := apiKeyPrefix + idgen.New()
+
+	key := domain.APIKey{
+		ID:        domain.APIKeyID(idgen.New()),
+		Name:      name,
+		Prefix:    secret[:apiKeyVisiblePrefix],
+		Scopes:    scopes,
+		CreatedAt: time.Now().UTC(),
+	}
+
+	err := s.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.apiKeyRepo.Create(ctx, key, hashAPIKey(secret)); err != nil {
+			return fmt.Errorf("create api key: %w", err)
+		}
+
+		return s.audit(ctx, domain.AuditAPIKeyCreated, string(key.ID), map[string]any{
+			"name":   key.Name,
+			"scopes": key.Scopes,
+		})
+	})
+	if err != nil {
+		return domain.APIKey{}, "", err
+	}
+
+	return key, secret, nil
+}
+
+// ListAPIKeys возвращает API-ключи организации из контекста.
+func (s *service) ListAPIKeys(ctx context.Context) ([]domain.APIKey, error) {
+	keys, err := s.apiKeyRepo.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("list api keys: %w", err)
+	}
+
+	return keys, nil
+}
+
+// RevokeAPIKey отзывает API-ключ организации из контекста.
+func (s *service) RevokeAPIKey(ctx context.Context, id domain.APIKeyID) error {
+	if err := authorizeAdmin(ctx); err != nil {
+		return err
+	}
+
+	return s.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.apiKeyRepo.Revoke(ctx, id, time.Now().UTC()); err != nil {
+			if errors.Is(err, repository.ErrNotFound) {
+				return ErrNotFound
+			}
+
+			return fmt.Errorf("revoke api key %s: %w", id, err)
+		}
+
+		return s.audit(ctx, domain.AuditAPIKeyRevoked, string(id), nil)
+	})
+}
+
+// AuthenticateAPIKey возвращает действующий API-ключ по его значению.
+func (s *service) AuthenticateAPIKey(ctx context.Context, secret string) (domain.APIKey, error) {
+	key, err := s.apiKeyRepo.Authenticate(ctx, hashAPIKey(secret), time.Now().UTC())
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return domain.APIKey{}, ErrUnauthenticated
+		}
+
+		return domain.APIKey{}, fmt.Errorf("authenticate api key: %w", err)
+	}
+
+	return key, nil
+}
+
+// hashAPIKey возвращает SHA-256 ключа. Ключи случайны и длинны, поэтому соль и медленный хэш не нужны.
+func hashAPIKey(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
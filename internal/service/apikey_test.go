@@ -0,0 +1,57 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/dixitix/pr-reviewer-service/internal/domain"
+)
+
+// TestAPIKey_Lifecycle проверяет выпуск, аутентификацию и отзыв API-ключа.
+func TestAPIKey_Lifecycle(t *testing.T) {
+	keys := &memoryAPIKeys{keys: map[string]domain.APIKey{}}
+	svc := &service{tx: passthroughTx{}, apiKeyRepo: keys, auditRepo: &memoryAudit{}}
+	ctx := context.Background()
+
+	key, secret, err := svc.CreateAPIKey(ctx, "ci", []domain.APIScope{domain.APIScopePRWrite})
+	if err != nil {
+		t.Fatalf("CreateAPIKey returned error: %v", err)
+	}
+
+	if !strings.HasPrefix(secret, key.Prefix) || len(secret) <= len(key.Prefix) {
+		t.Fatalf("unexpected key prefix %q for secret %q", key.Prefix, secret)
+	}
+
+	for hash := range keys.keys {
+		if strings.Contains(hash, secret) {
+			t.Fatalf("secret stored in plain text")
+		}
+	}
+
+	got, err := svc.AuthenticateAPIKey(ctx, secret)
+	if err != nil {
+		t.Fatalf("AuthenticateAPIKey returned error: %v", err)
+	}
+
+	if got.ID != key.ID || !got.HasScope(domain.APIScopePRWrite) || got.HasScope(domain.APIScopeTeamAdmin) {
+		t.Fatalf("unexpected authenticated key: %+v", got)
+	}
+
+	if _, err := svc.AuthenticateAPIKey(ctx, secret+"x"); !errors.Is(err, ErrUnauthenticated) {
+		t.Fatalf("expected ErrUnauthenticated for unknown key, got %v", err)
+	}
+
+	if err := svc.RevokeAPIKey(ctx, key.ID); err != nil {
+		t.Fatalf("RevokeAPIKey returned error: %v", err)
+	}
+
+	if _, err := svc.AuthenticateAPIKey(ctx, secret); !errors.Is(err, ErrUnauthenticated) {
+		t.Fatalf("expected ErrUnauthenticated for revoked key, got %v", err)
+	}
+
+	if err := svc.RevokeAPIKey(ctx, "missing"); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+}
@@ -0,0 +1,29 @@
+// Package service содержит реализацию бизнес-логики сервиса назначения ревьюеров.
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/dixitix/pr-reviewer-service/internal/auth"
+	"github.com/dixitix/pr-reviewer-service/internal/domain"
+)
+
+// audit записывает в журнал изменений, кто выполнил операцию action над target. Вызывается
+// в транзакции операции, чтобы запись не расходилась с самим изменением.
+func (s *service) audit(ctx context.Context, action domain.AuditAction, target string, details map[string]any) error {
+	entry := domain.AuditEntry{
+		Action:     action,
+		Target:     target,
+		Actor:      auth.Actor(ctx),
+		Details:    details,
+		OccurredAt: time.Now().UTC(),
+	}
+
+	if err := s.auditRepo.Record(ctx, entry); err != nil {
+		return fmt.Errorf("record %s of %s: %w", action, target, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,33 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/dixitix/pr-reviewer-service/internal/domain"
+)
+
+// TestAudit_RecordsActor проверяет, что изменяющие операции записывают вызывающего в журнал.
+func TestAudit_RecordsActor(t *testing.T) {
+	svc, _ := newTestService(time.Now())
+	audit := svc.auditRepo.(*memoryAudit)
+
+	if _, err := svc.SetUserActive(asUser("u3"), "u3", false); err != nil {
+		t.Fatalf("SetUserActive returned error: %v", err)
+	}
+
+	// Повтор с тем же значением ничего не меняет и не записывается.
+	if _, err := svc.SetUserActive(asUser("u3"), "u3", false); err != nil {
+		t.Fatalf("SetUserActive returned error: %v", err)
+	}
+
+	if len(audit.entries) != 1 {
+		t.Fatalf("expected 1 audit entry, got %+v", audit.entries)
+	}
+
+	entry := audit.entries[0]
+	if entry.Action != domain.AuditUserActiveSet || entry.Target != "u3" || entry.Actor != "user:u3" ||
+		entry.Details["is_active"] != false || entry.OccurredAt.IsZero() {
+		t.Fatalf("unexpected audit entry: %+v", entry)
+	}
+}
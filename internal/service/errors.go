@@ -11,4 +11,12 @@ var (
 	ErrPullRequestMerged        = errors.New("pull request already merged")
 	ErrReviewerNotAssigned      = errors.New("reviewer is not assigned to pull request")
 	ErrNoCandidate              = errors.New("no candidate for reviewer reassignment")
+	ErrEmailRequired            = errors.New("email is required for email notifications")
+	ErrTeamHierarchyCycle       = errors.New("team hierarchy must not contain cycles")
+	ErrPrimaryTeamMembership    = errors.New("primary team membership cannot be removed")
+	ErrOrganizationExists       = errors.New("organization already exists")
+	ErrUnauthenticated          = errors.New("invalid or revoked api key")
+	ErrForbidden                = errors.New("operation is not permitted for the caller")
+	ErrConflict                 = errors.New("resource was modified concurrently")
+	ErrPreconditionFailed       = errors.New("resource version does not match the expected one")
 )
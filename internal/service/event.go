@@ -0,0 +1,60 @@
+// Package service содержит реализацию бизнес-логики сервиса назначения ревьюеров.
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/dixitix/pr-reviewer-service/internal/domain"
+	"github.com/dixitix/pr-reviewer-service/internal/repository"
+)
+
+// streamEventTypes — события ленты: создание PR с назначением ревьюверов, переназначение и merge.
+var streamEventTypes = []domain.EventType{
+	domain.EventReviewersAssigned,
+	domain.EventReviewerReassigned,
+	domain.EventPullRequestMerged,
+}
+
+// ListEvents возвращает события назначений в порядке их записи.
+func (s *service) ListEvents(ctx context.Context, filter EventFilter) ([]domain.Event, error) {
+	if filter.TeamName != "" {
+		exists, err := s.teamRepo.TeamExists(ctx, filter.TeamName)
+		if err != nil {
+			return nil, fmt.Errorf("check team %s exists: %w", filter.TeamName, err)
+		}
+
+		if !exists {
+			return nil, ErrNotFound
+		}
+	}
+
+	if filter.UserID != "" {
+		if err := s.checkUserExists(ctx, filter.UserID); err != nil {
+			return nil, err
+		}
+	}
+
+	events, err := s.pullRequestRepo.ListEvents(ctx, repository.EventFilter{
+		AfterSequence: filter.AfterSequence,
+		Types:         streamEventTypes,
+		TeamName:      filter.TeamName,
+		UserID:        filter.UserID,
+		Limit:         filter.Limit,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("list events after %d: %w", filter.AfterSequence, err)
+	}
+
+	return events, nil
+}
+
+// LastEventSequence возвращает номер последнего события организации.
+func (s *service) LastEventSequence(ctx context.Context) (int64, error) {
+	seq, err := s.pullRequestRepo.LastEventSequence(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("get last event sequence: %w", err)
+	}
+
+	return seq, nil
+}
@@ -0,0 +1,396 @@
+package service
+
+import (
+	"context"
+	"math/rand"
+	"time"
+
+	"github.com/dixitix/pr-reviewer-service/internal/auth"
+	"github.com/dixitix/pr-reviewer-service/internal/domain"
+	"github.com/dixitix/pr-reviewer-service/internal/repository"
+)
+
+// fakeTeams — TeamRepository в памяти; реализованы только методы, нужные тестам сервиса.
+type fakeTeams struct {
+	repository.TeamRepository
+	policies []domain.ReviewPolicy
+	leads    map[domain.TeamName][]domain.User
+	teams    map[domain.TeamName]domain.Team
+	// users — пользователи, которых меняют UpsertMembers и AddMember.
+	users *fakeUsers
+}
+
+func (r *fakeTeams) ListLeads(_ context.Context, team domain.TeamName) ([]domain.User, error) {
+	return r.leads[team], nil
+}
+
+func (r *fakeTeams) ListTeamChain(_ context.Context, name domain.TeamName) ([]domain.Team, error) {
+	var chain []domain.Team
+
+	for name != "" {
+		team, ok := r.teams[name]
+		if !ok {
+			team = domain.Team{Name: name}
+		}
+
+		chain = append(chain, team)
+		name = team.Parent
+	}
+
+	return chain, nil
+}
+
+func (r *fakeTeams) ListTeams(context.Context) ([]domain.Team, error) {
+	teams := make([]domain.Team, 0, len(r.teams))
+	for _, t := range r.teams {
+		teams = append(teams, t)
+	}
+
+	return teams, nil
+}
+
+func (r *fakeTeams) ListReviewPolicies(context.Context) ([]domain.ReviewPolicy, error) {
+	return r.policies, nil
+}
+
+func (r *fakeTeams) TeamExists(_ context.Context, name domain.TeamName) (bool, error) {
+	_, ok := r.teams[name]
+	return ok, nil
+}
+
+func (r *fakeTeams) CreateTeam(_ context.Context, team domain.Team) error {
+	if _, ok := r.teams[team.Name]; ok {
+		return repository.ErrAlreadyExists
+	}
+
+	if r.teams == nil {
+		r.teams = make(map[domain.TeamName]domain.Team)
+	}
+
+	r.teams[team.Name] = team
+
+	return nil
+}
+
+func (r *fakeTeams) UpsertMembers(_ context.Context, name domain.TeamName, members []domain.User) error {
+	for _, m := range members {
+		m.TeamName = name
+		r.users.users[m.ID] = m
+	}
+
+	return nil
+}
+
+func (r *fakeTeams) AddMember(_ context.Context, name domain.TeamName, userID domain.UserID, primary bool) error {
+	if !primary {
+		if r.users.guilds == nil {
+			r.users.guilds = make(map[domain.UserID][]domain.TeamName)
+		}
+
+		r.users.guilds[userID] = append(r.users.guilds[userID], name)
+		return nil
+	}
+
+	user := r.users.users[userID]
+	user.TeamName = name
+	r.users.users[userID] = user
+
+	return nil
+}
+
+// fakeOrgs — OrganizationRepository с единственной организацией по умолчанию.
+type fakeOrgs struct {
+	repository.OrganizationRepository
+}
+
+func (fakeOrgs) List(context.Context) ([]domain.Organization, error) {
+	return []domain.Organization{{ID: domain.DefaultOrganizationID}}, nil
+}
+
+// fakeUsers — UserRepository в памяти; реализованы только методы, нужные тестам сервиса.
+type fakeUsers struct {
+	repository.UserRepository
+	users map[domain.UserID]domain.User
+	// guilds — дополнительные команды пользователей.
+	guilds map[domain.UserID][]domain.TeamName
+}
+
+func (r *fakeUsers) GetByID(_ context.Context, id domain.UserID) (domain.User, error) {
+	u, ok := r.users[id]
+	if !ok {
+		return domain.User{}, repository.ErrNotFound
+	}
+
+	return u, nil
+}
+
+func (r *fakeUsers) ListActiveByTeam(
+	_ context.Context,
+	team domain.TeamName,
+	excludeID *domain.UserID,
+) ([]domain.User, error) {
+	var result []domain.User
+
+	for _, u := range r.users {
+		if r.isMember(u, team) && u.IsActive && (excludeID == nil || u.ID != *excludeID) {
+			result = append(result, u)
+		}
+	}
+
+	return result, nil
+}
+
+func (r *fakeUsers) ListMemberships(_ context.Context, id domain.UserID) ([]domain.TeamMembership, error) {
+	memberships := []domain.TeamMembership{{TeamName: r.users[id].TeamName, UserID: id, IsPrimary: true}}
+	for _, team := range r.guilds[id] {
+		memberships = append(memberships, domain.TeamMembership{TeamName: team, UserID: id})
+	}
+
+	return memberships, nil
+}
+
+func (r *fakeUsers) isMember(u domain.User, team domain.TeamName) bool {
+	if u.TeamName == team {
+		return true
+	}
+
+	for _, guild := range r.guilds[u.ID] {
+		if guild == team {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (r *fakeUsers) SetActive(_ context.Context, id domain.UserID, isActive bool) error {
+	user := r.users[id]
+	user.IsActive = isActive
+	r.users[id] = user
+
+	return nil
+}
+
+// fakePullRequests — PullRequestRepository в памяти, запоминающий записанные события.
+// Update, как и хранилище, сверяет версию PR.
+type fakePullRequests struct {
+	repository.PullRequestRepository
+	prs         map[domain.PullRequestID]domain.PullRequest
+	assignments []domain.ReviewAssignment
+	events      []domain.Event
+	// teamAssignments — ответ CountAssignmentsByTeam.
+	teamAssignments map[domain.TeamName]int
+}
+
+func (r *fakePullRequests) GetByID(_ context.Context, id domain.PullRequestID) (domain.PullRequest, error) {
+	pr, ok := r.prs[id]
+	if !ok {
+		return domain.PullRequest{}, repository.ErrNotFound
+	}
+
+	return pr, nil
+}
+
+func (r *fakePullRequests) Update(_ context.Context, pr domain.PullRequest, events ...domain.Event) error {
+	if r.prs[pr.ID].Version != pr.Version {
+		return repository.ErrConflict
+	}
+
+	pr.Version++
+	r.prs[pr.ID] = pr
+	r.events = append(r.events, events...)
+
+	return nil
+}
+
+func (r *fakePullRequests) ListOpenAssignments(_ context.Context, before time.Time) ([]domain.ReviewAssignment, error) {
+	var result []domain.ReviewAssignment
+
+	for _, a := range r.assignments {
+		if a.AssignedAt.Before(before) {
+			result = append(result, a)
+		}
+	}
+
+	return result, nil
+}
+
+func (r *fakePullRequests) MarkReminded(
+	_ context.Context,
+	_ domain.PullRequestID,
+	_ domain.UserID,
+	_ time.Time,
+	events ...domain.Event,
+) error {
+	r.events = append(r.events, events...)
+	return nil
+}
+
+func (r *fakePullRequests) MarkEscalated(
+	_ context.Context,
+	_ domain.PullRequestID,
+	_ domain.UserID,
+	_ time.Time,
+	events ...domain.Event,
+) error {
+	r.events = append(r.events, events...)
+	return nil
+}
+
+func (r *fakePullRequests) CountAssignmentsByTeam(context.Context) (map[domain.TeamName]int, error) {
+	return r.teamAssignments, nil
+}
+
+// passthroughTx — Transactor без транзакций: фейковые репозитории в памяти применяют изменения сразу.
+type passthroughTx struct{}
+
+func (passthroughTx) WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+
+// newTestService создаёт сервис с фейковыми репозиториями: команда backend (alice — автор,
+// bob и carol — ревьюверы) и команда solo, в которой заменить ревьювера некем. Назначения PR
+// заданы относительно now для проверок SLA.
+func newTestService(now time.Time) (*service, *fakePullRequests) {
+	users := &fakeUsers{users: map[domain.UserID]domain.User{
+		"u1": {ID: "u1", Username: "alice", TeamName: "backend", IsActive: true},
+		"u2": {ID: "u2", Username: "bob", TeamName: "backend", IsActive: true},
+		"u3": {ID: "u3", Username: "carol", TeamName: "backend", IsActive: true},
+		"u4": {ID: "u4", Username: "dave", TeamName: "backend", IsActive: true},
+		"u5": {ID: "u5", Username: "erin", TeamName: "solo", IsActive: true},
+		"u6": {ID: "u6", Username: "frank", TeamName: "solo", IsActive: true},
+	}}
+
+	open := domain.PullRequestStatusOpen
+	prs := &fakePullRequests{prs: map[domain.PullRequestID]domain.PullRequest{
+		"pr-fresh": {ID: "pr-fresh", AuthorID: "u1", Status: open, AssignedReviewers: []domain.UserID{"u2"}},
+		"pr-stale": {ID: "pr-stale", AuthorID: "u1", Status: open, AssignedReviewers: []domain.UserID{"u2", "u3"}},
+		"pr-solo":  {ID: "pr-solo", AuthorID: "u5", Status: open, AssignedReviewers: []domain.UserID{"u6"}},
+	}}
+
+	reminded := now.Add(-time.Hour)
+	prs.assignments = []domain.ReviewAssignment{
+		{
+			PullRequest: prs.prs["pr-fresh"], ReviewerID: "u2", TeamName: "backend",
+			AssignedAt: now.Add(-26 * time.Hour), RemindedAt: &reminded,
+		},
+		{PullRequest: prs.prs["pr-stale"], ReviewerID: "u3", TeamName: "backend", AssignedAt: now.Add(-25 * time.Hour)},
+		{PullRequest: prs.prs["pr-stale"], ReviewerID: "u2", TeamName: "backend", AssignedAt: now.Add(-80 * time.Hour)},
+		{PullRequest: prs.prs["pr-solo"], ReviewerID: "u6", TeamName: "solo", AssignedAt: now.Add(-5 * time.Hour)},
+	}
+
+	teams := &fakeTeams{users: users, policies: []domain.ReviewPolicy{{
+		TeamName:      "solo",
+		ReminderAfter: 0,
+		EscalateAfter: 4 * time.Hour,
+		Action:        domain.EscalationActionReassign,
+	}}}
+
+	svc := &service{
+		tx:              passthroughTx{},
+		orgRepo:         fakeOrgs{},
+		teamRepo:        teams,
+		userRepo:        users,
+		pullRequestRepo: prs,
+		auditRepo:       &memoryAudit{},
+		metrics:         noMetrics{},
+		rnd:             rand.New(rand.NewSource(1)),
+	}
+
+	return svc, prs
+}
+
+// memoryAudit — AuditRepository в памяти.
+type memoryAudit struct {
+	entries []domain.AuditEntry
+}
+
+func (m *memoryAudit) Record(_ context.Context, entry domain.AuditEntry) error {
+	m.entries = append(m.entries, entry)
+	return nil
+}
+
+// memoryAPIKeys хранит API-ключи в памяти, индексируя их по хэшу.
+type memoryAPIKeys struct {
+	keys map[string]domain.APIKey
+}
+
+func (m *memoryAPIKeys) Create(_ context.Context, key domain.APIKey, hash string) error {
+	m.keys[hash] = key
+	return nil
+}
+
+func (m *memoryAPIKeys) List(context.Context) ([]domain.APIKey, error) {
+	keys := make([]domain.APIKey, 0, len(m.keys))
+	for _, key := range m.keys {
+		keys = append(keys, key)
+	}
+
+	return keys, nil
+}
+
+func (m *memoryAPIKeys) Revoke(_ context.Context, id domain.APIKeyID, at time.Time) error {
+	for hash, key := range m.keys {
+		if key.ID == id {
+			key.RevokedAt = &at
+			m.keys[hash] = key
+
+			return nil
+		}
+	}
+
+	return repository.ErrNotFound
+}
+
+func (m *memoryAPIKeys) Authenticate(_ context.Context, hash string, at time.Time) (domain.APIKey, error) {
+	key, ok := m.keys[hash]
+	if !ok || key.RevokedAt != nil {
+		return domain.APIKey{}, repository.ErrNotFound
+	}
+
+	key.LastUsedAt = &at
+	m.keys[hash] = key
+
+	return key, nil
+}
+
+// memoryNotifications — NotificationRepository в памяти; реализованы только методы изменения настроек.
+type memoryNotifications struct {
+	repository.NotificationRepository
+	prefs    map[domain.UserID]domain.NotificationPreferences
+	channels map[domain.TeamName]domain.TeamChatChannel
+}
+
+func (m *memoryNotifications) GetPreferences(_ context.Context, userID domain.UserID) (domain.NotificationPreferences, error) {
+	prefs, ok := m.prefs[userID]
+	if !ok {
+		prefs = domain.NotificationPreferences{UserID: userID}
+	}
+
+	return prefs, nil
+}
+
+func (m *memoryNotifications) UpsertPreferences(_ context.Context, prefs domain.NotificationPreferences) error {
+	m.prefs[prefs.UserID] = prefs
+	return nil
+}
+
+func (m *memoryNotifications) UpsertTeamChatChannel(_ context.Context, channel domain.TeamChatChannel) error {
+	m.channels[channel.TeamName] = channel
+	return nil
+}
+
+func (m *memoryNotifications) DeleteTeamChatChannel(_ context.Context, name domain.TeamName) error {
+	if _, ok := m.channels[name]; !ok {
+		return repository.ErrNotFound
+	}
+
+	delete(m.channels, name)
+
+	return nil
+}
+
+// asUser возвращает контекст запроса пользователя с ролями roles.
+func asUser(id domain.UserID, roles ...domain.Role) context.Context {
+	return auth.WithIdentity(context.Background(), auth.Identity{UserID: id, Roles: roles, Scopes: domain.AllAPIScopes()})
+}
@@ -0,0 +1,105 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/dixitix/pr-reviewer-service/internal/domain"
+)
+
+// withHierarchy делает solo сквадом команды backend, а backend — командой отдела platform.
+func withHierarchy(svc *service, fallback bool) {
+	svc.teamRepo.(*fakeTeams).teams = map[domain.TeamName]domain.Team{
+		"platform": {Name: "platform"},
+		"backend":  {Name: "backend", Parent: "platform"},
+		"solo":     {Name: "solo", Parent: "backend", FallbackToParent: fallback},
+	}
+}
+
+// TestReassignReviewer_ParentFallback проверяет поиск ревьювера в родительской команде,
+// только если сквад это разрешает.
+func TestReassignReviewer_ParentFallback(t *testing.T) {
+	svc, prs := newTestService(time.Now())
+	withHierarchy(svc, false)
+
+	if _, _, err := svc.ReassignReviewer(context.Background(), "pr-solo", "u6"); !errors.Is(err, ErrNoCandidate) {
+		t.Fatalf("expected ErrNoCandidate without fallback, got %v", err)
+	}
+
+	withHierarchy(svc, true)
+
+	_, replacedBy, err := svc.ReassignReviewer(context.Background(), "pr-solo", "u6")
+	if err != nil {
+		t.Fatalf("ReassignReviewer returned error: %v", err)
+	}
+
+	if users := svc.userRepo.(*fakeUsers); users.users[replacedBy].TeamName != "backend" {
+		t.Fatalf("expected reviewer from backend, got %s", replacedBy)
+	}
+
+	if len(prs.events) != 1 || prs.events[0].Reason != "no candidate in team, assigned from parent team backend" {
+		t.Fatalf("unexpected reassignment events: %+v", prs.events)
+	}
+}
+
+// emptyChainTeams возвращает пустую цепочку, как для команды, удалённой во время подбора.
+type emptyChainTeams struct {
+	*fakeTeams
+}
+
+func (emptyChainTeams) ListTeamChain(context.Context, domain.TeamName) ([]domain.Team, error) {
+	return nil, nil
+}
+
+// TestReassignReviewer_EmptyTeamChain проверяет, что пустая цепочка команд означает отсутствие кандидата.
+func TestReassignReviewer_EmptyTeamChain(t *testing.T) {
+	svc, _ := newTestService(time.Now())
+	withHierarchy(svc, true)
+	svc.teamRepo = emptyChainTeams{fakeTeams: svc.teamRepo.(*fakeTeams)}
+
+	if _, _, err := svc.ReassignReviewer(context.Background(), "pr-solo", "u6"); !errors.Is(err, ErrNoCandidate) {
+		t.Fatalf("expected ErrNoCandidate, got %v", err)
+	}
+}
+
+// TestSetTeamParent_Cycle проверяет запрет сделать команду потомком самой себя.
+func TestSetTeamParent_Cycle(t *testing.T) {
+	svc, _ := newTestService(time.Now())
+	withHierarchy(svc, false)
+
+	for _, parent := range []domain.TeamName{"platform", "solo"} {
+		if _, err := svc.SetTeamParent(context.Background(), parent, "solo", false); !errors.Is(err, ErrTeamHierarchyCycle) {
+			t.Fatalf("expected ErrTeamHierarchyCycle for %s under solo, got %v", parent, err)
+		}
+	}
+}
+
+// TestGetAssignmentsByTeam проверяет суммирование назначений вверх по иерархии.
+func TestGetAssignmentsByTeam(t *testing.T) {
+	svc, prs := newTestService(time.Now())
+	withHierarchy(svc, false)
+	prs.teamAssignments = map[domain.TeamName]int{"backend": 3, "solo": 1, "platform": 2}
+
+	stats, err := svc.GetAssignmentsByTeam(context.Background())
+	if err != nil {
+		t.Fatalf("GetAssignmentsByTeam returned error: %v", err)
+	}
+
+	want := map[domain.TeamName][2]int{
+		"platform": {2, 6},
+		"backend":  {3, 4},
+		"solo":     {1, 1},
+	}
+
+	if len(stats) != len(want) {
+		t.Fatalf("unexpected stats: %+v", stats)
+	}
+
+	for _, st := range stats {
+		if got := [2]int{st.Assignments, st.TotalAssignments}; got != want[st.TeamName] {
+			t.Fatalf("team %s: got %v, want %v", st.TeamName, got, want[st.TeamName])
+		}
+	}
+}
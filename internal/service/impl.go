@@ -2,18 +2,57 @@
 package service
 
 import (
+	"context"
 	"math/rand"
 	"sync"
 	"time"
 
+	"github.com/dixitix/pr-reviewer-service/internal/domain"
 	"github.com/dixitix/pr-reviewer-service/internal/repository"
 )
 
+// Config описывает настройки бизнес-логики.
+type Config struct {
+	// ReviewerPool — из каких команд подбираются ревьюверы; пустое значение — только основная команда.
+	ReviewerPool domain.ReviewerPool
+	// Metrics получает сведения о созданных и смёрженных PR и переназначениях; nil — не собираются.
+	Metrics Metrics
+}
+
+// Metrics получает сведения об операциях с PR для метрик. Методы вызываются после успешного
+// выполнения операции и не должны блокироваться.
+type Metrics interface {
+	// PullRequestCreated вызывается после создания PR.
+	PullRequestCreated()
+	// PullRequestMerged вызывается после merge PR; идемпотентный повтор merge не учитывается.
+	PullRequestMerged()
+	// ReviewerReassigned вызывается после переназначения ревьювера, в том числе по SLA.
+	ReviewerReassigned()
+	// NoCandidate вызывается, когда ревьювера некем заменить (ErrNoCandidate).
+	NoCandidate()
+}
+
+// noMetrics — Metrics, которые ничего не собирают.
+type noMetrics struct{}
+
+func (noMetrics) PullRequestCreated() {}
+func (noMetrics) PullRequestMerged()  {}
+func (noMetrics) ReviewerReassigned() {}
+func (noMetrics) NoCandidate()        {}
+
 // service — реализация интерфейса Service.
 type service struct {
+	tx              repository.Transactor
+	orgRepo         repository.OrganizationRepository
+	apiKeyRepo      repository.APIKeyRepository
 	teamRepo        repository.TeamRepository
 	userRepo        repository.UserRepository
 	pullRequestRepo repository.PullRequestRepository
+	webhookRepo     repository.WebhookRepository
+	notifyRepo      repository.NotificationRepository
+	auditRepo       repository.AuditRepository
+	reviewerPool    domain.ReviewerPool
+	metrics         Metrics
 
 	rndMu sync.Mutex
 	rnd   *rand.Rand
@@ -21,14 +60,55 @@ type service struct {
 
 // NewService создаёт новый экземпляр Service.
 func NewService(
+	tx repository.Transactor,
+	orgRepo repository.OrganizationRepository,
+	apiKeyRepo repository.APIKeyRepository,
 	teamRepo repository.TeamRepository,
 	userRepo repository.UserRepository,
 	pullRequestRepo repository.PullRequestRepository,
+	webhookRepo repository.WebhookRepository,
+	notifyRepo repository.NotificationRepository,
+	auditRepo repository.AuditRepository,
+	cfg Config,
 ) Service {
+	if cfg.ReviewerPool == "" {
+		cfg.ReviewerPool = domain.ReviewerPoolPrimary
+	}
+
+	if cfg.Metrics == nil {
+		cfg.Metrics = noMetrics{}
+	}
+
 	return &service{
+		tx:              tx,
+		orgRepo:         orgRepo,
+		apiKeyRepo:      apiKeyRepo,
 		teamRepo:        teamRepo,
 		userRepo:        userRepo,
 		pullRequestRepo: pullRequestRepo,
+		webhookRepo:     webhookRepo,
+		notifyRepo:      notifyRepo,
+		auditRepo:       auditRepo,
+		reviewerPool:    cfg.ReviewerPool,
+		metrics:         cfg.Metrics,
 		rnd:             rand.New(rand.NewSource(time.Now().UnixNano())),
 	}
 }
+
+// inTx выполняет fn в транзакции tx и возвращает её результат.
+func inTx[T any](ctx context.Context, tx repository.Transactor, fn func(ctx context.Context) (T, error)) (T, error) {
+	var result T
+
+	err := tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		var err error
+		result, err = fn(ctx)
+
+		return err
+	})
+	if err != nil {
+		var zero T
+		return zero, err
+	}
+
+	return result, nil
+}
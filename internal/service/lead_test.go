@@ -0,0 +1,72 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/dixitix/pr-reviewer-service/internal/domain"
+)
+
+// TestReassignReviewer_LeadFallback проверяет, что при отсутствии кандидатов в команде
+// ревью переназначается на активного лида, а без лидов возвращается ErrNoCandidate.
+func TestReassignReviewer_LeadFallback(t *testing.T) {
+	svc, prs := newTestService(time.Now())
+
+	if _, _, err := svc.ReassignReviewer(context.Background(), "pr-solo", "u6"); !errors.Is(err, ErrNoCandidate) {
+		t.Fatalf("expected ErrNoCandidate without leads, got %v", err)
+	}
+
+	users := svc.userRepo.(*fakeUsers)
+	users.users["u7"] = domain.User{ID: "u7", Username: "grace", TeamName: "backend", IsActive: true}
+	users.users["u8"] = domain.User{ID: "u8", Username: "heidi", TeamName: "backend", IsActive: false}
+
+	svc.teamRepo.(*fakeTeams).leads = map[domain.TeamName][]domain.User{
+		"solo": {users.users["u5"], users.users["u8"], users.users["u7"]},
+	}
+
+	pr, replacedBy, err := svc.ReassignReviewer(context.Background(), "pr-solo", "u6")
+	if err != nil {
+		t.Fatalf("ReassignReviewer returned error: %v", err)
+	}
+
+	// u5 — автор, u8 неактивен: остаётся только u7.
+	if replacedBy != "u7" || len(pr.AssignedReviewers) != 1 || pr.AssignedReviewers[0] != "u7" {
+		t.Fatalf("expected u6 replaced by lead u7, got %s (%v)", replacedBy, pr.AssignedReviewers)
+	}
+
+	if len(prs.events) != 1 || prs.events[0].Reason != noCandidateLeadReason {
+		t.Fatalf("expected reassignment event with lead reason, got %+v", prs.events)
+	}
+}
+
+// TestMergePullRequest_ForceNotifiesLeads проверяет, что принудительный merge адресуется лидам команды автора.
+func TestMergePullRequest_ForceNotifiesLeads(t *testing.T) {
+	svc, prs := newTestService(time.Now())
+
+	users := svc.userRepo.(*fakeUsers)
+	svc.teamRepo.(*fakeTeams).leads = map[domain.TeamName][]domain.User{
+		"backend": {users.users["u4"]},
+	}
+
+	if _, err := svc.MergePullRequest(context.Background(), "pr-fresh", MergeOptions{}); err != nil {
+		t.Fatalf("MergePullRequest returned error: %v", err)
+	}
+
+	if _, err := svc.MergePullRequest(context.Background(), "pr-stale", MergeOptions{Force: true}); err != nil {
+		t.Fatalf("MergePullRequest (force) returned error: %v", err)
+	}
+
+	if len(prs.events) != 2 {
+		t.Fatalf("expected 2 merge events, got %+v", prs.events)
+	}
+
+	if regular := prs.events[0]; len(regular.Leads) != 0 || regular.Reason != "" {
+		t.Fatalf("regular merge must not be addressed to leads: %+v", regular)
+	}
+
+	if forced := prs.events[1]; len(forced.Leads) != 1 || forced.Leads[0] != "u4" || forced.Reason == "" {
+		t.Fatalf("forced merge must be addressed to leads: %+v", forced)
+	}
+}
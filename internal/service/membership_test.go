@@ -0,0 +1,56 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/dixitix/pr-reviewer-service/internal/domain"
+)
+
+// withGuild добавляет ревьювера из solo (frank) и автора из backend (alice) в гильдию go-guild.
+func withGuild(svc *service) {
+	svc.userRepo.(*fakeUsers).guilds = map[domain.UserID][]domain.TeamName{
+		"u1": {"go-guild"},
+		"u6": {"go-guild"},
+	}
+}
+
+// TestReassignReviewer_ReviewerPool проверяет, что дополнительные команды ревьювера
+// учитываются только в режиме ReviewerPoolAllTeams.
+func TestReassignReviewer_ReviewerPool(t *testing.T) {
+	svc, prs := newTestService(time.Now())
+	withGuild(svc)
+
+	svc.reviewerPool = domain.ReviewerPoolPrimary
+
+	if _, _, err := svc.ReassignReviewer(context.Background(), "pr-solo", "u6"); !errors.Is(err, ErrNoCandidate) {
+		t.Fatalf("expected ErrNoCandidate for primary pool, got %v", err)
+	}
+
+	svc.reviewerPool = domain.ReviewerPoolAllTeams
+
+	_, replacedBy, err := svc.ReassignReviewer(context.Background(), "pr-solo", "u6")
+	if err != nil {
+		t.Fatalf("ReassignReviewer returned error: %v", err)
+	}
+
+	if replacedBy != "u1" {
+		t.Fatalf("expected reviewer from go-guild, got %s", replacedBy)
+	}
+
+	if len(prs.events) != 1 || prs.events[0].Reason != "" {
+		t.Fatalf("unexpected reassignment events: %+v", prs.events)
+	}
+}
+
+// TestRemoveTeamMember_Primary проверяет, что основную команду нельзя покинуть.
+func TestRemoveTeamMember_Primary(t *testing.T) {
+	svc, _ := newTestService(time.Now())
+	withGuild(svc)
+
+	if _, err := svc.RemoveTeamMember(context.Background(), "solo", "u6"); !errors.Is(err, ErrPrimaryTeamMembership) {
+		t.Fatalf("expected ErrPrimaryTeamMembership, got %v", err)
+	}
+}
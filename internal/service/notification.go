@@ -0,0 +1,150 @@
+// Package service содержит реализацию бизнес-логики сервиса назначения ревьюеров.
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/dixitix/pr-reviewer-service/internal/domain"
+	"github.com/dixitix/pr-reviewer-service/internal/repository"
+)
+
+// GetNotificationPreferences возвращает настройки уведомлений пользователя.
+func (s *service) GetNotificationPreferences(
+	ctx context.Context,
+	userID domain.UserID,
+) (domain.NotificationPreferences, error) {
+	if _, err := s.userRepo.GetByID(ctx, userID); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return domain.NotificationPreferences{}, ErrNotFound
+		}
+
+		return domain.NotificationPreferences{}, fmt.Errorf("get user by id %s: %w", userID, err)
+	}
+
+	prefs, err := s.notifyRepo.GetPreferences(ctx, userID)
+	if err != nil {
+		return domain.NotificationPreferences{}, fmt.Errorf("get notification preferences for %s: %w", userID, err)
+	}
+
+	return prefs, nil
+}
+
+// UpdateNotificationPreferences меняет переданные поля настроек уведомлений пользователя.
+// Менять настройки может сам пользователь или его лид.
+func (s *service) UpdateNotificationPreferences(
+	ctx context.Context,
+	userID domain.UserID,
+	update NotificationPreferencesUpdate,
+) (domain.NotificationPreferences, error) {
+	return inTx(ctx, s.tx, func(ctx context.Context) (domain.NotificationPreferences, error) {
+		user, err := s.userRepo.GetByID(ctx, userID)
+		if err != nil {
+			if errors.Is(err, repository.ErrNotFound) {
+				return domain.NotificationPreferences{}, ErrNotFound
+			}
+
+			return domain.NotificationPreferences{}, fmt.Errorf("get user by id %s: %w", userID, err)
+		}
+
+		if err := s.authorizeUser(ctx, user); err != nil {
+			return domain.NotificationPreferences{}, err
+		}
+
+		prefs, err := s.notifyRepo.GetPreferences(ctx, userID)
+		if err != nil {
+			return domain.NotificationPreferences{}, fmt.Errorf("get notification preferences for %s: %w", userID, err)
+		}
+
+		if update.ChatEnabled != nil {
+			prefs.ChatEnabled = *update.ChatEnabled
+		}
+
+		if update.Email != nil {
+			prefs.Email = *update.Email
+		}
+
+		if update.EmailMode != nil {
+			prefs.EmailMode = *update.EmailMode
+		}
+
+		if prefs.EmailMode != domain.EmailModeOff && prefs.Email == "" {
+			return domain.NotificationPreferences{}, ErrEmailRequired
+		}
+
+		if err := s.notifyRepo.UpsertPreferences(ctx, prefs); err != nil {
+			return domain.NotificationPreferences{}, fmt.Errorf("upsert notification preferences for %s: %w", userID, err)
+		}
+
+		// Адрес почты в журнал не попадает, записывается только факт его изменения.
+		err = s.audit(ctx, domain.AuditNotificationPrefsSet, string(userID), map[string]any{
+			"chat_enabled":  prefs.ChatEnabled,
+			"email_mode":    prefs.EmailMode,
+			"email_changed": update.Email != nil,
+		})
+		if err != nil {
+			return domain.NotificationPreferences{}, err
+		}
+
+		return prefs, nil
+	})
+}
+
+// GetTeamChatChannel возвращает канал чата команды.
+func (s *service) GetTeamChatChannel(ctx context.Context, teamName domain.TeamName) (domain.TeamChatChannel, error) {
+	channel, err := s.notifyRepo.GetTeamChatChannel(ctx, teamName)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return domain.TeamChatChannel{}, ErrNotFound
+		}
+
+		return domain.TeamChatChannel{}, fmt.Errorf("get chat channel of team %s: %w", teamName, err)
+	}
+
+	return channel, nil
+}
+
+// SetTeamChatChannel создаёт или обновляет канал чата команды. Канал меняет лид команды.
+func (s *service) SetTeamChatChannel(ctx context.Context, channel domain.TeamChatChannel) error {
+	return s.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.authorizeTeam(ctx, channel.TeamName); err != nil {
+			return err
+		}
+
+		exists, err := s.teamRepo.TeamExists(ctx, channel.TeamName)
+		if err != nil {
+			return fmt.Errorf("check team %s exists: %w", channel.TeamName, err)
+		}
+
+		if !exists {
+			return ErrNotFound
+		}
+
+		if err := s.notifyRepo.UpsertTeamChatChannel(ctx, channel); err != nil {
+			return fmt.Errorf("upsert chat channel of team %s: %w", channel.TeamName, err)
+		}
+
+		// URL входящего вебхука чата — секрет, в журнал записывается только канал.
+		return s.audit(ctx, domain.AuditTeamChatChannelSet, string(channel.TeamName), map[string]any{"channel": channel.Channel})
+	})
+}
+
+// DeleteTeamChatChannel удаляет канал чата команды. Канал удаляет лид команды.
+func (s *service) DeleteTeamChatChannel(ctx context.Context, teamName domain.TeamName) error {
+	return s.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.authorizeTeam(ctx, teamName); err != nil {
+			return err
+		}
+
+		if err := s.notifyRepo.DeleteTeamChatChannel(ctx, teamName); err != nil {
+			if errors.Is(err, repository.ErrNotFound) {
+				return ErrNotFound
+			}
+
+			return fmt.Errorf("delete chat channel of team %s: %w", teamName, err)
+		}
+
+		return s.audit(ctx, domain.AuditTeamChatChannelDeleted, string(teamName), nil)
+	})
+}
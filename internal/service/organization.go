@@ -0,0 +1,54 @@
+// Package service содержит реализацию бизнес-логики сервиса назначения ревьюеров.
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/dixitix/pr-reviewer-service/internal/domain"
+	"github.com/dixitix/pr-reviewer-service/internal/repository"
+	"github.com/dixitix/pr-reviewer-service/internal/tenant"
+)
+
+// CreateOrganization создаёт организацию.
+func (s *service) CreateOrganization(
+	ctx context.Context,
+	id domain.OrganizationID,
+	name string,
+) (domain.Organization, error) {
+	org := domain.Organization{
+		ID:        id,
+		Name:      name,
+		CreatedAt: time.Now().UTC(),
+	}
+
+	err := s.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.orgRepo.Create(ctx, org); err != nil {
+			if errors.Is(err, repository.ErrAlreadyExists) {
+				return ErrOrganizationExists
+			}
+
+			return fmt.Errorf("create organization %s: %w", id, err)
+		}
+
+		// Запись относится к журналу новой организации.
+		return s.audit(tenant.WithOrganization(ctx, id), domain.AuditOrganizationCreated, string(id), map[string]any{"name": name})
+	})
+	if err != nil {
+		return domain.Organization{}, err
+	}
+
+	return org, nil
+}
+
+// OrganizationExists сообщает, существует ли организация.
+func (s *service) OrganizationExists(ctx context.Context, id domain.OrganizationID) (bool, error) {
+	ok, err := s.orgRepo.Exists(ctx, id)
+	if err != nil {
+		return false, fmt.Errorf("check organization %s: %w", id, err)
+	}
+
+	return ok, nil
+}
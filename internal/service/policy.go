@@ -0,0 +1,155 @@
+// Package service содержит реализацию бизнес-логики сервиса назначения ревьюеров.
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/dixitix/pr-reviewer-service/internal/auth"
+	"github.com/dixitix/pr-reviewer-service/internal/domain"
+	"github.com/dixitix/pr-reviewer-service/internal/repository"
+)
+
+// Правила доступа к изменяющим операциям. Они применяются к пользователям, вошедшим по токену
+// провайдера идентификации: администратор может всё, лид управляет своей командой и её дочерними
+// командами, их участниками и их PR, автор — своими PR, а API-ключи и вебхуки меняет только
+// администратор. Забрать пользователя из его основной команды может только лид этой команды. API-ключи ограничены своими правами (scopes), а фоновые задачи (SLA) выполняются
+// без вызывающего, поэтому для них правила не проверяются.
+
+// restrictedCaller возвращает пользователя из контекста, если к нему применяются правила доступа.
+func restrictedCaller(ctx context.Context) (domain.UserID, bool) {
+	id, ok := auth.FromContext(ctx)
+	if !ok || id.UserID == "" || id.HasRole(domain.RoleAdmin) {
+		return "", false
+	}
+
+	return id.UserID, true
+}
+
+// authorizeAdmin проверяет, что вызывающий — администратор. Так защищены API-ключи и вебхуки:
+// через ключ с правом team:admin пользователь обошёл бы остальные правила.
+func authorizeAdmin(ctx context.Context) error {
+	if _, ok := restrictedCaller(ctx); ok {
+		return ErrForbidden
+	}
+
+	return nil
+}
+
+// authorizeScopes проверяет, что вызывающий сам имеет все права scopes, которые выдаёт API-ключу.
+func authorizeScopes(ctx context.Context, scopes []domain.APIScope) error {
+	id, ok := auth.FromContext(ctx)
+	if !ok || id.Root {
+		return nil
+	}
+
+	for _, scope := range scopes {
+		if !id.HasScope(scope) {
+			return ErrForbidden
+		}
+	}
+
+	return nil
+}
+
+// authorizeTeam проверяет, что вызывающий может управлять командой name.
+func (s *service) authorizeTeam(ctx context.Context, name domain.TeamName) error {
+	caller, ok := restrictedCaller(ctx)
+	if !ok {
+		return nil
+	}
+
+	return s.requireLead(ctx, caller, name)
+}
+
+// authorizeNewTeam проверяет, что вызывающий может создать команду в parent.
+// Корневые команды создаёт только администратор.
+func (s *service) authorizeNewTeam(ctx context.Context, parent domain.TeamName) error {
+	caller, ok := restrictedCaller(ctx)
+	if !ok {
+		return nil
+	}
+
+	if parent == "" {
+		return ErrForbidden
+	}
+
+	return s.requireLead(ctx, caller, parent)
+}
+
+// authorizeUser проверяет, что вызывающий может менять пользователя: это он сам или его лид.
+func (s *service) authorizeUser(ctx context.Context, user domain.User) error {
+	caller, ok := restrictedCaller(ctx)
+	if !ok || caller == user.ID {
+		return nil
+	}
+
+	return s.requireLead(ctx, caller, user.TeamName)
+}
+
+// authorizeMemberMove проверяет, что вызывающий может сменить основную команду пользователя userID
+// и перезаписать его данные: это он сам или лид его текущей основной команды. Пользователя, которого
+// ещё нет, может создать любой, кто управляет целевой командой.
+func (s *service) authorizeMemberMove(ctx context.Context, userID domain.UserID) error {
+	if _, ok := restrictedCaller(ctx); !ok {
+		return nil
+	}
+
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil
+		}
+
+		return fmt.Errorf("get user %s: %w", userID, err)
+	}
+
+	return s.authorizeUser(ctx, user)
+}
+
+// authorizePullRequest проверяет, что вызывающий может менять PR автора authorID: это сам автор
+// или лид команды автора. Если leadOnly, автору действие недоступно.
+func (s *service) authorizePullRequest(ctx context.Context, authorID domain.UserID, leadOnly bool) error {
+	caller, ok := restrictedCaller(ctx)
+	if !ok || (caller == authorID && !leadOnly) {
+		return nil
+	}
+
+	author, err := s.userRepo.GetByID(ctx, authorID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return ErrForbidden
+		}
+
+		return fmt.Errorf("get author %s: %w", authorID, err)
+	}
+
+	return s.requireLead(ctx, caller, author.TeamName)
+}
+
+// requireLead возвращает ErrForbidden, если userID не лид команды name или одной из её родительских команд.
+func (s *service) requireLead(ctx context.Context, userID domain.UserID, name domain.TeamName) error {
+	chain, err := s.teamRepo.ListTeamChain(ctx, name)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return ErrForbidden
+		}
+
+		return fmt.Errorf("list chain of team %s: %w", name, err)
+	}
+
+	for _, team := range chain {
+		leads, err := s.teamLeadIDs(ctx, team.Name)
+		if err != nil {
+			return err
+		}
+
+		if slices.Contains(leads, userID) {
+			return nil
+		}
+	}
+
+	return ErrForbidden
+}
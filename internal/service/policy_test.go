@@ -0,0 +1,242 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/dixitix/pr-reviewer-service/internal/auth"
+	"github.com/dixitix/pr-reviewer-service/internal/domain"
+)
+
+// TestPolicy_PullRequests проверяет, что PR меняют автор, лиды его команды и родительских команд
+// и администратор, а принудительный merge доступен только лидам и администратору.
+func TestPolicy_PullRequests(t *testing.T) {
+	svc, _ := newTestService(time.Now())
+
+	users := svc.userRepo.(*fakeUsers)
+	teams := svc.teamRepo.(*fakeTeams)
+	teams.teams = map[domain.TeamName]domain.Team{
+		"backend":     {Name: "backend", Parent: "engineering"},
+		"engineering": {Name: "engineering"},
+	}
+	teams.leads = map[domain.TeamName][]domain.User{
+		"backend":     {users.users["u4"]},
+		"engineering": {users.users["u5"]},
+	}
+
+	if _, _, err := svc.ReassignReviewer(asUser("u2"), "pr-fresh", "u2"); !errors.Is(err, ErrForbidden) {
+		t.Fatalf("expected ErrForbidden for reassignment by another engineer, got %v", err)
+	}
+
+	// Администратор не лид и не автор, но может переназначить ревьювера; u4 — единственный кандидат.
+	_, replacedBy, err := svc.ReassignReviewer(asUser("u6", domain.RoleAdmin), "pr-stale", "u3")
+	if err != nil {
+		t.Fatalf("reassignment by admin returned error: %v", err)
+	}
+
+	if replacedBy != "u4" {
+		t.Fatalf("expected u3 replaced by u4, got %s", replacedBy)
+	}
+
+	if _, err := svc.CreatePullRequest(asUser("u2"), "pr-new", "Add feature", "u1"); !errors.Is(err, ErrForbidden) {
+		t.Fatalf("expected ErrForbidden for pull request on behalf of another user, got %v", err)
+	}
+
+	if _, err := svc.MergePullRequest(asUser("u1"), "pr-stale", MergeOptions{Force: true}); !errors.Is(err, ErrForbidden) {
+		t.Fatalf("expected ErrForbidden for forced merge by author, got %v", err)
+	}
+
+	merged, err := svc.MergePullRequest(asUser("u1"), "pr-fresh", MergeOptions{})
+	if err != nil {
+		t.Fatalf("merge by author returned error: %v", err)
+	}
+
+	if merged.MergedBy != "user:u1" {
+		t.Fatalf("expected merge attributed to user:u1, got %q", merged.MergedBy)
+	}
+
+	if _, err := svc.MergePullRequest(asUser("u5"), "pr-stale", MergeOptions{Force: true}); err != nil {
+		t.Fatalf("forced merge by lead of parent team returned error: %v", err)
+	}
+}
+
+// TestPolicy_Users проверяет, что активность пользователя меняют он сам, его лид и администратор.
+func TestPolicy_Users(t *testing.T) {
+	svc, _ := newTestService(time.Now())
+
+	users := svc.userRepo.(*fakeUsers)
+	svc.teamRepo.(*fakeTeams).leads = map[domain.TeamName][]domain.User{
+		"backend": {users.users["u4"]},
+	}
+
+	// Флаг не меняется, поэтому разрешённые вызовы не доходят до записи.
+	for _, tc := range []struct {
+		ctx  context.Context
+		want error
+	}{
+		{ctx: asUser("u2"), want: ErrForbidden},
+		{ctx: asUser("u5"), want: ErrForbidden},
+		{ctx: asUser("u3"), want: nil},
+		{ctx: asUser("u4"), want: nil},
+		{ctx: asUser("u6", domain.RoleAdmin), want: nil},
+	} {
+		id, _ := auth.FromContext(tc.ctx)
+
+		if _, err := svc.SetUserActive(tc.ctx, "u3", true); !errors.Is(err, tc.want) {
+			t.Fatalf("SetUserActive by %s: expected %v, got %v", id.UserID, tc.want, err)
+		}
+	}
+}
+
+// TestPolicy_TeamMembers проверяет, что лид не может забрать пользователя из соседней команды
+// ни при создании дочерней команды, ни сделав свою команду его основной.
+func TestPolicy_TeamMembers(t *testing.T) {
+	svc, _ := newTestService(time.Now())
+
+	users := svc.userRepo.(*fakeUsers)
+	teams := svc.teamRepo.(*fakeTeams)
+	teams.teams = map[domain.TeamName]domain.Team{
+		"platform": {Name: "platform"},
+		"backend":  {Name: "backend", Parent: "platform"},
+		"solo":     {Name: "solo", Parent: "platform"},
+	}
+	teams.leads = map[domain.TeamName][]domain.User{
+		"backend": {users.users["u4"]},
+		"solo":    {users.users["u5"]},
+	}
+
+	bob := users.users["u2"]
+	takeover := []domain.User{{ID: "u2", Username: "bob-renamed", IsActive: false}}
+
+	if err := svc.CreateTeam(asUser("u5"), domain.Team{Name: "squad", Parent: "solo"}, takeover); !errors.Is(err, ErrForbidden) {
+		t.Fatalf("expected ErrForbidden for team created with a user from a sibling team, got %v", err)
+	}
+
+	if _, err := svc.AddTeamMember(asUser("u5"), "solo", "u2", true); !errors.Is(err, ErrForbidden) {
+		t.Fatalf("expected ErrForbidden for primary team changed by lead of a sibling team, got %v", err)
+	}
+
+	if got := users.users["u2"]; got != bob {
+		t.Fatalf("forbidden calls changed user: %+v", got)
+	}
+
+	if _, ok := teams.teams["squad"]; ok {
+		t.Fatalf("forbidden call created team squad")
+	}
+
+	// Свой участник и новый пользователь переносятся в дочернюю команду лида.
+	members := []domain.User{{ID: "u6", Username: "frank", IsActive: true}, {ID: "u9", Username: "ivan", IsActive: true}}
+	if err := svc.CreateTeam(asUser("u5"), domain.Team{Name: "squad", Parent: "solo"}, members); err != nil {
+		t.Fatalf("CreateTeam with own members returned error: %v", err)
+	}
+
+	// Дополнительная команда не меняет основную, поэтому хватает прав на целевую команду.
+	if _, err := svc.AddTeamMember(asUser("u5"), "solo", "u2", false); err != nil {
+		t.Fatalf("AddTeamMember as secondary team returned error: %v", err)
+	}
+
+	if _, err := svc.AddTeamMember(asUser("u4"), "solo", "u2", true); !errors.Is(err, ErrForbidden) {
+		t.Fatalf("expected ErrForbidden for primary team outside of the lead's teams, got %v", err)
+	}
+
+	if _, err := svc.AddTeamMember(asUser("u6", domain.RoleAdmin), "solo", "u2", true); err != nil {
+		t.Fatalf("AddTeamMember by admin returned error: %v", err)
+	}
+
+	if got := users.users["u2"].TeamName; got != "solo" {
+		t.Fatalf("expected solo to become primary team of u2, got %s", got)
+	}
+}
+
+// TestPolicy_APIKeysAndWebhooks проверяет, что API-ключи и вебхуки меняет только администратор,
+// а ключ выдаётся только с правами вызывающего.
+func TestPolicy_APIKeysAndWebhooks(t *testing.T) {
+	svc := &service{tx: passthroughTx{}, apiKeyRepo: &memoryAPIKeys{keys: map[string]domain.APIKey{}}, auditRepo: &memoryAudit{}}
+	scopes := []domain.APIScope{domain.APIScopeTeamAdmin}
+
+	if _, _, err := svc.CreateAPIKey(asUser("u1"), "escalate", scopes); !errors.Is(err, ErrForbidden) {
+		t.Fatalf("expected ErrForbidden for api key created by non-admin, got %v", err)
+	}
+
+	if err := svc.RevokeAPIKey(asUser("u1"), "k1"); !errors.Is(err, ErrForbidden) {
+		t.Fatalf("expected ErrForbidden for api key revoked by non-admin, got %v", err)
+	}
+
+	if _, err := svc.CreateWebhookSubscription(asUser("u1"), "https://example.com", nil, ""); !errors.Is(err, ErrForbidden) {
+		t.Fatalf("expected ErrForbidden for webhook created by non-admin, got %v", err)
+	}
+
+	if err := svc.DeleteWebhookSubscription(asUser("u1"), "s1"); !errors.Is(err, ErrForbidden) {
+		t.Fatalf("expected ErrForbidden for webhook deleted by non-admin, got %v", err)
+	}
+
+	readOnly := auth.WithIdentity(context.Background(), auth.Identity{KeyID: "k1", Scopes: []domain.APIScope{domain.APIScopeRead}})
+	if _, _, err := svc.CreateAPIKey(readOnly, "escalate", scopes); !errors.Is(err, ErrForbidden) {
+		t.Fatalf("expected ErrForbidden for api key with scopes the caller lacks, got %v", err)
+	}
+
+	if _, _, err := svc.CreateAPIKey(asUser("u1", domain.RoleAdmin), "ci", scopes); err != nil {
+		t.Fatalf("CreateAPIKey by admin returned error: %v", err)
+	}
+}
+
+// TestPolicy_Notifications проверяет, что настройки уведомлений меняют сам пользователь, его лид
+// и администратор, а канал чата команды — её лид и администратор.
+func TestPolicy_Notifications(t *testing.T) {
+	svc, _ := newTestService(time.Now())
+
+	users := svc.userRepo.(*fakeUsers)
+	teams := svc.teamRepo.(*fakeTeams)
+	teams.teams = map[domain.TeamName]domain.Team{"backend": {Name: "backend"}, "solo": {Name: "solo"}}
+	teams.leads = map[domain.TeamName][]domain.User{"backend": {users.users["u4"]}}
+
+	notifications := &memoryNotifications{
+		prefs:    map[domain.UserID]domain.NotificationPreferences{},
+		channels: map[domain.TeamName]domain.TeamChatChannel{"backend": {TeamName: "backend", Channel: "#backend"}},
+	}
+	svc.notifyRepo = notifications
+
+	email := "carol@example.com"
+	update := NotificationPreferencesUpdate{Email: &email}
+
+	for _, tc := range []struct {
+		ctx  context.Context
+		want error
+	}{
+		{ctx: asUser("u2"), want: ErrForbidden},
+		{ctx: asUser("u5"), want: ErrForbidden},
+		{ctx: asUser("u3"), want: nil},
+		{ctx: asUser("u4"), want: nil},
+		{ctx: asUser("u6", domain.RoleAdmin), want: nil},
+	} {
+		id, _ := auth.FromContext(tc.ctx)
+
+		if _, err := svc.UpdateNotificationPreferences(tc.ctx, "u3", update); !errors.Is(err, tc.want) {
+			t.Fatalf("UpdateNotificationPreferences by %s: expected %v, got %v", id.UserID, tc.want, err)
+		}
+	}
+
+	redirect := domain.TeamChatChannel{TeamName: "backend", Channel: "#attacker"}
+
+	if err := svc.SetTeamChatChannel(asUser("u2"), redirect); !errors.Is(err, ErrForbidden) {
+		t.Fatalf("expected ErrForbidden for chat channel changed by non-lead, got %v", err)
+	}
+
+	if err := svc.DeleteTeamChatChannel(asUser("u5"), "backend"); !errors.Is(err, ErrForbidden) {
+		t.Fatalf("expected ErrForbidden for chat channel removed by lead of another team, got %v", err)
+	}
+
+	if got := notifications.channels["backend"].Channel; got != "#backend" {
+		t.Fatalf("forbidden calls changed chat channel to %q", got)
+	}
+
+	if err := svc.SetTeamChatChannel(asUser("u4"), domain.TeamChatChannel{TeamName: "backend", Channel: "#reviews"}); err != nil {
+		t.Fatalf("SetTeamChatChannel by lead returned error: %v", err)
+	}
+
+	if err := svc.DeleteTeamChatChannel(asUser("u6", domain.RoleAdmin), "backend"); err != nil {
+		t.Fatalf("DeleteTeamChatChannel by admin returned error: %v", err)
+	}
+}
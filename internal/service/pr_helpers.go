@@ -1,7 +1,62 @@
 // Package service содержит реализацию бизнес-логики сервиса назначения ревьюеров.
 package service
 
-import "github.com/dixitix/pr-reviewer-service/internal/domain"
+import (
+	"context"
+	"fmt"
+
+	"github.com/dixitix/pr-reviewer-service/internal/domain"
+)
+
+// noCandidateLeadReason — причина назначения лида, когда в команде нет других кандидатов.
+const noCandidateLeadReason = "no candidate in team, assigned to team lead"
+
+// reviewerCandidates возвращает активных участников команд пользователя, из которых подбираются ревьюверы:
+// только основной команды или, если так настроено, всех его команд. Участник нескольких общих команд
+// встречается один раз. Если excludeID != nil, этот пользователь исключается.
+func (s *service) reviewerCandidates(
+	ctx context.Context,
+	user domain.User,
+	excludeID *domain.UserID,
+) ([]domain.User, error) {
+	teams := []domain.TeamName{user.TeamName}
+
+	if s.reviewerPool == domain.ReviewerPoolAllTeams {
+		memberships, err := s.userRepo.ListMemberships(ctx, user.ID)
+		if err != nil {
+			return nil, fmt.Errorf("list memberships of user %s: %w", user.ID, err)
+		}
+
+		for _, m := range memberships {
+			if m.TeamName != user.TeamName {
+				teams = append(teams, m.TeamName)
+			}
+		}
+	}
+
+	var (
+		candidates []domain.User
+		seen       = make(map[domain.UserID]struct{})
+	)
+
+	for _, team := range teams {
+		members, err := s.userRepo.ListActiveByTeam(ctx, team, excludeID)
+		if err != nil {
+			return nil, fmt.Errorf("list active users for team %s: %w", team, err)
+		}
+
+		for _, u := range members {
+			if _, ok := seen[u.ID]; ok {
+				continue
+			}
+
+			seen[u.ID] = struct{}{}
+			candidates = append(candidates, u)
+		}
+	}
+
+	return candidates, nil
+}
 
 // pickReviewersForNewPR выбирает до limit ревьюверов из списка активных участников команды.
 func (s *service) pickReviewersForNewPR(
@@ -67,3 +122,69 @@ func (s *service) buildReplacementCandidates(
 
 	return candidates
 }
+
+// fallbackCandidates подбирает ревьюверов, когда в команде нет кандидатов: сначала среди активных лидов
+// команды, затем, если команда это разрешает, среди участников и лидов родительских команд снизу вверх.
+// Автор, заменяемый ревьювер и уже назначенные ревьюверы исключаются. reason описывает, откуда взят кандидат.
+func (s *service) fallbackCandidates(
+	ctx context.Context,
+	teamName domain.TeamName,
+	pr domain.PullRequest,
+	reviewerID domain.UserID,
+) (candidates []domain.UserID, reason string, err error) {
+	if candidates, err = s.activeLeadCandidates(ctx, teamName, pr, reviewerID); err != nil || len(candidates) > 0 {
+		return candidates, noCandidateLeadReason, err
+	}
+
+	chain, err := s.teamRepo.ListTeamChain(ctx, teamName)
+	if err != nil {
+		return nil, "", fmt.Errorf("list chain of team %s: %w", teamName, err)
+	}
+
+	// Команду могли удалить параллельно: тогда кандидатов нет.
+	if len(chain) == 0 || !chain[0].FallbackToParent {
+		return nil, "", nil
+	}
+
+	for _, ancestor := range chain[1:] {
+		members, err := s.userRepo.ListActiveByTeam(ctx, ancestor.Name, nil)
+		if err != nil {
+			return nil, "", fmt.Errorf("list active users for team %s: %w", ancestor.Name, err)
+		}
+
+		if candidates = s.buildReplacementCandidates(pr, reviewerID, members); len(candidates) == 0 {
+			if candidates, err = s.activeLeadCandidates(ctx, ancestor.Name, pr, reviewerID); err != nil {
+				return nil, "", err
+			}
+		}
+
+		if len(candidates) > 0 {
+			return candidates, fmt.Sprintf("no candidate in team, assigned from parent team %s", ancestor.Name), nil
+		}
+	}
+
+	return nil, "", nil
+}
+
+// activeLeadCandidates подбирает активных лидов команды для ревью PR, исключая автора,
+// заменяемого ревьювера и уже назначенных ревьюверов.
+func (s *service) activeLeadCandidates(
+	ctx context.Context,
+	teamName domain.TeamName,
+	pr domain.PullRequest,
+	reviewerID domain.UserID,
+) ([]domain.UserID, error) {
+	leads, err := s.teamRepo.ListLeads(ctx, teamName)
+	if err != nil {
+		return nil, fmt.Errorf("list leads of team %s: %w", teamName, err)
+	}
+
+	active := make([]domain.User, 0, len(leads))
+	for _, lead := range leads {
+		if lead.IsActive {
+			active = append(active, lead)
+		}
+	}
+
+	return s.buildReplacementCandidates(pr, reviewerID, active), nil
+}
@@ -0,0 +1,40 @@
+// Package service содержит реализацию бизнес-логики сервиса назначения ревьюеров.
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/dixitix/pr-reviewer-service/internal/domain"
+	"github.com/dixitix/pr-reviewer-service/internal/precondition"
+	"github.com/dixitix/pr-reviewer-service/internal/repository"
+)
+
+// checkVersion возвращает ErrPreconditionFailed, если клиент ожидает версию ресурса, отличную от current.
+func checkVersion(ctx context.Context, current int64) error {
+	if expected, ok := precondition.Version(ctx); ok && expected != current {
+		return ErrPreconditionFailed
+	}
+
+	return nil
+}
+
+// checkTeamVersion сверяет версию команды с ожидаемой клиентом. Команда блокируется до конца
+// транзакции, чтобы версия не изменилась до записи.
+func (s *service) checkTeamVersion(ctx context.Context, name domain.TeamName) error {
+	if _, ok := precondition.Version(ctx); !ok {
+		return nil
+	}
+
+	version, err := s.teamRepo.LockTeam(ctx, name)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return ErrNotFound
+		}
+
+		return fmt.Errorf("lock team %s: %w", name, err)
+	}
+
+	return checkVersion(ctx, version)
+}
@@ -7,107 +7,258 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/dixitix/pr-reviewer-service/internal/auth"
 	"github.com/dixitix/pr-reviewer-service/internal/domain"
 	"github.com/dixitix/pr-reviewer-service/internal/repository"
 )
 
-// CreatePullRequest создаёт новый PR и назначает до двух ревьюверов из команды автора (исключая самого автора).
+// CreatePullRequest создаёт новый PR и назначает до двух ревьюверов из команд автора (исключая самого автора).
 func (s *service) CreatePullRequest(
 	ctx context.Context,
 	id domain.PullRequestID,
 	name string,
 	authorID domain.UserID,
 ) (domain.PullRequest, error) {
-	if _, err := s.pullRequestRepo.GetByID(ctx, id); err == nil {
-		return domain.PullRequest{}, ErrPullRequestAlreadyExists
-	} else if !errors.Is(err, repository.ErrNotFound) {
-		return domain.PullRequest{}, fmt.Errorf("get pull request %s: %w", id, err)
-	}
+	pr, err := inTx(ctx, s.tx, func(ctx context.Context) (domain.PullRequest, error) {
+		// PR от имени другого пользователя создаёт только его лид.
+		if err := s.authorizePullRequest(ctx, authorID, false); err != nil {
+			return domain.PullRequest{}, err
+		}
 
-	// Получаем автора, чтобы узнать его команду.
-	author, err := s.userRepo.GetByID(ctx, authorID)
-	if err != nil {
-		if errors.Is(err, repository.ErrNotFound) {
+		if _, err := s.pullRequestRepo.GetByID(ctx, id); err == nil {
+			return domain.PullRequest{}, ErrPullRequestAlreadyExists
+		} else if !errors.Is(err, repository.ErrNotFound) {
+			return domain.PullRequest{}, fmt.Errorf("get pull request %s: %w", id, err)
+		}
+
+		// Получаем автора, чтобы узнать его команду.
+		author, err := s.userRepo.GetByID(ctx, authorID)
+		if err != nil {
+			if errors.Is(err, repository.ErrNotFound) {
+				return domain.PullRequest{}, ErrNotFound
+			}
+
+			return domain.PullRequest{}, fmt.Errorf("get author %s: %w", authorID, err)
+		}
+
+		// Проверяем, что команда автора существует.
+		exists, err := s.teamRepo.TeamExists(ctx, author.TeamName)
+		if err != nil {
+			return domain.PullRequest{}, fmt.Errorf("check team %s exists: %w", author.TeamName, err)
+		}
+
+		if !exists {
 			return domain.PullRequest{}, ErrNotFound
 		}
 
-		return domain.PullRequest{}, fmt.Errorf("get author %s: %w", authorID, err)
-	}
+		// Берём активных участников команд автора, исключая самого автора.
+		excludeAuthor := author.ID
+		activeMembers, err := s.reviewerCandidates(ctx, author, &excludeAuthor)
+		if err != nil {
+			return domain.PullRequest{}, err
+		}
 
-	// Проверяем, что команда автора существует.
-	exists, err := s.teamRepo.TeamExists(ctx, author.TeamName)
-	if err != nil {
-		return domain.PullRequest{}, fmt.Errorf("check team %s exists: %w", author.TeamName, err)
-	}
+		// Выбираем до двух ревьюверов из списка активных участников.
+		reviewerIDs := s.pickReviewersForNewPR(activeMembers, 2)
 
-	if !exists {
-		return domain.PullRequest{}, ErrNotFound
-	}
+		// Если в команде некого назначить, ревью достаётся лиду команды или участнику родительской команды.
+		var reason string
+		if len(reviewerIDs) == 0 {
+			fallback, fallbackReason, err := s.fallbackCandidates(ctx, author.TeamName, domain.PullRequest{AuthorID: authorID}, "")
+			if err != nil {
+				return domain.PullRequest{}, err
+			}
 
-	// Берём активных участников команды автора, исключая самого автора.
-	excludeAuthor := author.ID
-	activeMembers, err := s.userRepo.ListActiveByTeam(ctx, author.TeamName, &excludeAuthor)
-	if err != nil {
-		return domain.PullRequest{}, fmt.Errorf("list active users for team %s: %w", author.TeamName, err)
-	}
+			if len(fallback) > 0 {
+				s.rndMu.Lock()
+				reviewerIDs = []domain.UserID{fallback[s.rnd.Intn(len(fallback))]}
+				s.rndMu.Unlock()
 
-	// Выбираем до двух ревьюверов из списка активных участников.
-	reviewerIDs := s.pickReviewersForNewPR(activeMembers, 2)
+				reason = fallbackReason
+			}
+		}
 
-	now := time.Now().UTC()
+		now := time.Now().UTC()
+		actor := auth.Actor(ctx)
+
+		pr := domain.PullRequest{
+			ID:                id,
+			Name:              name,
+			AuthorID:          authorID,
+			Status:            domain.PullRequestStatusOpen,
+			AssignedReviewers: reviewerIDs,
+			CreatedAt:         &now,
+			CreatedBy:         actor,
+			Version:           1,
+			// MergedAt остаётся nil.
+		}
 
-	pr := domain.PullRequest{
-		ID:                id,
-		Name:              name,
-		AuthorID:          authorID,
-		Status:            domain.PullRequestStatusOpen,
-		AssignedReviewers: reviewerIDs,
-		CreatedAt:         &now,
-		// MergedAt остаётся nil.
-	}
+		// Событие пишется в outbox в одной транзакции с PR.
+		var events []domain.Event
+		if len(reviewerIDs) > 0 {
+			events = append(events, domain.Event{
+				Type:           domain.EventReviewersAssigned,
+				PullRequest:    pr,
+				AddedReviewers: reviewerIDs,
+				Reason:         reason,
+				Actor:          actor,
+				OccurredAt:     now,
+			})
+		}
+
+		// Проверка выше не защищает от параллельного создания — дубликат отклоняет само хранилище.
+		if err := s.pullRequestRepo.Create(ctx, pr, events...); err != nil {
+			if errors.Is(err, repository.ErrAlreadyExists) {
+				return domain.PullRequest{}, ErrPullRequestAlreadyExists
+			}
 
-	if err := s.pullRequestRepo.Create(ctx, pr); err != nil {
-		return domain.PullRequest{}, fmt.Errorf("create pull request %s: %w", id, err)
+			return domain.PullRequest{}, fmt.Errorf("create pull request %s: %w", id, err)
+		}
+
+		return pr, nil
+	})
+	if err != nil {
+		return domain.PullRequest{}, err
 	}
 
+	s.metrics.PullRequestCreated()
+
 	return pr, nil
 }
 
-// MergePullRequest помечает PR как MERGED.
+// MergePullRequest помечает PR как MERGED. О принудительном merge сообщается лидам команды автора.
 func (s *service) MergePullRequest(
 	ctx context.Context,
 	id domain.PullRequestID,
+	opts MergeOptions,
 ) (domain.PullRequest, error) {
-	pr, err := s.pullRequestRepo.GetByID(ctx, id)
-	if err != nil {
-		if errors.Is(err, repository.ErrNotFound) {
-			return domain.PullRequest{}, ErrNotFound
+	// mergedNow отличает merge от идемпотентного повтора, который не меняет PR.
+	mergedNow := false
+
+	pr, err := inTx(ctx, s.tx, func(ctx context.Context) (domain.PullRequest, error) {
+		pr, err := s.pullRequestRepo.GetByID(ctx, id)
+		if err != nil {
+			if errors.Is(err, repository.ErrNotFound) {
+				return domain.PullRequest{}, ErrNotFound
+			}
+
+			return domain.PullRequest{}, fmt.Errorf("get pull request %s: %w", id, err)
 		}
 
-		return domain.PullRequest{}, fmt.Errorf("get pull request %s: %w", id, err)
-	}
+		// Принудительный merge без завершённого ревью доступен только лидам.
+		if err := s.authorizePullRequest(ctx, pr.AuthorID, opts.Force); err != nil {
+			return domain.PullRequest{}, err
+		}
+
+		if err := checkVersion(ctx, pr.Version); err != nil {
+			return domain.PullRequest{}, err
+		}
+
+		if pr.Status == domain.PullRequestStatusMerged {
+			// Идемпотентность: возвращаем текущее состояние без ошибок.
+			return pr, nil
+		}
+
+		now := time.Now().UTC()
+		pr.Status = domain.PullRequestStatusMerged
+		pr.MergedAt = &now
+		pr.MergedBy = auth.Actor(ctx)
+
+		merged := domain.Event{
+			Type:        domain.EventPullRequestMerged,
+			PullRequest: pr,
+			Actor:       pr.MergedBy,
+			OccurredAt:  now,
+		}
+
+		if opts.Force {
+			author, err := s.userRepo.GetByID(ctx, pr.AuthorID)
+			if err != nil {
+				return domain.PullRequest{}, fmt.Errorf("get author %s: %w", pr.AuthorID, err)
+			}
+
+			if merged.Leads, err = s.teamLeadIDs(ctx, author.TeamName); err != nil {
+				return domain.PullRequest{}, err
+			}
+
+			merged.Reason = "forced merge without completed review"
+		}
+
+		if err := s.pullRequestRepo.Update(ctx, pr, merged); err != nil {
+			if errors.Is(err, repository.ErrConflict) {
+				return domain.PullRequest{}, ErrConflict
+			}
+
+			return domain.PullRequest{}, fmt.Errorf("update pull request %s on merge: %w", id, err)
+		}
+
+		pr.Version++
+		mergedNow = true
 
-	if pr.Status == domain.PullRequestStatusMerged {
-		// Идемпотентность: возвращаем текущее состояние без ошибок.
 		return pr, nil
+	})
+	if err != nil {
+		return domain.PullRequest{}, err
 	}
 
-	now := time.Now().UTC()
-	pr.Status = domain.PullRequestStatusMerged
-	pr.MergedAt = &now
-
-	if err := s.pullRequestRepo.Update(ctx, pr); err != nil {
-		return domain.PullRequest{}, fmt.Errorf("update pull request %s on merge: %w", id, err)
+	if mergedNow {
+		s.metrics.PullRequestMerged()
 	}
 
 	return pr, nil
 }
 
-// ReassignReviewer переназначает ревьювера на случайного активного участника из его команды.
+// ReassignReviewer переназначает ревьювера на случайного активного участника из его команд.
 func (s *service) ReassignReviewer(
 	ctx context.Context,
 	prID domain.PullRequestID,
 	reviewerID domain.UserID,
+) (domain.PullRequest, domain.UserID, error) {
+	var (
+		pr         domain.PullRequest
+		replacedBy domain.UserID
+	)
+
+	err := s.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		if _, ok := restrictedCaller(ctx); ok {
+			current, err := s.pullRequestRepo.GetByID(ctx, prID)
+			if err != nil {
+				if errors.Is(err, repository.ErrNotFound) {
+					return ErrNotFound
+				}
+
+				return fmt.Errorf("get pull request %s: %w", prID, err)
+			}
+
+			if err := s.authorizePullRequest(ctx, current.AuthorID, false); err != nil {
+				return err
+			}
+		}
+
+		var err error
+		pr, replacedBy, err = s.reassignReviewer(ctx, prID, reviewerID, "")
+
+		return err
+	})
+	if err != nil {
+		if errors.Is(err, ErrNoCandidate) {
+			s.metrics.NoCandidate()
+		}
+
+		return domain.PullRequest{}, "", err
+	}
+
+	s.metrics.ReviewerReassigned()
+
+	return pr, replacedBy, nil
+}
+
+// reassignReviewer переназначает ревьювера; reason попадает в событие и историю PR.
+func (s *service) reassignReviewer(
+	ctx context.Context,
+	prID domain.PullRequestID,
+	reviewerID domain.UserID,
+	reason string,
 ) (domain.PullRequest, domain.UserID, error) {
 	pr, err := s.pullRequestRepo.GetByID(ctx, prID)
 	if err != nil {
@@ -118,6 +269,10 @@ func (s *service) ReassignReviewer(
 		return domain.PullRequest{}, "", fmt.Errorf("get pull request %s: %w", prID, err)
 	}
 
+	if err := checkVersion(ctx, pr.Version); err != nil {
+		return domain.PullRequest{}, "", err
+	}
+
 	if pr.Status == domain.PullRequestStatusMerged {
 		// После MERGED менять ревьюверов запрещено.
 		return domain.PullRequest{}, "", ErrPullRequestMerged
@@ -145,15 +300,29 @@ func (s *service) ReassignReviewer(
 		return domain.PullRequest{}, "", fmt.Errorf("get reviewer %s: %w", reviewerID, err)
 	}
 
-	// Берём активных участников команды заменяемого ревьювера.
-	activeMembers, err := s.userRepo.ListActiveByTeam(ctx, reviewer.TeamName, nil)
+	// Берём активных участников команд заменяемого ревьювера.
+	activeMembers, err := s.reviewerCandidates(ctx, reviewer, nil)
 	if err != nil {
-		return domain.PullRequest{}, "", fmt.Errorf("list active users for team %s: %w", reviewer.TeamName, err)
+		return domain.PullRequest{}, "", err
 	}
 
 	candidates := s.buildReplacementCandidates(pr, reviewerID, activeMembers)
 	if len(candidates) == 0 {
-		return domain.PullRequest{}, "", ErrNoCandidate
+		// В команде некого назначить — ревью достаётся лиду команды или участнику родительской команды.
+		var fallbackReason string
+		if candidates, fallbackReason, err = s.fallbackCandidates(ctx, reviewer.TeamName, pr, reviewerID); err != nil {
+			return domain.PullRequest{}, "", err
+		}
+
+		if len(candidates) == 0 {
+			return domain.PullRequest{}, "", ErrNoCandidate
+		}
+
+		if reason == "" {
+			reason = fallbackReason
+		} else {
+			reason += ", " + fallbackReason
+		}
 	}
 
 	s.rndMu.Lock()
@@ -165,10 +334,60 @@ func (s *service) ReassignReviewer(
 	newReviewerID := candidates[0]
 
 	pr.AssignedReviewers[reviewerIndex] = newReviewerID
+	pr.ReassignedBy = auth.Actor(ctx)
+
+	reassigned := domain.Event{
+		Type:             domain.EventReviewerReassigned,
+		PullRequest:      pr,
+		AddedReviewers:   []domain.UserID{newReviewerID},
+		RemovedReviewers: []domain.UserID{reviewerID},
+		Reason:           reason,
+		Actor:            pr.ReassignedBy,
+		OccurredAt:       time.Now().UTC(),
+	}
+
+	// PR мог измениться после чтения (параллельный merge или переназначение) — тогда клиент повторяет запрос.
+	if err := s.pullRequestRepo.Update(ctx, pr, reassigned); err != nil {
+		if errors.Is(err, repository.ErrConflict) {
+			return domain.PullRequest{}, "", ErrConflict
+		}
 
-	if err := s.pullRequestRepo.Update(ctx, pr); err != nil {
 		return domain.PullRequest{}, "", fmt.Errorf("update pull request %s on reassign: %w", prID, err)
 	}
 
+	pr.Version++
+
 	return pr, newReviewerID, nil
 }
+
+// GetPullRequest возвращает PR.
+func (s *service) GetPullRequest(ctx context.Context, id domain.PullRequestID) (domain.PullRequest, error) {
+	pr, err := s.pullRequestRepo.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return domain.PullRequest{}, ErrNotFound
+		}
+
+		return domain.PullRequest{}, fmt.Errorf("get pull request %s: %w", id, err)
+	}
+
+	return pr, nil
+}
+
+// GetPullRequestHistory возвращает события PR в порядке их записи.
+func (s *service) GetPullRequestHistory(ctx context.Context, id domain.PullRequestID) ([]domain.Event, error) {
+	if _, err := s.pullRequestRepo.GetByID(ctx, id); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrNotFound
+		}
+
+		return nil, fmt.Errorf("get pull request %s: %w", id, err)
+	}
+
+	history, err := s.pullRequestRepo.ListHistory(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("list history of pull request %s: %w", id, err)
+	}
+
+	return history, nil
+}
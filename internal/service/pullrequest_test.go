@@ -0,0 +1,82 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/dixitix/pr-reviewer-service/internal/domain"
+	"github.com/dixitix/pr-reviewer-service/internal/precondition"
+)
+
+// TestReassignReviewer_Conflict проверяет, что переназначение по устаревшему состоянию PR
+// не затирает параллельное изменение и возвращает ErrConflict.
+func TestReassignReviewer_Conflict(t *testing.T) {
+	svc, prs := newTestService(time.Now())
+	ctx := context.Background()
+
+	pr, _, err := svc.ReassignReviewer(ctx, "pr-stale", "u2")
+	if err != nil {
+		t.Fatalf("ReassignReviewer returned error: %v", err)
+	}
+
+	if pr.Version != 1 || prs.prs["pr-stale"].Version != 1 {
+		t.Fatalf("expected version 1 after reassign, got %d/%d", pr.Version, prs.prs["pr-stale"].Version)
+	}
+
+	// Параллельный запрос прочитал PR до первого изменения.
+	stale := prs.prs["pr-stale"]
+	stale.Version = 0
+	svc.pullRequestRepo = &staleReads{fakePullRequests: prs, pr: stale}
+
+	if _, _, err := svc.ReassignReviewer(ctx, "pr-stale", "u3"); !errors.Is(err, ErrConflict) {
+		t.Fatalf("expected ErrConflict, got %v", err)
+	}
+
+	if _, err := svc.MergePullRequest(ctx, "pr-stale", MergeOptions{}); !errors.Is(err, ErrConflict) {
+		t.Fatalf("expected ErrConflict on merge, got %v", err)
+	}
+
+	if got := prs.prs["pr-stale"]; got.Status != domain.PullRequestStatusOpen || got.Version != 1 {
+		t.Fatalf("stale update must not be applied: %+v", got)
+	}
+}
+
+// staleReads возвращает из GetByID заданное устаревшее состояние PR.
+type staleReads struct {
+	*fakePullRequests
+	pr domain.PullRequest
+}
+
+func (r *staleReads) GetByID(context.Context, domain.PullRequestID) (domain.PullRequest, error) {
+	pr := r.pr
+	pr.AssignedReviewers = append([]domain.UserID(nil), r.pr.AssignedReviewers...)
+
+	return pr, nil
+}
+
+// TestMergePullRequest_Precondition проверяет, что merge с устаревшей версией из If-Match отклоняется.
+func TestMergePullRequest_Precondition(t *testing.T) {
+	svc, prs := newTestService(time.Now())
+
+	stale := precondition.WithVersion(context.Background(), 5)
+	if _, err := svc.MergePullRequest(stale, "pr-fresh", MergeOptions{}); !errors.Is(err, ErrPreconditionFailed) {
+		t.Fatalf("expected ErrPreconditionFailed, got %v", err)
+	}
+
+	if prs.prs["pr-fresh"].Status != domain.PullRequestStatusOpen {
+		t.Fatalf("pull request must stay open")
+	}
+
+	current := precondition.WithVersion(context.Background(), prs.prs["pr-fresh"].Version)
+
+	pr, err := svc.MergePullRequest(current, "pr-fresh", MergeOptions{})
+	if err != nil {
+		t.Fatalf("MergePullRequest returned error: %v", err)
+	}
+
+	if pr.Status != domain.PullRequestStatusMerged || pr.Version != 1 {
+		t.Fatalf("unexpected merged pull request: %+v", pr)
+	}
+}
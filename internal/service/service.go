@@ -3,39 +3,166 @@ package service
 
 import (
 	"context"
+	"time"
 
 	"github.com/dixitix/pr-reviewer-service/internal/domain"
 )
 
 // TeamService описывает операции над командами и их участниками.
+// Изменяющие методы возвращают ErrForbidden, если вызывающий не лид команды и не администратор.
+// SetTeamParent, SetTeamLeads, AddTeamMember и RemoveTeamMember возвращают ErrPreconditionFailed,
+// если в контексте задана версия команды (precondition.WithVersion) и она не совпадает с текущей.
 type TeamService interface {
-	// CreateTeam создаёт новую команду и обновляет/создаёт её участников.
-	CreateTeam(ctx context.Context, name domain.TeamName, members []domain.User) error
+	// CreateTeam создаёт новую команду, обновляет/создаёт её участников и назначает лидов.
+	// Лид должен быть среди members или существующим пользователем, а родитель — существующей командой,
+	// иначе возвращается ErrNotFound.
+	CreateTeam(ctx context.Context, team domain.Team, members []domain.User) error
 
-	// GetTeam возвращает команду и всех её участников.
+	// SetTeamParent меняет родительскую команду (пустое значение — корень) и разрешение искать
+	// ревьюверов вверх по иерархии. Если команда или родитель не найдены, возвращается ErrNotFound,
+	// если родитель — потомок команды, возвращается ErrTeamHierarchyCycle.
+	SetTeamParent(ctx context.Context, name domain.TeamName, parent domain.TeamName, fallbackToParent bool) (domain.Team, error)
+
+	// ListTeamTreeMembers возвращает участников команды и всех её дочерних команд.
+	// Если команда не найдена, возвращается ErrNotFound.
+	ListTeamTreeMembers(ctx context.Context, name domain.TeamName) ([]domain.User, error)
+
+	// SetTeamLeads заменяет список лидов команды и возвращает обновлённую команду.
+	// Если команда или кто-то из лидов не найден, возвращается ErrNotFound.
+	SetTeamLeads(ctx context.Context, name domain.TeamName, leads []domain.UserID) (domain.Team, error)
+
+	// AddTeamMember добавляет пользователя в команду и возвращает все его команды. Если primary,
+	// команда становится основной, а прежняя основная — дополнительной.
+	// Если команда или пользователь не найдены, возвращается ErrNotFound.
+	AddTeamMember(ctx context.Context, name domain.TeamName, userID domain.UserID, primary bool) ([]domain.TeamMembership, error)
+
+	// RemoveTeamMember исключает пользователя из дополнительной команды и возвращает оставшиеся команды.
+	// Если пользователь не состоит в команде, возвращается ErrNotFound, если команда основная — ErrPrimaryTeamMembership.
+	RemoveTeamMember(ctx context.Context, name domain.TeamName, userID domain.UserID) ([]domain.TeamMembership, error)
+
+	// GetTeam возвращает команду и всех её участников, включая дополнительных.
 	GetTeam(ctx context.Context, name domain.TeamName) (domain.Team, []domain.User, error)
+
+	// ListTeams возвращает все команды организации с их родителями, без лидов и участников.
+	ListTeams(ctx context.Context) ([]domain.Team, error)
+
+	// GetReviewPolicy возвращает SLA ревью команды.
+	// Если команда не найдена или SLA не задано, возвращается ErrNotFound.
+	GetReviewPolicy(ctx context.Context, name domain.TeamName) (domain.ReviewPolicy, error)
+
+	// SetReviewPolicy создаёт или обновляет SLA ревью команды. Если команда не найдена, возвращается ErrNotFound.
+	SetReviewPolicy(ctx context.Context, policy domain.ReviewPolicy) error
 }
 
 // UserService описывает операции над пользователями.
+// SetUserActive возвращает ErrForbidden, если вызывающий не сам пользователь, не его лид и не администратор.
 type UserService interface {
+	// GetUser возвращает пользователя по ID. Если пользователь не найден, возвращается ErrNotFound.
+	GetUser(ctx context.Context, userID domain.UserID) (domain.User, error)
+
 	// SetUserActive меняет флаг активности пользователя и возвращает обновлённого пользователя.
 	SetUserActive(ctx context.Context, userID domain.UserID, isActive bool) (domain.User, error)
 
 	// GetUserReviewPullRequests возвращает список PR'ов, где пользователь выступает ревьювером.
 	// Если пользователь не найден, возвращается ErrNotFound.
 	GetUserReviewPullRequests(ctx context.Context, userID domain.UserID) ([]domain.PullRequest, error)
+
+	// GetUserTeams возвращает команды пользователя, основную первой.
+	// Если пользователь не найден, возвращается ErrNotFound.
+	GetUserTeams(ctx context.Context, userID domain.UserID) ([]domain.TeamMembership, error)
 }
 
 // PullRequestService описывает операции над Pull Request'ами.
+// Изменяющие методы возвращают ErrForbidden, если вызывающий не автор PR, не лид команды автора
+// и не администратор; принудительный merge автору недоступен. MergePullRequest и ReassignReviewer
+// возвращают ErrPreconditionFailed, если в контексте задана версия PR и она не совпадает с текущей.
 type PullRequestService interface {
 	// CreatePullRequest создаёт новый PR и назначает ревьюверов согласно правилам.
 	CreatePullRequest(ctx context.Context, id domain.PullRequestID, name string, authorID domain.UserID) (domain.PullRequest, error)
 
 	// MergePullRequest выполняет идемпотентный merge PR.
-	MergePullRequest(ctx context.Context, id domain.PullRequestID) (domain.PullRequest, error)
+	MergePullRequest(ctx context.Context, id domain.PullRequestID, opts MergeOptions) (domain.PullRequest, error)
 
 	// ReassignReviewer переназначает ревьювера и возвращает обновлённый PR и user_id нового ревьювера.
 	ReassignReviewer(ctx context.Context, prID domain.PullRequestID, oldReviewerID domain.UserID) (domain.PullRequest, domain.UserID, error)
+
+	// GetPullRequest возвращает PR. Если PR не найден, возвращается ErrNotFound.
+	GetPullRequest(ctx context.Context, id domain.PullRequestID) (domain.PullRequest, error)
+
+	// GetPullRequestHistory возвращает события PR (назначения, напоминания, эскалации, merge) в порядке их записи.
+	// Если PR не найден, возвращается ErrNotFound.
+	GetPullRequestHistory(ctx context.Context, id domain.PullRequestID) ([]domain.Event, error)
+}
+
+// EventService описывает чтение ленты событий назначений для потоковой выдачи клиентам.
+type EventService interface {
+	// ListEvents возвращает события создания, переназначения и merge PR с номером больше
+	// filter.AfterSequence в порядке их записи, не больше filter.Limit.
+	// Если команда или пользователь из фильтра не найдены, возвращается ErrNotFound.
+	ListEvents(ctx context.Context, filter EventFilter) ([]domain.Event, error)
+
+	// LastEventSequence возвращает номер последнего события организации или 0, если событий нет.
+	// С него начинается лента клиента, который подключается впервые.
+	LastEventSequence(ctx context.Context) (int64, error)
+}
+
+// EventFilter описывает выборку событий ленты.
+type EventFilter struct {
+	// AfterSequence — номер последнего полученного клиентом события; 0 — с начала истории.
+	AfterSequence int64
+	// TeamName — только события, где автор или ревьювер состоит в команде; пустое значение — без фильтра.
+	TeamName domain.TeamName
+	// UserID — только события, где пользователь автор или ревьювер; пустое значение — без фильтра.
+	UserID domain.UserID
+	// Limit — максимальное число событий.
+	Limit int
+}
+
+// MergeOptions описывает параметры merge PR.
+type MergeOptions struct {
+	// Force — merge без завершённого ревью; о нём сообщается лидам команды автора.
+	Force bool
+}
+
+// OrganizationService описывает операции над организациями. Остальные операции сервиса
+// выполняются в организации из контекста (tenant.WithOrganization).
+type OrganizationService interface {
+	// CreateOrganization создаёт организацию. Если она уже есть, возвращается ErrOrganizationExists.
+	CreateOrganization(ctx context.Context, id domain.OrganizationID, name string) (domain.Organization, error)
+
+	// OrganizationExists сообщает, существует ли организация.
+	OrganizationExists(ctx context.Context, id domain.OrganizationID) (bool, error)
+}
+
+// APIKeyService описывает выпуск и проверку API-ключей.
+type APIKeyService interface {
+	// CreateAPIKey выпускает API-ключ организации из контекста и возвращает его вместе со значением ключа.
+	// Значение ключа больше нигде не сохраняется и не может быть получено повторно.
+	CreateAPIKey(ctx context.Context, name string, scopes []domain.APIScope) (domain.APIKey, string, error)
+
+	// ListAPIKeys возвращает API-ключи организации из контекста, включая отозванные.
+	ListAPIKeys(ctx context.Context) ([]domain.APIKey, error)
+
+	// RevokeAPIKey отзывает API-ключ. Если ключ не найден, возвращается ErrNotFound.
+	RevokeAPIKey(ctx context.Context, id domain.APIKeyID) error
+
+	// AuthenticateAPIKey возвращает действующий API-ключ по его значению.
+	// Если ключ неизвестен или отозван, возвращается ErrUnauthenticated.
+	AuthenticateAPIKey(ctx context.Context, secret string) (domain.APIKey, error)
+}
+
+// ReviewSLAService описывает контроль сроков ревью.
+type ReviewSLAService interface {
+	// ProcessStaleReviews напоминает о просроченных ревью и эскалирует их согласно SLA команд
+	// во всех организациях. Для команд без собственного SLA используется defaults.
+	ProcessStaleReviews(ctx context.Context, now time.Time, defaults domain.ReviewPolicy) (SLAReport, error)
+}
+
+// SLAReport описывает результат одного прохода контроля сроков ревью.
+type SLAReport struct {
+	Reminded   int
+	Reassigned int
+	Escalated  int
 }
 
 // StatsService описывает операции получения статистики назначений.
@@ -45,6 +172,65 @@ type StatsService interface {
 
 	// GetAssignmentsByPullRequest возвращает количество назначений по каждому Pull Request.
 	GetAssignmentsByPullRequest(ctx context.Context) (map[domain.PullRequestID]int, error)
+
+	// GetAssignmentsByTeam возвращает количество назначений по каждой команде: собственных
+	// и вместе со всеми дочерними командами.
+	GetAssignmentsByTeam(ctx context.Context) ([]domain.TeamAssignmentStat, error)
+
+	// CountOpenReviewsByTeam возвращает количество назначений на открытые PR по основной команде
+	// ревьювера во всех организациях. Используется для метрик и не зависит от организации из контекста.
+	CountOpenReviewsByTeam(ctx context.Context) (map[domain.OrganizationID]map[domain.TeamName]int, error)
+}
+
+// WebhookService описывает управление подписками на исходящие вебхуки.
+type WebhookService interface {
+	// CreateWebhookSubscription регистрирует подписчика. Пустой список событий означает подписку на все события,
+	// пустой секрет заменяется сгенерированным.
+	CreateWebhookSubscription(ctx context.Context, url string, events []domain.EventType, secret string) (domain.WebhookSubscription, error)
+
+	// ListWebhookSubscriptions возвращает все подписки.
+	ListWebhookSubscriptions(ctx context.Context) ([]domain.WebhookSubscription, error)
+
+	// DeleteWebhookSubscription удаляет подписку. Если подписка не найдена, возвращается ErrNotFound.
+	DeleteWebhookSubscription(ctx context.Context, id domain.WebhookSubscriptionID) error
+
+	// ListFailedWebhookDeliveries возвращает недоставленные вебхуки, начиная с самых новых.
+	ListFailedWebhookDeliveries(ctx context.Context, subscriptionID *domain.WebhookSubscriptionID, limit int) ([]domain.WebhookDelivery, error)
+}
+
+// NotificationService описывает настройки уведомлений пользователей и каналы чата команд.
+type NotificationService interface {
+	// GetNotificationPreferences возвращает настройки уведомлений пользователя.
+	// Если пользователь не найден, возвращается ErrNotFound.
+	GetNotificationPreferences(ctx context.Context, userID domain.UserID) (domain.NotificationPreferences, error)
+
+	// UpdateNotificationPreferences меняет переданные поля настроек уведомлений пользователя
+	// и возвращает итоговые настройки. Если пользователь не найден, возвращается ErrNotFound,
+	// если почтовые уведомления включены без адреса — ErrEmailRequired.
+	UpdateNotificationPreferences(
+		ctx context.Context,
+		userID domain.UserID,
+		update NotificationPreferencesUpdate,
+	) (domain.NotificationPreferences, error)
+
+	// GetTeamChatChannel возвращает канал чата команды.
+	// Если команда не найдена или канал не настроен, возвращается ErrNotFound.
+	GetTeamChatChannel(ctx context.Context, teamName domain.TeamName) (domain.TeamChatChannel, error)
+
+	// SetTeamChatChannel создаёт или обновляет канал чата команды.
+	// Если команда не найдена, возвращается ErrNotFound.
+	SetTeamChatChannel(ctx context.Context, channel domain.TeamChatChannel) error
+
+	// DeleteTeamChatChannel удаляет канал чата команды. Если канал не настроен, возвращается ErrNotFound.
+	DeleteTeamChatChannel(ctx context.Context, teamName domain.TeamName) error
+}
+
+// NotificationPreferencesUpdate описывает частичное изменение настроек уведомлений.
+// Поля со значением nil не меняются.
+type NotificationPreferencesUpdate struct {
+	ChatEnabled *bool
+	Email       *string
+	EmailMode   *domain.EmailMode
 }
 
 // UserAssignmentStat описывает количество назначений по пользователям.
@@ -61,8 +247,14 @@ type PullRequestAssignmentStat struct {
 
 // Service агрегирует все доменные сервисы.
 type Service interface {
+	OrganizationService
+	APIKeyService
 	TeamService
 	UserService
 	PullRequestService
+	EventService
 	StatsService
+	WebhookService
+	NotificationService
+	ReviewSLAService
 }
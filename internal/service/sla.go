@@ -0,0 +1,226 @@
+// Package service содержит реализацию бизнес-логики сервиса назначения ревьюеров.
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/dixitix/pr-reviewer-service/internal/auth"
+	"github.com/dixitix/pr-reviewer-service/internal/domain"
+	"github.com/dixitix/pr-reviewer-service/internal/tenant"
+)
+
+// ProcessStaleReviews напоминает о просроченных ревью и эскалирует их согласно SLA команд
+// по очереди во всех организациях.
+func (s *service) ProcessStaleReviews(
+	ctx context.Context,
+	now time.Time,
+	defaults domain.ReviewPolicy,
+) (SLAReport, error) {
+	orgs, err := s.orgRepo.List(ctx)
+	if err != nil {
+		return SLAReport{}, fmt.Errorf("list organizations: %w", err)
+	}
+
+	var (
+		report SLAReport
+		errs   error
+	)
+
+	// Напоминания, переназначения и эскалации записываются как действия сервиса.
+	ctx = auth.WithIdentity(ctx, auth.Identity{System: true})
+
+	for _, org := range orgs {
+		r, err := s.processOrganizationReviews(tenant.WithOrganization(ctx, org.ID), now, defaults)
+		if err != nil {
+			errs = errors.Join(errs, fmt.Errorf("organization %s: %w", org.ID, err))
+		}
+
+		report.Reminded += r.Reminded
+		report.Reassigned += r.Reassigned
+		report.Escalated += r.Escalated
+	}
+
+	return report, errs
+}
+
+// processOrganizationReviews применяет SLA к ревью организации из контекста.
+func (s *service) processOrganizationReviews(
+	ctx context.Context,
+	now time.Time,
+	defaults domain.ReviewPolicy,
+) (SLAReport, error) {
+	policies, err := s.teamRepo.ListReviewPolicies(ctx)
+	if err != nil {
+		return SLAReport{}, fmt.Errorf("list review policies: %w", err)
+	}
+
+	byTeam := make(map[domain.TeamName]domain.ReviewPolicy, len(policies))
+
+	// Самый короткий порог определяет, какие назначения вообще нужно рассматривать.
+	minThreshold := minPositive(defaults.ReminderAfter, defaults.EscalateAfter)
+	for _, p := range policies {
+		byTeam[p.TeamName] = p
+		minThreshold = minPositive(minThreshold, p.ReminderAfter, p.EscalateAfter)
+	}
+
+	if minThreshold == 0 {
+		return SLAReport{}, nil
+	}
+
+	assignments, err := s.pullRequestRepo.ListOpenAssignments(ctx, now.Add(-minThreshold))
+	if err != nil {
+		return SLAReport{}, fmt.Errorf("list open assignments: %w", err)
+	}
+
+	var (
+		report SLAReport
+		errs   error
+	)
+
+	for _, a := range assignments {
+		policy, ok := byTeam[a.TeamName]
+		if !ok {
+			policy = defaults
+		}
+
+		// Переназначение или эскалация и их события фиксируются вместе.
+		err := s.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+			return s.processAssignment(ctx, now, policy, a, &report)
+		})
+		if err != nil {
+			errs = errors.Join(errs, fmt.Errorf("pull request %s, reviewer %s: %w", a.PullRequest.ID, a.ReviewerID, err))
+		}
+	}
+
+	return report, errs
+}
+
+// processAssignment применяет SLA к одному назначению.
+func (s *service) processAssignment(
+	ctx context.Context,
+	now time.Time,
+	policy domain.ReviewPolicy,
+	a domain.ReviewAssignment,
+	report *SLAReport,
+) error {
+	waiting := now.Sub(a.AssignedAt)
+
+	if policy.EscalateAfter > 0 && waiting >= policy.EscalateAfter &&
+		a.EscalatedAt == nil && policy.Action != domain.EscalationActionNone {
+		reason := fmt.Sprintf("review pending for %s, team SLA is %s", waiting.Round(time.Minute), policy.EscalateAfter)
+
+		if policy.Action == domain.EscalationActionReassign {
+			_, _, err := s.reassignReviewer(ctx, a.PullRequest.ID, a.ReviewerID, reason)
+
+			switch {
+			case err == nil:
+				report.Reassigned++
+				s.metrics.ReviewerReassigned()
+
+				return nil
+			case errors.Is(err, ErrPullRequestMerged), errors.Is(err, ErrReviewerNotAssigned):
+				// PR смёржен или ревьювер заменён после выборки назначений.
+				return nil
+			case !errors.Is(err, ErrNoCandidate):
+				return fmt.Errorf("reassign stale reviewer: %w", err)
+			}
+
+			// Заменить некем, даже лидом — сообщаем команде.
+			s.metrics.NoCandidate()
+
+			reason += ", no candidate for reassignment"
+		}
+
+		if err := s.escalate(ctx, now, a, reason); err != nil {
+			return err
+		}
+
+		report.Escalated++
+
+		return nil
+	}
+
+	if policy.ReminderAfter <= 0 {
+		return nil
+	}
+
+	last := a.AssignedAt
+	if a.RemindedAt != nil {
+		last = *a.RemindedAt
+	}
+
+	if now.Sub(last) < policy.ReminderAfter {
+		return nil
+	}
+
+	pr, err := s.pullRequestRepo.GetByID(ctx, a.PullRequest.ID)
+	if err != nil {
+		return fmt.Errorf("get pull request: %w", err)
+	}
+
+	assignedAt := a.AssignedAt
+	reminder := domain.Event{
+		Type:         domain.EventReviewReminder,
+		PullRequest:  pr,
+		Reviewer:     a.ReviewerID,
+		WaitingSince: &assignedAt,
+		Reason:       fmt.Sprintf("review pending for %s", waiting.Round(time.Minute)),
+		Actor:        auth.Actor(ctx),
+		OccurredAt:   now,
+	}
+
+	if err := s.pullRequestRepo.MarkReminded(ctx, a.PullRequest.ID, a.ReviewerID, now, reminder); err != nil {
+		return fmt.Errorf("mark reminded: %w", err)
+	}
+
+	report.Reminded++
+
+	return nil
+}
+
+// escalate отмечает ревью эскалированным и сообщает о нём лидам команды событием review.escalated.
+func (s *service) escalate(ctx context.Context, now time.Time, a domain.ReviewAssignment, reason string) error {
+	pr, err := s.pullRequestRepo.GetByID(ctx, a.PullRequest.ID)
+	if err != nil {
+		return fmt.Errorf("get pull request: %w", err)
+	}
+
+	leads, err := s.teamLeadIDs(ctx, a.TeamName)
+	if err != nil {
+		return err
+	}
+
+	assignedAt := a.AssignedAt
+	escalated := domain.Event{
+		Type:         domain.EventReviewEscalated,
+		PullRequest:  pr,
+		Reviewer:     a.ReviewerID,
+		WaitingSince: &assignedAt,
+		Reason:       reason,
+		Leads:        leads,
+		Actor:        auth.Actor(ctx),
+		OccurredAt:   now,
+	}
+
+	if err := s.pullRequestRepo.MarkEscalated(ctx, a.PullRequest.ID, a.ReviewerID, now, escalated); err != nil {
+		return fmt.Errorf("mark escalated: %w", err)
+	}
+
+	return nil
+}
+
+// minPositive возвращает наименьшую положительную длительность или 0, если таких нет.
+func minPositive(durations ...time.Duration) time.Duration {
+	var result time.Duration
+
+	for _, d := range durations {
+		if d > 0 && (result == 0 || d < result) {
+			result = d
+		}
+	}
+
+	return result
+}
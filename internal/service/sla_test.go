@@ -0,0 +1,80 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/dixitix/pr-reviewer-service/internal/domain"
+)
+
+// TestProcessStaleReviews проверяет напоминания, переназначение по SLA и эскалацию при отсутствии кандидатов.
+func TestProcessStaleReviews(t *testing.T) {
+	now := time.Date(2025, 3, 10, 12, 0, 0, 0, time.UTC)
+	svc, prs := newTestService(now)
+
+	defaults := domain.ReviewPolicy{
+		ReminderAfter: 24 * time.Hour,
+		EscalateAfter: 72 * time.Hour,
+		Action:        domain.EscalationActionReassign,
+	}
+
+	report, err := svc.ProcessStaleReviews(context.Background(), now, defaults)
+	if err != nil {
+		t.Fatalf("ProcessStaleReviews returned error: %v", err)
+	}
+
+	want := SLAReport{Reminded: 1, Reassigned: 1, Escalated: 1}
+	if report != want {
+		t.Fatalf("unexpected report: got %+v, want %+v", report, want)
+	}
+
+	byType := make(map[domain.EventType][]domain.Event)
+	for _, e := range prs.events {
+		byType[e.Type] = append(byType[e.Type], e)
+	}
+
+	if r := byType[domain.EventReviewReminder]; len(r) != 1 || r[0].Reviewer != "u3" || r[0].PullRequest.ID != "pr-stale" {
+		t.Fatalf("expected reminder for u3 on pr-stale, got %+v", r)
+	}
+
+	reassigned := byType[domain.EventReviewerReassigned]
+	if len(reassigned) != 1 || reassigned[0].Reason == "" {
+		t.Fatalf("expected 1 reassignment with reason, got %+v", reassigned)
+	}
+
+	if got := reassigned[0].AddedReviewers; len(got) != 1 || got[0] != "u4" {
+		t.Fatalf("expected u2 replaced by u4, got %v", got)
+	}
+
+	escalated := byType[domain.EventReviewEscalated]
+	if len(escalated) != 1 || escalated[0].Reviewer != "u6" || escalated[0].WaitingSince == nil {
+		t.Fatalf("expected escalation for u6 on pr-solo, got %+v", escalated)
+	}
+
+	for _, e := range prs.events {
+		if e.Actor != domain.ActorSystem {
+			t.Fatalf("expected SLA event %s attributed to system, got %q", e.Type, e.Actor)
+		}
+	}
+
+	if pr := prs.prs["pr-stale"]; pr.ReassignedBy != domain.ActorSystem {
+		t.Fatalf("expected reassignment by system, got %q", pr.ReassignedBy)
+	}
+}
+
+// TestProcessStaleReviews_Disabled проверяет, что SLA с нулевыми порогами ничего не делает.
+func TestProcessStaleReviews_Disabled(t *testing.T) {
+	now := time.Date(2025, 3, 10, 12, 0, 0, 0, time.UTC)
+	svc, prs := newTestService(now)
+	svc.teamRepo = &fakeTeams{}
+
+	report, err := svc.ProcessStaleReviews(context.Background(), now, domain.ReviewPolicy{})
+	if err != nil {
+		t.Fatalf("ProcessStaleReviews returned error: %v", err)
+	}
+
+	if report != (SLAReport{}) || len(prs.events) != 0 {
+		t.Fatalf("expected no actions, got report %+v and events %+v", report, prs.events)
+	}
+}
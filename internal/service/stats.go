@@ -6,6 +6,7 @@ import (
 	"fmt"
 
 	"github.com/dixitix/pr-reviewer-service/internal/domain"
+	"github.com/dixitix/pr-reviewer-service/internal/tenant"
 )
 
 // GetAssignmentsByUser возвращает количество назначений по каждому пользователю.
@@ -39,3 +40,75 @@ func (s *service) GetAssignmentsByPullRequest(
 
 	return stats, nil
 }
+
+// GetAssignmentsByTeam возвращает количество назначений по командам с суммами по иерархии.
+func (s *service) GetAssignmentsByTeam(ctx context.Context) ([]domain.TeamAssignmentStat, error) {
+	teams, err := s.teamRepo.ListTeams(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("list teams: %w", err)
+	}
+
+	counts, err := s.pullRequestRepo.CountAssignmentsByTeam(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("count assignments by team: %w", err)
+	}
+
+	parents := make(map[domain.TeamName]domain.TeamName, len(teams))
+	for _, t := range teams {
+		parents[t.Name] = t.Parent
+	}
+
+	// Назначения каждой команды прибавляются ко всем её предкам.
+	totals := make(map[domain.TeamName]int, len(teams))
+	for _, t := range teams {
+		own := counts[t.Name]
+		if own == 0 {
+			continue
+		}
+
+		visited := make(map[domain.TeamName]struct{})
+		for name := t.Name; name != ""; name = parents[name] {
+			if _, seen := visited[name]; seen {
+				break
+			}
+
+			visited[name] = struct{}{}
+			totals[name] += own
+		}
+	}
+
+	result := make([]domain.TeamAssignmentStat, len(teams))
+	for i, t := range teams {
+		result[i] = domain.TeamAssignmentStat{
+			TeamName:         t.Name,
+			Parent:           t.Parent,
+			Assignments:      counts[t.Name],
+			TotalAssignments: totals[t.Name],
+		}
+	}
+
+	return result, nil
+}
+
+// CountOpenReviewsByTeam возвращает количество назначений на открытые PR по командам во всех организациях.
+func (s *service) CountOpenReviewsByTeam(
+	ctx context.Context,
+) (map[domain.OrganizationID]map[domain.TeamName]int, error) {
+	orgs, err := s.orgRepo.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("list organizations: %w", err)
+	}
+
+	result := make(map[domain.OrganizationID]map[domain.TeamName]int, len(orgs))
+
+	for _, org := range orgs {
+		counts, err := s.pullRequestRepo.CountOpenReviewsByTeam(tenant.WithOrganization(ctx, org.ID))
+		if err != nil {
+			return nil, fmt.Errorf("count open reviews in organization %s: %w", org.ID, err)
+		}
+
+		result[org.ID] = counts
+	}
+
+	return result, nil
+}
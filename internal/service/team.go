@@ -10,35 +10,342 @@ import (
 	"github.com/dixitix/pr-reviewer-service/internal/repository"
 )
 
-// CreateTeam создаёт новую команду и при необходимости добавляет/обновляет её участников.
+// CreateTeam создаёт новую команду и при необходимости добавляет/обновляет её участников и лидов.
 func (s *service) CreateTeam(
 	ctx context.Context,
-	name domain.TeamName,
+	team domain.Team,
 	members []domain.User,
 ) error {
-	team := domain.Team{
-		Name: name,
-	}
+	return s.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		name := team.Name
+
+		if err := s.authorizeNewTeam(ctx, team.Parent); err != nil {
+			return err
+		}
+
+		// UpsertMembers перезаписывает существующих пользователей и переносит их в новую команду.
+		for _, m := range members {
+			if err := s.authorizeMemberMove(ctx, m.ID); err != nil {
+				return err
+			}
+		}
+
+		if team.Parent != "" {
+			exists, err := s.teamRepo.TeamExists(ctx, team.Parent)
+			if err != nil {
+				return fmt.Errorf("check team %s exists: %w", team.Parent, err)
+			}
+
+			if !exists {
+				return ErrNotFound
+			}
+		}
+
+		// Лид должен быть участником новой команды или уже существующим пользователем.
+		newMembers := make(map[domain.UserID]struct{}, len(members))
+		for _, m := range members {
+			newMembers[m.ID] = struct{}{}
+		}
+
+		for _, id := range team.Leads {
+			if _, ok := newMembers[id]; ok {
+				continue
+			}
+
+			if err := s.checkUserExists(ctx, id); err != nil {
+				return err
+			}
+		}
+
+		if err := s.teamRepo.CreateTeam(ctx, team); err != nil {
+			if errors.Is(err, repository.ErrAlreadyExists) {
+				return ErrTeamAlreadyExists
+			}
+
+			return fmt.Errorf("create team %s: %w", name, err)
+		}
+
+		if len(members) > 0 {
+			if err := s.teamRepo.UpsertMembers(ctx, name, members); err != nil {
+				return fmt.Errorf("upsert members for team %s: %w", name, err)
+			}
+		}
+
+		if len(team.Leads) > 0 {
+			if err := s.teamRepo.SetLeads(ctx, name, team.Leads); err != nil {
+				return fmt.Errorf("set leads for team %s: %w", name, err)
+			}
+		}
+
+		memberIDs := make([]domain.UserID, len(members))
+		for i, m := range members {
+			memberIDs[i] = m.ID
+		}
+
+		return s.audit(ctx, domain.AuditTeamCreated, string(name), map[string]any{
+			"parent":  team.Parent,
+			"leads":   team.Leads,
+			"members": memberIDs,
+		})
+	})
+}
+
+// SetTeamLeads заменяет список лидов команды и возвращает обновлённую команду.
+func (s *service) SetTeamLeads(
+	ctx context.Context,
+	name domain.TeamName,
+	leads []domain.UserID,
+) (domain.Team, error) {
+	return inTx(ctx, s.tx, func(ctx context.Context) (domain.Team, error) {
+		if err := s.authorizeTeam(ctx, name); err != nil {
+			return domain.Team{}, err
+		}
+
+		if err := s.checkTeamVersion(ctx, name); err != nil {
+			return domain.Team{}, err
+		}
+
+		exists, err := s.teamRepo.TeamExists(ctx, name)
+		if err != nil {
+			return domain.Team{}, fmt.Errorf("check team %s exists: %w", name, err)
+		}
+
+		if !exists {
+			return domain.Team{}, ErrNotFound
+		}
 
-	if err := s.teamRepo.CreateTeam(ctx, team); err != nil {
-		if errors.Is(err, repository.ErrAlreadyExists) {
-			return ErrTeamAlreadyExists
+		for _, id := range leads {
+			if err := s.checkUserExists(ctx, id); err != nil {
+				return domain.Team{}, err
+			}
 		}
 
-		return fmt.Errorf("create team %s: %w", name, err)
+		if err := s.teamRepo.SetLeads(ctx, name, leads); err != nil {
+			return domain.Team{}, fmt.Errorf("set leads for team %s: %w", name, err)
+		}
+
+		if err := s.audit(ctx, domain.AuditTeamLeadsSet, string(name), map[string]any{"leads": leads}); err != nil {
+			return domain.Team{}, err
+		}
+
+		team, _, err := s.teamRepo.GetTeamWithMembers(ctx, name)
+		if err != nil {
+			return domain.Team{}, fmt.Errorf("get team %s: %w", name, err)
+		}
+
+		return team, nil
+	})
+}
+
+// SetTeamParent меняет родительскую команду и разрешение искать ревьюверов вверх по иерархии.
+func (s *service) SetTeamParent(
+	ctx context.Context,
+	name domain.TeamName,
+	parent domain.TeamName,
+	fallbackToParent bool,
+) (domain.Team, error) {
+	return inTx(ctx, s.tx, func(ctx context.Context) (domain.Team, error) {
+		// Переносить команду можно только в команду, которой вызывающий тоже управляет.
+		if err := s.authorizeTeam(ctx, name); err != nil {
+			return domain.Team{}, err
+		}
+
+		if err := s.authorizeNewTeam(ctx, parent); err != nil {
+			return domain.Team{}, err
+		}
+
+		if err := s.checkTeamVersion(ctx, name); err != nil {
+			return domain.Team{}, err
+		}
+
+		if parent != "" {
+			// Команда не может стать потомком самой себя.
+			chain, err := s.teamRepo.ListTeamChain(ctx, parent)
+			if err != nil {
+				if errors.Is(err, repository.ErrNotFound) {
+					return domain.Team{}, ErrNotFound
+				}
+
+				return domain.Team{}, fmt.Errorf("list chain of team %s: %w", parent, err)
+			}
+
+			for _, ancestor := range chain {
+				if ancestor.Name == name {
+					return domain.Team{}, ErrTeamHierarchyCycle
+				}
+			}
+		}
+
+		if err := s.teamRepo.SetParent(ctx, name, parent, fallbackToParent); err != nil {
+			if errors.Is(err, repository.ErrNotFound) {
+				return domain.Team{}, ErrNotFound
+			}
+
+			return domain.Team{}, fmt.Errorf("set parent of team %s: %w", name, err)
+		}
+
+		err := s.audit(ctx, domain.AuditTeamParentSet, string(name), map[string]any{
+			"parent":             parent,
+			"fallback_to_parent": fallbackToParent,
+		})
+		if err != nil {
+			return domain.Team{}, err
+		}
+
+		team, _, err := s.teamRepo.GetTeamWithMembers(ctx, name)
+		if err != nil {
+			return domain.Team{}, fmt.Errorf("get team %s: %w", name, err)
+		}
+
+		return team, nil
+	})
+}
+
+// ListTeamTreeMembers возвращает участников команды и всех её дочерних команд.
+func (s *service) ListTeamTreeMembers(ctx context.Context, name domain.TeamName) ([]domain.User, error) {
+	exists, err := s.teamRepo.TeamExists(ctx, name)
+	if err != nil {
+		return nil, fmt.Errorf("check team %s exists: %w", name, err)
+	}
+
+	if !exists {
+		return nil, ErrNotFound
 	}
 
-	if len(members) == 0 {
-		return nil
+	members, err := s.teamRepo.ListSubtreeMembers(ctx, name)
+	if err != nil {
+		return nil, fmt.Errorf("list subtree members of team %s: %w", name, err)
 	}
 
-	if err := s.teamRepo.UpsertMembers(ctx, name, members); err != nil {
-		return fmt.Errorf("upsert members for team %s: %w", name, err)
+	return members, nil
+}
+
+// AddTeamMember добавляет пользователя в команду и возвращает все его команды.
+func (s *service) AddTeamMember(
+	ctx context.Context,
+	name domain.TeamName,
+	userID domain.UserID,
+	primary bool,
+) ([]domain.TeamMembership, error) {
+	return inTx(ctx, s.tx, func(ctx context.Context) ([]domain.TeamMembership, error) {
+		if err := s.authorizeTeam(ctx, name); err != nil {
+			return nil, err
+		}
+
+		// Новая основная команда заменяет прежнюю, поэтому нужны права и на прежнюю.
+		if primary {
+			if err := s.authorizeMemberMove(ctx, userID); err != nil {
+				return nil, err
+			}
+		}
+
+		if err := s.checkTeamVersion(ctx, name); err != nil {
+			return nil, err
+		}
+
+		exists, err := s.teamRepo.TeamExists(ctx, name)
+		if err != nil {
+			return nil, fmt.Errorf("check team %s exists: %w", name, err)
+		}
+
+		if !exists {
+			return nil, ErrNotFound
+		}
+
+		if err := s.checkUserExists(ctx, userID); err != nil {
+			return nil, err
+		}
+
+		if err := s.teamRepo.AddMember(ctx, name, userID, primary); err != nil {
+			return nil, fmt.Errorf("add member %s to team %s: %w", userID, name, err)
+		}
+
+		err = s.audit(ctx, domain.AuditTeamMemberAdded, string(name), map[string]any{
+			"user_id":    userID,
+			"is_primary": primary,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		return s.GetUserTeams(ctx, userID)
+	})
+}
+
+// RemoveTeamMember исключает пользователя из дополнительной команды и возвращает оставшиеся команды.
+func (s *service) RemoveTeamMember(
+	ctx context.Context,
+	name domain.TeamName,
+	userID domain.UserID,
+) ([]domain.TeamMembership, error) {
+	return inTx(ctx, s.tx, func(ctx context.Context) ([]domain.TeamMembership, error) {
+		// Покинуть дополнительную команду пользователь может и сам.
+		if caller, ok := restrictedCaller(ctx); ok && caller != userID {
+			if err := s.authorizeTeam(ctx, name); err != nil {
+				return nil, err
+			}
+		}
+
+		if err := s.checkTeamVersion(ctx, name); err != nil {
+			return nil, err
+		}
+
+		memberships, err := s.GetUserTeams(ctx, userID)
+		if err != nil {
+			return nil, err
+		}
+
+		// Основную команду можно только сменить через AddTeamMember или /team/add.
+		for _, m := range memberships {
+			if m.TeamName == name && m.IsPrimary {
+				return nil, ErrPrimaryTeamMembership
+			}
+		}
+
+		if err := s.teamRepo.RemoveMember(ctx, name, userID); err != nil {
+			if errors.Is(err, repository.ErrNotFound) {
+				return nil, ErrNotFound
+			}
+
+			return nil, fmt.Errorf("remove member %s from team %s: %w", userID, name, err)
+		}
+
+		if err := s.audit(ctx, domain.AuditTeamMemberRemoved, string(name), map[string]any{"user_id": userID}); err != nil {
+			return nil, err
+		}
+
+		return s.GetUserTeams(ctx, userID)
+	})
+}
+
+// checkUserExists возвращает ErrNotFound, если пользователя нет.
+func (s *service) checkUserExists(ctx context.Context, id domain.UserID) error {
+	if _, err := s.userRepo.GetByID(ctx, id); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return ErrNotFound
+		}
+
+		return fmt.Errorf("get user %s: %w", id, err)
 	}
 
 	return nil
 }
 
+// teamLeadIDs возвращает ID лидов команды.
+func (s *service) teamLeadIDs(ctx context.Context, name domain.TeamName) ([]domain.UserID, error) {
+	leads, err := s.teamRepo.ListLeads(ctx, name)
+	if err != nil {
+		return nil, fmt.Errorf("list leads of team %s: %w", name, err)
+	}
+
+	ids := make([]domain.UserID, len(leads))
+	for i, lead := range leads {
+		ids[i] = lead.ID
+	}
+
+	return ids, nil
+}
+
 // GetTeam возвращает команду и всех её участников.
 func (s *service) GetTeam(
 	ctx context.Context,
@@ -55,3 +362,55 @@ func (s *service) GetTeam(
 
 	return team, members, nil
 }
+
+// ListTeams возвращает все команды организации в порядке имени.
+func (s *service) ListTeams(ctx context.Context) ([]domain.Team, error) {
+	teams, err := s.teamRepo.ListTeams(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("list teams: %w", err)
+	}
+
+	return teams, nil
+}
+
+// GetReviewPolicy возвращает SLA ревью команды.
+func (s *service) GetReviewPolicy(ctx context.Context, name domain.TeamName) (domain.ReviewPolicy, error) {
+	policy, err := s.teamRepo.GetReviewPolicy(ctx, name)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return domain.ReviewPolicy{}, ErrNotFound
+		}
+
+		return domain.ReviewPolicy{}, fmt.Errorf("get review policy of team %s: %w", name, err)
+	}
+
+	return policy, nil
+}
+
+// SetReviewPolicy создаёт или обновляет SLA ревью команды.
+func (s *service) SetReviewPolicy(ctx context.Context, policy domain.ReviewPolicy) error {
+	return s.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.authorizeTeam(ctx, policy.TeamName); err != nil {
+			return err
+		}
+
+		exists, err := s.teamRepo.TeamExists(ctx, policy.TeamName)
+		if err != nil {
+			return fmt.Errorf("check team %s exists: %w", policy.TeamName, err)
+		}
+
+		if !exists {
+			return ErrNotFound
+		}
+
+		if err := s.teamRepo.UpsertReviewPolicy(ctx, policy); err != nil {
+			return fmt.Errorf("upsert review policy of team %s: %w", policy.TeamName, err)
+		}
+
+		return s.audit(ctx, domain.AuditReviewPolicySet, string(policy.TeamName), map[string]any{
+			"reminder_after_minutes": int(policy.ReminderAfter.Minutes()),
+			"escalate_after_minutes": int(policy.EscalateAfter.Minutes()),
+			"escalation_action":      policy.Action,
+		})
+	})
+}
@@ -10,12 +10,8 @@ import (
 	"github.com/dixitix/pr-reviewer-service/internal/repository"
 )
 
-// SetUserActive меняет флаг активности пользователя и возвращает обновлённого пользователя.
-func (s *service) SetUserActive(
-	ctx context.Context,
-	userID domain.UserID,
-	isActive bool,
-) (domain.User, error) {
+// GetUser возвращает пользователя по ID.
+func (s *service) GetUser(ctx context.Context, userID domain.UserID) (domain.User, error) {
 	user, err := s.userRepo.GetByID(ctx, userID)
 	if err != nil {
 		if errors.Is(err, repository.ErrNotFound) {
@@ -25,18 +21,46 @@ func (s *service) SetUserActive(
 		return domain.User{}, fmt.Errorf("get user by id %s: %w", userID, err)
 	}
 
-	// Если флаг активности уже такой — операция идемпотентна.
-	if user.IsActive == isActive {
-		return user, nil
-	}
+	return user, nil
+}
 
-	if err := s.userRepo.SetActive(ctx, userID, isActive); err != nil {
-		return domain.User{}, fmt.Errorf("set user %s active=%t: %w", userID, isActive, err)
-	}
+// SetUserActive меняет флаг активности пользователя и возвращает обновлённого пользователя.
+func (s *service) SetUserActive(
+	ctx context.Context,
+	userID domain.UserID,
+	isActive bool,
+) (domain.User, error) {
+	return inTx(ctx, s.tx, func(ctx context.Context) (domain.User, error) {
+		user, err := s.userRepo.GetByID(ctx, userID)
+		if err != nil {
+			if errors.Is(err, repository.ErrNotFound) {
+				return domain.User{}, ErrNotFound
+			}
+
+			return domain.User{}, fmt.Errorf("get user by id %s: %w", userID, err)
+		}
 
-	user.IsActive = isActive
+		if err := s.authorizeUser(ctx, user); err != nil {
+			return domain.User{}, err
+		}
 
-	return user, nil
+		// Если флаг активности уже такой — операция идемпотентна.
+		if user.IsActive == isActive {
+			return user, nil
+		}
+
+		if err := s.userRepo.SetActive(ctx, userID, isActive); err != nil {
+			return domain.User{}, fmt.Errorf("set user %s active=%t: %w", userID, isActive, err)
+		}
+
+		if err := s.audit(ctx, domain.AuditUserActiveSet, string(userID), map[string]any{"is_active": isActive}); err != nil {
+			return domain.User{}, err
+		}
+
+		user.IsActive = isActive
+
+		return user, nil
+	})
 }
 
 // GetUserReviewPullRequests возвращает список PR'ов, где пользователь выступает ревьювером.
@@ -65,3 +89,17 @@ func (s *service) GetUserReviewPullRequests(
 
 	return prs, nil
 }
+
+// GetUserTeams возвращает команды пользователя, основную первой.
+func (s *service) GetUserTeams(ctx context.Context, userID domain.UserID) ([]domain.TeamMembership, error) {
+	if err := s.checkUserExists(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	memberships, err := s.userRepo.ListMemberships(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("list memberships of user %s: %w", userID, err)
+	}
+
+	return memberships, nil
+}
@@ -0,0 +1,96 @@
+// Package service содержит реализацию бизнес-логики сервиса назначения ревьюеров.
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/dixitix/pr-reviewer-service/internal/domain"
+	"github.com/dixitix/pr-reviewer-service/internal/idgen"
+	"github.com/dixitix/pr-reviewer-service/internal/repository"
+)
+
+// CreateWebhookSubscription регистрирует подписчика вебхуков.
+func (s *service) CreateWebhookSubscription(
+	ctx context.Context,
+	url string,
+	events []domain.EventType,
+	secret string,
+) (domain.WebhookSubscription, error) {
+	if err := authorizeAdmin(ctx); err != nil {
+		return domain.WebhookSubscription{}, err
+	}
+
+	if secret == "" {
+		secret = idgen.New()
+	}
+
+	sub := domain.WebhookSubscription{
+		ID:        domain.WebhookSubscriptionID(idgen.New()),
+		URL:       url,
+		Events:    events,
+		Secret:    secret,
+		CreatedAt: time.Now().UTC(),
+	}
+
+	err := s.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.webhookRepo.CreateSubscription(ctx, sub); err != nil {
+			return fmt.Errorf("create webhook subscription: %w", err)
+		}
+
+		return s.audit(ctx, domain.AuditWebhookCreated, string(sub.ID), map[string]any{
+			"url":    sub.URL,
+			"events": sub.Events,
+		})
+	})
+	if err != nil {
+		return domain.WebhookSubscription{}, err
+	}
+
+	return sub, nil
+}
+
+// ListWebhookSubscriptions возвращает все подписки.
+func (s *service) ListWebhookSubscriptions(ctx context.Context) ([]domain.WebhookSubscription, error) {
+	subs, err := s.webhookRepo.ListSubscriptions(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("list webhook subscriptions: %w", err)
+	}
+
+	return subs, nil
+}
+
+// DeleteWebhookSubscription удаляет подписку.
+func (s *service) DeleteWebhookSubscription(ctx context.Context, id domain.WebhookSubscriptionID) error {
+	if err := authorizeAdmin(ctx); err != nil {
+		return err
+	}
+
+	return s.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.webhookRepo.DeleteSubscription(ctx, id); err != nil {
+			if errors.Is(err, repository.ErrNotFound) {
+				return ErrNotFound
+			}
+
+			return fmt.Errorf("delete webhook subscription %s: %w", id, err)
+		}
+
+		return s.audit(ctx, domain.AuditWebhookDeleted, string(id), nil)
+	})
+}
+
+// ListFailedWebhookDeliveries возвращает недоставленные вебхуки, начиная с самых новых.
+func (s *service) ListFailedWebhookDeliveries(
+	ctx context.Context,
+	subscriptionID *domain.WebhookSubscriptionID,
+	limit int,
+) ([]domain.WebhookDelivery, error) {
+	deliveries, err := s.webhookRepo.ListDeadLetters(ctx, subscriptionID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("list webhook dead letters: %w", err)
+	}
+
+	return deliveries, nil
+}
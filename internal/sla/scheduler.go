@@ -0,0 +1,82 @@
+// Package sla содержит фоновый контроль сроков ревью: напоминания и эскалации по SLA команд.
+package sla
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/dixitix/pr-reviewer-service/internal/domain"
+	"github.com/dixitix/pr-reviewer-service/internal/service"
+)
+
+// DefaultInterval — период проверки, если он не задан.
+const DefaultInterval = 5 * time.Minute
+
+// Scheduler периодически проверяет открытые ревью и применяет к ним SLA.
+type Scheduler struct {
+	svc      service.ReviewSLAService
+	defaults domain.ReviewPolicy
+	interval time.Duration
+	now      func() time.Time
+	logger   *slog.Logger
+}
+
+// NewScheduler создаёт планировщик. defaults применяется к командам без собственного SLA.
+func NewScheduler(
+	svc service.ReviewSLAService,
+	defaults domain.ReviewPolicy,
+	interval time.Duration,
+	logger *slog.Logger,
+) *Scheduler {
+	if interval <= 0 {
+		interval = DefaultInterval
+	}
+
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	return &Scheduler{
+		svc:      svc,
+		defaults: defaults,
+		interval: interval,
+		now:      time.Now,
+		logger:   logger,
+	}
+}
+
+// Run проверяет ревью сразу и затем каждые interval до отмены ctx.
+func (s *Scheduler) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		s.RunOnce(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// RunOnce выполняет одну проверку. Ошибки логируются: необработанные ревью будут рассмотрены на следующем проходе.
+func (s *Scheduler) RunOnce(ctx context.Context) service.SLAReport {
+	report, err := s.svc.ProcessStaleReviews(ctx, s.now().UTC(), s.defaults)
+	if err != nil && ctx.Err() == nil {
+		s.logger.Error("process stale reviews", slog.Any("err", err))
+	}
+
+	if report.Reminded+report.Reassigned+report.Escalated > 0 {
+		s.logger.Info(
+			"review SLA applied",
+			slog.Int("reminded", report.Reminded),
+			slog.Int("reassigned", report.Reassigned),
+			slog.Int("escalated", report.Escalated),
+		)
+	}
+
+	return report
+}
@@ -0,0 +1,23 @@
+// Package tenant передаёт организацию вызывающего через context.
+// Организацию кладёт в контекст HTTP-слой или фоновый обработчик, репозитории
+// ограничивают по ней все запросы.
+package tenant
+
+import (
+	"context"
+
+	"github.com/dixitix/pr-reviewer-service/internal/domain"
+)
+
+type organizationKey struct{}
+
+// WithOrganization возвращает контекст, в котором действует организация id.
+func WithOrganization(ctx context.Context, id domain.OrganizationID) context.Context {
+	return context.WithValue(ctx, organizationKey{}, id)
+}
+
+// Organization возвращает организацию из контекста. ok == false, если организация не задана.
+func Organization(ctx context.Context) (id domain.OrganizationID, ok bool) {
+	id, ok = ctx.Value(organizationKey{}).(domain.OrganizationID)
+	return id, ok && id != ""
+}
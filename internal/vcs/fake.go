@@ -0,0 +1,103 @@
+// Package vcs содержит клиентов VCS-провайдеров и асинхронную синхронизацию
+// назначенных ревьюверов с Pull Request'ами на стороне провайдера.
+package vcs
+
+import (
+	"context"
+	"slices"
+	"sync"
+
+	"github.com/dixitix/pr-reviewer-service/internal/domain"
+)
+
+// FakeCall описывает один вызов, записанный FakeClient.
+type FakeCall struct {
+	Method    string
+	PRID      domain.PullRequestID
+	Reviewers []domain.UserID
+}
+
+// FakeClient — реализация Client в памяти для тестов.
+// Хранит запрошенных ревьюверов по PR и может возвращать заданные ошибки.
+type FakeClient struct {
+	mu        sync.Mutex
+	calls     []FakeCall
+	requested map[domain.PullRequestID][]domain.UserID
+	errs      []error
+}
+
+// NewFakeClient создаёт пустой FakeClient.
+func NewFakeClient() *FakeClient {
+	return &FakeClient{
+		requested: make(map[domain.PullRequestID][]domain.UserID),
+	}
+}
+
+// FailNext задаёт ошибки, которые будут возвращены следующими вызовами по порядку.
+func (f *FakeClient) FailNext(errs ...error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.errs = append(f.errs, errs...)
+}
+
+// RequestReviewers записывает вызов и добавляет ревьюверов PR.
+func (f *FakeClient) RequestReviewers(_ context.Context, prID domain.PullRequestID, reviewers []domain.UserID) error {
+	return f.record("RequestReviewers", prID, reviewers, func() {
+		for _, id := range reviewers {
+			if !slices.Contains(f.requested[prID], id) {
+				f.requested[prID] = append(f.requested[prID], id)
+			}
+		}
+	})
+}
+
+// RemoveReviewers записывает вызов и убирает ревьюверов PR.
+func (f *FakeClient) RemoveReviewers(_ context.Context, prID domain.PullRequestID, reviewers []domain.UserID) error {
+	return f.record("RemoveReviewers", prID, reviewers, func() {
+		f.requested[prID] = slices.DeleteFunc(f.requested[prID], func(id domain.UserID) bool {
+			return slices.Contains(reviewers, id)
+		})
+	})
+}
+
+// record сохраняет вызов и применяет изменение, если для него не задана ошибка.
+func (f *FakeClient) record(method string, prID domain.PullRequestID, reviewers []domain.UserID, apply func()) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.calls = append(f.calls, FakeCall{
+		Method:    method,
+		PRID:      prID,
+		Reviewers: slices.Clone(reviewers),
+	})
+
+	if len(f.errs) > 0 {
+		err := f.errs[0]
+		f.errs = f.errs[1:]
+
+		if err != nil {
+			return err
+		}
+	}
+
+	apply()
+
+	return nil
+}
+
+// Calls возвращает копию всех записанных вызовов.
+func (f *FakeClient) Calls() []FakeCall {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return slices.Clone(f.calls)
+}
+
+// Requested возвращает текущих запрошенных ревьюверов PR.
+func (f *FakeClient) Requested(prID domain.PullRequestID) []domain.UserID {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return slices.Clone(f.requested[prID])
+}
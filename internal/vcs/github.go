@@ -0,0 +1,167 @@
+// Package vcs содержит клиентов VCS-провайдеров и асинхронную синхронизацию
+// назначенных ревьюверов с Pull Request'ами на стороне провайдера.
+package vcs
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dixitix/pr-reviewer-service/internal/domain"
+)
+
+// DefaultGitHubAPIURL — адрес публичного GitHub REST API.
+const DefaultGitHubAPIURL = "https://api.github.com"
+
+// GitHubConfig описывает настройки клиента GitHub.
+type GitHubConfig struct {
+	APIURL string
+	Token  string
+	Owner  string
+	Repo   string
+}
+
+// GitHubClient реализует Client поверх GitHub REST API.
+type GitHubClient struct {
+	cfg        GitHubConfig
+	httpClient *http.Client
+}
+
+// NewGitHubClient создаёт клиента GitHub. Если httpClient == nil, используется клиент с таймаутом 10 секунд.
+func NewGitHubClient(cfg GitHubConfig, httpClient *http.Client) *GitHubClient {
+	if cfg.APIURL == "" {
+		cfg.APIURL = DefaultGitHubAPIURL
+	}
+
+	cfg.APIURL = strings.TrimRight(cfg.APIURL, "/")
+
+	if httpClient == nil {
+		httpClient = &http.Client{Timeout: 10 * time.Second}
+	}
+
+	return &GitHubClient{
+		cfg:        cfg,
+		httpClient: httpClient,
+	}
+}
+
+// requestedReviewersBody описывает тело запросов к /requested_reviewers.
+type requestedReviewersBody struct {
+	Reviewers []string `json:"reviewers"`
+}
+
+// RequestReviewers запрашивает ревью через POST /repos/{owner}/{repo}/pulls/{number}/requested_reviewers.
+func (c *GitHubClient) RequestReviewers(ctx context.Context, prID domain.PullRequestID, reviewers []domain.UserID) error {
+	return c.doReviewers(ctx, http.MethodPost, prID, reviewers)
+}
+
+// RemoveReviewers снимает запрос ревью через DELETE /repos/{owner}/{repo}/pulls/{number}/requested_reviewers.
+func (c *GitHubClient) RemoveReviewers(ctx context.Context, prID domain.PullRequestID, reviewers []domain.UserID) error {
+	return c.doReviewers(ctx, http.MethodDelete, prID, reviewers)
+}
+
+// doReviewers выполняет запрос к эндпоинту requested_reviewers.
+func (c *GitHubClient) doReviewers(
+	ctx context.Context,
+	method string,
+	prID domain.PullRequestID,
+	reviewers []domain.UserID,
+) error {
+	if len(reviewers) == 0 {
+		return nil
+	}
+
+	owner, repo, number, err := c.resolve(prID)
+	if err != nil {
+		return err
+	}
+
+	body := requestedReviewersBody{Reviewers: make([]string, len(reviewers))}
+	for i, id := range reviewers {
+		body.Reviewers[i] = string(id)
+	}
+
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return fmt.Errorf("marshal reviewers: %w", err)
+	}
+
+	url := fmt.Sprintf("%s/repos/%s/%s/pulls/%d/requested_reviewers", c.cfg.APIURL, owner, repo, number)
+
+	req, err := http.NewRequestWithContext(ctx, method, url, bytes.NewReader(payload))
+	if err != nil {
+		return fmt.Errorf("new request: %w", err)
+	}
+
+	req.Header.Set("Accept", "application/vnd.github+json")
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-GitHub-Api-Version", "2022-11-28")
+
+	if c.cfg.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.cfg.Token)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("%s %s: %w", method, url, err)
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		_, _ = io.Copy(io.Discard, resp.Body)
+		return nil
+	}
+
+	msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+	err = fmt.Errorf("%s %s: unexpected status %d: %s", method, url, resp.StatusCode, strings.TrimSpace(string(msg)))
+
+	// 4xx, кроме rate limit, повторять бессмысленно.
+	if resp.StatusCode >= 400 && resp.StatusCode < 500 &&
+		resp.StatusCode != http.StatusTooManyRequests && resp.StatusCode != http.StatusForbidden {
+		return fmt.Errorf("%w: %w", ErrPermanent, err)
+	}
+
+	return err
+}
+
+// resolve определяет репозиторий и номер PR по его идентификатору.
+// Поддерживаются форматы "owner/repo#123" и идентификаторы, оканчивающиеся номером ("123", "pr-123"),
+// для которых используется репозиторий из конфигурации.
+func (c *GitHubClient) resolve(prID domain.PullRequestID) (string, string, int, error) {
+	raw := string(prID)
+	owner, repo := c.cfg.Owner, c.cfg.Repo
+
+	if repoPart, numPart, ok := strings.Cut(raw, "#"); ok {
+		o, r, found := strings.Cut(repoPart, "/")
+		if !found || o == "" || r == "" {
+			return "", "", 0, fmt.Errorf("%w: invalid repository in pull request id %q", ErrPermanent, raw)
+		}
+
+		owner, repo, raw = o, r, numPart
+	}
+
+	end := len(raw)
+	start := end
+	for start > 0 && raw[start-1] >= '0' && raw[start-1] <= '9' {
+		start--
+	}
+
+	number, err := strconv.Atoi(raw[start:end])
+	if err != nil || number <= 0 {
+		return "", "", 0, fmt.Errorf("%w: pull request id %q has no number", ErrPermanent, prID)
+	}
+
+	if owner == "" || repo == "" {
+		return "", "", 0, fmt.Errorf("%w: repository is not configured for pull request %q", ErrPermanent, prID)
+	}
+
+	return owner, repo, number, nil
+}
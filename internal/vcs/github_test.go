@@ -0,0 +1,115 @@
+package vcs
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+
+	"github.com/dixitix/pr-reviewer-service/internal/domain"
+)
+
+// TestGitHubClient_RequestAndRemoveReviewers проверяет формирование запросов к requested_reviewers.
+func TestGitHubClient_RequestAndRemoveReviewers(t *testing.T) {
+	type call struct {
+		method    string
+		path      string
+		auth      string
+		reviewers []string
+	}
+
+	var calls []call
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body requestedReviewersBody
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Errorf("decode body: %v", err)
+		}
+
+		calls = append(calls, call{
+			method:    r.Method,
+			path:      r.URL.Path,
+			auth:      r.Header.Get("Authorization"),
+			reviewers: body.Reviewers,
+		})
+
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer srv.Close()
+
+	client := NewGitHubClient(GitHubConfig{
+		APIURL: srv.URL,
+		Token:  "secret",
+		Owner:  "acme",
+		Repo:   "backend",
+	}, srv.Client())
+
+	ctx := context.Background()
+
+	if err := client.RequestReviewers(ctx, "pr-42", []domain.UserID{"alice", "bob"}); err != nil {
+		t.Fatalf("RequestReviewers returned error: %v", err)
+	}
+
+	if err := client.RemoveReviewers(ctx, "other/repo#7", []domain.UserID{"carol"}); err != nil {
+		t.Fatalf("RemoveReviewers returned error: %v", err)
+	}
+
+	if len(calls) != 2 {
+		t.Fatalf("expected 2 calls, got %d", len(calls))
+	}
+
+	if calls[0].method != http.MethodPost || calls[0].path != "/repos/acme/backend/pulls/42/requested_reviewers" {
+		t.Fatalf("unexpected first call: %+v", calls[0])
+	}
+
+	if calls[0].auth != "Bearer secret" {
+		t.Fatalf("unexpected Authorization header: %q", calls[0].auth)
+	}
+
+	if !slices.Equal(calls[0].reviewers, []string{"alice", "bob"}) {
+		t.Fatalf("unexpected reviewers: %v", calls[0].reviewers)
+	}
+
+	if calls[1].method != http.MethodDelete || calls[1].path != "/repos/other/repo/pulls/7/requested_reviewers" {
+		t.Fatalf("unexpected second call: %+v", calls[1])
+	}
+}
+
+// TestGitHubClient_Errors проверяет классификацию ошибок провайдера.
+func TestGitHubClient_Errors(t *testing.T) {
+	status := http.StatusUnprocessableEntity
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(status)
+	}))
+	defer srv.Close()
+
+	client := NewGitHubClient(GitHubConfig{APIURL: srv.URL, Owner: "acme", Repo: "backend"}, srv.Client())
+	ctx := context.Background()
+
+	t.Run("client error is permanent", func(t *testing.T) {
+		err := client.RequestReviewers(ctx, "1", []domain.UserID{"alice"})
+		if !errors.Is(err, ErrPermanent) {
+			t.Fatalf("expected ErrPermanent, got %v", err)
+		}
+	})
+
+	t.Run("server error is retryable", func(t *testing.T) {
+		status = http.StatusBadGateway
+
+		err := client.RequestReviewers(ctx, "1", []domain.UserID{"alice"})
+		if err == nil || errors.Is(err, ErrPermanent) {
+			t.Fatalf("expected retryable error, got %v", err)
+		}
+	})
+
+	t.Run("id without number", func(t *testing.T) {
+		err := client.RequestReviewers(ctx, "feature", []domain.UserID{"alice"})
+		if !errors.Is(err, ErrPermanent) {
+			t.Fatalf("expected ErrPermanent, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,333 @@
+// Package vcs содержит клиентов VCS-провайдеров и асинхронную синхронизацию
+// назначенных ревьюверов с Pull Request'ами на стороне провайдера.
+package vcs
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"slices"
+	"time"
+
+	"github.com/dixitix/pr-reviewer-service/internal/domain"
+	"github.com/dixitix/pr-reviewer-service/internal/repository"
+)
+
+// ReviewerChange описывает изменение набора ревьюверов PR, которое нужно отправить провайдеру.
+type ReviewerChange struct {
+	// ID — номер сохранённой неудачи; 0 — изменение ещё не сохранялось.
+	ID             int64
+	OrganizationID domain.OrganizationID
+	PullRequestID  domain.PullRequestID
+	Add            []domain.UserID
+	Remove         []domain.UserID
+	Attempts       int
+	LastError      string
+	Permanent      bool
+	FailedAt       time.Time
+}
+
+// SyncerConfig описывает настройки очереди синхронизации.
+type SyncerConfig struct {
+	// QueueSize — ёмкость очереди изменений.
+	QueueSize int
+	// MaxAttempts — число попыток доставки одного изменения.
+	MaxAttempts int
+	// InitialBackoff — пауза перед первой повторной попыткой, далее удваивается.
+	InitialBackoff time.Duration
+	// MaxBackoff — верхняя граница паузы между попытками.
+	MaxBackoff time.Duration
+	// RedriveInterval — период повторной постановки в очередь неудачных изменений.
+	// Ноль отключает автоматическую повторную постановку.
+	RedriveInterval time.Duration
+	// RedriveLease — на сколько захваченное для повтора изменение скрывается от других экземпляров.
+	RedriveLease time.Duration
+}
+
+// DefaultSyncerConfig возвращает настройки очереди по умолчанию.
+func DefaultSyncerConfig() SyncerConfig {
+	return SyncerConfig{
+		QueueSize:       256,
+		MaxAttempts:     5,
+		InitialBackoff:  500 * time.Millisecond,
+		MaxBackoff:      30 * time.Second,
+		RedriveInterval: 5 * time.Minute,
+		RedriveLease:    10 * time.Minute,
+	}
+}
+
+// Syncer асинхронно отправляет изменения ревьюверов VCS-провайдеру.
+// Реализует outbox.Sink: события кладутся в очередь, а доставка с повторами выполняется в Run.
+// Событие outbox считается доставленным, как только попало в очередь, поэтому неудачные изменения
+// и изменения, оставшиеся в очереди при остановке, сохраняются в repo и повторяются оттуда.
+type Syncer struct {
+	client Client
+	repo   repository.VCSSyncRepository
+	cfg    SyncerConfig
+	logger *slog.Logger
+
+	queue chan ReviewerChange
+}
+
+// NewSyncer создаёт очередь синхронизации поверх клиента провайдера и хранилища неудач.
+func NewSyncer(client Client, repo repository.VCSSyncRepository, cfg SyncerConfig, logger *slog.Logger) *Syncer {
+	def := DefaultSyncerConfig()
+
+	if cfg.QueueSize <= 0 {
+		cfg.QueueSize = def.QueueSize
+	}
+
+	if cfg.MaxAttempts <= 0 {
+		cfg.MaxAttempts = def.MaxAttempts
+	}
+
+	if cfg.InitialBackoff <= 0 {
+		cfg.InitialBackoff = def.InitialBackoff
+	}
+
+	if cfg.MaxBackoff < cfg.InitialBackoff {
+		cfg.MaxBackoff = cfg.InitialBackoff
+	}
+
+	if cfg.RedriveLease <= 0 {
+		cfg.RedriveLease = def.RedriveLease
+	}
+
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	return &Syncer{
+		client: client,
+		repo:   repo,
+		cfg:    cfg,
+		logger: logger,
+		queue:  make(chan ReviewerChange, cfg.QueueSize),
+	}
+}
+
+// Name возвращает имя получателя событий.
+func (s *Syncer) Name() string {
+	return "vcs"
+}
+
+// Deliver превращает событие об изменении ревьюверов в задачу синхронизации.
+// Если очередь переполнена, возвращается ErrQueueFull и событие будет передано повторно.
+func (s *Syncer) Deliver(_ context.Context, event domain.Event) error {
+	if len(event.AddedReviewers) == 0 && len(event.RemovedReviewers) == 0 {
+		return nil
+	}
+
+	return s.Enqueue(ReviewerChange{
+		OrganizationID: event.OrganizationID,
+		PullRequestID:  event.PullRequest.ID,
+		Add:            slices.Clone(event.AddedReviewers),
+		Remove:         slices.Clone(event.RemovedReviewers),
+	})
+}
+
+// Enqueue ставит изменение в очередь без блокировки.
+func (s *Syncer) Enqueue(change ReviewerChange) error {
+	select {
+	case s.queue <- change:
+		return nil
+	default:
+		return ErrQueueFull
+	}
+}
+
+// Run обрабатывает очередь до отмены контекста. При остановке изменения, оставшиеся в очереди,
+// сохраняются как неудачные, чтобы их отправил следующий запуск.
+func (s *Syncer) Run(ctx context.Context) {
+	var redrive <-chan time.Time
+
+	if s.cfg.RedriveInterval > 0 {
+		ticker := time.NewTicker(s.cfg.RedriveInterval)
+		defer ticker.Stop()
+
+		redrive = ticker.C
+	}
+
+	for {
+		// select выбирает готовую ветку случайно: после остановки очередь только сохраняется.
+		if ctx.Err() != nil {
+			s.drain(ctx)
+			return
+		}
+
+		select {
+		case <-ctx.Done():
+			s.drain(ctx)
+			return
+		case change := <-s.queue:
+			s.deliver(ctx, change)
+		case <-redrive:
+			if err := s.Redrive(ctx); err != nil {
+				s.logger.ErrorContext(ctx, "vcs redrive failed", slog.Any("err", err))
+			}
+		}
+	}
+}
+
+// drain сохраняет изменения, которые остались в очереди при остановке.
+func (s *Syncer) drain(ctx context.Context) {
+	for {
+		select {
+		case change := <-s.queue:
+			if change.LastError == "" {
+				change.LastError = "not delivered before shutdown"
+			}
+
+			s.fail(ctx, change)
+		default:
+			return
+		}
+	}
+}
+
+// deliver отправляет изменение провайдеру с экспоненциальными повторами.
+func (s *Syncer) deliver(ctx context.Context, change ReviewerChange) {
+	backoff := s.cfg.InitialBackoff
+	change.Attempts = 0
+
+	for {
+		change.Attempts++
+
+		err := s.apply(ctx, change)
+		if err == nil {
+			s.resolve(ctx, change)
+			s.logger.Info(
+				"vcs reviewers synced",
+				slog.String("pull_request_id", string(change.PullRequestID)),
+				slog.Int("attempts", change.Attempts),
+			)
+
+			return
+		}
+
+		change.LastError = err.Error()
+		change.Permanent = errors.Is(err, ErrPermanent)
+
+		if change.Permanent || change.Attempts >= s.cfg.MaxAttempts || ctx.Err() != nil {
+			s.fail(ctx, change)
+			return
+		}
+
+		s.logger.Warn(
+			"vcs reviewers sync failed, retrying",
+			slog.String("pull_request_id", string(change.PullRequestID)),
+			slog.Int("attempt", change.Attempts),
+			slog.Duration("backoff", backoff),
+			slog.Any("err", err),
+		)
+
+		timer := time.NewTimer(backoff)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			s.fail(ctx, change)
+			return
+		case <-timer.C:
+		}
+
+		backoff = min(backoff*2, s.cfg.MaxBackoff)
+	}
+}
+
+// apply выполняет снятие и запрос ревьюверов у провайдера.
+func (s *Syncer) apply(ctx context.Context, change ReviewerChange) error {
+	if len(change.Remove) > 0 {
+		if err := s.client.RemoveReviewers(ctx, change.PullRequestID, change.Remove); err != nil {
+			return err
+		}
+	}
+
+	if len(change.Add) > 0 {
+		if err := s.client.RequestReviewers(ctx, change.PullRequestID, change.Add); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// resolve удаляет сохранённую неудачу после успешной повторной отправки.
+func (s *Syncer) resolve(ctx context.Context, change ReviewerChange) {
+	if change.ID == 0 {
+		return
+	}
+
+	if err := s.repo.DeleteFailure(context.WithoutCancel(ctx), change.ID); err != nil {
+		s.logger.Error(
+			"failed to delete resolved vcs sync failure",
+			slog.Int64("id", change.ID),
+			slog.Any("err", err),
+		)
+	}
+}
+
+// fail сохраняет неудачное изменение. Временная ошибка будет повторена через RedriveInterval.
+// Сохранение не зависит от отмены ctx, чтобы изменение не потерялось при остановке.
+func (s *Syncer) fail(ctx context.Context, change ReviewerChange) {
+	change.FailedAt = time.Now().UTC()
+
+	s.logger.Error(
+		"vcs reviewers sync failed",
+		slog.String("pull_request_id", string(change.PullRequestID)),
+		slog.Int("attempts", change.Attempts),
+		slog.String("err", change.LastError),
+	)
+
+	_, err := s.repo.SaveFailure(context.WithoutCancel(ctx), repository.VCSSyncFailure{
+		ID:             change.ID,
+		OrganizationID: change.OrganizationID,
+		PullRequestID:  change.PullRequestID,
+		Add:            change.Add,
+		Remove:         change.Remove,
+		Attempts:       change.Attempts,
+		LastError:      change.LastError,
+		Permanent:      change.Permanent,
+		FailedAt:       change.FailedAt,
+	}, change.FailedAt.Add(s.cfg.RedriveInterval))
+	if err != nil {
+		s.logger.Error(
+			"failed to save vcs sync failure, change is lost",
+			slog.String("pull_request_id", string(change.PullRequestID)),
+			slog.Any("err", err),
+		)
+	}
+}
+
+// Redrive возвращает в очередь сохранённые неудачные изменения, кроме постоянных ошибок.
+// Захватывается не больше изменений, чем свободно места в очереди; изменение, которое всё же
+// не поместилось, будет захвачено снова после RedriveLease.
+func (s *Syncer) Redrive(ctx context.Context) error {
+	free := cap(s.queue) - len(s.queue)
+	if free <= 0 {
+		return nil
+	}
+
+	failures, err := s.repo.ClaimFailures(ctx, free, s.cfg.RedriveLease)
+	if err != nil {
+		return err
+	}
+
+	for _, f := range failures {
+		change := ReviewerChange{
+			ID:             f.ID,
+			OrganizationID: f.OrganizationID,
+			PullRequestID:  f.PullRequestID,
+			Add:            f.Add,
+			Remove:         f.Remove,
+			Attempts:       f.Attempts,
+			LastError:      f.LastError,
+			FailedAt:       f.FailedAt,
+		}
+
+		if err := s.Enqueue(change); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,220 @@
+package vcs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/dixitix/pr-reviewer-service/internal/domain"
+	"github.com/dixitix/pr-reviewer-service/internal/repository"
+)
+
+// fakeVCSSyncRepo хранит неудачные изменения в памяти.
+type fakeVCSSyncRepo struct {
+	repository.VCSSyncRepository
+
+	mu       sync.Mutex
+	nextID   int64
+	failures map[int64]repository.VCSSyncFailure
+}
+
+func newFakeVCSSyncRepo() *fakeVCSSyncRepo {
+	return &fakeVCSSyncRepo{failures: make(map[int64]repository.VCSSyncFailure)}
+}
+
+func (r *fakeVCSSyncRepo) SaveFailure(_ context.Context, f repository.VCSSyncFailure, _ time.Time) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if f.ID == 0 {
+		r.nextID++
+		f.ID = r.nextID
+	}
+
+	r.failures[f.ID] = f
+
+	return f.ID, nil
+}
+
+func (r *fakeVCSSyncRepo) ClaimFailures(_ context.Context, limit int, _ time.Duration) ([]repository.VCSSyncFailure, error) {
+	result := make([]repository.VCSSyncFailure, 0)
+
+	for _, f := range r.list() {
+		if !f.Permanent && len(result) < limit {
+			result = append(result, f)
+		}
+	}
+
+	return result, nil
+}
+
+func (r *fakeVCSSyncRepo) DeleteFailure(_ context.Context, id int64) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.failures, id)
+
+	return nil
+}
+
+// list возвращает сохранённые изменения по возрастанию ID.
+func (r *fakeVCSSyncRepo) list() []repository.VCSSyncFailure {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	result := make([]repository.VCSSyncFailure, 0, len(r.failures))
+	for _, f := range r.failures {
+		result = append(result, f)
+	}
+
+	slices.SortFunc(result, func(a, b repository.VCSSyncFailure) int { return int(a.ID - b.ID) })
+
+	return result
+}
+
+// newTestSyncer создаёт Syncer с короткими паузами и запускает его обработку.
+func newTestSyncer(t *testing.T, client Client, repo repository.VCSSyncRepository, maxAttempts int) *Syncer {
+	t.Helper()
+
+	s := NewSyncer(client, repo, SyncerConfig{
+		MaxAttempts:    maxAttempts,
+		InitialBackoff: time.Millisecond,
+		MaxBackoff:     5 * time.Millisecond,
+	}, slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	go s.Run(ctx)
+
+	return s
+}
+
+// waitFor ждёт выполнения условия не дольше секунды.
+func waitFor(t *testing.T, cond func() bool) {
+	t.Helper()
+
+	deadline := time.Now().Add(time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatalf("condition not met in time")
+		}
+
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+// TestSyncer_ReassignAfterRetry проверяет доставку переназначения с повтором после временной ошибки.
+func TestSyncer_ReassignAfterRetry(t *testing.T) {
+	fake := NewFakeClient()
+	fake.FailNext(errors.New("temporary"))
+
+	repo := newFakeVCSSyncRepo()
+	s := newTestSyncer(t, fake, repo, 3)
+
+	err := s.Deliver(context.Background(), domain.Event{
+		Type:             domain.EventReviewerReassigned,
+		PullRequest:      domain.PullRequest{ID: "pr-1"},
+		AddedReviewers:   []domain.UserID{"u3"},
+		RemovedReviewers: []domain.UserID{"u2"},
+	})
+	if err != nil {
+		t.Fatalf("Deliver returned error: %v", err)
+	}
+
+	waitFor(t, func() bool {
+		return slices.Equal(fake.Requested("pr-1"), []domain.UserID{"u3"})
+	})
+
+	calls := fake.Calls()
+	if len(calls) != 3 {
+		t.Fatalf("expected 3 calls (failed remove, remove, request), got %d: %+v", len(calls), calls)
+	}
+
+	if failures := repo.list(); len(failures) != 0 {
+		t.Fatalf("expected no failures, got %+v", failures)
+	}
+}
+
+// TestSyncer_FailureQueue проверяет сохранение неудачных изменений и их повторную отправку.
+func TestSyncer_FailureQueue(t *testing.T) {
+	fake := NewFakeClient()
+	fake.FailNext(
+		errors.New("down"),
+		errors.New("down"),
+		fmt.Errorf("%w: not found", ErrPermanent),
+	)
+
+	repo := newFakeVCSSyncRepo()
+	s := newTestSyncer(t, fake, repo, 2)
+	ctx := context.Background()
+
+	for _, id := range []domain.UserID{"1", "2"} {
+		err := s.Deliver(ctx, domain.Event{
+			OrganizationID: "org-1",
+			PullRequest:    domain.PullRequest{ID: domain.PullRequestID("pr-" + id)},
+			AddedReviewers: []domain.UserID{"u" + id},
+		})
+		if err != nil {
+			t.Fatalf("Deliver returned error: %v", err)
+		}
+	}
+
+	waitFor(t, func() bool { return len(repo.list()) == 2 })
+
+	failures := repo.list()
+	if failures[0].PullRequestID != "pr-1" || failures[0].OrganizationID != "org-1" ||
+		failures[0].Attempts != 2 || failures[0].Permanent {
+		t.Fatalf("unexpected first failure: %+v", failures[0])
+	}
+
+	if failures[1].PullRequestID != "pr-2" || !failures[1].Permanent {
+		t.Fatalf("unexpected second failure: %+v", failures[1])
+	}
+
+	if err := s.Redrive(ctx); err != nil {
+		t.Fatalf("Redrive returned error: %v", err)
+	}
+
+	waitFor(t, func() bool {
+		return slices.Equal(fake.Requested("pr-1"), []domain.UserID{"u1"})
+	})
+
+	waitFor(t, func() bool { return len(repo.list()) == 1 })
+
+	failures = repo.list()
+	if len(failures) != 1 || failures[0].PullRequestID != "pr-2" {
+		t.Fatalf("expected only permanent failure to remain, got %+v", failures)
+	}
+}
+
+// TestSyncer_DrainOnShutdown проверяет сохранение изменений, оставшихся в очереди при остановке.
+func TestSyncer_DrainOnShutdown(t *testing.T) {
+	repo := newFakeVCSSyncRepo()
+	s := NewSyncer(NewFakeClient(), repo, SyncerConfig{}, slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	err := s.Deliver(context.Background(), domain.Event{
+		OrganizationID: "org-1",
+		PullRequest:    domain.PullRequest{ID: "pr-1"},
+		AddedReviewers: []domain.UserID{"u1"},
+	})
+	if err != nil {
+		t.Fatalf("Deliver returned error: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	s.Run(ctx)
+
+	failures := repo.list()
+	if len(failures) != 1 || failures[0].PullRequestID != "pr-1" || failures[0].Permanent {
+		t.Fatalf("expected queued change to be saved, got %+v", failures)
+	}
+}
@@ -0,0 +1,27 @@
+// Package vcs содержит клиентов VCS-провайдеров и асинхронную синхронизацию
+// назначенных ревьюверов с Pull Request'ами на стороне провайдера.
+package vcs
+
+import (
+	"context"
+	"errors"
+
+	"github.com/dixitix/pr-reviewer-service/internal/domain"
+)
+
+// ErrPermanent помечает ошибку провайдера, повторять которую бессмысленно
+// (например, PR не найден или нет прав).
+var ErrPermanent = errors.New("permanent vcs error")
+
+// ErrQueueFull возвращается, когда очередь синхронизации переполнена.
+var ErrQueueFull = errors.New("vcs sync queue is full")
+
+// Client описывает исходящие вызовы к VCS-провайдеру.
+// Ревьюверы передаются по user_id, который должен совпадать с логином у провайдера.
+type Client interface {
+	// RequestReviewers запрашивает ревью у перечисленных пользователей.
+	RequestReviewers(ctx context.Context, prID domain.PullRequestID, reviewers []domain.UserID) error
+
+	// RemoveReviewers снимает запрос ревью с перечисленных пользователей.
+	RemoveReviewers(ctx context.Context, prID domain.PullRequestID, reviewers []domain.UserID) error
+}
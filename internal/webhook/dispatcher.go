@@ -0,0 +1,273 @@
+// Package webhook содержит асинхронную доставку исходящих вебхуков подписчикам.
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/dixitix/pr-reviewer-service/internal/domain"
+	"github.com/dixitix/pr-reviewer-service/internal/idgen"
+	"github.com/dixitix/pr-reviewer-service/internal/repository"
+	"github.com/dixitix/pr-reviewer-service/internal/tenant"
+)
+
+// ErrQueueFull возвращается, когда очередь доставок переполнена.
+var ErrQueueFull = errors.New("webhook queue is full")
+
+// Config описывает настройки доставки вебхуков.
+type Config struct {
+	// Workers — число параллельных доставщиков.
+	Workers int
+	// QueueSize — ёмкость очереди доставок.
+	QueueSize int
+	// MaxAttempts — число попыток доставки одного вебхука.
+	MaxAttempts int
+	// InitialBackoff — пауза перед первой повторной попыткой, далее удваивается.
+	InitialBackoff time.Duration
+	// MaxBackoff — верхняя граница паузы между попытками.
+	MaxBackoff time.Duration
+	// Timeout — таймаут одного HTTP-запроса к подписчику.
+	Timeout time.Duration
+}
+
+// DefaultConfig возвращает настройки доставки по умолчанию.
+func DefaultConfig() Config {
+	return Config{
+		Workers:        4,
+		QueueSize:      1024,
+		MaxAttempts:    6,
+		InitialBackoff: time.Second,
+		MaxBackoff:     5 * time.Minute,
+		Timeout:        5 * time.Second,
+	}
+}
+
+// job описывает одну доставку события одному подписчику.
+type job struct {
+	org        domain.OrganizationID
+	sub        domain.WebhookSubscription
+	deliveryID string
+	eventType  domain.EventType
+	body       []byte
+}
+
+// Dispatcher асинхронно доставляет события подписчикам вебхуков.
+// Реализует outbox.Sink: Deliver ставит доставки в очередь, HTTP-запросы выполняют воркеры из Run.
+type Dispatcher struct {
+	repo       repository.WebhookRepository
+	cfg        Config
+	httpClient *http.Client
+	logger     *slog.Logger
+
+	jobs chan job
+}
+
+// NewDispatcher создаёт доставщика вебхуков. Если httpClient == nil, создаётся клиент с таймаутом из cfg.
+func NewDispatcher(
+	repo repository.WebhookRepository,
+	cfg Config,
+	httpClient *http.Client,
+	logger *slog.Logger,
+) *Dispatcher {
+	def := DefaultConfig()
+
+	if cfg.Workers <= 0 {
+		cfg.Workers = def.Workers
+	}
+
+	if cfg.QueueSize <= 0 {
+		cfg.QueueSize = def.QueueSize
+	}
+
+	if cfg.MaxAttempts <= 0 {
+		cfg.MaxAttempts = def.MaxAttempts
+	}
+
+	if cfg.InitialBackoff <= 0 {
+		cfg.InitialBackoff = def.InitialBackoff
+	}
+
+	if cfg.MaxBackoff < cfg.InitialBackoff {
+		cfg.MaxBackoff = cfg.InitialBackoff
+	}
+
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = def.Timeout
+	}
+
+	if httpClient == nil {
+		httpClient = &http.Client{Timeout: cfg.Timeout}
+	}
+
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	return &Dispatcher{
+		repo:       repo,
+		cfg:        cfg,
+		httpClient: httpClient,
+		logger:     logger,
+		jobs:       make(chan job, cfg.QueueSize),
+	}
+}
+
+// Name возвращает имя получателя событий.
+func (d *Dispatcher) Name() string {
+	return "webhook"
+}
+
+// Deliver находит подписчиков события и ставит доставки в очередь.
+// Ошибка возвращается, если подписчиков не удалось получить или очередь переполнена,
+// тогда событие будет передано повторно.
+func (d *Dispatcher) Deliver(ctx context.Context, event domain.Event) error {
+	subs, err := d.repo.ListSubscriptionsByEvent(ctx, event.Type)
+	if err != nil {
+		return fmt.Errorf("list webhook subscriptions for %s: %w", event.Type, err)
+	}
+
+	for _, sub := range subs {
+		deliveryID := idgen.New()
+
+		body, err := json.Marshal(NewPayload(deliveryID, event))
+		if err != nil {
+			return fmt.Errorf("marshal webhook payload: %w", err)
+		}
+
+		select {
+		case d.jobs <- job{org: event.OrganizationID, sub: sub, deliveryID: deliveryID, eventType: event.Type, body: body}:
+		default:
+			return ErrQueueFull
+		}
+	}
+
+	return nil
+}
+
+// Run запускает воркеры доставки и блокируется до отмены контекста.
+func (d *Dispatcher) Run(ctx context.Context) {
+	for i := 1; i < d.cfg.Workers; i++ {
+		go d.worker(ctx)
+	}
+
+	d.worker(ctx)
+}
+
+// worker доставляет вебхуки из очереди.
+func (d *Dispatcher) worker(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case j := <-d.jobs:
+			d.deliver(ctx, j)
+		}
+	}
+}
+
+// deliver отправляет вебхук с экспоненциальными повторами и сохраняет его в dead letter при неудаче.
+func (d *Dispatcher) deliver(ctx context.Context, j job) {
+	backoff := d.cfg.InitialBackoff
+
+	var (
+		statusCode int
+		err        error
+	)
+
+	for attempt := 1; ; attempt++ {
+		statusCode, err = d.send(ctx, j)
+		if err == nil {
+			return
+		}
+
+		if attempt >= d.cfg.MaxAttempts || ctx.Err() != nil {
+			d.deadLetter(j, attempt, statusCode, err)
+			return
+		}
+
+		d.logger.Warn(
+			"webhook delivery failed, retrying",
+			slog.String("subscription_id", string(j.sub.ID)),
+			slog.String("delivery_id", j.deliveryID),
+			slog.Int("attempt", attempt),
+			slog.Duration("backoff", backoff),
+			slog.Any("err", err),
+		)
+
+		timer := time.NewTimer(backoff)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			d.deadLetter(j, attempt, statusCode, err)
+			return
+		case <-timer.C:
+		}
+
+		backoff = min(backoff*2, d.cfg.MaxBackoff)
+	}
+}
+
+// send выполняет одну попытку доставки и возвращает HTTP-статус ответа.
+func (d *Dispatcher) send(ctx context.Context, j job) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, j.sub.URL, bytes.NewReader(j.body))
+	if err != nil {
+		return 0, fmt.Errorf("new request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(HeaderEvent, string(j.eventType))
+	req.Header.Set(HeaderDelivery, j.deliveryID)
+	req.Header.Set(HeaderSignature, Sign(j.sub.Secret, j.body))
+
+	resp, err := d.httpClient.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("post webhook: %w", err)
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return resp.StatusCode, fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+
+	return resp.StatusCode, nil
+}
+
+// deadLetter сохраняет недоставленный вебхук.
+func (d *Dispatcher) deadLetter(j job, attempts, statusCode int, deliveryErr error) {
+	d.logger.Error(
+		"webhook delivery failed",
+		slog.String("subscription_id", string(j.sub.ID)),
+		slog.String("delivery_id", j.deliveryID),
+		slog.Int("attempts", attempts),
+		slog.Any("err", deliveryErr),
+	)
+
+	// Контекст обработки уже может быть отменён при остановке, сохраняем с собственным таймаутом.
+	ctx, cancel := context.WithTimeout(tenant.WithOrganization(context.Background(), j.org), 5*time.Second)
+	defer cancel()
+
+	err := d.repo.CreateDeadLetter(ctx, domain.WebhookDelivery{
+		ID:             j.deliveryID,
+		SubscriptionID: j.sub.ID,
+		EventType:      j.eventType,
+		Payload:        j.body,
+		Attempts:       attempts,
+		LastError:      deliveryErr.Error(),
+		LastStatusCode: statusCode,
+		FailedAt:       time.Now().UTC(),
+	})
+	if err != nil {
+		d.logger.Error("save webhook dead letter", slog.String("delivery_id", j.deliveryID), slog.Any("err", err))
+	}
+}
@@ -0,0 +1,186 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/dixitix/pr-reviewer-service/internal/domain"
+	"github.com/dixitix/pr-reviewer-service/internal/repository"
+)
+
+// memoryRepo — реализация repository.WebhookRepository в памяти для тестов доставщика.
+type memoryRepo struct {
+	repository.WebhookRepository
+
+	mu          sync.Mutex
+	subs        []domain.WebhookSubscription
+	deadLetters []domain.WebhookDelivery
+}
+
+func (m *memoryRepo) ListSubscriptionsByEvent(_ context.Context, eventType domain.EventType) ([]domain.WebhookSubscription, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var result []domain.WebhookSubscription
+	for _, sub := range m.subs {
+		if sub.Matches(eventType) {
+			result = append(result, sub)
+		}
+	}
+
+	return result, nil
+}
+
+func (m *memoryRepo) CreateDeadLetter(_ context.Context, d domain.WebhookDelivery) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.deadLetters = append(m.deadLetters, d)
+
+	return nil
+}
+
+func (m *memoryRepo) DeadLetters() []domain.WebhookDelivery {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return append([]domain.WebhookDelivery(nil), m.deadLetters...)
+}
+
+// startDispatcher создаёт и запускает доставщика с короткими паузами.
+func startDispatcher(t *testing.T, repo repository.WebhookRepository) *Dispatcher {
+	t.Helper()
+
+	d := NewDispatcher(repo, Config{
+		Workers:        1,
+		MaxAttempts:    3,
+		InitialBackoff: time.Millisecond,
+		MaxBackoff:     5 * time.Millisecond,
+	}, nil, slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	go d.Run(ctx)
+
+	return d
+}
+
+// TestDispatcher_SignedDelivery проверяет доставку подписанного вебхука с учётом фильтра событий.
+func TestDispatcher_SignedDelivery(t *testing.T) {
+	received := make(chan *http.Request, 4)
+	bodies := make(chan []byte, 4)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		received <- r
+		bodies <- body
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+
+	repo := &memoryRepo{subs: []domain.WebhookSubscription{
+		{ID: "merged-only", URL: srv.URL, Events: []domain.EventType{domain.EventPullRequestMerged}, Secret: "s1"},
+		{ID: "reassign", URL: srv.URL, Events: []domain.EventType{domain.EventReviewerReassigned}, Secret: "s2"},
+	}}
+
+	d := startDispatcher(t, repo)
+
+	err := d.Deliver(context.Background(), domain.Event{
+		Type:             domain.EventReviewerReassigned,
+		PullRequest:      domain.PullRequest{ID: "pr-1", AuthorID: "u1", Status: domain.PullRequestStatusOpen},
+		AddedReviewers:   []domain.UserID{"u3"},
+		RemovedReviewers: []domain.UserID{"u2"},
+		OccurredAt:       time.Now().UTC(),
+	})
+	if err != nil {
+		t.Fatalf("Deliver returned error: %v", err)
+	}
+
+	var (
+		req  *http.Request
+		body []byte
+	)
+
+	select {
+	case req = <-received:
+		body = <-bodies
+	case <-time.After(time.Second):
+		t.Fatalf("webhook was not delivered")
+	}
+
+	if got := req.Header.Get(HeaderEvent); got != string(domain.EventReviewerReassigned) {
+		t.Fatalf("unexpected event header: %q", got)
+	}
+
+	if !Verify("s2", body, req.Header.Get(HeaderSignature)) {
+		t.Fatalf("signature does not match secret of subscription")
+	}
+
+	var payload Payload
+	if err := json.Unmarshal(body, &payload); err != nil {
+		t.Fatalf("unmarshal payload: %v", err)
+	}
+
+	if payload.PullRequest.PullRequestID != "pr-1" || payload.DeliveryID != req.Header.Get(HeaderDelivery) {
+		t.Fatalf("unexpected payload: %+v", payload)
+	}
+
+	if len(payload.AddedReviewers) != 1 || payload.AddedReviewers[0] != "u3" {
+		t.Fatalf("unexpected added reviewers: %v", payload.AddedReviewers)
+	}
+
+	select {
+	case <-received:
+		t.Fatalf("subscription with non-matching filter received webhook")
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+// TestDispatcher_DeadLetter проверяет повторы и сохранение вебхука в dead letter.
+func TestDispatcher_DeadLetter(t *testing.T) {
+	var calls atomic.Int32
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer srv.Close()
+
+	repo := &memoryRepo{subs: []domain.WebhookSubscription{{ID: "all", URL: srv.URL, Secret: "s"}}}
+	d := startDispatcher(t, repo)
+
+	err := d.Deliver(context.Background(), domain.Event{
+		Type:        domain.EventPullRequestMerged,
+		PullRequest: domain.PullRequest{ID: "pr-2"},
+	})
+	if err != nil {
+		t.Fatalf("Deliver returned error: %v", err)
+	}
+
+	deadline := time.Now().Add(time.Second)
+	for len(repo.DeadLetters()) == 0 {
+		if time.Now().After(deadline) {
+			t.Fatalf("dead letter was not saved")
+		}
+
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	dl := repo.DeadLetters()[0]
+	if dl.SubscriptionID != "all" || dl.Attempts != 3 || dl.LastStatusCode != http.StatusServiceUnavailable {
+		t.Fatalf("unexpected dead letter: %+v", dl)
+	}
+
+	if calls.Load() != 3 {
+		t.Fatalf("expected 3 attempts, got %d", calls.Load())
+	}
+}
@@ -0,0 +1,45 @@
+// Package webhook содержит асинхронную доставку исходящих вебхуков подписчикам.
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+
+	"github.com/dixitix/pr-reviewer-service/internal/domain"
+	"github.com/dixitix/pr-reviewer-service/internal/event"
+)
+
+// Заголовки исходящих вебхуков.
+const (
+	HeaderEvent     = "X-Webhook-Event"
+	HeaderDelivery  = "X-Webhook-Delivery"
+	HeaderSignature = "X-Webhook-Signature-256"
+)
+
+// Payload описывает тело вебхука: событие и идентификатор доставки.
+type Payload struct {
+	DeliveryID string `json:"delivery_id"`
+	event.Message
+}
+
+// NewPayload строит тело вебхука из доменного события.
+func NewPayload(deliveryID string, e domain.Event) Payload {
+	return Payload{
+		DeliveryID: deliveryID,
+		Message:    event.NewMessage(e),
+	}
+}
+
+// Sign возвращает значение заголовка подписи: "sha256=" + hex(HMAC-SHA256(secret, body)).
+func Sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify проверяет подпись тела вебхука в постоянное время.
+func Verify(secret string, body []byte, signature string) bool {
+	return hmac.Equal([]byte(Sign(secret, body)), []byte(signature))
+}
@@ -0,0 +1,6 @@
+-- 0003_webhooks.down.sql
+-- Удаляет таблицы исходящих вебхуков.
+
+DROP TABLE IF EXISTS webhook_dead_letters;
+DROP TABLE IF EXISTS webhook_subscription_events;
+DROP TABLE IF EXISTS webhook_subscriptions;
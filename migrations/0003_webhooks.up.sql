@@ -0,0 +1,32 @@
+-- 0003_webhooks.up.sql
+-- Подписки на исходящие вебхуки и недоставленные вебхуки (dead letter).
+
+CREATE TABLE webhook_subscriptions (
+    id text PRIMARY KEY,
+    url text NOT NULL,
+    secret text NOT NULL,
+    created_at timestamptz NOT NULL DEFAULT now()
+);
+
+CREATE TABLE webhook_subscription_events (
+    subscription_id text NOT NULL REFERENCES webhook_subscriptions(id) ON DELETE CASCADE,
+    event_type text NOT NULL,
+    PRIMARY KEY (subscription_id, event_type)
+);
+
+CREATE INDEX idx_webhook_subscription_events_type
+    ON webhook_subscription_events (event_type);
+
+CREATE TABLE webhook_dead_letters (
+    id text PRIMARY KEY,
+    subscription_id text NOT NULL REFERENCES webhook_subscriptions(id) ON DELETE CASCADE,
+    event_type text NOT NULL,
+    payload jsonb NOT NULL,
+    attempts integer NOT NULL,
+    last_error text NOT NULL,
+    last_status_code integer,
+    failed_at timestamptz NOT NULL DEFAULT now()
+);
+
+CREATE INDEX idx_webhook_dead_letters_subscription
+    ON webhook_dead_letters (subscription_id, failed_at DESC);
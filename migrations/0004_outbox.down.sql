@@ -0,0 +1,4 @@
+-- 0004_outbox.down.sql
+-- Удаляет таблицу outbox.
+
+DROP TABLE IF EXISTS outbox_events;
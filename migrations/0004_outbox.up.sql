@@ -0,0 +1,22 @@
+-- 0004_outbox.up.sql
+-- Transactional outbox для надёжной публикации событий об изменениях PR.
+
+CREATE TABLE outbox_events (
+    id bigserial PRIMARY KEY,
+    event_type text NOT NULL,
+    pull_request_id text NOT NULL,
+    payload jsonb NOT NULL,
+    created_at timestamptz NOT NULL DEFAULT now(),
+    available_at timestamptz NOT NULL DEFAULT now(),
+    attempts integer NOT NULL DEFAULT 0,
+    last_error text,
+    published_at timestamptz
+);
+
+CREATE INDEX idx_outbox_events_pending
+    ON outbox_events (available_at, id)
+    WHERE published_at IS NULL;
+
+CREATE INDEX idx_outbox_events_published
+    ON outbox_events (published_at)
+    WHERE published_at IS NOT NULL;
@@ -0,0 +1,5 @@
+-- 0005_notifications.down.sql
+-- Удаляет настройки уведомлений и каналы чата команд.
+
+DROP TABLE IF EXISTS team_chat_channels;
+DROP TABLE IF EXISTS user_notification_preferences;
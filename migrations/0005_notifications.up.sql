@@ -0,0 +1,15 @@
+-- 0005_notifications.up.sql
+-- Настройки уведомлений пользователей и каналы чата команд.
+
+CREATE TABLE user_notification_preferences (
+    user_id text PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    chat_enabled boolean NOT NULL DEFAULT true,
+    updated_at timestamptz NOT NULL DEFAULT now()
+);
+
+CREATE TABLE team_chat_channels (
+    team_name text PRIMARY KEY REFERENCES teams(name) ON DELETE CASCADE,
+    webhook_url text NOT NULL,
+    channel text NOT NULL DEFAULT '',
+    updated_at timestamptz NOT NULL DEFAULT now()
+);
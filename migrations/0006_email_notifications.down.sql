@@ -0,0 +1,10 @@
+-- 0006_email_notifications.down.sql
+-- Удаляет настройки почтовых уведомлений и учёт ежедневных сводок.
+
+DROP TABLE IF EXISTS email_digests;
+
+DROP INDEX IF EXISTS idx_user_notification_preferences_email_mode;
+
+ALTER TABLE user_notification_preferences
+    DROP COLUMN IF EXISTS email_mode,
+    DROP COLUMN IF EXISTS email;
@@ -0,0 +1,17 @@
+-- 0006_email_notifications.up.sql
+-- Настройки почтовых уведомлений и учёт отправленных ежедневных сводок.
+
+ALTER TABLE user_notification_preferences
+    ADD COLUMN email text NOT NULL DEFAULT '',
+    ADD COLUMN email_mode text NOT NULL DEFAULT 'off'
+        CHECK (email_mode IN ('off', 'immediate', 'digest'));
+
+CREATE INDEX idx_user_notification_preferences_email_mode
+    ON user_notification_preferences (email_mode);
+
+CREATE TABLE email_digests (
+    user_id text NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    digest_date date NOT NULL,
+    sent_at timestamptz NOT NULL DEFAULT now(),
+    PRIMARY KEY (user_id, digest_date)
+);
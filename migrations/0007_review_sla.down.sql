@@ -0,0 +1,12 @@
+-- 0007_review_sla.down.sql
+-- Удаляет SLA ревью команд, историю PR и время назначения ревьюверов.
+
+DROP TABLE IF EXISTS pull_request_history;
+DROP TABLE IF EXISTS team_review_policies;
+
+DROP INDEX IF EXISTS idx_pr_reviewers_assigned_at;
+
+ALTER TABLE pull_request_reviewers
+    DROP COLUMN IF EXISTS escalated_at,
+    DROP COLUMN IF EXISTS reminded_at,
+    DROP COLUMN IF EXISTS assigned_at;
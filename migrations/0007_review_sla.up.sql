@@ -0,0 +1,35 @@
+-- 0007_review_sla.up.sql
+-- Время назначения ревьюверов, SLA ревью команд и история PR.
+
+ALTER TABLE pull_request_reviewers
+    ADD COLUMN assigned_at timestamptz NOT NULL DEFAULT now(),
+    ADD COLUMN reminded_at timestamptz,
+    ADD COLUMN escalated_at timestamptz;
+
+-- Для уже назначенных ревьюверов точное время назначения неизвестно, берём время создания PR.
+UPDATE pull_request_reviewers r
+SET assigned_at = pr.created_at
+FROM pull_requests pr
+WHERE pr.id = r.pull_request_id;
+
+CREATE INDEX idx_pr_reviewers_assigned_at
+    ON pull_request_reviewers (assigned_at);
+
+CREATE TABLE team_review_policies (
+    team_name text PRIMARY KEY REFERENCES teams(name) ON DELETE CASCADE,
+    reminder_after_seconds bigint NOT NULL CHECK (reminder_after_seconds >= 0),
+    escalate_after_seconds bigint NOT NULL CHECK (escalate_after_seconds >= 0),
+    escalation_action text NOT NULL CHECK (escalation_action IN ('none', 'reassign', 'escalate')),
+    updated_at timestamptz NOT NULL DEFAULT now()
+);
+
+CREATE TABLE pull_request_history (
+    id bigserial PRIMARY KEY,
+    pull_request_id text NOT NULL REFERENCES pull_requests(id) ON DELETE CASCADE,
+    event_type text NOT NULL,
+    payload jsonb NOT NULL,
+    occurred_at timestamptz NOT NULL
+);
+
+CREATE INDEX idx_pull_request_history_pr
+    ON pull_request_history (pull_request_id, id);
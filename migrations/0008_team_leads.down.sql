@@ -0,0 +1,4 @@
+-- 0008_team_leads.down.sql
+-- Удаляет лидов команд.
+
+DROP TABLE IF EXISTS team_leads;
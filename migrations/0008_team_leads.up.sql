@@ -0,0 +1,12 @@
+-- 0008_team_leads.up.sql
+-- Лиды команд: адресаты эскалаций и запасные ревьюверы.
+
+CREATE TABLE team_leads (
+    team_name text NOT NULL REFERENCES teams(name) ON DELETE CASCADE,
+    user_id text NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    created_at timestamptz NOT NULL DEFAULT now(),
+    PRIMARY KEY (team_name, user_id)
+);
+
+CREATE INDEX idx_team_leads_user
+    ON team_leads (user_id);
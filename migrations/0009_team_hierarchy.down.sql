@@ -0,0 +1,9 @@
+-- 0009_team_hierarchy.down.sql
+-- Удаляет иерархию команд.
+
+DROP INDEX IF EXISTS idx_teams_parent;
+
+ALTER TABLE teams
+    DROP CONSTRAINT IF EXISTS teams_parent_not_self,
+    DROP COLUMN IF EXISTS fallback_to_parent,
+    DROP COLUMN IF EXISTS parent_name;
@@ -0,0 +1,10 @@
+-- 0009_team_hierarchy.up.sql
+-- Иерархия команд: отделы → команды → сквады.
+
+ALTER TABLE teams
+    ADD COLUMN parent_name text REFERENCES teams(name) ON DELETE RESTRICT,
+    ADD COLUMN fallback_to_parent boolean NOT NULL DEFAULT false,
+    ADD CONSTRAINT teams_parent_not_self CHECK (parent_name <> name);
+
+CREATE INDEX idx_teams_parent
+    ON teams (parent_name);
@@ -0,0 +1,4 @@
+-- 0010_team_memberships.down.sql
+-- Удаляет членство в нескольких командах; основная команда остаётся в users.team_name.
+
+DROP TABLE IF EXISTS team_memberships;
@@ -0,0 +1,22 @@
+-- 0010_team_memberships.up.sql
+-- Членство пользователей в нескольких командах. users.team_name остаётся основной командой
+-- и дублируется в team_memberships с is_primary = TRUE.
+
+CREATE TABLE team_memberships (
+    team_name text NOT NULL REFERENCES teams(name) ON DELETE CASCADE,
+    user_id text NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    is_primary boolean NOT NULL DEFAULT FALSE,
+    created_at timestamptz NOT NULL DEFAULT now(),
+    PRIMARY KEY (team_name, user_id)
+);
+
+CREATE INDEX idx_team_memberships_user
+    ON team_memberships (user_id);
+
+CREATE UNIQUE INDEX idx_team_memberships_primary
+    ON team_memberships (user_id)
+    WHERE is_primary;
+
+INSERT INTO team_memberships (team_name, user_id, is_primary, created_at)
+SELECT team_name, id, TRUE, created_at
+FROM users;
@@ -0,0 +1,103 @@
+-- 0011_organizations.down.sql
+-- Возвращает глобальные идентификаторы. Откат возможен, только пока идентификаторы
+-- команд, пользователей и PR не повторяются в разных организациях.
+
+DROP INDEX IF EXISTS idx_webhook_dead_letters_org;
+DROP INDEX IF EXISTS idx_webhook_subscriptions_org;
+
+ALTER TABLE teams DROP CONSTRAINT teams_org_id_parent_name_fkey;
+ALTER TABLE users DROP CONSTRAINT users_org_id_team_name_fkey;
+ALTER TABLE pull_requests DROP CONSTRAINT pull_requests_org_id_author_id_fkey;
+ALTER TABLE pull_request_reviewers
+    DROP CONSTRAINT pull_request_reviewers_org_id_pull_request_id_fkey,
+    DROP CONSTRAINT pull_request_reviewers_org_id_reviewer_id_fkey;
+ALTER TABLE user_notification_preferences DROP CONSTRAINT user_notification_preferences_org_id_user_id_fkey;
+ALTER TABLE team_chat_channels DROP CONSTRAINT team_chat_channels_org_id_team_name_fkey;
+ALTER TABLE email_digests DROP CONSTRAINT email_digests_org_id_user_id_fkey;
+ALTER TABLE team_review_policies DROP CONSTRAINT team_review_policies_org_id_team_name_fkey;
+ALTER TABLE pull_request_history DROP CONSTRAINT pull_request_history_org_id_pull_request_id_fkey;
+ALTER TABLE team_leads
+    DROP CONSTRAINT team_leads_org_id_team_name_fkey,
+    DROP CONSTRAINT team_leads_org_id_user_id_fkey;
+ALTER TABLE team_memberships
+    DROP CONSTRAINT team_memberships_org_id_team_name_fkey,
+    DROP CONSTRAINT team_memberships_org_id_user_id_fkey;
+
+ALTER TABLE teams DROP CONSTRAINT teams_pkey, ADD PRIMARY KEY (name);
+ALTER TABLE users DROP CONSTRAINT users_pkey, ADD PRIMARY KEY (id);
+ALTER TABLE pull_requests DROP CONSTRAINT pull_requests_pkey, ADD PRIMARY KEY (id);
+ALTER TABLE pull_request_reviewers
+    DROP CONSTRAINT pull_request_reviewers_pkey,
+    ADD PRIMARY KEY (pull_request_id, reviewer_id);
+ALTER TABLE user_notification_preferences DROP CONSTRAINT user_notification_preferences_pkey, ADD PRIMARY KEY (user_id);
+ALTER TABLE team_chat_channels DROP CONSTRAINT team_chat_channels_pkey, ADD PRIMARY KEY (team_name);
+ALTER TABLE email_digests DROP CONSTRAINT email_digests_pkey, ADD PRIMARY KEY (user_id, digest_date);
+ALTER TABLE team_review_policies DROP CONSTRAINT team_review_policies_pkey, ADD PRIMARY KEY (team_name);
+ALTER TABLE team_leads DROP CONSTRAINT team_leads_pkey, ADD PRIMARY KEY (team_name, user_id);
+ALTER TABLE team_memberships DROP CONSTRAINT team_memberships_pkey, ADD PRIMARY KEY (team_name, user_id);
+
+DROP INDEX idx_users_team_active;
+CREATE INDEX idx_users_team_active ON users (team_name, is_active);
+DROP INDEX idx_teams_parent;
+CREATE INDEX idx_teams_parent ON teams (parent_name);
+DROP INDEX idx_pull_requests_author;
+CREATE INDEX idx_pull_requests_author ON pull_requests (author_id);
+DROP INDEX idx_pr_reviewers_reviewer;
+CREATE INDEX idx_pr_reviewers_reviewer ON pull_request_reviewers (reviewer_id);
+DROP INDEX idx_pull_request_history_pr;
+CREATE INDEX idx_pull_request_history_pr ON pull_request_history (pull_request_id, id);
+DROP INDEX idx_team_leads_user;
+CREATE INDEX idx_team_leads_user ON team_leads (user_id);
+DROP INDEX idx_team_memberships_user;
+CREATE INDEX idx_team_memberships_user ON team_memberships (user_id);
+DROP INDEX idx_team_memberships_primary;
+CREATE UNIQUE INDEX idx_team_memberships_primary ON team_memberships (user_id) WHERE is_primary;
+
+ALTER TABLE teams DROP COLUMN org_id;
+ALTER TABLE users DROP COLUMN org_id;
+ALTER TABLE pull_requests DROP COLUMN org_id;
+ALTER TABLE pull_request_reviewers DROP COLUMN org_id;
+ALTER TABLE user_notification_preferences DROP COLUMN org_id;
+ALTER TABLE team_chat_channels DROP COLUMN org_id;
+ALTER TABLE email_digests DROP COLUMN org_id;
+ALTER TABLE team_review_policies DROP COLUMN org_id;
+ALTER TABLE pull_request_history DROP COLUMN org_id;
+ALTER TABLE team_leads DROP COLUMN org_id;
+ALTER TABLE team_memberships DROP COLUMN org_id;
+ALTER TABLE outbox_events DROP COLUMN org_id;
+ALTER TABLE webhook_subscriptions DROP COLUMN org_id;
+ALTER TABLE webhook_dead_letters DROP COLUMN org_id;
+
+ALTER TABLE teams
+    ADD CONSTRAINT teams_parent_name_fkey FOREIGN KEY (parent_name) REFERENCES teams (name) ON DELETE RESTRICT;
+ALTER TABLE users
+    ADD CONSTRAINT users_team_name_fkey FOREIGN KEY (team_name) REFERENCES teams (name) ON DELETE RESTRICT;
+ALTER TABLE pull_requests
+    ADD CONSTRAINT pull_requests_author_id_fkey FOREIGN KEY (author_id) REFERENCES users (id) ON DELETE RESTRICT;
+ALTER TABLE pull_request_reviewers
+    ADD CONSTRAINT pull_request_reviewers_pull_request_id_fkey
+        FOREIGN KEY (pull_request_id) REFERENCES pull_requests (id) ON DELETE CASCADE,
+    ADD CONSTRAINT pull_request_reviewers_reviewer_id_fkey
+        FOREIGN KEY (reviewer_id) REFERENCES users (id) ON DELETE RESTRICT;
+ALTER TABLE user_notification_preferences
+    ADD CONSTRAINT user_notification_preferences_user_id_fkey
+        FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE;
+ALTER TABLE team_chat_channels
+    ADD CONSTRAINT team_chat_channels_team_name_fkey
+        FOREIGN KEY (team_name) REFERENCES teams (name) ON DELETE CASCADE;
+ALTER TABLE email_digests
+    ADD CONSTRAINT email_digests_user_id_fkey FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE;
+ALTER TABLE team_review_policies
+    ADD CONSTRAINT team_review_policies_team_name_fkey
+        FOREIGN KEY (team_name) REFERENCES teams (name) ON DELETE CASCADE;
+ALTER TABLE pull_request_history
+    ADD CONSTRAINT pull_request_history_pull_request_id_fkey
+        FOREIGN KEY (pull_request_id) REFERENCES pull_requests (id) ON DELETE CASCADE;
+ALTER TABLE team_leads
+    ADD CONSTRAINT team_leads_team_name_fkey FOREIGN KEY (team_name) REFERENCES teams (name) ON DELETE CASCADE,
+    ADD CONSTRAINT team_leads_user_id_fkey FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE;
+ALTER TABLE team_memberships
+    ADD CONSTRAINT team_memberships_team_name_fkey FOREIGN KEY (team_name) REFERENCES teams (name) ON DELETE CASCADE,
+    ADD CONSTRAINT team_memberships_user_id_fkey FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE;
+
+DROP TABLE IF EXISTS organizations;
@@ -0,0 +1,130 @@
+-- 0011_organizations.up.sql
+-- Организации (арендаторы). Все данные принадлежат организации, идентификаторы команд,
+-- пользователей и PR уникальны в пределах организации. Существующие данные переносятся
+-- в организацию 'default'.
+
+CREATE TABLE organizations (
+    id text PRIMARY KEY,
+    name text NOT NULL,
+    created_at timestamptz NOT NULL DEFAULT now()
+);
+
+INSERT INTO organizations (id, name) VALUES ('default', 'Default organization');
+
+-- Внешние ключи снимаются до замены первичных ключей, на которые они ссылаются.
+ALTER TABLE teams DROP CONSTRAINT teams_parent_name_fkey;
+ALTER TABLE users DROP CONSTRAINT users_team_name_fkey;
+ALTER TABLE pull_requests DROP CONSTRAINT pull_requests_author_id_fkey;
+ALTER TABLE pull_request_reviewers
+    DROP CONSTRAINT pull_request_reviewers_pull_request_id_fkey,
+    DROP CONSTRAINT pull_request_reviewers_reviewer_id_fkey;
+ALTER TABLE user_notification_preferences DROP CONSTRAINT user_notification_preferences_user_id_fkey;
+ALTER TABLE team_chat_channels DROP CONSTRAINT team_chat_channels_team_name_fkey;
+ALTER TABLE email_digests DROP CONSTRAINT email_digests_user_id_fkey;
+ALTER TABLE team_review_policies DROP CONSTRAINT team_review_policies_team_name_fkey;
+ALTER TABLE pull_request_history DROP CONSTRAINT pull_request_history_pull_request_id_fkey;
+ALTER TABLE team_leads
+    DROP CONSTRAINT team_leads_team_name_fkey,
+    DROP CONSTRAINT team_leads_user_id_fkey;
+ALTER TABLE team_memberships
+    DROP CONSTRAINT team_memberships_team_name_fkey,
+    DROP CONSTRAINT team_memberships_user_id_fkey;
+
+ALTER TABLE teams ADD COLUMN org_id text NOT NULL DEFAULT 'default' REFERENCES organizations(id);
+ALTER TABLE users ADD COLUMN org_id text NOT NULL DEFAULT 'default' REFERENCES organizations(id);
+ALTER TABLE pull_requests ADD COLUMN org_id text NOT NULL DEFAULT 'default' REFERENCES organizations(id);
+ALTER TABLE pull_request_reviewers ADD COLUMN org_id text NOT NULL DEFAULT 'default' REFERENCES organizations(id);
+ALTER TABLE user_notification_preferences ADD COLUMN org_id text NOT NULL DEFAULT 'default' REFERENCES organizations(id);
+ALTER TABLE team_chat_channels ADD COLUMN org_id text NOT NULL DEFAULT 'default' REFERENCES organizations(id);
+ALTER TABLE email_digests ADD COLUMN org_id text NOT NULL DEFAULT 'default' REFERENCES organizations(id);
+ALTER TABLE team_review_policies ADD COLUMN org_id text NOT NULL DEFAULT 'default' REFERENCES organizations(id);
+ALTER TABLE pull_request_history ADD COLUMN org_id text NOT NULL DEFAULT 'default' REFERENCES organizations(id);
+ALTER TABLE team_leads ADD COLUMN org_id text NOT NULL DEFAULT 'default' REFERENCES organizations(id);
+ALTER TABLE team_memberships ADD COLUMN org_id text NOT NULL DEFAULT 'default' REFERENCES organizations(id);
+ALTER TABLE outbox_events ADD COLUMN org_id text NOT NULL DEFAULT 'default' REFERENCES organizations(id);
+ALTER TABLE webhook_subscriptions ADD COLUMN org_id text NOT NULL DEFAULT 'default' REFERENCES organizations(id);
+ALTER TABLE webhook_dead_letters ADD COLUMN org_id text NOT NULL DEFAULT 'default' REFERENCES organizations(id);
+
+-- Организацию новых строк всегда задаёт приложение.
+ALTER TABLE teams ALTER COLUMN org_id DROP DEFAULT;
+ALTER TABLE users ALTER COLUMN org_id DROP DEFAULT;
+ALTER TABLE pull_requests ALTER COLUMN org_id DROP DEFAULT;
+ALTER TABLE pull_request_reviewers ALTER COLUMN org_id DROP DEFAULT;
+ALTER TABLE user_notification_preferences ALTER COLUMN org_id DROP DEFAULT;
+ALTER TABLE team_chat_channels ALTER COLUMN org_id DROP DEFAULT;
+ALTER TABLE email_digests ALTER COLUMN org_id DROP DEFAULT;
+ALTER TABLE team_review_policies ALTER COLUMN org_id DROP DEFAULT;
+ALTER TABLE pull_request_history ALTER COLUMN org_id DROP DEFAULT;
+ALTER TABLE team_leads ALTER COLUMN org_id DROP DEFAULT;
+ALTER TABLE team_memberships ALTER COLUMN org_id DROP DEFAULT;
+ALTER TABLE outbox_events ALTER COLUMN org_id DROP DEFAULT;
+ALTER TABLE webhook_subscriptions ALTER COLUMN org_id DROP DEFAULT;
+ALTER TABLE webhook_dead_letters ALTER COLUMN org_id DROP DEFAULT;
+
+ALTER TABLE teams DROP CONSTRAINT teams_pkey, ADD PRIMARY KEY (org_id, name);
+ALTER TABLE users DROP CONSTRAINT users_pkey, ADD PRIMARY KEY (org_id, id);
+ALTER TABLE pull_requests DROP CONSTRAINT pull_requests_pkey, ADD PRIMARY KEY (org_id, id);
+ALTER TABLE pull_request_reviewers
+    DROP CONSTRAINT pull_request_reviewers_pkey,
+    ADD PRIMARY KEY (org_id, pull_request_id, reviewer_id);
+ALTER TABLE user_notification_preferences
+    DROP CONSTRAINT user_notification_preferences_pkey,
+    ADD PRIMARY KEY (org_id, user_id);
+ALTER TABLE team_chat_channels DROP CONSTRAINT team_chat_channels_pkey, ADD PRIMARY KEY (org_id, team_name);
+ALTER TABLE email_digests DROP CONSTRAINT email_digests_pkey, ADD PRIMARY KEY (org_id, user_id, digest_date);
+ALTER TABLE team_review_policies DROP CONSTRAINT team_review_policies_pkey, ADD PRIMARY KEY (org_id, team_name);
+ALTER TABLE team_leads DROP CONSTRAINT team_leads_pkey, ADD PRIMARY KEY (org_id, team_name, user_id);
+ALTER TABLE team_memberships DROP CONSTRAINT team_memberships_pkey, ADD PRIMARY KEY (org_id, team_name, user_id);
+
+ALTER TABLE teams
+    ADD FOREIGN KEY (org_id, parent_name) REFERENCES teams (org_id, name) ON DELETE RESTRICT;
+ALTER TABLE users
+    ADD FOREIGN KEY (org_id, team_name) REFERENCES teams (org_id, name) ON DELETE RESTRICT;
+ALTER TABLE pull_requests
+    ADD FOREIGN KEY (org_id, author_id) REFERENCES users (org_id, id) ON DELETE RESTRICT;
+ALTER TABLE pull_request_reviewers
+    ADD FOREIGN KEY (org_id, pull_request_id) REFERENCES pull_requests (org_id, id) ON DELETE CASCADE,
+    ADD FOREIGN KEY (org_id, reviewer_id) REFERENCES users (org_id, id) ON DELETE RESTRICT;
+ALTER TABLE user_notification_preferences
+    ADD FOREIGN KEY (org_id, user_id) REFERENCES users (org_id, id) ON DELETE CASCADE;
+ALTER TABLE team_chat_channels
+    ADD FOREIGN KEY (org_id, team_name) REFERENCES teams (org_id, name) ON DELETE CASCADE;
+ALTER TABLE email_digests
+    ADD FOREIGN KEY (org_id, user_id) REFERENCES users (org_id, id) ON DELETE CASCADE;
+ALTER TABLE team_review_policies
+    ADD FOREIGN KEY (org_id, team_name) REFERENCES teams (org_id, name) ON DELETE CASCADE;
+ALTER TABLE pull_request_history
+    ADD FOREIGN KEY (org_id, pull_request_id) REFERENCES pull_requests (org_id, id) ON DELETE CASCADE;
+ALTER TABLE team_leads
+    ADD FOREIGN KEY (org_id, team_name) REFERENCES teams (org_id, name) ON DELETE CASCADE,
+    ADD FOREIGN KEY (org_id, user_id) REFERENCES users (org_id, id) ON DELETE CASCADE;
+ALTER TABLE team_memberships
+    ADD FOREIGN KEY (org_id, team_name) REFERENCES teams (org_id, name) ON DELETE CASCADE,
+    ADD FOREIGN KEY (org_id, user_id) REFERENCES users (org_id, id) ON DELETE CASCADE;
+
+DROP INDEX idx_users_team_active;
+CREATE INDEX idx_users_team_active ON users (org_id, team_name, is_active);
+
+DROP INDEX idx_teams_parent;
+CREATE INDEX idx_teams_parent ON teams (org_id, parent_name);
+
+DROP INDEX idx_pull_requests_author;
+CREATE INDEX idx_pull_requests_author ON pull_requests (org_id, author_id);
+
+DROP INDEX idx_pr_reviewers_reviewer;
+CREATE INDEX idx_pr_reviewers_reviewer ON pull_request_reviewers (org_id, reviewer_id);
+
+DROP INDEX idx_pull_request_history_pr;
+CREATE INDEX idx_pull_request_history_pr ON pull_request_history (org_id, pull_request_id, id);
+
+DROP INDEX idx_team_leads_user;
+CREATE INDEX idx_team_leads_user ON team_leads (org_id, user_id);
+
+DROP INDEX idx_team_memberships_user;
+CREATE INDEX idx_team_memberships_user ON team_memberships (org_id, user_id);
+
+DROP INDEX idx_team_memberships_primary;
+CREATE UNIQUE INDEX idx_team_memberships_primary ON team_memberships (org_id, user_id) WHERE is_primary;
+
+CREATE INDEX idx_webhook_subscriptions_org ON webhook_subscriptions (org_id);
+CREATE INDEX idx_webhook_dead_letters_org ON webhook_dead_letters (org_id, failed_at DESC);
@@ -0,0 +1,3 @@
+-- 0012_api_keys.down.sql
+
+DROP TABLE IF EXISTS api_keys;
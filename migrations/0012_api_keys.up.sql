@@ -0,0 +1,16 @@
+-- 0012_api_keys.up.sql
+-- API-ключи организаций. Хранится только SHA-256 ключа; ключ показывается один раз при выпуске.
+
+CREATE TABLE api_keys (
+    id           text PRIMARY KEY,
+    org_id       text NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    name         text NOT NULL,
+    key_prefix   text NOT NULL,
+    key_hash     text NOT NULL UNIQUE,
+    scopes       text[] NOT NULL,
+    created_at   timestamptz NOT NULL DEFAULT now(),
+    last_used_at timestamptz,
+    revoked_at   timestamptz
+);
+
+CREATE INDEX idx_api_keys_org ON api_keys (org_id, created_at);
@@ -0,0 +1,6 @@
+-- 0013_actor_attribution.down.sql
+
+ALTER TABLE pull_requests
+    DROP COLUMN reassigned_by,
+    DROP COLUMN merged_by,
+    DROP COLUMN created_by;
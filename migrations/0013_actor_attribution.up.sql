@@ -0,0 +1,8 @@
+-- 0013_actor_attribution.up.sql
+-- Кто создал PR, выполнил merge и последним переназначил ревьювера.
+-- Для существующих PR значения неизвестны и остаются NULL.
+
+ALTER TABLE pull_requests
+    ADD COLUMN created_by text,
+    ADD COLUMN merged_by text,
+    ADD COLUMN reassigned_by text;
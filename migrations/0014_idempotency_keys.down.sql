@@ -0,0 +1,3 @@
+-- 0014_idempotency_keys.down.sql
+
+DROP TABLE IF EXISTS idempotency_keys;
@@ -0,0 +1,17 @@
+-- 0014_idempotency_keys.up.sql
+-- Ответы на POST-запросы с заголовком Idempotency-Key. status_code IS NULL — запрос ещё выполняется.
+
+CREATE TABLE idempotency_keys (
+    org_id text NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    key text NOT NULL,
+    request_hash text NOT NULL,
+    status_code integer,
+    content_type text,
+    response_body bytea,
+    created_at timestamptz NOT NULL,
+    expires_at timestamptz NOT NULL,
+    PRIMARY KEY (org_id, key)
+);
+
+CREATE INDEX idx_idempotency_keys_expires_at
+    ON idempotency_keys (expires_at);
@@ -0,0 +1,5 @@
+-- Версия PR для оптимистичной блокировки: каждое обновление увеличивает её на единицу
+-- и выполняется, только если версия не изменилась с момента чтения.
+
+ALTER TABLE pull_requests
+    ADD COLUMN version bigint NOT NULL DEFAULT 1;
@@ -0,0 +1,5 @@
+-- Версия команды для ETag: растёт при изменении иерархии, лидов, состава команды
+-- и активности её участников.
+
+ALTER TABLE teams
+    ADD COLUMN version bigint NOT NULL DEFAULT 1;
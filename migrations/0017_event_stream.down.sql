@@ -0,0 +1 @@
+DROP INDEX idx_pull_request_history_org_id;
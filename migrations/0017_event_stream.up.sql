@@ -0,0 +1,4 @@
+-- Лента событий организации (/events/stream) читается из pull_request_history по возрастанию id.
+
+CREATE INDEX idx_pull_request_history_org_id
+    ON pull_request_history (org_id, id);
@@ -0,0 +1,3 @@
+-- 0018_vcs_sync_failures.down.sql
+
+DROP TABLE IF EXISTS vcs_sync_failures;
@@ -0,0 +1,21 @@
+-- 0018_vcs_sync_failures.up.sql
+-- Изменения ревьюверов, которые не удалось отправить VCS-провайдеру. Хранятся в БД, чтобы
+-- пережить перезапуск: событие outbox к этому моменту уже отмечено доставленным.
+-- permanent — постоянная ошибка, такие записи не повторяются и остаются для разбора.
+
+CREATE TABLE vcs_sync_failures (
+    id bigserial PRIMARY KEY,
+    org_id text NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    pull_request_id text NOT NULL,
+    add_reviewers jsonb NOT NULL,
+    remove_reviewers jsonb NOT NULL,
+    attempts integer NOT NULL,
+    last_error text NOT NULL,
+    permanent boolean NOT NULL DEFAULT false,
+    failed_at timestamptz NOT NULL,
+    retry_at timestamptz NOT NULL
+);
+
+CREATE INDEX idx_vcs_sync_failures_retry_at
+    ON vcs_sync_failures (retry_at)
+    WHERE NOT permanent;
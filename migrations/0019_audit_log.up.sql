@@ -0,0 +1,17 @@
+-- 0019_audit_log.up.sql
+-- Журнал изменений команд, пользователей, настроек уведомлений, API-ключей и вебхуков: кто и когда
+-- выполнил операцию. Запись делается в той же транзакции, что и изменение. Изменения PR
+-- записываются в pull_requests (created_by, merged_by, reassigned_by) и pull_request_history.
+
+CREATE TABLE audit_log (
+    id bigserial PRIMARY KEY,
+    org_id text NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    action text NOT NULL,
+    target text NOT NULL,
+    actor text,
+    details jsonb NOT NULL DEFAULT '{}'::jsonb,
+    occurred_at timestamptz NOT NULL
+);
+
+CREATE INDEX idx_audit_log_org_id
+    ON audit_log (org_id, id);
@@ -12,7 +12,10 @@ import (
 	"github.com/dixitix/pr-reviewer-service/internal/http/httperr"
 )
 
-const defaultBaseURL = "http://localhost:18080"
+const (
+	defaultBaseURL = "http://localhost:18080"
+	defaultAPIKey  = "e2e-root-key-0123456789abcdef0123"
+)
 
 // baseURL возвращает базовый URL сервиса для e2e-тестов.
 func baseURL() string {
@@ -22,6 +25,14 @@ func baseURL() string {
 	return defaultBaseURL
 }
 
+// apiKey возвращает API-ключ, с которым e2e-тесты обращаются к сервису.
+func apiKey() string {
+	if v := os.Getenv("E2E_API_KEY"); v != "" {
+		return v
+	}
+	return defaultAPIKey
+}
+
 // doRequest — helper, который отправляет HTTP-запрос и проверяет статус.
 func doRequest(
 	t *testing.T,
@@ -52,6 +63,7 @@ func doRequest(
 	if body != nil {
 		req.Header.Set("Content-Type", "application/json")
 	}
+	req.Header.Set("Authorization", "Bearer "+apiKey())
 
 	client := &http.Client{
 		Timeout: 5 * time.Second,
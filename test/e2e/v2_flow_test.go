@@ -0,0 +1,83 @@
+// Package e2e содержит e2e тесты.
+package e2e
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/dixitix/pr-reviewer-service/internal/http/pullrequest"
+	"github.com/dixitix/pr-reviewer-service/internal/http/team"
+	"github.com/dixitix/pr-reviewer-service/internal/http/user"
+)
+
+// TestE2E_V2Flow проходит основной сценарий через ресурсные маршруты /v2:
+// 1) POST /v2/teams и GET /v2/teams/{name}
+// 2) POST /v2/pull-requests и GET /v2/pull-requests/{id}
+// 3) PATCH /v2/users/{id}
+// 4) POST /v2/pull-requests/{id}/merge без тела
+// 5) GET /v2/users/{id}/reviews
+func TestE2E_V2Flow(t *testing.T) {
+	suffix := time.Now().UnixNano()
+
+	teamName := fmt.Sprintf("v2-e2e-%d", suffix)
+	authorID := fmt.Sprintf("u-v2-author-%d", suffix)
+	reviewerID := fmt.Sprintf("u-v2-reviewer-%d", suffix)
+	prID := fmt.Sprintf("pr-v2-%d", suffix)
+
+	teamReq := team.DTO{
+		TeamName: teamName,
+		Members: []team.MemberDTO{
+			{UserID: authorID, Username: "Author", IsActive: true},
+			{UserID: reviewerID, Username: "Reviewer", IsActive: true},
+		},
+	}
+
+	doRequest(t, http.MethodPost, "/v2/teams", teamReq, http.StatusCreated, nil)
+
+	var teamResp team.GetTeamResponse
+	doRequest(t, http.MethodGet, "/v2/teams/"+teamName, nil, http.StatusOK, &teamResp)
+
+	if len(teamResp.Team.Members) != 2 {
+		t.Fatalf("expected 2 team members, got %d", len(teamResp.Team.Members))
+	}
+
+	createReq := pullrequest.CreatePullRequestRequest{
+		PullRequestID:   prID,
+		PullRequestName: "Add v2 routes",
+		AuthorID:        authorID,
+	}
+
+	doRequest(t, http.MethodPost, "/v2/pull-requests", createReq, http.StatusCreated, nil)
+
+	var getResp pullrequest.Envelope
+	doRequest(t, http.MethodGet, "/v2/pull-requests/"+prID, nil, http.StatusOK, &getResp)
+
+	if len(getResp.PullRequest.AssignedReviewers) != 1 || getResp.PullRequest.AssignedReviewers[0] != reviewerID {
+		t.Fatalf("assigned_reviewers = %+v, want [%s]", getResp.PullRequest.AssignedReviewers, reviewerID)
+	}
+
+	var userResp user.SetUserActiveResponse
+	doRequest(t, http.MethodPatch, "/v2/users/"+authorID, map[string]bool{"is_active": false}, http.StatusOK, &userResp)
+
+	if userResp.User.IsActive {
+		t.Fatalf("user %s is still active after PATCH", authorID)
+	}
+
+	var mergeResp pullrequest.Envelope
+	doRequest(t, http.MethodPost, "/v2/pull-requests/"+prID+"/merge", nil, http.StatusOK, &mergeResp)
+
+	if mergeResp.PullRequest.Status != "MERGED" {
+		t.Fatalf("status after merge: got %q, want %q", mergeResp.PullRequest.Status, "MERGED")
+	}
+
+	var reviewResp user.GetUserReviewResponse
+	doRequest(t, http.MethodGet, "/v2/users/"+reviewerID+"/reviews", nil, http.StatusOK, &reviewResp)
+
+	if len(reviewResp.PullRequests) != 1 || reviewResp.PullRequests[0].PullRequestID != prID {
+		t.Fatalf("reviews of %s = %+v, want only %s", reviewerID, reviewResp.PullRequests, prID)
+	}
+
+	doRequest(t, http.MethodDelete, "/v2/pull-requests/"+prID, nil, http.StatusMethodNotAllowed, nil)
+}
@@ -1,5 +1,6 @@
 from locust import HttpUser, task, between
 import uuid
+import os
 import random
 
 # 20 команд * 10 пользователей = 200 author_id
@@ -14,6 +15,9 @@ class PRReviewerUser(HttpUser):
     wait_time = between(1, 3)
 
     def on_start(self):
+        api_key = os.environ.get("API_KEY")
+        if api_key:
+            self.client.headers["Authorization"] = f"Bearer {api_key}"
         self.author_id = random.choice(AUTHORS)
         self._create_initial_pr()
 